@@ -21,6 +21,128 @@ USE `iam`;
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `access_token`
+--
+
+DROP TABLE IF EXISTS `access_token`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `access_token` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(45) NOT NULL,
+  `username` varchar(255) NOT NULL,
+  `hash` char(64) NOT NULL COMMENT 'sha256 of the personal access token',
+  `scopesShadow` longtext DEFAULT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `expiresAt` timestamp NULL DEFAULT NULL COMMENT 'the token never expires when null',
+  `lastUsedAt` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_hash` (`hash`),
+  UNIQUE KEY `idx_username_name` (`username`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `audit_log`
+--
+
+DROP TABLE IF EXISTS `audit_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `audit_log` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `tenant` varchar(45) NOT NULL DEFAULT 'default',
+  `actor` varchar(255) NOT NULL DEFAULT '' COMMENT 'empty for the anonymous requests',
+  `action` varchar(16) NOT NULL DEFAULT '' COMMENT 'create, update or delete, empty when no audited resource changed',
+  `kind` varchar(16) NOT NULL DEFAULT '',
+  `name` varchar(255) NOT NULL DEFAULT '',
+  `owner` varchar(255) NOT NULL DEFAULT '',
+  `changesShadow` longtext DEFAULT NULL COMMENT 'the changed fields, credentials redacted',
+  `method` varchar(8) NOT NULL,
+  `path` varchar(1024) NOT NULL,
+  `statusCode` int(3) NOT NULL,
+  `requestID` varchar(64) NOT NULL DEFAULT '',
+  `clientIP` varchar(64) NOT NULL DEFAULT '',
+  `userAgent` varchar(255) NOT NULL DEFAULT '',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  KEY `idx_tenant_createdAt` (`tenant`,`createdAt`),
+  KEY `idx_actor` (`actor`),
+  KEY `idx_kind_name` (`kind`,`name`),
+  KEY `idx_requestID` (`requestID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `email_verification`
+--
+
+DROP TABLE IF EXISTS `email_verification`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `email_verification` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(255) NOT NULL,
+  `email` varchar(256) NOT NULL COMMENT 'the email the token was sent to',
+  `hash` char(64) NOT NULL COMMENT 'sha256 of the email verification token',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `expiresAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `usedAt` timestamp NULL DEFAULT NULL COMMENT 'a token can only be used once',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_hash` (`hash`),
+  KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `group_member`
+--
+
+DROP TABLE IF EXISTS `group_member`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `group_member` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `tenant` varchar(45) NOT NULL DEFAULT 'default',
+  `groupName` varchar(45) NOT NULL,
+  `username` varchar(45) NOT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_group_username` (`tenant`,`groupName`,`username`),
+  KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `group_member`
+--
+
+LOCK TABLES `group_member` WRITE;
+/*!40000 ALTER TABLE `group_member` DISABLE KEYS */;
+/*!40000 ALTER TABLE `group_member` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `password_reset`
+--
+
+DROP TABLE IF EXISTS `password_reset`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `password_reset` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(255) NOT NULL,
+  `hash` char(64) NOT NULL COMMENT 'sha256 of the password reset token',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `expiresAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `usedAt` timestamp NULL DEFAULT NULL COMMENT 'a token can only be used once',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_hash` (`hash`),
+  KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `policy`
 --
@@ -102,6 +224,114 @@ LOCK TABLES `policy_audit` WRITE;
 /*!40000 ALTER TABLE `policy_audit` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `policy_binding`
+--
+
+DROP TABLE IF EXISTS `policy_binding`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `policy_binding` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `tenant` varchar(45) NOT NULL DEFAULT 'default',
+  `principal` varchar(128) NOT NULL,
+  `policyOwner` varchar(45) NOT NULL,
+  `policyName` varchar(45) NOT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_principal_policy` (`tenant`,`principal`,`policyOwner`,`policyName`),
+  KEY `idx_policy` (`policyOwner`,`policyName`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `policy_binding`
+--
+
+LOCK TABLES `policy_binding` WRITE;
+/*!40000 ALTER TABLE `policy_binding` DISABLE KEYS */;
+/*!40000 ALTER TABLE `policy_binding` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `refresh_token`
+--
+
+DROP TABLE IF EXISTS `refresh_token`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `refresh_token` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `hash` char(64) NOT NULL COMMENT 'sha256 of the opaque refresh token',
+  `sessionID` varchar(64) NOT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `rotatedAt` timestamp NULL DEFAULT NULL COMMENT 'rotated tokens must never be used again',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_hash` (`hash`),
+  KEY `idx_sessionID` (`sessionID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `role`
+--
+
+DROP TABLE IF EXISTS `role`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `role` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `instanceID` varchar(32) DEFAULT NULL,
+  `name` varchar(45) NOT NULL,
+  `tenant` varchar(45) NOT NULL DEFAULT 'default',
+  `description` varchar(255) NOT NULL DEFAULT '',
+  `extendShadow` longtext DEFAULT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_tenant_name` (`tenant`,`name`),
+  UNIQUE KEY `instanceID_UNIQUE` (`instanceID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `role`
+--
+
+LOCK TABLES `role` WRITE;
+/*!40000 ALTER TABLE `role` DISABLE KEYS */;
+/*!40000 ALTER TABLE `role` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `role_binding`
+--
+
+DROP TABLE IF EXISTS `role_binding`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `role_binding` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `tenant` varchar(45) NOT NULL DEFAULT 'default',
+  `roleName` varchar(45) NOT NULL,
+  `kind` varchar(16) NOT NULL COMMENT 'user or group',
+  `subject` varchar(45) NOT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_role_subject` (`tenant`,`roleName`,`kind`,`subject`),
+  KEY `idx_subject` (`kind`,`subject`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `role_binding`
+--
+
+LOCK TABLES `role_binding` WRITE;
+/*!40000 ALTER TABLE `role_binding` DISABLE KEYS */;
+/*!40000 ALTER TABLE `role_binding` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `secret`
 --
@@ -118,11 +348,16 @@ CREATE TABLE `secret` (
   `secretKey` varchar(255) NOT NULL,
   `expires` int(64) unsigned NOT NULL DEFAULT 1534308590,
   `description` varchar(255) NOT NULL,
+  `scopesShadow` longtext DEFAULT NULL,
+  `previousKey` varchar(255) NOT NULL DEFAULT '',
+  `previousKeyExpires` int(64) unsigned NOT NULL DEFAULT 0,
+  `lastUsedAt` timestamp NULL DEFAULT NULL,
   `extendShadow` longtext DEFAULT NULL,
   `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
   `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
   PRIMARY KEY (`id`),
   UNIQUE KEY `instanceID_UNIQUE` (`instanceID`),
+  KEY `secretID_idx` (`secretID`),
   KEY `fk_secret_user_idx` (`username`),
   CONSTRAINT `fk_secret_user` FOREIGN KEY (`username`) REFERENCES `user` (`name`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB AUTO_INCREMENT=22 DEFAULT CHARSET=utf8;
@@ -137,6 +372,108 @@ LOCK TABLES `secret` WRITE;
 /*!40000 ALTER TABLE `secret` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `session`
+--
+
+DROP TABLE IF EXISTS `session`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `session` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `sessionID` varchar(64) NOT NULL,
+  `username` varchar(255) NOT NULL,
+  `userAgent` varchar(255) NOT NULL DEFAULT '',
+  `clientIP` varchar(64) NOT NULL DEFAULT '',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `lastUsedAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `expiresAt` timestamp NOT NULL DEFAULT current_timestamp() COMMENT 'the session is over unless refreshed before expiresAt',
+  `revokedAt` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_sessionID` (`sessionID`),
+  KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `signing_key`
+--
+
+DROP TABLE IF EXISTS `signing_key`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `signing_key` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `kid` varchar(64) NOT NULL,
+  `algorithm` varchar(16) NOT NULL COMMENT 'RS256, ES256 or EdDSA',
+  `privateKey` text NOT NULL COMMENT 'PKCS #8 PEM encoded private key',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `retiredAt` timestamp NULL DEFAULT NULL COMMENT 'retired keys do not sign tokens anymore',
+  `expiresAt` timestamp NULL DEFAULT NULL COMMENT 'retired keys are deleted after expiresAt',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_kid` (`kid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `tenant`
+--
+
+DROP TABLE IF EXISTS `tenant`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `tenant` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `instanceID` varchar(32) DEFAULT NULL,
+  `name` varchar(45) NOT NULL,
+  `description` varchar(255) NOT NULL DEFAULT '',
+  `extendShadow` longtext DEFAULT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_name` (`name`),
+  UNIQUE KEY `instanceID_UNIQUE` (`instanceID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `tenant`
+--
+
+LOCK TABLES `tenant` WRITE;
+/*!40000 ALTER TABLE `tenant` DISABLE KEYS */;
+INSERT INTO `tenant` VALUES (1,'tenant-default','default','the tenant of the users without membership','{}',now(),now());
+/*!40000 ALTER TABLE `tenant` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `tenant_member`
+--
+
+DROP TABLE IF EXISTS `tenant_member`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `tenant_member` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `tenantName` varchar(45) NOT NULL,
+  `username` varchar(45) NOT NULL,
+  `isAdmin` tinyint(1) unsigned NOT NULL DEFAULT 0 COMMENT '1: tenant administrator, 0: non-administrator',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_username` (`username`),
+  KEY `idx_tenant` (`tenantName`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `tenant_member`
+--
+
+LOCK TABLES `tenant_member` WRITE;
+/*!40000 ALTER TABLE `tenant_member` DISABLE KEYS */;
+/*!40000 ALTER TABLE `tenant_member` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `user`
 --
@@ -148,7 +485,7 @@ CREATE TABLE `user` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
   `instanceID` varchar(32) DEFAULT NULL,
   `name` varchar(45) NOT NULL,
-  `status` int(1) DEFAULT 1 COMMENT '1:可用，0:不可用',
+  `status` int(1) DEFAULT 1 COMMENT '0:因长期未登录被禁用，1:可用，2:待验证邮箱，3:已暂停',
   `nickname` varchar(30) NOT NULL,
   `password` varchar(255) NOT NULL,
   `email` varchar(256) NOT NULL,
@@ -186,6 +523,17 @@ DELIMITER ;;
 BEGIN
 	delete from secret where username = old.name;
     delete from policy where username = old.name;
+    delete from group_member where username = old.name;
+    delete from role_binding where kind = 'user' and subject = old.name;
+    delete from policy_binding where policyOwner = old.name;
+    delete from tenant_member where username = old.name;
+    delete from refresh_token where sessionID in (select sessionID from session where username = old.name);
+    delete from session where username = old.name;
+    delete from user_totp where username = old.name;
+    delete from password_reset where username = old.name;
+    delete from email_verification where username = old.name;
+    delete from user_identity where username = old.name;
+    delete from access_token where username = old.name;
 END */;;
 DELIMITER ;
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
@@ -193,6 +541,95 @@ DELIMITER ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
 
+--
+-- Table structure for table `user_group`
+--
+
+DROP TABLE IF EXISTS `user_group`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_group` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `instanceID` varchar(32) DEFAULT NULL,
+  `name` varchar(45) NOT NULL,
+  `tenant` varchar(45) NOT NULL DEFAULT 'default',
+  `description` varchar(255) NOT NULL DEFAULT '',
+  `extendShadow` longtext DEFAULT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_tenant_name` (`tenant`,`name`),
+  UNIQUE KEY `instanceID_UNIQUE` (`instanceID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `user_group`
+--
+
+LOCK TABLES `user_group` WRITE;
+/*!40000 ALTER TABLE `user_group` DISABLE KEYS */;
+/*!40000 ALTER TABLE `user_group` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `user_identity`
+--
+
+DROP TABLE IF EXISTS `user_identity`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_identity` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(255) NOT NULL,
+  `issuer` varchar(255) NOT NULL COMMENT 'issuer of the external identity provider',
+  `subject` varchar(255) NOT NULL COMMENT 'subject of the account at the provider',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_issuer_subject` (`issuer`,`subject`),
+  KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `user_status_change`
+--
+
+DROP TABLE IF EXISTS `user_status_change`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_status_change` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(255) NOT NULL,
+  `status` int(1) NOT NULL COMMENT 'the status the user changed to, 4 means deleted',
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `operator` varchar(255) NOT NULL DEFAULT '' COMMENT 'empty for the changes made by the system',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `user_totp`
+--
+
+DROP TABLE IF EXISTS `user_totp`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_totp` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(255) NOT NULL,
+  `secret` varchar(64) NOT NULL COMMENT 'base32 encoded TOTP secret',
+  `recoveryCodes` text NOT NULL COMMENT 'comma separated sha256 of the unused recovery codes',
+  `lastStep` bigint(20) NOT NULL DEFAULT 0 COMMENT 'time step of the last accepted code',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `enabledAt` timestamp NULL DEFAULT NULL COMMENT 'the enrollment is pending until confirmed by a first code',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_username` (`username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Dumping events for database 'iam'
 --
@@ -5,14 +5,17 @@
 -- Item table to store general product information
 CREATE TABLE Item (
     id BIGINT PRIMARY KEY AUTO_INCREMENT,
-    asin VARCHAR(10) NOT NULL UNIQUE,
-    sku VARCHAR(255) NOT NULL UNIQUE,
+    asin VARCHAR(10) NOT NULL,
+    sku VARCHAR(255) NOT NULL,
     brand VARCHAR(255),
     title VARCHAR(500),
     product_group VARCHAR(255),
     product_type VARCHAR(255),
+    tenant VARCHAR(45) NOT NULL DEFAULT 'default',
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY idx_tenant_asin (tenant, asin),
+    UNIQUE KEY idx_tenant_sku (tenant, sku)
 );
 
 -- ItemAttributes table to store product attributes
@@ -105,4 +108,31 @@ CREATE TABLE FulfillmentAvailability (
     FOREIGN KEY (offer_id) REFERENCES ItemOfferByMarketplace(id),
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+-- ItemImportJob table to record the progress of the catalog imports
+CREATE TABLE ItemImportJob (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    tenant VARCHAR(45) NOT NULL DEFAULT 'default',
+    username VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    message TEXT,
+    processed_rows BIGINT NOT NULL DEFAULT 0,
+    created_rows BIGINT NOT NULL DEFAULT 0,
+    updated_rows BIGINT NOT NULL DEFAULT 0,
+    failed_rows BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP NULL DEFAULT NULL
+);
+
+-- ItemImportError table to record the rows rejected by a catalog import
+CREATE TABLE ItemImportError (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    job_id BIGINT NOT NULL,
+    `row` BIGINT NOT NULL,
+    `key` VARCHAR(255),
+    message TEXT,
+    FOREIGN KEY (job_id) REFERENCES ItemImportJob(id)
+);
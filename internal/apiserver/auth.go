@@ -12,14 +12,26 @@ import (
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
 	v1 "github.com/marmotedu/api/apiserver/v1"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/util/idutil"
+	"github.com/marmotedu/errors"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 	"github.com/spf13/viper"
 
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/lockout"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
 )
 
 const (
@@ -37,39 +49,98 @@ type loginInfo struct {
 
 func newBasicAuth() middleware.AuthStrategy {
 
-	return auth.NewBasicStrategy(func(username string, password string) bool {
+	return auth.NewBasicStrategy(func(username string, password string) error {
 		// fetch user from database
 		user, err := store.Client().Users().Get(context.TODO(), username, metav1.GetOptions{})
 		if err != nil {
-			return false
+			// unknown usernames count as mismatches, so that they cannot be told apart.
+			if errors.IsCode(err, code.ErrUserNotFound) {
+				return errors.WithCode(code.ErrPasswordIncorrect, err.Error())
+			}
+
+			return err
 		}
 
 		// Compare the login password with the user password.
 		if err := user.Compare(password); err != nil {
-			return false
+			return errors.WithCode(code.ErrPasswordIncorrect, err.Error())
+		}
+
+		if err := srvv1.CheckUserActive(user); err != nil {
+			return err
+		}
+
+		// a password alone is not enough for the users who must pass a second factor.
+		if secondFactorRequired(context.TODO(), user) {
+			return errors.WithCode(code.ErrPermissionDenied, "user %s must pass a second factor", username)
 		}
 
 		user.LoginedAt = time.Now()
 		_ = store.Client().Users().Update(context.TODO(), user, metav1.UpdateOptions{})
 
-		return true
+		return nil
+	}, newLoginLimiter())
+}
+
+// newLoginLimiter creates the limiter of the failed password authentications,
+// shared by the login and the basic authentication.
+func newLoginLimiter() *lockout.Limiter {
+	return lockout.NewLimiter(lockout.Client(), lockout.Policy{
+		MaxUserFailures: viper.GetInt64("lockout.max-user-failures"),
+		MaxIPFailures:   viper.GetInt64("lockout.max-ip-failures"),
+		Window:          viper.GetDuration("lockout.window"),
+		BaseLockout:     viper.GetDuration("lockout.base-duration"),
+		MaxLockout:      viper.GetDuration("lockout.max-duration"),
+	}, recordLockout)
+}
+
+// recordLockout records a lockout in the audit log of the tenant of the user.
+func recordLockout(e *lockout.Event) {
+	changes, err := audit.Diff(nil, e)
+	if err != nil {
+		log.Errorf("record lockout of user %s failed: %s", e.Username, err.Error())
+
+		return
+	}
+
+	err = store.Client().AuditLogs().Create(context.TODO(), &model.AuditLog{
+		Tenant:    tenantOf(e.Username),
+		Action:    audit.ActionLockout,
+		Kind:      event.KindUser,
+		Name:      e.Username,
+		Changes:   changes,
+		ClientIP:  e.ClientIP,
+		CreatedAt: time.Now(),
 	})
+	if err != nil {
+		log.Errorf("record lockout of user %s failed: %s", e.Username, err.Error())
+	}
 }
 
 func newJWTAuth() middleware.AuthStrategy {
+	var (
+		signer  auth.TokenSigner
+		keyFunc gojwt.Keyfunc
+	)
+
+	// gin-jwt signs with a single key and no kid, so it keeps HS256 and jwt.key
+	// while the rotated asymmetric keys sign and verify the tokens.
+	if signingKeys != nil {
+		signer, keyFunc = signingKeys, jwks.KeyFunc(signingKeys)
+	}
+
 	ginjwt, _ := jwt.New(&jwt.GinJWTMiddleware{
 		Realm:            viper.GetString("jwt.Realm"),
 		SigningAlgorithm: "HS256",
 		Key:              []byte(viper.GetString("jwt.key")),
+		KeyFunc:          keyFunc,
 		Timeout:          viper.GetDuration("jwt.timeout"),
 		MaxRefresh:       viper.GetDuration("jwt.max-refresh"),
 		Authenticator:    authenticator(),
-		LoginResponse:    loginResponse(),
 		LogoutResponse: func(c *gin.Context, code int) {
 			c.JSON(http.StatusOK, nil)
 		},
-		RefreshResponse: refreshResponse(),
-		PayloadFunc:     payloadFunc(),
+		PayloadFunc: payloadFunc(),
 		IdentityHandler: func(c *gin.Context) interface{} {
 			claims := jwt.ExtractClaims(c)
 
@@ -89,11 +160,26 @@ func newJWTAuth() middleware.AuthStrategy {
 		// TODO: HTTPStatusMessageFunc:
 	})
 
-	return auth.NewJWTStrategy(*ginjwt)
+	return auth.NewJWTStrategy(*ginjwt, revocation.Client(), signer, newSessionManager(), newSecondFactor())
 }
 
 func newAutoAuth() middleware.AuthStrategy {
-	return auth.NewAutoStrategy(newBasicAuth().(auth.BasicStrategy), newJWTAuth().(auth.JWTStrategy))
+	return auth.NewAutoStrategy(
+		newBasicAuth().(auth.BasicStrategy),
+		newJWTAuth().(auth.JWTStrategy),
+		newTokenAuth().(auth.TokenStrategy),
+	)
+}
+
+func newTokenAuth() middleware.AuthStrategy {
+	return auth.NewTokenStrategy(func(ctx context.Context, plain string) (*auth.AccessToken, error) {
+		token, err := srvv1.NewService(store.Client()).AccessTokens().Authenticate(ctx, plain)
+		if err != nil {
+			return nil, err
+		}
+
+		return &auth.AccessToken{Name: token.Name, Username: token.Username, Scopes: token.Scopes}, nil
+	})
 }
 
 func authorizator() func(data interface{}, c *gin.Context) bool {
@@ -109,6 +195,8 @@ func authorizator() func(data interface{}, c *gin.Context) bool {
 }
 
 func authenticator() func(c *gin.Context) (interface{}, error) {
+	limiter := newLoginLimiter()
+
 	return func(c *gin.Context) (interface{}, error) {
 		var login loginInfo
 		var err error
@@ -123,19 +211,37 @@ func authenticator() func(c *gin.Context) (interface{}, error) {
 			return "", jwt.ErrFailedAuthentication
 		}
 
+		if err := limiter.Allow(login.Username, c.ClientIP()); err != nil {
+			return "", err
+		}
+
 		// Get the user information by the login username.
 		user, err := store.Client().Users().Get(c, login.Username, metav1.GetOptions{})
 		if err != nil {
 			log.Errorf("get user information failed: %s", err.Error())
+			// unknown usernames count as well, so that they cannot be told apart.
+			limiter.Failed(login.Username, c.ClientIP())
 
 			return "", jwt.ErrFailedAuthentication
 		}
 
 		// Compare the login password with the user password.
 		if err := user.Compare(login.Password); err != nil {
+			limiter.Failed(login.Username, c.ClientIP())
+
 			return "", jwt.ErrFailedAuthentication
 		}
 
+		// the failures are forgotten once the second factor passes, if any.
+		if !secondFactorRequired(c, user) {
+			limiter.Succeeded(login.Username, c.ClientIP())
+		}
+
+		// the status is only told to the users who know their password.
+		if err := srvv1.CheckUserActive(user); err != nil {
+			return "", err
+		}
+
 		user.LoginedAt = time.Now()
 		_ = store.Client().Users().Update(c, user, metav1.UpdateOptions{})
 
@@ -182,35 +288,32 @@ func parseWithBody(c *gin.Context) (loginInfo, error) {
 	return login, nil
 }
 
-func refreshResponse() func(c *gin.Context, code int, token string, expire time.Time) {
-	return func(c *gin.Context, code int, token string, expire time.Time) {
-		c.JSON(http.StatusOK, gin.H{
-			"token":  token,
-			"expire": expire.Format(time.RFC3339),
-		})
-	}
-}
-
-func loginResponse() func(c *gin.Context, code int, token string, expire time.Time) {
-	return func(c *gin.Context, code int, token string, expire time.Time) {
-		c.JSON(http.StatusOK, gin.H{
-			"token":  token,
-			"expire": expire.Format(time.RFC3339),
-		})
-	}
-}
-
 func payloadFunc() func(data interface{}) jwt.MapClaims {
 	return func(data interface{}) jwt.MapClaims {
 		claims := jwt.MapClaims{
 			"iss": APIServerIssuer,
-			"aud": APIServerAudience,
+			// tokens signed with an asymmetric key are also accepted by iam-authz-server.
+			"aud": []string{APIServerAudience, auth.AuthzAudience},
 		}
 		if u, ok := data.(*v1.User); ok {
 			claims[jwt.IdentityKey] = u.Name
 			claims["sub"] = u.Name
+			claims[tenant.Key] = tenantOf(u.Name)
+			// jti and iat identify the token, so they can be used to revoke it.
+			claims[revocation.JTIKey] = idutil.GetUUID36("")
+			claims[revocation.IssuedAtKey] = time.Now().Unix()
 		}
 
 		return claims
 	}
 }
+
+// tenantOf returns the tenant of the user, users without membership belong to the default tenant.
+func tenantOf(username string) string {
+	member, err := store.Client().Tenants().GetMember(context.TODO(), username, metav1.GetOptions{})
+	if err != nil {
+		return tenant.Default
+	}
+
+	return member.Tenant
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apiserver
+
+import (
+	"context"
+	"time"
+
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/shutdown"
+)
+
+// importJobSweepInterval defines how often the interrupted catalog import jobs
+// are looked for.
+const importJobSweepInterval = time.Minute
+
+// initImportJobs marks failed the catalog import jobs interrupted by a restart
+// of any instance, at startup and then periodically.
+func (s *apiServer) initImportJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
+		cancel()
+
+		return nil
+	}))
+
+	go func() {
+		ticker := time.NewTicker(importJobSweepInterval)
+		defer ticker.Stop()
+
+		catalog := srvv1.NewService(store.Client()).Catalog()
+		for {
+			if err := catalog.FailStaleImportJobs(ctx); err != nil {
+				log.Errorf("fail interrupted catalog import jobs failed: %s", err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package accesstoken implements the personal access token handlers.
+package accesstoken
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/errors"
+
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+)
+
+// AccessTokenController create a personal access token handler used to handle
+// request for access token resource.
+type AccessTokenController struct {
+	srv srvv1.Service
+
+	maxPerUser int64
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+}
+
+// NewAccessTokenController creates a personal access token handler. Users can
+// create at most maxPerUser tokens, which expire after defaultTTL unless the
+// create request chooses another lifetime up to maxTTL.
+func NewAccessTokenController(
+	store store.Factory,
+	maxPerUser int64,
+	defaultTTL, maxTTL time.Duration,
+) *AccessTokenController {
+	return &AccessTokenController{
+		srv:        srvv1.NewService(store),
+		maxPerUser: maxPerUser,
+		defaultTTL: defaultTTL,
+		maxTTL:     maxTTL,
+	}
+}
+
+// checkNotAccessToken refuses to manage the tokens with a token, which would
+// let a scoped token create a token with more scopes.
+func checkNotAccessToken(c *gin.Context) error {
+	if name, ok := c.Get(auth.AccessTokenKey); ok {
+		return errors.WithCode(code.ErrPermissionDenied, "access tokens can not be managed with access token %s", name)
+	}
+
+	return nil
+}
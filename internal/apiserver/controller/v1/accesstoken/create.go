@@ -0,0 +1,119 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package accesstoken
+
+import (
+	"time"
+
+	"github.com/AlekSi/pointer"
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// CreateAccessTokenRequest defines the request of a new personal access token.
+type CreateAccessTokenRequest struct {
+	Name   string        `json:"name"`
+	Scopes []scope.Scope `json:"scopes,omitempty"`
+	// ExpiresIn is the lifetime of the token, e.g. `720h`. The configured
+	// lifetime is used when empty, `0s` creates a token which never expires.
+	ExpiresIn string `json:"expiresIn,omitempty"`
+}
+
+// Create creates a personal access token for the current user. The token is
+// only returned in this response.
+func (a *AccessTokenController) Create(c *gin.Context) {
+	log.L(c).Info("create access token function called.")
+
+	if err := checkNotAccessToken(c); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	var r CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ttl, err := a.lifetime(r.ExpiresIn)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	token := &model.AccessToken{
+		Name:     r.Name,
+		Username: c.GetString(middleware.UsernameKey),
+		Scopes:   r.Scopes,
+	}
+	if ttl > 0 {
+		token.ExpiresAt = pointer.ToTime(time.Now().Add(ttl))
+	}
+
+	if errs := token.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+
+		return
+	}
+
+	if err := scope.Validate(token.Scopes); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, err.Error()), nil)
+
+		return
+	}
+
+	tokens, err := a.srv.AccessTokens().List(c, token.Username, metav1.ListOptions{
+		Offset: pointer.ToInt64(0),
+		Limit:  pointer.ToInt64(-1),
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if tokens.TotalCount >= a.maxPerUser {
+		core.WriteResponse(c, errors.WithCode(code.ErrReachMaxCount, "access token count: %d", tokens.TotalCount), nil)
+
+		return
+	}
+
+	if err := a.srv.AccessTokens().Create(c, token); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, token)
+}
+
+// lifetime returns the lifetime of a new token, 0 when it never expires.
+func (a *AccessTokenController) lifetime(expiresIn string) (time.Duration, error) {
+	if expiresIn == "" {
+		return a.defaultTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(expiresIn)
+	if err != nil {
+		return 0, errors.WithCode(code.ErrValidation, "invalid expiresIn: %s", err.Error())
+	}
+
+	if ttl < 0 || a.maxTTL > 0 && (ttl == 0 || ttl > a.maxTTL) {
+		return 0, errors.WithCode(code.ErrValidation, "expiresIn must be between 0s and %s, excluded", a.maxTTL)
+	}
+
+	return ttl, nil
+}
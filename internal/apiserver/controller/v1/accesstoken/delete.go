@@ -0,0 +1,33 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package accesstoken
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Delete revokes a personal access token of the current user, it is no longer
+// accepted from now on.
+func (a *AccessTokenController) Delete(c *gin.Context) {
+	log.L(c).Info("delete access token function called.")
+
+	if err := checkNotAccessToken(c); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if err := a.srv.AccessTokens().Delete(c, c.GetString(middleware.UsernameKey), c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
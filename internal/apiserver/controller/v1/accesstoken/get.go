@@ -0,0 +1,27 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package accesstoken
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Get returns a personal access token of the current user by its name.
+func (a *AccessTokenController) Get(c *gin.Context) {
+	log.L(c).Info("get access token function called.")
+
+	token, err := a.srv.AccessTokens().Get(c, c.GetString(middleware.UsernameKey), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, token)
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package auditlog implements the audit log handlers.
+package auditlog
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// AuditLogController create an audit log handler used to handle request for audit log resource.
+type AuditLogController struct {
+	srv srvv1.Service
+}
+
+// NewAuditLogController creates an audit log handler.
+func NewAuditLogController(store store.Factory) *AuditLogController {
+	return &AuditLogController{
+		srv: srvv1.NewService(store),
+	}
+}
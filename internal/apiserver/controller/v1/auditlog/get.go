@@ -0,0 +1,38 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auditlog
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Get returns an audit log of the tenant by its id.
+// Only administrator can call this function.
+func (a *AuditLogController) Get(c *gin.Context) {
+	log.L(c).Info("get audit log function called.")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrAuditLogNotFound, "invalid audit log id %s", c.Param("id")), nil)
+
+		return
+	}
+
+	auditLog, err := a.srv.AuditLogs().Get(c, id)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, auditLog)
+}
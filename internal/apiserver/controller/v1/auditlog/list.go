@@ -0,0 +1,37 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auditlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// List lists the audit logs of the tenant matching the query, the latest first.
+// Only administrator can call this function.
+func (a *AuditLogController) List(c *gin.Context) {
+	log.L(c).Info("list audit log function called.")
+
+	var r model.AuditLogListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	logs, err := a.srv.AuditLogs().List(c, r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, logs)
+}
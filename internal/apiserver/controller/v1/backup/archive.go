@@ -0,0 +1,39 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Backup streams an archive of the tenant of the request.
+func (b *BackupController) Backup(c *gin.Context) {
+	log.L(c).Info("backup function called.")
+
+	name := c.GetString(tenant.Key)
+	if name == "" {
+		name = tenant.Default
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("iam-backup-%s-%s.jsonl", name, time.Now().UTC().Format("20060102T150405Z")),
+	}))
+	c.Status(http.StatusOK)
+
+	// the status is sent with the header of the archive, a later failure can
+	// only cut the archive, which the restore detects by its missing trailer.
+	if err := b.srv.Backups().Backup(c, c.GetHeader(PassphraseHeader), c.Writer); err != nil {
+		log.L(c).Errorf("backup failed: %s", err.Error())
+	}
+}
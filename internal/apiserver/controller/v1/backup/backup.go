@@ -0,0 +1,27 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package backup implements the handlers which back up and restore a tenant.
+package backup
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// PassphraseHeader is the header which holds the passphrase the keys of the
+// secrets are encrypted with.
+const PassphraseHeader = "X-Backup-Passphrase"
+
+// BackupController create a backup handler used to back up and restore a tenant.
+type BackupController struct {
+	srv srvv1.Service
+}
+
+// NewBackupController creates a backup handler.
+func NewBackupController(store store.Factory) *BackupController {
+	return &BackupController{
+		srv: srvv1.NewService(store),
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Restore restores the archive sent as the request body into the tenant of the
+// request. The conflict query parameter tells what to do with the resources
+// which already exist: skip, overwrite or fail, the default.
+func (b *BackupController) Restore(c *gin.Context) {
+	log.L(c).Info("restore function called.")
+
+	conflict := c.DefaultQuery("conflict", model.ConflictFail)
+
+	result, err := b.srv.Backups().Restore(c, conflict, c.GetHeader(PassphraseHeader), c.Request.Body)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, result)
+}
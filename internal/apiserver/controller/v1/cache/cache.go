@@ -2,17 +2,23 @@
 // Use of this source code is governed by a MIT style
 // license that can be found in the LICENSE file.
 
-// Package cache defines a cache service which can return all secrets and policies.
+// Package cache defines a cache service which can return all secrets, policies
+// and the group and role memberships of users.
 package cache
 
 import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 
 	pb "github.com/marmotedu/api/proto/apiserver/v1"
@@ -76,6 +82,59 @@ func (c *Cache) ListSecrets(ctx context.Context, r *pb.ListSecretsRequest) (*pb.
 	}, nil
 }
 
+// ListSecretKeys returns all secrets with their scopes and the keys
+// replaced by a rotation which are still accepted.
+func (c *Cache) ListSecretKeys(ctx context.Context, r *rpc.ListSecretKeysRequest) (*rpc.ListSecretKeysResponse, error) {
+	log.L(ctx).Info("list secret keys function called.")
+	opts := metav1.ListOptions{
+		Offset: pointer.ToInt64(0),
+		Limit:  pointer.ToInt64(-1),
+	}
+
+	secrets, err := c.store.Secrets().List(ctx, "", opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	now := time.Now()
+	rsp := &rpc.ListSecretKeysResponse{Items: make([]*rpc.SecretInfo, 0, len(secrets.Items))}
+	for _, secret := range secrets.Items {
+		info := &rpc.SecretInfo{
+			SecretID:  secret.SecretID,
+			Username:  secret.Username,
+			SecretKey: secret.SecretKey,
+			Expires:   secret.Expires,
+			Scopes:    secret.Scopes,
+		}
+		if secret.PreviousKeyValid(now) {
+			info.PreviousKey, info.PreviousKeyExpires = secret.PreviousKey, secret.PreviousKeyExpires
+		}
+
+		rsp.Items = append(rsp.Items, info)
+	}
+
+	return rsp, nil
+}
+
+// ReportSecretUsage records when the secrets were last used by iam-authz-server.
+func (c *Cache) ReportSecretUsage(
+	ctx context.Context,
+	r *rpc.ReportSecretUsageRequest,
+) (*rpc.ReportSecretUsageResponse, error) {
+	log.L(ctx).Info("report secret usage function called.")
+
+	lastUsed := make(map[string]time.Time, len(r.LastUsed))
+	for secretID, unix := range r.LastUsed {
+		lastUsed[secretID] = time.Unix(unix, 0)
+	}
+
+	if err := c.store.Secrets().RecordUsage(ctx, lastUsed); err != nil {
+		return nil, err
+	}
+
+	return &rpc.ReportSecretUsageResponse{}, nil
+}
+
 // ListPolicies returns all policies.
 func (c *Cache) ListPolicies(ctx context.Context, r *pb.ListPoliciesRequest) (*pb.ListPoliciesResponse, error) {
 	log.L(ctx).Info("list policies function called.")
@@ -104,3 +163,90 @@ func (c *Cache) ListPolicies(ctx context.Context, r *pb.ListPoliciesRequest) (*p
 		Items:      items,
 	}, nil
 }
+
+// ListMemberships returns the groups, roles and tenants of all users, and the
+// policies bound to every group and role.
+func (c *Cache) ListMemberships(
+	ctx context.Context,
+	r *rpc.ListMembershipsRequest,
+) (*rpc.ListMembershipsResponse, error) {
+	log.L(ctx).Info("list memberships function called.")
+	opts := metav1.ListOptions{
+		Offset: pointer.ToInt64(0),
+		Limit:  pointer.ToInt64(-1),
+	}
+
+	members, err := c.store.Groups().ListMembers(ctx, "", opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	bindings, err := c.store.Roles().ListBindings(ctx, "", opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	policyBindings, err := c.store.PolicyBindings().List(ctx, "", opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	tenantMembers, err := c.store.Tenants().ListMembers(ctx, "", opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	principals := make(map[string]map[string]struct{})
+	add := func(username, principal string) {
+		if principals[username] == nil {
+			principals[username] = make(map[string]struct{})
+		}
+		principals[username][principal] = struct{}{}
+	}
+
+	// the names of the groups and roles are only unique in their tenant, so
+	// the principals are qualified by the tenant.
+	groupMembers := make(map[string][]string)
+	for _, m := range members.Items {
+		group := tenant.Qualify(m.Tenant, m.Group)
+		groupMembers[group] = append(groupMembers[group], m.Username)
+		add(m.Username, tenant.Qualify(m.Tenant, v1.Principal(v1.PrincipalKindGroup, m.Group)))
+	}
+
+	for _, b := range bindings.Items {
+		principal := tenant.Qualify(b.Tenant, v1.Principal(v1.PrincipalKindRole, b.Role))
+		switch b.Kind {
+		case v1.SubjectKindUser:
+			add(b.Subject, principal)
+		case v1.SubjectKindGroup:
+			for _, username := range groupMembers[tenant.Qualify(b.Tenant, b.Subject)] {
+				add(username, principal)
+			}
+		}
+	}
+
+	rsp := &rpc.ListMembershipsResponse{
+		Principals: make(map[string][]string, len(principals)),
+		Policies:   make(map[string][]rpc.PolicyRef),
+		Tenants:    make(map[string]string, len(tenantMembers.Items)),
+	}
+	for username, set := range principals {
+		for principal := range set {
+			rsp.Principals[username] = append(rsp.Principals[username], principal)
+		}
+	}
+
+	for _, b := range policyBindings.Items {
+		principal := tenant.Qualify(b.Tenant, b.Principal)
+		rsp.Policies[principal] = append(rsp.Policies[principal], rpc.PolicyRef{
+			Username: b.PolicyOwner,
+			Name:     b.PolicyName,
+		})
+	}
+
+	for _, m := range tenantMembers.Items {
+		rsp.Tenants[m.Username] = m.Tenant
+	}
+
+	return rsp, nil
+}
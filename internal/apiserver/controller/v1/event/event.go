@@ -0,0 +1,36 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package event implements the handler which streams the resource changes.
+package event
+
+import (
+	"time"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
+)
+
+const (
+	// bufferSize is the number of events buffered for a watcher, a watcher
+	// which falls further behind is disconnected.
+	bufferSize = 100
+
+	// heartbeatInterval is how often a comment is sent on idle streams, so
+	// that the proxies do not close them.
+	heartbeatInterval = 30 * time.Second
+)
+
+// EventController create an event handler used to stream the resource changes.
+type EventController struct {
+	broker    event.Broker
+	heartbeat time.Duration
+}
+
+// NewEventController creates an event handler.
+func NewEventController(broker event.Broker) *EventController {
+	return &EventController{
+		broker:    broker,
+		heartbeat: heartbeatInterval,
+	}
+}
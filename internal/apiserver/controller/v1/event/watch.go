@@ -0,0 +1,96 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Watch streams the changes of the resources as server-sent events, from the
+// time of the request on. The kind query selects the kinds of the resources,
+// a comma separated list, and the name query a single resource. The users see
+// the changes of their own user, policies and secrets, the administrators the
+// ones of the whole tenant. Everybody sees the changes of the items.
+func (e *EventController) Watch(c *gin.Context) {
+	log.L(c).Info("watch events function called.")
+
+	filter := &event.Filter{
+		Tenant:   c.GetString(tenant.Key),
+		Username: c.GetString(middleware.UsernameKey),
+		Admin:    middleware.IsAdmin(c),
+		Name:     c.Query("name"),
+	}
+
+	if kinds := c.Query("kind"); kinds != "" {
+		filter.Kinds = strings.Split(kinds, ",")
+	}
+
+	for _, kind := range filter.Kinds {
+		if !contains(event.Kinds, kind) {
+			core.WriteResponse(c, errors.WithCode(code.ErrValidation,
+				"unknown kind %q, the kinds are: %s", kind, strings.Join(event.Kinds, ",")), nil)
+
+			return
+		}
+	}
+
+	subscription := e.broker.Subscribe(bufferSize)
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// nginx buffers the responses by default.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(200)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(e.heartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case ev, ok := <-subscription.C:
+			if !ok {
+				return false
+			}
+
+			if filter.Match(ev) {
+				c.SSEvent(string(ev.Type), ev)
+			}
+
+			return true
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": heartbeat\n\n")
+
+			return err == nil
+		}
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
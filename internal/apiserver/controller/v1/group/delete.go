@@ -0,0 +1,27 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Delete deletes a group by the group name, the members and the policy bindings
+// of the group are deleted too.
+// Only administrator can call this function.
+func (g *GroupController) Delete(c *gin.Context) {
+	log.L(c).Info("delete group function called.")
+
+	if err := g.srv.Groups().Delete(c, c.Param("name"), metav1.DeleteOptions{}); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
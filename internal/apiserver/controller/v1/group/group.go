@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package group implements the group handlers.
+package group
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// GroupController create a group handler used to handle request for group resource.
+type GroupController struct {
+	srv srvv1.Service
+}
+
+// NewGroupController creates a group handler.
+func NewGroupController(store store.Factory) *GroupController {
+	return &GroupController{
+		srv: srvv1.NewService(store),
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// AddMember adds a user to the group.
+// Only administrator can call this function.
+func (g *GroupController) AddMember(c *gin.Context) {
+	log.L(c).Info("add group member function called.")
+
+	if err := g.srv.Groups().AddMember(c, c.Param("name"), c.Param("username")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// RemoveMember removes a user from the group.
+// Only administrator can call this function.
+func (g *GroupController) RemoveMember(c *gin.Context) {
+	log.L(c).Info("remove group member function called.")
+
+	if err := g.srv.Groups().RemoveMember(c, c.Param("name"), c.Param("username")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ListMembers lists the members of the group.
+func (g *GroupController) ListMembers(c *gin.Context) {
+	log.L(c).Info("list group member function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	members, err := g.srv.Groups().ListMembers(c, c.Param("name"), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, members)
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// contentTypes maps the catalog formats to the media types of the files.
+var contentTypes = map[string]string{
+	v1.CatalogFormatCSV:   "text/csv",
+	v1.CatalogFormatJSONL: "application/x-ndjson",
+}
+
+// CatalogController creates a catalog handler used to import and export items in bulk.
+type CatalogController struct {
+	srv           srvv1.Service
+	maxImportSize int64
+}
+
+// NewCatalogController creates a catalog handler, maxImportSize is the largest
+// catalog file accepted by the import, in bytes.
+func NewCatalogController(store store.Factory, maxImportSize int64) *CatalogController {
+	return &CatalogController{
+		srv:           srvv1.NewService(store),
+		maxImportSize: maxImportSize,
+	}
+}
+
+// Import starts an import job of the catalog file sent as the request body.
+// The format is given by the format query parameter, or else by the content type.
+func (cc *CatalogController) Import(c *gin.Context) {
+	log.L(c).Info("import catalog function called.")
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, cc.maxImportSize)
+
+	job, err := cc.srv.Catalog().Import(c, catalogFormat(c), body)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Get returns the progress of an import job.
+func (cc *CatalogController) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	job, err := cc.srv.Catalog().GetImportJob(c, id)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, job)
+}
+
+// ListErrors returns the rows rejected by an import job.
+func (cc *CatalogController) ListErrors(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	rowErrors, err := cc.srv.Catalog().ListImportErrors(c, id, r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, rowErrors)
+}
+
+// Export streams the listed items as a catalog file, the items are selected
+// with the same query parameters as the item list.
+func (cc *CatalogController) Export(c *gin.Context) {
+	log.L(c).Info("export catalog function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	format := c.DefaultQuery("format", v1.CatalogFormatCSV)
+
+	contentType, ok := contentTypes[format]
+	if !ok {
+		core.WriteResponse(c, errors.WithCode(code.ErrCatalogFormat, "unsupported catalog format %q", format), nil)
+
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": "catalog." + format,
+	}))
+	c.Status(http.StatusOK)
+
+	// the status is sent with the first page, a later failure can only cut the file.
+	if err := cc.srv.Catalog().Export(c, format, r, c.Writer); err != nil {
+		log.L(c).Errorf("export catalog failed: %s", err.Error())
+	}
+}
+
+// catalogFormat returns the format of the catalog file sent with the request.
+func catalogFormat(c *gin.Context) string {
+	if format := c.Query("format"); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	for format, contentType := range contentTypes {
+		if mediaType == contentType {
+			return format
+		}
+	}
+
+	switch mediaType {
+	case "application/jsonl", "application/x-jsonlines":
+		return v1.CatalogFormatJSONL
+	default:
+		return mediaType
+	}
+}
@@ -63,8 +63,11 @@ func (iac *ItemAttributesController) Update(c *gin.Context) {
 		return
 	}
 
-	// Update itemAttribute fields here
-	// itemAttribute.FieldName = newItemAttribute.FieldName
+	// The identity of the attributes can not be changed.
+	newItemAttribute.ID = itemAttribute.ID
+	newItemAttribute.ItemID = itemAttribute.ItemID
+	newItemAttribute.CreatedAt = itemAttribute.CreatedAt
+	itemAttribute = &newItemAttribute
 
 	if err := iac.srv.ItemAttributes().Update(c, itemAttribute, metav1.UpdateOptions{}); err != nil {
 		core.WriteResponse(c, errors.WithCode(code.ErrDatabase, err.Error()), nil)
@@ -207,7 +207,7 @@ func (ctrl *itemImageController) Get(c *gin.Context) {
 }
 
 func (ctrl *itemImageController) List(c *gin.Context) {
-	itemIDStr := c.Param("item_id")
+	itemIDStr := c.Param("itemID")
 	itemID, err := strconv.ParseUint(itemIDStr, 10, 64)
 	if err != nil {
 		core.WriteResponse(c, errors.WithCode(code.ErrUnknown, err.Error()), nil)
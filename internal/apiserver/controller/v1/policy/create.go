@@ -12,6 +12,7 @@ import (
 	"github.com/marmotedu/errors"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
@@ -33,6 +34,12 @@ func (p *PolicyController) Create(c *gin.Context) {
 		return
 	}
 
+	if err := policylint.Validate(&r.Policy.DefaultPolicy); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, err.Error()), nil)
+
+		return
+	}
+
 	r.Username = c.GetString(middleware.UsernameKey)
 
 	if err := p.srv.Policies().Create(c, &r, metav1.CreateOptions{}); err != nil {
@@ -0,0 +1,37 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/simulator"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Test evaluates a suite of access requests against a candidate policy set
+// and reports the cases whose effect differs from the expected one.
+// Neither the stored policies nor the iam-authz-server cache are touched.
+func (p *PolicyController) Test(c *gin.Context) {
+	log.L(c).Info("test policy function called.")
+
+	var r simulator.Suite
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if err := r.Validate(); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, err.Error()), nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, r.Run())
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/marmotedu/errors"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
@@ -43,6 +44,12 @@ func (p *PolicyController) Update(c *gin.Context) {
 		return
 	}
 
+	if err := policylint.Validate(&pol.Policy.DefaultPolicy); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, err.Error()), nil)
+
+		return
+	}
+
 	if err := p.srv.Policies().Update(c, pol, metav1.UpdateOptions{}); err != nil {
 		core.WriteResponse(c, err, nil)
 
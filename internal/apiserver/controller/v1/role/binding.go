@@ -0,0 +1,88 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// BindUser binds the role to a user.
+// Only administrator can call this function.
+func (rc *RoleController) BindUser(c *gin.Context) {
+	log.L(c).Info("bind role to user function called.")
+
+	rc.bind(c, v1.SubjectKindUser, c.Param("username"))
+}
+
+// UnbindUser unbinds the role from a user.
+// Only administrator can call this function.
+func (rc *RoleController) UnbindUser(c *gin.Context) {
+	log.L(c).Info("unbind role from user function called.")
+
+	rc.unbind(c, v1.SubjectKindUser, c.Param("username"))
+}
+
+// BindGroup binds the role to a group, the role applies to all the members of the group.
+// Only administrator can call this function.
+func (rc *RoleController) BindGroup(c *gin.Context) {
+	log.L(c).Info("bind role to group function called.")
+
+	rc.bind(c, v1.SubjectKindGroup, c.Param("group"))
+}
+
+// UnbindGroup unbinds the role from a group.
+// Only administrator can call this function.
+func (rc *RoleController) UnbindGroup(c *gin.Context) {
+	log.L(c).Info("unbind role from group function called.")
+
+	rc.unbind(c, v1.SubjectKindGroup, c.Param("group"))
+}
+
+// ListBindings lists the users and groups the role is bound to.
+func (rc *RoleController) ListBindings(c *gin.Context) {
+	log.L(c).Info("list role binding function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	bindings, err := rc.srv.Roles().ListBindings(c, c.Param("name"), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, bindings)
+}
+
+func (rc *RoleController) bind(c *gin.Context, kind, subject string) {
+	if err := rc.srv.Roles().Bind(c, c.Param("name"), kind, subject); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+func (rc *RoleController) unbind(c *gin.Context, kind, subject string) {
+	if err := rc.srv.Roles().Unbind(c, c.Param("name"), kind, subject); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// BindPolicy binds a policy of the current user to the role, the policy
+// applies to all the users and groups the role is bound to.
+// Only administrator can call this function.
+func (rc *RoleController) BindPolicy(c *gin.Context) {
+	log.L(c).Info("bind role policy function called.")
+
+	if err := rc.srv.Roles().BindPolicy(c, c.Param("name"), c.GetString(middleware.UsernameKey),
+		c.Param("policy")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// UnbindPolicy unbinds a policy of the current user from the role.
+// Only administrator can call this function.
+func (rc *RoleController) UnbindPolicy(c *gin.Context) {
+	log.L(c).Info("unbind role policy function called.")
+
+	if err := rc.srv.Roles().UnbindPolicy(c, c.Param("name"), c.GetString(middleware.UsernameKey),
+		c.Param("policy")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ListPolicies lists the policies bound to the role.
+func (rc *RoleController) ListPolicies(c *gin.Context) {
+	log.L(c).Info("list role policy function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	bindings, err := rc.srv.Roles().ListPolicies(c, c.Param("name"), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, bindings)
+}
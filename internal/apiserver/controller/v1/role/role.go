@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package role implements the role handlers.
+package role
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// RoleController create a role handler used to handle request for role resource.
+type RoleController struct {
+	srv srvv1.Service
+}
+
+// NewRoleController creates a role handler.
+func NewRoleController(store store.Factory) *RoleController {
+	return &RoleController{
+		srv: srvv1.NewService(store),
+	}
+}
@@ -7,23 +7,22 @@ package secret
 import (
 	"github.com/AlekSi/pointer"
 	"github.com/gin-gonic/gin"
-	v1 "github.com/marmotedu/api/apiserver/v1"
 	"github.com/marmotedu/component-base/pkg/core"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/component-base/pkg/util/idutil"
 	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
-const maxSecretCount = 10
-
 // Create add new secret key pairs to the storage.
 func (s *SecretController) Create(c *gin.Context) {
 	log.L(c).Info("create secret function called.")
 
-	var r v1.Secret
+	var r model.Secret
 
 	if err := c.ShouldBindJSON(&r); err != nil {
 		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
@@ -37,6 +36,12 @@ func (s *SecretController) Create(c *gin.Context) {
 		return
 	}
 
+	if err := scope.Validate(r.Scopes); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, err.Error()), nil)
+
+		return
+	}
+
 	username := c.GetString(middleware.UsernameKey)
 
 	secrets, err := s.srv.Secrets().List(c, username, metav1.ListOptions{
@@ -49,7 +54,7 @@ func (s *SecretController) Create(c *gin.Context) {
 		return
 	}
 
-	if secrets.TotalCount >= maxSecretCount {
+	if secrets.TotalCount >= s.maxPerUser {
 		core.WriteResponse(c, errors.WithCode(code.ErrReachMaxCount, "secret count: %d", secrets.TotalCount), nil)
 
 		return
@@ -61,6 +66,8 @@ func (s *SecretController) Create(c *gin.Context) {
 	// generate secret id and secret key
 	r.SecretID = idutil.NewSecretID()
 	r.SecretKey = idutil.NewSecretKey()
+	r.PreviousKeyExpires = 0
+	r.LastUsedAt = nil
 
 	if err := s.srv.Secrets().Create(c, &r, metav1.CreateOptions{}); err != nil {
 		core.WriteResponse(c, err, nil)
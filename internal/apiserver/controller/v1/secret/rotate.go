@@ -0,0 +1,63 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// RotateRequest defines the optional body of a secret rotation.
+type RotateRequest struct {
+	// GracePeriod is how long the current key is still accepted, e.g. `1h`.
+	// The configured grace period is used when empty, `0s` revokes it at once.
+	GracePeriod string `json:"gracePeriod"`
+}
+
+// Rotate issues a new key for the secret, the secret id is kept.
+func (s *SecretController) Rotate(c *gin.Context) {
+	log.L(c).Info("rotate secret function called.")
+
+	var r RotateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&r); err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+			return
+		}
+	}
+
+	grace := s.gracePeriod
+	if r.GracePeriod != "" {
+		var err error
+		if grace, err = time.ParseDuration(r.GracePeriod); err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrValidation, "invalid grace period: %s", err.Error()), nil)
+
+			return
+		}
+	}
+
+	if grace < 0 || grace > s.maxGracePeriod {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation,
+			"grace period must be between 0s and %s", s.maxGracePeriod), nil)
+
+		return
+	}
+
+	secret, err := s.srv.Secrets().Rotate(c, c.GetString(middleware.UsernameKey), c.Param("name"), grace)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, secret)
+}
@@ -5,6 +5,8 @@
 package secret
 
 import (
+	"time"
+
 	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
 )
@@ -12,11 +14,24 @@ import (
 // SecretController create a secret handler used to handle request for secret resource.
 type SecretController struct {
 	srv srvv1.Service
+
+	maxPerUser     int64
+	gracePeriod    time.Duration
+	maxGracePeriod time.Duration
 }
 
-// NewSecretController creates a secret handler.
-func NewSecretController(store store.Factory) *SecretController {
+// NewSecretController creates a secret handler. Users can create at most
+// maxPerUser secrets, and the keys they rotate stay valid for gracePeriod unless
+// the rotation chooses another grace period up to maxGracePeriod.
+func NewSecretController(
+	store store.Factory,
+	maxPerUser int64,
+	gracePeriod, maxGracePeriod time.Duration,
+) *SecretController {
 	return &SecretController{
-		srv: srvv1.NewService(store),
+		srv:            srvv1.NewService(store),
+		maxPerUser:     maxPerUser,
+		gracePeriod:    gracePeriod,
+		maxGracePeriod: maxGracePeriod,
 	}
 }
@@ -6,12 +6,13 @@ package secret
 
 import (
 	"github.com/gin-gonic/gin"
-	v1 "github.com/marmotedu/api/apiserver/v1"
 	"github.com/marmotedu/component-base/pkg/core"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
@@ -19,7 +20,7 @@ import (
 func (s *SecretController) Update(c *gin.Context) {
 	log.L(c).Info("update secret function called.")
 
-	var r v1.Secret
+	var r model.Secret
 	if err := c.ShouldBindJSON(&r); err != nil {
 		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
 
@@ -36,17 +37,28 @@ func (s *SecretController) Update(c *gin.Context) {
 		return
 	}
 
-	// only update expires and description
+	// only update expires, description and scopes
 	secret.Expires = r.Expires
 	secret.Description = r.Description
 	secret.Extend = r.Extend
 
+	// scopes are kept when omitted, an empty list lifts the restriction.
+	if r.Scopes != nil {
+		secret.Scopes = r.Scopes
+	}
+
 	if errs := secret.Validate(); len(errs) != 0 {
 		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
 
 		return
 	}
 
+	if err := scope.Validate(secret.Scopes); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, err.Error()), nil)
+
+		return
+	}
+
 	if err := s.srv.Secrets().Update(c, secret, metav1.UpdateOptions{}); err != nil {
 		core.WriteResponse(c, err, nil)
 
@@ -0,0 +1,27 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Delete revokes a login session of the current user by the session identifier,
+// its refresh token and access tokens are no longer accepted.
+func (s *SessionController) Delete(c *gin.Context) {
+	log.L(c).Info("delete session function called.")
+
+	if err := s.srv.Sessions().Revoke(c, c.GetString(middleware.UsernameKey), c.Param("id")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package session implements the login session handlers.
+package session
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// SessionController create a session handler used to handle request for session resource.
+type SessionController struct {
+	srv srvv1.Service
+}
+
+// NewSessionController creates a session handler.
+func NewSessionController(store store.Factory) *SessionController {
+	return &SessionController{
+		srv: srvv1.NewService(store),
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Delete deletes a tenant by the tenant name, the tenant must have no members.
+// Only platform administrator can call this function.
+func (tc *TenantController) Delete(c *gin.Context) {
+	log.L(c).Info("delete tenant function called.")
+
+	if err := tc.srv.Tenants().Delete(c, c.Param("name"), metav1.DeleteOptions{}); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// AddMemberOptions defines the options of adding a user to a tenant.
+type AddMemberOptions struct {
+	// Admin makes the user an administrator of the tenant.
+	Admin bool `form:"admin"`
+}
+
+// AddMember moves a user to the tenant, use `?admin=true` to make the user an
+// administrator of the tenant.
+// Only platform administrator can call this function.
+func (tc *TenantController) AddMember(c *gin.Context) {
+	log.L(c).Info("add tenant member function called.")
+
+	var r AddMemberOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if err := tc.srv.Tenants().AddMember(c, c.Param("name"), c.Param("username"), r.Admin); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// RemoveMember removes a user from the tenant, the user falls back to the default tenant.
+// Only platform administrator can call this function.
+func (tc *TenantController) RemoveMember(c *gin.Context) {
+	log.L(c).Info("remove tenant member function called.")
+
+	if err := tc.srv.Tenants().RemoveMember(c, c.Param("name"), c.Param("username")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ListMembers lists the members of the tenant.
+// Only platform administrator can call this function.
+func (tc *TenantController) ListMembers(c *gin.Context) {
+	log.L(c).Info("list tenant member function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	members, err := tc.srv.Tenants().ListMembers(c, c.Param("name"), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, members)
+}
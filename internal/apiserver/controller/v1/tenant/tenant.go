@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package tenant implements the tenant handlers.
+package tenant
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// TenantController create a tenant handler used to handle request for tenant resource.
+type TenantController struct {
+	srv srvv1.Service
+}
+
+// NewTenantController creates a tenant handler.
+func NewTenantController(store store.Factory) *TenantController {
+	return &TenantController{
+		srv: srvv1.NewService(store),
+	}
+}
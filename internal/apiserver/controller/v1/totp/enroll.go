@@ -0,0 +1,28 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package totp
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Enroll starts a new two-factor authentication enrollment of the current user.
+// The secret, its provisioning URI and the recovery codes are only returned once.
+func (t *TOTPController) Enroll(c *gin.Context) {
+	log.L(c).Info("enroll totp function called.")
+
+	enrollment, err := t.srv.TOTPs().Enroll(c, c.GetString(middleware.UsernameKey), t.issuer)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, enrollment)
+}
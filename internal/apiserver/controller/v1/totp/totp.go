@@ -0,0 +1,31 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package totp implements the two-factor authentication handlers, users manage
+// their own enrollment.
+package totp
+
+import (
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+)
+
+// TOTPController create a totp handler used to handle request for two-factor authentication.
+type TOTPController struct {
+	srv    srvv1.Service
+	issuer string
+}
+
+// codeRequest defines the body of the requests which must be confirmed by a code.
+type codeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// NewTOTPController creates a totp handler, issuer is shown by the authenticator apps.
+func NewTOTPController(store store.Factory, issuer string) *TOTPController {
+	return &TOTPController{
+		srv:    srvv1.NewService(store),
+		issuer: issuer,
+	}
+}
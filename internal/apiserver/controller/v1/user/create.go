@@ -9,6 +9,7 @@ import (
 	"github.com/marmotedu/component-base/pkg/core"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
@@ -31,7 +32,13 @@ func (u *UserController) Create(c *gin.Context) {
 	}
 
 	r.Password, _ = auth.Encrypt(r.Password)
-	r.Status = 1
+	r.Status = model.UserStatusActive
+	if u.requireEmailVerification {
+		r.Status = model.UserStatusPending
+	}
+	// platform administrators are provisioned in the database, the administrators
+	// of a tenant are appointed through the tenant members api.
+	r.IsAdmin = 0
 	r.LoginedAt = time.Now()
 
 	// Insert the user to the storage.
@@ -0,0 +1,84 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// LookupRequest defines the data format of the anonymous requests which
+// identify an user by username or email.
+type LookupRequest struct {
+	// Username of the user, takes precedence over email.
+	Username string `json:"username" binding:"omitempty"`
+
+	// Email of the user.
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+// ResetPasswordRequest defines the ResetPasswordRequest data format.
+type ResetPasswordRequest struct {
+	// Token sent to the user.
+	// Required: true
+	Token string `json:"token" binding:"required"`
+
+	// New password.
+	// Required: true
+	NewPassword string `json:"newPassword" binding:"required,password"`
+}
+
+// RequestPasswordReset sends a password reset token to the user. The response
+// is the same whether the user exists or not.
+func (u *UserController) RequestPasswordReset(c *gin.Context) {
+	log.L(c).Info("request password reset function called.")
+
+	var r LookupRequest
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if r.Username == "" && r.Email == "" {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, "username or email is required"), nil)
+
+		return
+	}
+
+	if err := u.srv.PasswordResets().Request(c, r.Username, r.Email); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ResetPassword sets a new password with a password reset token.
+func (u *UserController) ResetPassword(c *gin.Context) {
+	log.L(c).Info("reset password function called.")
+
+	var r ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if err := u.srv.PasswordResets().Confirm(c, r.Token, r.NewPassword); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// SuspendRequest defines the SuspendRequest data format.
+type SuspendRequest struct {
+	// Reason of the suspension.
+	// Required: true
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// Suspend suspends an user, the user can no longer log in.
+// Only administrator can call this function.
+func (u *UserController) Suspend(c *gin.Context) {
+	log.L(c).Info("suspend user function called.")
+
+	var r SuspendRequest
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if err := u.srv.Users().Suspend(c, c.Param("name"), r.Reason); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// Reactivate activates a suspended, disabled or pending user again.
+// Only administrator can call this function.
+func (u *UserController) Reactivate(c *gin.Context) {
+	log.L(c).Info("reactivate user function called.")
+
+	if err := u.srv.Users().Reactivate(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ListStatusChanges lists the status changes of an user, the latest first.
+// Only administrator can call this function.
+func (u *UserController) ListStatusChanges(c *gin.Context) {
+	log.L(c).Info("list user status changes function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	changes, err := u.srv.Users().ListStatusChanges(c, c.Param("name"), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, changes)
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Unlock unlocks an user locked out by too many failed logins, together with
+// the client IPs given by the ip query parameters.
+// Only administrator can call this function.
+func (u *UserController) Unlock(c *gin.Context) {
+	log.L(c).Info("unlock user function called.")
+
+	if err := u.srv.Users().Unlock(c, c.Param("name"), c.QueryArray("ip")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -11,11 +11,14 @@ import (
 // UserController create a user handler used to handle request for user resource.
 type UserController struct {
 	srv srvv1.Service
+	// requireEmailVerification keeps the new users pending until they verify their email.
+	requireEmailVerification bool
 }
 
 // NewUserController creates a user handler.
-func NewUserController(store store.Factory) *UserController {
+func NewUserController(store store.Factory, requireEmailVerification bool) *UserController {
 	return &UserController{
-		srv: srvv1.NewService(store),
+		srv:                      srvv1.NewService(store),
+		requireEmailVerification: requireEmailVerification,
 	}
 }
@@ -0,0 +1,71 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// VerifyEmailRequest defines the VerifyEmailRequest data format.
+type VerifyEmailRequest struct {
+	// Token sent to the email of the user.
+	// Required: true
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmail verifies the email of an user with a verification token, and
+// activates the user pending verification.
+func (u *UserController) VerifyEmail(c *gin.Context) {
+	log.L(c).Info("verify email function called.")
+
+	var r VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if err := u.srv.EmailVerifications().Verify(c, r.Token); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ResendVerification sends a new verification token to an user pending
+// verification. The response is the same whether the user exists or not.
+func (u *UserController) ResendVerification(c *gin.Context) {
+	log.L(c).Info("resend email verification function called.")
+
+	var r LookupRequest
+
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if r.Username == "" && r.Email == "" {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, "username or email is required"), nil)
+
+		return
+	}
+
+	if err := u.srv.EmailVerifications().Resend(c, r.Username, r.Email); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
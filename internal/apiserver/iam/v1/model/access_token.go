@@ -0,0 +1,83 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/validation"
+	"github.com/marmotedu/component-base/pkg/validation/field"
+	"gorm.io/gorm"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+)
+
+// AccessToken represents a personal access token, used by the automations of
+// a user in place of the password. Only the sha256 hash of the token is stored.
+type AccessToken struct {
+	ID       uint64 `json:"-"        gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Name     string `json:"name"     gorm:"column:name"     validate:"required,name"`
+	Username string `json:"username" gorm:"column:username"`
+	Hash     string `json:"-"        gorm:"column:hash"`
+
+	// Token is only returned once, when the token is created.
+	Token string `json:"token,omitempty" gorm:"-"`
+
+	// Scopes restrict the requests the token can authenticate, resources are
+	// matched with the request path and actions with the request method.
+	Scopes       []scope.Scope `json:"scopes,omitempty" gorm:"-"`
+	ScopesShadow string        `json:"-"                gorm:"column:scopesShadow"`
+
+	CreatedAt  time.Time  `json:"createdAt"            gorm:"column:createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"  gorm:"column:expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" gorm:"column:lastUsedAt"`
+}
+
+// AccessTokenList is the whole list of the access tokens of a user.
+type AccessTokenList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*AccessToken `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (t *AccessToken) TableName() string {
+	return "access_token"
+}
+
+// BeforeCreate run before create database record.
+func (t *AccessToken) BeforeCreate(tx *gorm.DB) error {
+	t.ScopesShadow = ""
+	if len(t.Scopes) != 0 {
+		data, _ := json.Marshal(t.Scopes)
+		t.ScopesShadow = string(data)
+	}
+
+	return nil
+}
+
+// AfterFind run after find to unmarshal the scopes shadow string.
+func (t *AccessToken) AfterFind(tx *gorm.DB) error {
+	if t.ScopesShadow == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(t.ScopesShadow), &t.Scopes)
+}
+
+// Validate validates that an access token object is valid.
+func (t *AccessToken) Validate() field.ErrorList {
+	val := validation.NewValidator(t)
+
+	return val.Validate()
+}
+
+// Expired tells whether the token has expired at the given time.
+func (t *AccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
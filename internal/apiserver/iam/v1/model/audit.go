@@ -0,0 +1,100 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"gorm.io/gorm"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
+)
+
+// AuditLog records a mutating request, or one of the resources it changed.
+type AuditLog struct {
+	ID     uint64 `json:"id"     gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Tenant string `json:"tenant" gorm:"column:tenant"`
+	// Actor is the user who made the request, empty for the anonymous requests,
+	// e.g. the registrations.
+	Actor string `json:"actor" gorm:"column:actor"`
+	// Action, Kind, Name and Owner tell which resource was changed and how, they
+	// are empty when the request changed none of the audited resources, e.g.
+	// when it failed.
+	Action string `json:"action,omitempty" gorm:"column:action"`
+	Kind   string `json:"kind,omitempty"   gorm:"column:kind"`
+	Name   string `json:"name,omitempty"   gorm:"column:name"`
+	Owner  string `json:"owner,omitempty"  gorm:"column:owner"`
+	// Changes are the fields of the resource which changed, the credentials
+	// are redacted.
+	Changes       []audit.FieldChange `json:"changes,omitempty" gorm:"-"`
+	ChangesShadow string              `json:"-"                 gorm:"column:changesShadow"`
+
+	Method     string    `json:"method"     gorm:"column:method"`
+	Path       string    `json:"path"       gorm:"column:path"`
+	StatusCode int       `json:"statusCode" gorm:"column:statusCode"`
+	RequestID  string    `json:"requestID"  gorm:"column:requestID"`
+	ClientIP   string    `json:"clientIP"   gorm:"column:clientIP"`
+	UserAgent  string    `json:"userAgent"  gorm:"column:userAgent"`
+	CreatedAt  time.Time `json:"createdAt"  gorm:"column:createdAt"`
+}
+
+// AuditLogList is the list of the audit logs matching a query, the latest first.
+type AuditLogList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*AuditLog `json:"items"`
+}
+
+// AuditLogListOptions selects the audit logs to list, the empty filters match
+// all the logs.
+type AuditLogListOptions struct {
+	metav1.ListOptions `json:",inline"`
+
+	Actor     string `json:"actor,omitempty"     form:"actor"`
+	Action    string `json:"action,omitempty"    form:"action"`
+	Kind      string `json:"kind,omitempty"      form:"kind"`
+	Name      string `json:"name,omitempty"      form:"name"`
+	RequestID string `json:"requestID,omitempty" form:"requestID"`
+	// Since and Until bound the time of the requests, in RFC3339.
+	Since *time.Time `json:"since,omitempty" form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until *time.Time `json:"until,omitempty" form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// TableName maps to mysql table name.
+func (a *AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// BeforeCreate run before create database record.
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if len(a.Changes) == 0 {
+		a.ChangesShadow = ""
+
+		return nil
+	}
+
+	data, err := json.Marshal(a.Changes)
+	if err != nil {
+		return err
+	}
+
+	a.ChangesShadow = string(data)
+
+	return nil
+}
+
+// AfterFind run after find to unmarshal the changes shadow string.
+func (a *AuditLog) AfterFind(tx *gorm.DB) error {
+	if a.ChangesShadow == "" {
+		a.Changes = nil
+
+		return nil
+	}
+
+	return json.Unmarshal([]byte(a.ChangesShadow), &a.Changes)
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+)
+
+// BackupVersion is the version of the backup archives written by iam-apiserver.
+// Archives of a later version are rejected by the restore.
+const BackupVersion = 1
+
+// Kinds of the records of a backup archive. An archive starts with a header,
+// holds the users, secrets, policies and items in this order, and ends with a
+// trailer, so a cut archive is detected.
+const (
+	BackupKindHeader  = "Header"
+	BackupKindUser    = "User"
+	BackupKindSecret  = "Secret"
+	BackupKindPolicy  = "Policy"
+	BackupKindItem    = "Item"
+	BackupKindTrailer = "Trailer"
+)
+
+// Conflict policies of a restore, applied to the resources which already exist.
+const (
+	// ConflictSkip keeps the existing resources.
+	ConflictSkip = "skip"
+	// ConflictOverwrite replaces the existing resources with the restored ones.
+	ConflictOverwrite = "overwrite"
+	// ConflictFail aborts the restore, nothing is restored.
+	ConflictFail = "fail"
+)
+
+// BackupRecord is a line of a backup archive.
+type BackupRecord struct {
+	Kind    string          `json:"kind"`
+	Header  *BackupHeader   `json:"header,omitempty"`
+	Object  json.RawMessage `json:"object,omitempty"`
+	Trailer *BackupTrailer  `json:"trailer,omitempty"`
+}
+
+// BackupHeader describes a backup archive.
+type BackupHeader struct {
+	Version   int       `json:"version"`
+	Tenant    string    `json:"tenant"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Encryption is set when the keys of the secrets are encrypted.
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+}
+
+// BackupEncryption describes how the keys of the secrets are encrypted: with
+// AES-256-GCM, by a key derived from a passphrase with scrypt. Check is a known
+// text encrypted with the key, used to reject a wrong passphrase upfront.
+type BackupEncryption struct {
+	Algorithm string `json:"algorithm"`
+	KDF       string `json:"kdf"`
+	Salt      []byte `json:"salt"`
+	Check     []byte `json:"check"`
+}
+
+// BackupTrailer ends a backup archive with the number of records of each kind.
+type BackupTrailer struct {
+	Counts map[string]int `json:"counts"`
+}
+
+// BackupSecret is a secret in a backup archive. It holds the key replaced by
+// the last rotation, which is not part of the API object. Both keys are
+// base64 encoded ciphertexts when the archive is encrypted.
+type BackupSecret struct {
+	*Secret `json:",inline"`
+
+	PreviousSecretKey string `json:"previousSecretKey,omitempty"`
+}
+
+// BackupItem is an item in a backup archive, together with the rows of the
+// item tables which belong to it.
+type BackupItem struct {
+	*itemv1.Item `json:",inline"`
+
+	Attributes []*itemv1.ItemAttributes           `json:"attributes,omitempty"`
+	Images     []*itemv1.ItemImage                `json:"images,omitempty"`
+	Summaries  []*itemv1.ItemSummaryByMarketplace `json:"summaries,omitempty"`
+	Issues     []*itemv1.Issue                    `json:"issues,omitempty"`
+	Offers     []*itemv1.ItemOfferByMarketplace   `json:"offers,omitempty"`
+}
+
+// RestoreResult reports the number of restored resources of each kind.
+type RestoreResult struct {
+	Created     map[string]int `json:"created"`
+	Overwritten map[string]int `json:"overwritten"`
+	Skipped     map[string]int `json:"skipped"`
+}
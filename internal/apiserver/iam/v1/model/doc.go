@@ -0,0 +1,7 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package model defines the iam resources of iam-apiserver which are not part of
+// github.com/marmotedu/api. They are also used as gorm models.
+package model
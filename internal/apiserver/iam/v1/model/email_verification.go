@@ -0,0 +1,25 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import "time"
+
+// EmailVerification represents a pending verification of the email of a user.
+// Only the hash of the verification token is stored, the token itself is sent
+// to the email.
+type EmailVerification struct {
+	ID        uint64     `json:"-"         gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Username  string     `json:"username"  gorm:"column:username"`
+	Email     string     `json:"email"     gorm:"column:email"`
+	Hash      string     `json:"-"         gorm:"column:hash"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:createdAt"`
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"column:expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"    gorm:"column:usedAt"`
+}
+
+// TableName maps to mysql table name.
+func (e *EmailVerification) TableName() string {
+	return "email_verification"
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/util/idutil"
+	"github.com/marmotedu/component-base/pkg/validation"
+	"github.com/marmotedu/component-base/pkg/validation/field"
+	"gorm.io/gorm"
+)
+
+// Group represents a group of users restful resource. Policies bound to a
+// group apply to all the members of the group.
+type Group struct {
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Tenant is filled in by the storage from the tenant of the request.
+	Tenant string `json:"tenant" gorm:"column:tenant" validate:"omitempty"`
+
+	Description string `json:"description" gorm:"column:description" validate:"omitempty,max=255"`
+
+	TotalMember int64 `json:"totalMember" gorm:"-" validate:"omitempty"`
+}
+
+// GroupList is the whole list of all groups which have been stored in stroage.
+type GroupList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Group `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (g *Group) TableName() string {
+	return "user_group"
+}
+
+// AfterCreate run after create database record.
+func (g *Group) AfterCreate(tx *gorm.DB) error {
+	g.InstanceID = idutil.GetInstanceID(g.ID, "group-")
+
+	return tx.Save(g).Error
+}
+
+// Validate validates that a group object is valid.
+func (g *Group) Validate() field.ErrorList {
+	val := validation.NewValidator(g)
+
+	return val.Validate()
+}
+
+// GroupMember represents the membership of a user in a group.
+type GroupMember struct {
+	ID        uint64    `json:"id"        gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Tenant    string    `json:"tenant"    gorm:"column:tenant"`
+	Group     string    `json:"group"     gorm:"column:groupName"`
+	Username  string    `json:"username"  gorm:"column:username"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+// GroupMemberList is the whole list of the members of a group.
+type GroupMemberList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*GroupMember `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (m *GroupMember) TableName() string {
+	return "group_member"
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+)
+
+// Principal kinds a policy can be bound to, besides its owner.
+const (
+	PrincipalKindGroup = "group"
+	PrincipalKindRole  = "role"
+)
+
+// Principal returns the identifier of a group or a role principal, e.g. `group:warehouse`.
+func Principal(kind, name string) string {
+	return kind + ":" + name
+}
+
+// PolicyBinding attaches an existing policy to a group or a role. The policy
+// is still owned and maintained by its owner.
+type PolicyBinding struct {
+	ID          uint64    `json:"id"          gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Tenant      string    `json:"tenant"      gorm:"column:tenant"`
+	Principal   string    `json:"principal"   gorm:"column:principal"`
+	PolicyOwner string    `json:"policyOwner" gorm:"column:policyOwner"`
+	PolicyName  string    `json:"policyName"  gorm:"column:policyName"`
+	CreatedAt   time.Time `json:"createdAt"   gorm:"column:createdAt"`
+}
+
+// PolicyBindingList is the whole list of the policies bound to a principal.
+type PolicyBindingList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*PolicyBinding `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (b *PolicyBinding) TableName() string {
+	return "policy_binding"
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/util/idutil"
+	"github.com/marmotedu/component-base/pkg/validation"
+	"github.com/marmotedu/component-base/pkg/validation/field"
+	"gorm.io/gorm"
+)
+
+// Subject kinds a role can be bound to.
+const (
+	SubjectKindUser  = "user"
+	SubjectKindGroup = "group"
+)
+
+// Role represents a role restful resource. A role is a named set of policies
+// which can be bound to users and groups.
+type Role struct {
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Tenant is filled in by the storage from the tenant of the request.
+	Tenant string `json:"tenant" gorm:"column:tenant" validate:"omitempty"`
+
+	Description string `json:"description" gorm:"column:description" validate:"omitempty,max=255"`
+}
+
+// RoleList is the whole list of all roles which have been stored in stroage.
+type RoleList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Role `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (r *Role) TableName() string {
+	return "role"
+}
+
+// AfterCreate run after create database record.
+func (r *Role) AfterCreate(tx *gorm.DB) error {
+	r.InstanceID = idutil.GetInstanceID(r.ID, "role-")
+
+	return tx.Save(r).Error
+}
+
+// Validate validates that a role object is valid.
+func (r *Role) Validate() field.ErrorList {
+	val := validation.NewValidator(r)
+
+	return val.Validate()
+}
+
+// RoleBinding binds a role to a user or a group.
+type RoleBinding struct {
+	ID        uint64    `json:"id"        gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Tenant    string    `json:"tenant"    gorm:"column:tenant"`
+	Role      string    `json:"role"      gorm:"column:roleName"`
+	Kind      string    `json:"kind"      gorm:"column:kind"`
+	Subject   string    `json:"subject"   gorm:"column:subject"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+// RoleBindingList is the whole list of the bindings of a role.
+type RoleBindingList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*RoleBinding `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (b *RoleBinding) TableName() string {
+	return "role_binding"
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"gorm.io/gorm"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+)
+
+// Secret extends the secret of github.com/marmotedu/api with the scopes it is
+// restricted to, the key it replaced during a rotation and when it was last used.
+type Secret struct {
+	v1.Secret `json:",inline"`
+
+	// Scopes restrict the requests the secret can authorize, it is not
+	// restricted when empty.
+	Scopes       []scope.Scope `json:"scopes,omitempty" gorm:"-"`
+	ScopesShadow string        `json:"-"                gorm:"column:scopesShadow"`
+
+	// PreviousKey is still accepted until PreviousKeyExpires after a rotation,
+	// so that the clients can switch to the new key.
+	PreviousKey        string `json:"-"                            gorm:"column:previousKey"`
+	PreviousKeyExpires int64  `json:"previousKeyExpires,omitempty" gorm:"column:previousKeyExpires"`
+
+	// LastUsedAt is reported by iam-authz-server, at most a few minutes late.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" gorm:"column:lastUsedAt"`
+}
+
+// SecretList is the whole list of all secrets which have been stored in stroage.
+type SecretList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Secret `json:"items"`
+}
+
+// BeforeCreate run before create database record.
+func (s *Secret) BeforeCreate(tx *gorm.DB) error {
+	s.ScopesShadow = s.scopesString()
+
+	return s.Secret.BeforeCreate(tx)
+}
+
+// BeforeUpdate run before update database record.
+func (s *Secret) BeforeUpdate(tx *gorm.DB) error {
+	s.ScopesShadow = s.scopesString()
+
+	return s.Secret.BeforeUpdate(tx)
+}
+
+// AfterFind run after find to unmarshal the scopes shadow string.
+func (s *Secret) AfterFind(tx *gorm.DB) error {
+	if err := s.Secret.AfterFind(tx); err != nil {
+		return err
+	}
+
+	if s.ScopesShadow == "" {
+		s.Scopes = nil
+
+		return nil
+	}
+
+	return json.Unmarshal([]byte(s.ScopesShadow), &s.Scopes)
+}
+
+// PreviousKeyValid tells whether the key replaced by the last rotation is still
+// accepted.
+func (s *Secret) PreviousKeyValid(now time.Time) bool {
+	return s.PreviousKey != "" && now.Unix() < s.PreviousKeyExpires
+}
+
+func (s *Secret) scopesString() string {
+	if len(s.Scopes) == 0 {
+		return ""
+	}
+
+	data, _ := json.Marshal(s.Scopes)
+
+	return string(data)
+}
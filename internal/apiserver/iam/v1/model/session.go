@@ -0,0 +1,53 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+)
+
+// Session represents a login session of a user. A session lasts as long as
+// its refresh token keeps being rotated before ExpiresAt.
+type Session struct {
+	ID         uint64     `json:"-"                   gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	SessionID  string     `json:"id"                  gorm:"column:sessionID"`
+	Username   string     `json:"username"            gorm:"column:username"`
+	UserAgent  string     `json:"userAgent"           gorm:"column:userAgent"`
+	ClientIP   string     `json:"clientIP"            gorm:"column:clientIP"`
+	CreatedAt  time.Time  `json:"createdAt"           gorm:"column:createdAt"`
+	LastUsedAt time.Time  `json:"lastUsedAt"          gorm:"column:lastUsedAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"           gorm:"column:expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" gorm:"column:revokedAt"`
+}
+
+// SessionList is the whole list of the sessions of a user.
+type SessionList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Session `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (s *Session) TableName() string {
+	return "session"
+}
+
+// RefreshToken represents an opaque refresh token of a session, only its
+// sha256 hash is stored. A rotated token must never be presented again.
+type RefreshToken struct {
+	ID        uint64     `json:"id"        gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Hash      string     `json:"-"         gorm:"column:hash"`
+	SessionID string     `json:"sessionID" gorm:"column:sessionID"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:createdAt"`
+	RotatedAt *time.Time `json:"rotatedAt" gorm:"column:rotatedAt"`
+}
+
+// TableName maps to mysql table name.
+func (t *RefreshToken) TableName() string {
+	return "refresh_token"
+}
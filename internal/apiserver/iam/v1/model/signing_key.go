@@ -0,0 +1,25 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import "time"
+
+// SigningKey represents an asymmetric key used to sign the jwt tokens issued by
+// iam-apiserver. The newest active key signs the new tokens, retired keys only
+// verify the tokens they have signed until ExpiresAt.
+type SigningKey struct {
+	ID         uint64     `json:"id"         gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	KID        string     `json:"kid"        gorm:"column:kid"`
+	Algorithm  string     `json:"algorithm"  gorm:"column:algorithm"`
+	PrivateKey string     `json:"-"          gorm:"column:privateKey"`
+	CreatedAt  time.Time  `json:"createdAt"  gorm:"column:createdAt"`
+	RetiredAt  *time.Time `json:"retiredAt"  gorm:"column:retiredAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"  gorm:"column:expiresAt"`
+}
+
+// TableName maps to mysql table name.
+func (k *SigningKey) TableName() string {
+	return "signing_key"
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/util/idutil"
+	"github.com/marmotedu/component-base/pkg/validation"
+	"github.com/marmotedu/component-base/pkg/validation/field"
+	"gorm.io/gorm"
+)
+
+// Tenant represents an organization restful resource, e.g. a storefront. Users,
+// secrets, policies, groups, roles and items of different tenants are isolated
+// from each other.
+type Tenant struct {
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Description string `json:"description" gorm:"column:description" validate:"omitempty,max=255"`
+
+	TotalMember int64 `json:"totalMember" gorm:"-" validate:"omitempty"`
+}
+
+// TenantList is the whole list of all tenants which have been stored in stroage.
+type TenantList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*Tenant `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (t *Tenant) TableName() string {
+	return "tenant"
+}
+
+// AfterCreate run after create database record.
+func (t *Tenant) AfterCreate(tx *gorm.DB) error {
+	t.InstanceID = idutil.GetInstanceID(t.ID, "tenant-")
+
+	return tx.Save(t).Error
+}
+
+// Validate validates that a tenant object is valid.
+func (t *Tenant) Validate() field.ErrorList {
+	val := validation.NewValidator(t)
+
+	return val.Validate()
+}
+
+// TenantMember represents the membership of a user in a tenant. A user belongs to
+// exactly one tenant, users without membership belong to the default tenant.
+type TenantMember struct {
+	ID       uint64 `json:"id"       gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Tenant   string `json:"tenant"   gorm:"column:tenantName"`
+	Username string `json:"username" gorm:"column:username"`
+	// IsAdmin marks the administrators of the tenant, they can manage the users,
+	// groups and roles of their own tenant.
+	IsAdmin   int       `json:"isAdmin"   gorm:"column:isAdmin"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+// TenantMemberList is the whole list of the members of a tenant.
+type TenantMemberList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*TenantMember `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (m *TenantMember) TableName() string {
+	return "tenant_member"
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import "time"
+
+// TOTP represents the time-based one-time password enrollment of a user. The
+// enrollment is pending until it is confirmed by a first code.
+type TOTP struct {
+	ID       uint64 `json:"-"        gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Username string `json:"username" gorm:"column:username"`
+	Secret   string `json:"-"        gorm:"column:secret"`
+	// RecoveryCodes holds the comma separated hashes of the unused recovery codes.
+	RecoveryCodes string `json:"-" gorm:"column:recoveryCodes"`
+	// LastStep is the time step of the last accepted code, codes can only be used once.
+	LastStep  int64      `json:"-"         gorm:"column:lastStep"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"column:createdAt"`
+	EnabledAt *time.Time `json:"enabledAt" gorm:"column:enabledAt"`
+}
+
+// TableName maps to mysql table name.
+func (t *TOTP) TableName() string {
+	return "user_totp"
+}
+
+// Enabled tells whether the enrollment has been confirmed.
+func (t *TOTP) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// TOTPEnrollment defines the provisioning details of a new enrollment, they are
+// only returned once.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth provisioning URI, usually rendered as a QR code.
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
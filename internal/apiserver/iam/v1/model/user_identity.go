@@ -0,0 +1,22 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import "time"
+
+// UserIdentity links a user to its account at an external identity provider,
+// identified by the issuer of the provider and the subject of the account.
+type UserIdentity struct {
+	ID        uint64    `json:"-"         gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Username  string    `json:"username"  gorm:"column:username"`
+	Issuer    string    `json:"issuer"    gorm:"column:issuer"`
+	Subject   string    `json:"subject"   gorm:"column:subject"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+// TableName maps to mysql table name.
+func (u *UserIdentity) TableName() string {
+	return "user_identity"
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+)
+
+// The statuses of the user lifecycle, stored in the status of the user.
+const (
+	// UserStatusDisabled is set by the watcher to the users inactive for too long.
+	UserStatusDisabled = iota
+	UserStatusActive
+	// UserStatusPending is the status of the new users until they verify their email.
+	UserStatusPending
+	UserStatusSuspended
+	// UserStatusDeleted is only recorded in the status changes, the deleted
+	// users are removed from the database.
+	UserStatusDeleted
+)
+
+var userStatusNames = map[int]string{
+	UserStatusDisabled:  "disabled",
+	UserStatusActive:    "active",
+	UserStatusPending:   "pending",
+	UserStatusSuspended: "suspended",
+	UserStatusDeleted:   "deleted",
+}
+
+// UserStatusName returns the name of the user status.
+func UserStatusName(status int) string {
+	if name, ok := userStatusNames[status]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// UserStatusChange records a transition of the user lifecycle.
+type UserStatusChange struct {
+	ID       uint64 `json:"-"        gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Username string `json:"username" gorm:"column:username"`
+	Status   int    `json:"status"   gorm:"column:status"`
+	Reason   string `json:"reason"   gorm:"column:reason"`
+	// Operator is the user who made the change, empty for the changes made by the system.
+	Operator  string    `json:"operator"  gorm:"column:operator"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:createdAt"`
+}
+
+// UserStatusChangeList is the whole list of the status changes of a user.
+type UserStatusChangeList struct {
+	// Standard list metadata.
+	metav1.ListMeta `json:",inline"`
+
+	Items []*UserStatusChange `json:"items"`
+}
+
+// TableName maps to mysql table name.
+func (u *UserStatusChange) TableName() string {
+	return "user_status_change"
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+)
+
+// The catalog file formats supported by the import and the export.
+const (
+	CatalogFormatCSV   = "csv"
+	CatalogFormatJSONL = "jsonl"
+)
+
+// The statuses of an import job.
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobSucceeded = "succeeded"
+	ImportJobFailed    = "failed"
+)
+
+// CatalogRow is a row of the catalog import and export files. It covers an item
+// and, optionally, its attributes, its offers and the URLs of its images. Items
+// are matched by ASIN, or by SKU when the ASIN is empty.
+type CatalogRow struct {
+	ASIN         string `json:"asin,omitempty"`
+	SKU          string `json:"sku,omitempty"`
+	Brand        string `json:"brand,omitempty"`
+	Title        string `json:"title,omitempty"`
+	ProductGroup string `json:"product_group,omitempty"`
+	ProductType  string `json:"product_type,omitempty"`
+	// Status defaults to 1, the only status of the listed items.
+	Status *int `json:"status,omitempty"`
+
+	Attributes *CatalogAttributes `json:"attributes,omitempty"`
+	Offers     []*CatalogOffer    `json:"offers,omitempty"`
+	Images     []string           `json:"images,omitempty"`
+}
+
+// Key returns the key the row is matched with.
+func (r *CatalogRow) Key() string {
+	if r.ASIN != "" {
+		return r.ASIN
+	}
+
+	return r.SKU
+}
+
+// CatalogAttributes is the part of a catalog row which holds the item attributes.
+type CatalogAttributes struct {
+	Binding               string  `json:"binding,omitempty"`
+	ItemHeight            float64 `json:"item_height,omitempty"`
+	ItemLength            float64 `json:"item_length,omitempty"`
+	ItemWidth             float64 `json:"item_width,omitempty"`
+	ItemWeight            float64 `json:"item_weight,omitempty"`
+	ItemDimensionsUnit    string  `json:"item_dimensions_unit,omitempty"`
+	PackageHeight         float64 `json:"package_height,omitempty"`
+	PackageLength         float64 `json:"package_length,omitempty"`
+	PackageWidth          float64 `json:"package_width,omitempty"`
+	PackageWeight         float64 `json:"package_weight,omitempty"`
+	PackageDimensionsUnit string  `json:"package_dimensions_unit,omitempty"`
+	// ReleaseDate is formatted as 2006-01-02.
+	ReleaseDate string `json:"release_date,omitempty"`
+}
+
+// CatalogOffer is the part of a catalog row which holds an offer of the item,
+// offers are matched by marketplace.
+type CatalogOffer struct {
+	MarketplaceID      string  `json:"marketplace_id"`
+	ListPrice          float64 `json:"list_price,omitempty"`
+	CurrencyCode       string  `json:"currency_code,omitempty"`
+	PackageQuantity    int     `json:"package_quantity,omitempty"`
+	AvailabilityStatus string  `json:"availability_status,omitempty"`
+	FulfillmentChannel string  `json:"fulfillment_channel,omitempty"`
+}
+
+// ImportJob records the progress of a catalog import.
+type ImportJob struct {
+	ID       uint64 `json:"id"                 gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Tenant   string `json:"tenant"             gorm:"column:tenant"`
+	Username string `json:"username"           gorm:"column:username"`
+	Format   string `json:"format"             gorm:"column:format"`
+	Status   string `json:"status"             gorm:"column:status"`
+	Message  string `json:"message,omitempty"  gorm:"column:message"`
+
+	// ProcessedRows counts the rows read so far, the other counters split them.
+	ProcessedRows int64 `json:"processedRows" gorm:"column:processed_rows"`
+	CreatedRows   int64 `json:"createdRows"   gorm:"column:created_rows"`
+	UpdatedRows   int64 `json:"updatedRows"   gorm:"column:updated_rows"`
+	FailedRows    int64 `json:"failedRows"    gorm:"column:failed_rows"`
+
+	CreatedAt  time.Time  `json:"createdAt"            gorm:"column:created_at"`
+	UpdatedAt  time.Time  `json:"updatedAt"            gorm:"column:updated_at"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" gorm:"column:finished_at"`
+}
+
+// TableName maps to mysql table name.
+func (ImportJob) TableName() string {
+	return "itemimportjob"
+}
+
+// Finished tells whether the job will not make any more progress.
+func (j *ImportJob) Finished() bool {
+	return j.Status == ImportJobSucceeded || j.Status == ImportJobFailed
+}
+
+// ImportRowError records why a row of an import job was rejected.
+type ImportRowError struct {
+	ID    uint64 `json:"-"     gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	JobID uint64 `json:"jobID" gorm:"column:job_id"`
+	// Row is the line number of the row in the file, starting at 1.
+	Row     int64  `json:"row"           gorm:"column:row"`
+	Key     string `json:"key,omitempty" gorm:"column:key"`
+	Message string `json:"message"       gorm:"column:message"`
+}
+
+// TableName maps to mysql table name.
+func (ImportRowError) TableName() string {
+	return "itemimporterror"
+}
+
+// ImportRowErrorList is the whole list of the rejected rows of an import job.
+type ImportRowErrorList struct {
+	metav1.ListMeta `json:",inline"`
+
+	Items []*ImportRowError `json:"items"`
+}
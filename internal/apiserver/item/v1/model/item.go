@@ -40,6 +40,9 @@ type Item struct {
 	ProductGroup string `json:"product_group"`
 	ProductType  string `json:"product_type"`
 	Status       int    `json:"status"`
+	// Tenant is the storefront the item belongs to, it is filled in by the storage
+	// from the tenant of the request.
+	Tenant string `json:"tenant"`
 }
 
 func (Item) TableName() string {
@@ -0,0 +1,109 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/shutdown"
+)
+
+// keyReloadInterval defines how often the signing keys are reloaded, to pick up
+// the rotations made by iam-watcher.
+const keyReloadInterval = time.Minute
+
+// signingKeys signs the jwt tokens when an asymmetric signing algorithm is
+// configured, it is nil when tokens are signed with HS256 and jwt.key.
+var signingKeys *jwks.KeySet
+
+// initSigningKeys loads the jwt signing keys from the storage, and creates the
+// first key when there is no active key of the configured algorithm.
+func (s *apiServer) initSigningKeys() {
+	alg := viper.GetString("jwt.signing-algorithm")
+	if !jwks.Supported(alg) {
+		return
+	}
+
+	srv := srvv1.NewService(store.Client())
+	ctx, cancel := context.WithCancel(context.Background())
+	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
+		cancel()
+
+		return nil
+	}))
+
+	active, err := srv.SigningKeys().Active(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get active jwt signing key: %s", err.Error())
+	}
+
+	if active == nil || active.Algorithm != alg {
+		if err := srv.SigningKeys().Rotate(ctx, alg, maxTokenLife()); err != nil {
+			log.Fatalf("Failed to create jwt signing key: %s", err.Error())
+		}
+	}
+
+	keys, err := srv.SigningKeys().Keys(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load jwt signing keys: %s", err.Error())
+	}
+
+	if signingKeys, err = jwks.NewKeySet(keys); err != nil {
+		log.Fatalf("Failed to load jwt signing keys: %s", err.Error())
+	}
+
+	go reloadSigningKeys(ctx, srv)
+}
+
+func reloadSigningKeys(ctx context.Context, srv srvv1.Service) {
+	ticker := time.NewTicker(keyReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			keys, err := srv.SigningKeys().Keys(ctx)
+			if err == nil {
+				err = signingKeys.Replace(keys)
+			}
+
+			if err != nil {
+				log.Errorf("reload jwt signing keys failed: %s", err.Error())
+			}
+		}
+	}
+}
+
+// maxTokenLife returns how long a token can be used or refreshed after it is signed.
+func maxTokenLife() time.Duration {
+	timeout, maxRefresh := viper.GetDuration("jwt.timeout"), viper.GetDuration("jwt.max-refresh")
+	if maxRefresh > timeout {
+		return maxRefresh
+	}
+
+	return timeout
+}
+
+// jwksHandler publishes the public signing keys, so that tokens can be verified offline.
+func jwksHandler(c *gin.Context) {
+	set := jwks.Set{Keys: []jwks.JWK{}}
+	if signingKeys != nil {
+		set = signingKeys.JWKS()
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, set)
+}
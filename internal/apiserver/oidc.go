@@ -0,0 +1,186 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apiserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+	"github.com/spf13/viper"
+
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/oidc"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
+)
+
+const (
+	oidcStateKeyPrefix = "iam-oidc-state-"
+	oidcStateCookie    = "oidc_state"
+	oidcStateTTL       = 10 * time.Minute
+)
+
+// oidcRequest defines a pending authorization request, stored in redis under
+// the hash of its state.
+type oidcRequest struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// oidcLogin logs in the users of an OpenID Connect provider with the
+// authorization code flow, and issues them the tokens of a normal login.
+type oidcLogin struct {
+	redis    storage.RedisCluster
+	client   *oidc.Client
+	strategy auth.JWTStrategy
+
+	usernameClaim string
+	emailClaim    string
+	nicknameClaim string
+	provision     bool
+}
+
+// newOIDCLogin returns nil when the OpenID Connect login is not configured.
+func newOIDCLogin(strategy auth.JWTStrategy) *oidcLogin {
+	if viper.GetString("oidc.issuer") == "" {
+		return nil
+	}
+
+	return &oidcLogin{
+		client: oidc.NewClient(oidc.Config{
+			Issuer:       viper.GetString("oidc.issuer"),
+			ClientID:     viper.GetString("oidc.client-id"),
+			ClientSecret: viper.GetString("oidc.client-secret"),
+			RedirectURL:  viper.GetString("oidc.redirect-url"),
+			Scopes:       viper.GetStringSlice("oidc.scopes"),
+		}),
+		strategy:      strategy,
+		usernameClaim: viper.GetString("oidc.username-claim"),
+		emailClaim:    viper.GetString("oidc.email-claim"),
+		nicknameClaim: viper.GetString("oidc.nickname-claim"),
+		provision:     viper.GetBool("oidc.provision"),
+	}
+}
+
+// loginHandler redirects the user agent to the provider. The state is also
+// set in a cookie, so that the callback can only complete a login started by
+// the same user agent.
+func (o *oidcLogin) loginHandler(c *gin.Context) {
+	var (
+		req   = &oidcRequest{}
+		state string
+		err   error
+	)
+
+	for _, token := range []*string{&state, &req.Nonce, &req.Verifier} {
+		if *token, err = oidc.RandomToken(); err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrUnknown, err.Error()), nil)
+
+			return
+		}
+	}
+
+	authURL, err := o.client.AuthCodeURL(c, state, req.Nonce, req.Verifier)
+	if err != nil {
+		log.L(c).Errorf("build OpenID Connect authorization request failed: %s", err.Error())
+		core.WriteResponse(c, errors.WithCode(code.ErrOIDCLoginFailed, err.Error()), nil)
+
+		return
+	}
+
+	value, _ := json.Marshal(req)
+	if err := o.redis.SetKey(oidcStateKeyPrefix+hashToken(state), string(value), oidcStateTTL); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrUnknown, err.Error()), nil)
+
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, int(oidcStateTTL/time.Second), "/login/oidc", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// callbackHandler completes the login once the provider redirects the user
+// agent back, and responds like the login with a password.
+func (o *oidcLogin) callbackHandler(c *gin.Context) {
+	if e := c.Query("error"); e != "" {
+		core.WriteResponse(c, errors.WithCode(code.ErrOIDCLoginFailed, "%s: %s", e, c.Query("error_description")), nil)
+
+		return
+	}
+
+	req, err := o.takeRequest(c)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	token, err := o.client.Exchange(c, c.Query("code"), req.Verifier)
+	if err != nil {
+		log.L(c).Warnf("exchange OpenID Connect code failed: %s", err.Error())
+		core.WriteResponse(c, errors.WithCode(code.ErrOIDCLoginFailed, err.Error()), nil)
+
+		return
+	}
+
+	idToken, err := o.client.Verify(c, token.IDToken, req.Nonce)
+	if err != nil {
+		log.L(c).Warnf("verify OpenID Connect id token failed: %s", err.Error())
+		core.WriteResponse(c, errors.WithCode(code.ErrOIDCLoginFailed, err.Error()), nil)
+
+		return
+	}
+
+	user, err := srvv1.NewService(store.Client()).Identities().Login(c, &srvv1.ExternalIdentity{
+		Issuer:   idToken.Issuer,
+		Subject:  idToken.Subject,
+		Username: idToken.String(o.usernameClaim),
+		Email:    idToken.String(o.emailClaim),
+		Nickname: idToken.String(o.nicknameClaim),
+	}, o.provision)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	// the provider is in charge of the second factor of its users.
+	o.strategy.LoginWith(c, user)
+}
+
+// takeRequest returns the authorization request of the callback state, which
+// can only be used once.
+func (o *oidcLogin) takeRequest(c *gin.Context) (*oidcRequest, error) {
+	state := c.Query("state")
+	cookie, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, "/login/oidc", "", c.Request.TLS != nil, true)
+
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookie)) != 1 {
+		return nil, errors.WithCode(code.ErrOIDCStateInvalid, "state does not match the login of the user agent")
+	}
+
+	key := oidcStateKeyPrefix + hashToken(state)
+
+	value, err := o.redis.GetKey(key)
+	if err != nil || !o.redis.DeleteKey(key) {
+		return nil, errors.WithCode(code.ErrOIDCStateInvalid, "state is unknown or has expired")
+	}
+
+	var req oidcRequest
+	if err := json.Unmarshal([]byte(value), &req); err != nil {
+		return nil, errors.WithCode(code.ErrOIDCStateInvalid, err.Error())
+	}
+
+	return &req, nil
+}
@@ -23,6 +23,14 @@ type Options struct {
 	MySQLOptions            *genericoptions.MySQLOptions           `json:"mysql"    mapstructure:"mysql"`
 	RedisOptions            *genericoptions.RedisOptions           `json:"redis"    mapstructure:"redis"`
 	JwtOptions              *genericoptions.JwtOptions             `json:"jwt"      mapstructure:"jwt"`
+	TwoFactorOptions        *genericoptions.TwoFactorOptions       `json:"two-factor" mapstructure:"two-factor"`
+	LockoutOptions          *genericoptions.LockoutOptions         `json:"lockout"  mapstructure:"lockout"`
+	NotifierOptions         *genericoptions.NotifierOptions        `json:"notifier" mapstructure:"notifier"`
+	RegistrationOptions     *genericoptions.RegistrationOptions    `json:"registration" mapstructure:"registration"`
+	OIDCOptions             *genericoptions.OIDCOptions            `json:"oidc"     mapstructure:"oidc"`
+	SecretOptions           *genericoptions.SecretOptions          `json:"secret"   mapstructure:"secret"`
+	AccessTokenOptions      *genericoptions.AccessTokenOptions     `json:"access-token" mapstructure:"access-token"`
+	CatalogOptions          *genericoptions.CatalogOptions         `json:"catalog"  mapstructure:"catalog"`
 	Log                     *log.Options                           `json:"log"      mapstructure:"log"`
 	FeatureOptions          *genericoptions.FeatureOptions         `json:"feature"  mapstructure:"feature"`
 	FileStorageOptions      *genericoptions.FileStorageOptions     `json:"fileStorage" mapstructure:"fileStorage"`
@@ -38,6 +46,14 @@ func NewOptions() *Options {
 		MySQLOptions:            genericoptions.NewMySQLOptions(),
 		RedisOptions:            genericoptions.NewRedisOptions(),
 		JwtOptions:              genericoptions.NewJwtOptions(),
+		TwoFactorOptions:        genericoptions.NewTwoFactorOptions(),
+		LockoutOptions:          genericoptions.NewLockoutOptions(),
+		NotifierOptions:         genericoptions.NewNotifierOptions(),
+		RegistrationOptions:     genericoptions.NewRegistrationOptions(),
+		OIDCOptions:             genericoptions.NewOIDCOptions(),
+		SecretOptions:           genericoptions.NewSecretOptions(),
+		AccessTokenOptions:      genericoptions.NewAccessTokenOptions(),
+		CatalogOptions:          genericoptions.NewCatalogOptions(),
 		Log:                     log.NewOptions(),
 		FeatureOptions:          genericoptions.NewFeatureOptions(),
 		FileStorageOptions:      genericoptions.NewFileStorageOptions(),
@@ -55,6 +71,14 @@ func (o *Options) ApplyTo(c *server.Config) error {
 func (o *Options) Flags() (fss cliflag.NamedFlagSets) {
 	o.GenericServerRunOptions.AddFlags(fss.FlagSet("generic"))
 	o.JwtOptions.AddFlags(fss.FlagSet("jwt"))
+	o.TwoFactorOptions.AddFlags(fss.FlagSet("two-factor"))
+	o.LockoutOptions.AddFlags(fss.FlagSet("lockout"))
+	o.NotifierOptions.AddFlags(fss.FlagSet("notifier"))
+	o.RegistrationOptions.AddFlags(fss.FlagSet("registration"))
+	o.OIDCOptions.AddFlags(fss.FlagSet("oidc"))
+	o.SecretOptions.AddFlags(fss.FlagSet("secret"))
+	o.AccessTokenOptions.AddFlags(fss.FlagSet("access-token"))
+	o.CatalogOptions.AddFlags(fss.FlagSet("catalog"))
 	o.GRPCOptions.AddFlags(fss.FlagSet("grpc"))
 	o.MySQLOptions.AddFlags(fss.FlagSet("mysql"))
 	o.RedisOptions.AddFlags(fss.FlagSet("redis"))
@@ -15,6 +15,14 @@ func (o *Options) Validate() []error {
 	errs = append(errs, o.MySQLOptions.Validate()...)
 	errs = append(errs, o.RedisOptions.Validate()...)
 	errs = append(errs, o.JwtOptions.Validate()...)
+	errs = append(errs, o.TwoFactorOptions.Validate()...)
+	errs = append(errs, o.LockoutOptions.Validate()...)
+	errs = append(errs, o.NotifierOptions.Validate()...)
+	errs = append(errs, o.RegistrationOptions.Validate()...)
+	errs = append(errs, o.OIDCOptions.Validate()...)
+	errs = append(errs, o.SecretOptions.Validate()...)
+	errs = append(errs, o.AccessTokenOptions.Validate()...)
+	errs = append(errs, o.CatalogOptions.Validate()...)
 	errs = append(errs, o.Log.Validate()...)
 	errs = append(errs, o.FeatureOptions.Validate()...)
 
@@ -4,13 +4,23 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/marmotedu/component-base/pkg/core"
 	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/accesstoken"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/auditlog"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/backup"
+	eventv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/event"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/group"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/item"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/policy"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/role"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/secret"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/session"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/totp"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/user"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/options"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store/mysql"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
 	"github.com/spf13/viper"
@@ -32,9 +42,18 @@ func installController(g *gin.Engine) *gin.Engine {
 	// Middlewares.
 	jwtStrategy, _ := newJWTAuth().(auth.JWTStrategy)
 	g.POST("/login", jwtStrategy.LoginHandler)
+	// second step of the logins challenged by two-factor authentication
+	g.POST("/login/2fa", jwtStrategy.SecondFactorHandler)
+	g.POST("/login/2fa/enroll", newSecondFactor().enrollHandler)
 	g.POST("/logout", jwtStrategy.LogoutHandler)
-	// Refresh time can be longer than token timeout
+	// exchanges the opaque refresh token returned by login for a new token pair
 	g.POST("/refresh", jwtStrategy.RefreshHandler)
+	g.GET("/.well-known/jwks.json", jwksHandler)
+	// login through the OpenID Connect provider, when one is configured
+	if oidcLogin := newOIDCLogin(jwtStrategy); oidcLogin != nil {
+		g.GET("/login/oidc", oidcLogin.loginHandler)
+		g.GET("/login/oidc/callback", oidcLogin.callbackHandler)
+	}
 
 	auto := newAutoAuth()
 	g.NoRoute(auto.AuthFunc(), func(c *gin.Context) {
@@ -48,31 +67,44 @@ func installController(g *gin.Engine) *gin.Engine {
 	// 	log.Fatalf("Failed to create file storage instance: %v", err)
 	// }
 
-	v1 := g.Group("/v1")
+	// the mutating requests are recorded in the audit log.
+	v1 := g.Group("/v1", middleware.Audit())
 	{
 		// user RESTful resource
 		userv1 := v1.Group("/users")
 		{
-			userController := user.NewUserController(storeIns)
+			userController := user.NewUserController(storeIns, viper.GetBool("registration.require-email-verification"))
 
-			userv1.POST("", userController.Create)
-			userv1.Use(auto.AuthFunc(), middleware.Validation())
-			// v1.PUT("/find_password", userController.FindPassword)
+			userv1.POST("", middleware.Tenant(), userController.Create)
+			// password resets and email verifications are anonymous and not limited
+			// to a tenant, the usernames are unique.
+			userv1.POST("password-reset", userController.RequestPasswordReset)
+			userv1.POST("password-reset/confirm", userController.ResetPassword)
+			userv1.POST("verify-email", userController.VerifyEmail)
+			userv1.POST("verify-email/resend", userController.ResendVerification)
+			userv1.Use(auto.AuthFunc(), middleware.Tenant(), middleware.Validation())
 			userv1.DELETE("", userController.DeleteCollection) // admin api
 			userv1.DELETE(":name", userController.Delete)      // admin api
 			userv1.PUT(":name/change-password", userController.ChangePassword)
+			userv1.DELETE(":name/lockout", middleware.AdminRequired(), userController.Unlock)                // admin api
+			userv1.POST(":name/suspend", middleware.AdminRequired(), userController.Suspend)                 // admin api
+			userv1.POST(":name/reactivate", middleware.AdminRequired(), userController.Reactivate)           // admin api
+			userv1.GET(":name/status-changes", middleware.AdminRequired(), userController.ListStatusChanges) // admin api
 			userv1.PUT(":name", userController.Update)
 			userv1.GET("", userController.List)
 			userv1.GET(":name", userController.Get) // admin api
 		}
 
-		v1.Use(auto.AuthFunc())
+		v1.Use(auto.AuthFunc(), middleware.Tenant())
 
 		// policy RESTful resource
+		policyController := policy.NewPolicyController(storeIns)
+
+		// policy simulation never changes the live policies, so it is not published
+		v1.POST("/policies/test", policyController.Test)
+
 		policyv1 := v1.Group("/policies", middleware.Publish())
 		{
-			policyController := policy.NewPolicyController(storeIns)
-
 			policyv1.POST("", policyController.Create)
 			policyv1.DELETE("", policyController.DeleteCollection)
 			policyv1.DELETE(":name", policyController.Delete)
@@ -81,26 +113,144 @@ func installController(g *gin.Engine) *gin.Engine {
 			policyv1.GET(":name", policyController.Get)
 		}
 
+		// group RESTful resource
+		groupv1 := v1.Group("/groups", middleware.Publish())
+		{
+			groupController := group.NewGroupController(storeIns)
+
+			groupv1.GET("", groupController.List)
+			groupv1.GET(":name", groupController.Get)
+			groupv1.GET(":name/members", groupController.ListMembers)
+			groupv1.GET(":name/policies", groupController.ListPolicies)
+
+			groupv1.Use(middleware.AdminRequired())
+			groupv1.POST("", groupController.Create)
+			groupv1.PUT(":name", groupController.Update)
+			groupv1.DELETE(":name", groupController.Delete)
+			groupv1.PUT(":name/members/:username", groupController.AddMember)
+			groupv1.DELETE(":name/members/:username", groupController.RemoveMember)
+			groupv1.PUT(":name/policies/:policy", groupController.BindPolicy)
+			groupv1.DELETE(":name/policies/:policy", groupController.UnbindPolicy)
+		}
+
+		// role RESTful resource
+		rolev1 := v1.Group("/roles", middleware.Publish())
+		{
+			roleController := role.NewRoleController(storeIns)
+
+			rolev1.GET("", roleController.List)
+			rolev1.GET(":name", roleController.Get)
+			rolev1.GET(":name/bindings", roleController.ListBindings)
+			rolev1.GET(":name/policies", roleController.ListPolicies)
+
+			rolev1.Use(middleware.AdminRequired())
+			rolev1.POST("", roleController.Create)
+			rolev1.PUT(":name", roleController.Update)
+			rolev1.DELETE(":name", roleController.Delete)
+			rolev1.PUT(":name/users/:username", roleController.BindUser)
+			rolev1.DELETE(":name/users/:username", roleController.UnbindUser)
+			rolev1.PUT(":name/groups/:group", roleController.BindGroup)
+			rolev1.DELETE(":name/groups/:group", roleController.UnbindGroup)
+			rolev1.PUT(":name/policies/:policy", roleController.BindPolicy)
+			rolev1.DELETE(":name/policies/:policy", roleController.UnbindPolicy)
+		}
+
+		// tenant RESTful resource
+		tenantv1 := v1.Group("/tenants", middleware.Publish(), middleware.PlatformAdminRequired())
+		{
+			tenantController := tenant.NewTenantController(storeIns)
+
+			tenantv1.POST("", tenantController.Create)
+			tenantv1.PUT(":name", tenantController.Update)
+			tenantv1.DELETE(":name", tenantController.Delete)
+			tenantv1.GET("", tenantController.List)
+			tenantv1.GET(":name", tenantController.Get)
+			tenantv1.GET(":name/members", tenantController.ListMembers)
+			tenantv1.PUT(":name/members/:username", tenantController.AddMember)
+			tenantv1.DELETE(":name/members/:username", tenantController.RemoveMember)
+		}
+
 		// secret RESTful resource
 		secretv1 := v1.Group("/secrets", middleware.Publish())
 		{
-			secretController := secret.NewSecretController(storeIns)
+			secretController := secret.NewSecretController(
+				storeIns,
+				viper.GetInt64("secret.max-per-user"),
+				viper.GetDuration("secret.rotation-grace-period"),
+				viper.GetDuration("secret.max-rotation-grace-period"),
+			)
 
 			secretv1.POST("", secretController.Create)
 			secretv1.DELETE(":name", secretController.Delete)
 			secretv1.PUT(":name", secretController.Update)
 			secretv1.GET("", secretController.List)
 			secretv1.GET(":name", secretController.Get)
+			secretv1.POST(":name/rotate", secretController.Rotate)
+		}
+
+		// session RESTful resource, users only see their own sessions
+		sessionv1 := v1.Group("/sessions")
+		{
+			sessionController := session.NewSessionController(storeIns)
+
+			sessionv1.GET("", sessionController.List)
+			sessionv1.DELETE(":id", sessionController.Delete)
+		}
+
+		// personal access token RESTful resource, users only see their own tokens
+		tokenv1 := v1.Group("/tokens")
+		{
+			tokenController := accesstoken.NewAccessTokenController(
+				storeIns,
+				viper.GetInt64("access-token.max-per-user"),
+				viper.GetDuration("access-token.default-ttl"),
+				viper.GetDuration("access-token.max-ttl"),
+			)
+
+			tokenv1.POST("", tokenController.Create)
+			tokenv1.GET("", tokenController.List)
+			tokenv1.GET(":name", tokenController.Get)
+			tokenv1.DELETE(":name", tokenController.Delete)
+		}
+
+		// backup and restore of a tenant, platform admin api
+		backupController := backup.NewBackupController(storeIns)
+		v1.GET("/backup", middleware.PlatformAdminRequired(), backupController.Backup)
+		v1.POST("/restore", middleware.Publish(), middleware.PlatformAdminRequired(), backupController.Restore)
+
+		// server-sent events of the changes of the users, policies, secrets and items
+		eventController := eventv1.NewEventController(event.Client())
+		v1.GET("/events", eventController.Watch)
+
+		// audit log of the mutating requests of the tenant, admin api
+		auditv1 := v1.Group("/audit-logs", middleware.AdminRequired())
+		{
+			auditController := auditlog.NewAuditLogController(storeIns)
+
+			auditv1.GET("", auditController.List)
+			auditv1.GET(":id", auditController.Get)
+		}
+
+		// two-factor authentication of the current user
+		totpv1 := v1.Group("/totp")
+		{
+			totpController := totp.NewTOTPController(storeIns, viper.GetString("two-factor.issuer"))
+
+			totpv1.GET("", totpController.Get)
+			totpv1.POST("", totpController.Enroll)
+			totpv1.POST("confirm", totpController.Confirm)
+			totpv1.POST("disable", totpController.Disable)
+			totpv1.POST("recovery-codes", totpController.RecoveryCodes)
 		}
 	}
 
-	v2 := g.Group("/v2")
+	v2 := g.Group("/v2", middleware.Audit())
 	{
 		// item RESTful resource
 		itemv2 := v2.Group("/items", middleware.Publish())
 		{
 			itemController := item.NewItemController(storeIns)
-			itemv2.Use(auto.AuthFunc())
+			itemv2.Use(auto.AuthFunc(), middleware.Tenant())
 			itemv2.POST("", itemController.Create)
 			itemv2.DELETE(":itemID", itemController.Delete)
 			itemv2.PUT(":itemID", itemController.Update)
@@ -114,10 +264,27 @@ func installController(g *gin.Engine) *gin.Engine {
 			itemv2.GET(":itemID/images", itemImageController.List)
 		}
 
+		// catalog import jobs and exports
+		catalogController := item.NewCatalogController(storeIns, viper.GetInt64("catalog.max-import-size"))
+
+		itemImportV2 := v2.Group("/itemImports", middleware.Publish())
+		{
+			itemImportV2.Use(auto.AuthFunc(), middleware.Tenant())
+			itemImportV2.POST("", catalogController.Import)
+			itemImportV2.GET(":id", catalogController.Get)
+			itemImportV2.GET(":id/errors", catalogController.ListErrors)
+		}
+
+		itemExportV2 := v2.Group("/itemExports")
+		{
+			itemExportV2.Use(auto.AuthFunc(), middleware.Tenant())
+			itemExportV2.GET("", catalogController.Export)
+		}
+
 		itemAtrriV2 := v2.Group("/itemAttris", middleware.Publish())
 		{
 			itemAttriController := item.NewItemAttributesController(storeIns)
-			itemAtrriV2.Use(auto.AuthFunc())
+			itemAtrriV2.Use(auto.AuthFunc(), middleware.Tenant())
 			itemAtrriV2.POST("", itemAttriController.Create)
 			itemAtrriV2.PUT(":attributeID", itemAttriController.Update)
 			itemAtrriV2.GET(":attributeID", itemAttriController.Get)
@@ -139,7 +306,7 @@ func installController(g *gin.Engine) *gin.Engine {
 				log.Errorf("Failed to create itemImageController: %v", err) // Handle this error according to your project's logging strategy
 			}
 
-			itemImageV2.Use(auto.AuthFunc())
+			itemImageV2.Use(auto.AuthFunc(), middleware.Tenant())
 			itemImageV2.POST("", itemImageController.Create)
 			itemImageV2.PUT(":id", itemImageController.Update)
 			itemImageV2.GET(":id", itemImageController.Get)
@@ -19,7 +19,11 @@ import (
 	cachev1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/controller/v1/cache"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store/mysql"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/notifier"
 	genericoptions "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/options"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
 	genericapiserver "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/server"
 
 	// file_storage "github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/file_storage"
@@ -72,6 +76,17 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 		return nil, err
 	}
 
+	notifierIns, err := notifier.New(cfg.NotifierOptions.Type, cfg.NotifierOptions.File)
+	if err != nil {
+		return nil, err
+	}
+	notifier.SetClient(notifierIns)
+
+	// the tokens are rejected while redis is unavailable, unless availability must win.
+	if cfg.JwtOptions.RevocationFailOpen {
+		revocation.SetClient(revocation.FailOpen(revocation.Client()))
+	}
+
 	server := &apiServer{
 		gs:               gs,
 		redisOptions:     cfg.RedisOptions,
@@ -83,10 +98,13 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 }
 
 func (s *apiServer) PrepareRun() preparedAPIServer {
+	s.initSigningKeys()
 
 	initRouter(s.genericAPIServer.Engine)
 
 	s.initRedisStore()
+	s.initEventHub()
+	s.initImportJobs()
 
 	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
 		mysqlStore, _ := mysql.GetMySQLFactoryOr(nil)
@@ -147,6 +165,7 @@ func (c *completedExtraConfig) New() (*grpcAPIServer, error) {
 	}
 
 	pb.RegisterCacheServer(grpcServer, cacheIns)
+	rpc.RegisterDirectoryServer(grpcServer, cacheIns)
 
 	reflection.Register(grpcServer)
 
@@ -213,3 +232,21 @@ func (s *apiServer) initRedisStore() {
 	// try to connect to redis
 	go storage.ConnectToRedis(ctx, config)
 }
+
+// initEventHub subscribes to the events published by all the instances, the
+// streams of the watchers end on shutdown.
+func (s *apiServer) initEventHub() {
+	hub, ok := event.Client().(*event.Hub)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
+		cancel()
+
+		return nil
+	}))
+
+	go hub.Run(ctx)
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// accessTokenUsageInterval is how often the last used time of an access token
+// is written, at most.
+const accessTokenUsageInterval = time.Minute
+
+// AccessTokenSrv defines functions used to handle personal access token requests.
+type AccessTokenSrv interface {
+	// Create creates the access token and returns it, token.Token holds the
+	// plain token which can not be obtained again.
+	Create(ctx context.Context, token *model.AccessToken) error
+	Get(ctx context.Context, username, name string) (*model.AccessToken, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*model.AccessTokenList, error)
+	// Delete revokes the access token of the user.
+	Delete(ctx context.Context, username, name string) error
+	// Authenticate returns the access token of the plain token, provided it
+	// has not expired and its user is active.
+	Authenticate(ctx context.Context, plain string) (*model.AccessToken, error)
+}
+
+type accessTokenService struct {
+	store store.Factory
+}
+
+var _ AccessTokenSrv = (*accessTokenService)(nil)
+
+func newAccessTokens(srv *service) *accessTokenService {
+	return &accessTokenService{store: srv.store}
+}
+
+func (s *accessTokenService) Create(ctx context.Context, token *model.AccessToken) error {
+	opaque, err := newOpaqueToken()
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	token.Token = auth.AccessTokenPrefix + opaque
+	token.Hash = hashOpaqueToken(token.Token)
+	token.CreatedAt = time.Now()
+	token.LastUsedAt = nil
+
+	if err := s.store.AccessTokens().Create(ctx, token); err != nil {
+		if duplicateEntry.MatchString(err.Error()) {
+			return errors.WithCode(code.ErrAccessTokenAlreadyExist, "access token %s already exists", token.Name)
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *accessTokenService) Get(ctx context.Context, username, name string) (*model.AccessToken, error) {
+	return s.store.AccessTokens().Get(ctx, username, name)
+}
+
+func (s *accessTokenService) List(
+	ctx context.Context,
+	username string,
+	opts metav1.ListOptions,
+) (*model.AccessTokenList, error) {
+	return s.store.AccessTokens().List(ctx, username, opts)
+}
+
+func (s *accessTokenService) Delete(ctx context.Context, username, name string) error {
+	return s.store.AccessTokens().Delete(ctx, username, name)
+}
+
+func (s *accessTokenService) Authenticate(ctx context.Context, plain string) (*model.AccessToken, error) {
+	if !strings.HasPrefix(plain, auth.AccessTokenPrefix) {
+		return nil, errors.WithCode(code.ErrAccessTokenInvalid, "not a personal access token")
+	}
+
+	token, err := s.store.AccessTokens().GetByHash(ctx, hashOpaqueToken(plain))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.Expired(now) {
+		return nil, errors.WithCode(code.ErrAccessTokenInvalid, "access token %s has expired", token.Name)
+	}
+
+	user, err := s.store.Users().Get(ctx, token.Username, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckUserActive(user); err != nil {
+		return nil, err
+	}
+
+	// the usage is only informative, it is not worth a write on every request.
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenUsageInterval {
+		if err := s.store.AccessTokens().RecordUsage(ctx, token.ID, now); err != nil {
+			log.L(ctx).Warnf("record usage of access token %s failed: %s", token.Name, err.Error())
+		}
+
+		token.LastUsedAt = &now
+	}
+
+	return token, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+)
+
+func TestAccessTokenAuthenticate(t *testing.T) {
+	tokens, users := newMemoryAccessTokens(), activeUsers("colin")
+	srv := newTestService(testStores{"AccessTokens": tokens, "Users": users}).AccessTokens()
+	ctx := context.Background()
+
+	token := &model.AccessToken{Name: "ci", Username: "colin"}
+	assert.Nil(t, srv.Create(ctx, token))
+	assert.True(t, strings.HasPrefix(token.Token, auth.AccessTokenPrefix))
+	assert.NotContains(t, tokens.tokens, token.Token, "the plain token must not be stored")
+
+	authenticated, err := srv.Authenticate(ctx, token.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "colin", authenticated.Username)
+	assert.NotNil(t, authenticated.LastUsedAt)
+
+	// the usage is written at most once per interval.
+	_, err = srv.Authenticate(ctx, token.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, tokens.usages)
+
+	_, err = srv.Authenticate(ctx, auth.AccessTokenPrefix+"unknown")
+	assert.True(t, errors.IsCode(err, code.ErrAccessTokenInvalid))
+
+	_, err = srv.Authenticate(ctx, strings.TrimPrefix(token.Token, auth.AccessTokenPrefix))
+	assert.True(t, errors.IsCode(err, code.ErrAccessTokenInvalid))
+
+	users["colin"].Status = model.UserStatusSuspended
+	_, err = srv.Authenticate(ctx, token.Token)
+	assert.True(t, errors.IsCode(err, code.ErrUserSuspended))
+}
+
+func TestAccessTokenExpiredAndRevoked(t *testing.T) {
+	stores := testStores{"AccessTokens": newMemoryAccessTokens(), "Users": activeUsers("colin")}
+	srv := newTestService(stores).AccessTokens()
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(-time.Second)
+	expired := &model.AccessToken{Name: "expired", Username: "colin", ExpiresAt: &expiresAt}
+	assert.Nil(t, srv.Create(ctx, expired))
+
+	_, err := srv.Authenticate(ctx, expired.Token)
+	assert.True(t, errors.IsCode(err, code.ErrAccessTokenInvalid))
+
+	revoked := &model.AccessToken{Name: "revoked", Username: "colin"}
+	assert.Nil(t, srv.Create(ctx, revoked))
+	assert.Nil(t, srv.Delete(ctx, "colin", "revoked"))
+
+	_, err = srv.Authenticate(ctx, revoked.Token)
+	assert.True(t, errors.IsCode(err, code.ErrAccessTokenInvalid))
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
+)
+
+// AuditLogSrv defines functions used to query the audit log.
+type AuditLogSrv interface {
+	Get(ctx context.Context, id uint64) (*model.AuditLog, error)
+	List(ctx context.Context, opts model.AuditLogListOptions) (*model.AuditLogList, error)
+}
+
+type auditLogService struct {
+	store store.Factory
+}
+
+var _ AuditLogSrv = (*auditLogService)(nil)
+
+func newAuditLogs(srv *service) *auditLogService {
+	return &auditLogService{store: srv.store}
+}
+
+func (a *auditLogService) Get(ctx context.Context, id uint64) (*model.AuditLog, error) {
+	return a.store.AuditLogs().Get(ctx, id)
+}
+
+func (a *auditLogService) List(ctx context.Context, opts model.AuditLogListOptions) (*model.AuditLogList, error) {
+	return a.store.AuditLogs().List(ctx, opts)
+}
+
+// snapshot returns the resource read by get before the request of ctx changes
+// it, so that the change can be audited. It returns nil when the request is not
+// audited or the resource can not be read.
+func snapshot(ctx context.Context, get func() (interface{}, error)) interface{} {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+
+	obj, err := get()
+	if err != nil {
+		return nil
+	}
+
+	return obj
+}
@@ -0,0 +1,377 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	"github.com/marmotedu/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+const (
+	backupAlgorithm = "AES-256-GCM"
+	backupKDF       = "scrypt"
+	// backupCheck is encrypted in the header of the encrypted archives, to
+	// reject a wrong passphrase before anything is restored.
+	backupCheck = "iam-backup"
+)
+
+// BackupSrv defines functions used to back up and restore a tenant.
+type BackupSrv interface {
+	// Backup writes an archive of the users, secrets, policies and items of the
+	// tenant of the request to w. The keys of the secrets are encrypted with
+	// the passphrase, unless it is empty.
+	Backup(ctx context.Context, passphrase string, w io.Writer) error
+	// Restore restores an archive into the tenant of the request, in one
+	// transaction. The resources which already exist are handled according to
+	// the conflict policy.
+	Restore(ctx context.Context, conflict, passphrase string, r io.Reader) (*model.RestoreResult, error)
+}
+
+type backupService struct {
+	store store.Factory
+}
+
+var _ BackupSrv = (*backupService)(nil)
+
+func newBackups(srv *service) *backupService {
+	return &backupService{store: srv.store}
+}
+
+func (s *backupService) Backup(ctx context.Context, passphrase string, w io.Writer) error {
+	header := &model.BackupHeader{
+		Version:   model.BackupVersion,
+		Tenant:    tenant.Default,
+		CreatedAt: time.Now().UTC(),
+	}
+	if name, ok := tenant.FromContext(ctx); ok {
+		header.Tenant = name
+	}
+
+	var keys *backupCipher
+
+	if passphrase != "" {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return errors.WithCode(code.ErrUnknown, err.Error())
+		}
+
+		var err error
+		if keys, err = newBackupCipher(passphrase, salt); err != nil {
+			return errors.WithCode(code.ErrUnknown, err.Error())
+		}
+
+		header.Encryption = &model.BackupEncryption{
+			Algorithm: backupAlgorithm,
+			KDF:       backupKDF,
+			Salt:      salt,
+			Check:     keys.seal([]byte(backupCheck), ""),
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+
+	if err := encoder.Encode(&model.BackupRecord{Kind: model.BackupKindHeader, Header: header}); err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+
+	err := s.store.Backups().Export(ctx, func(obj interface{}) error {
+		kind, object, err := backupObject(obj, keys)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+
+		counts[kind]++
+
+		return encoder.Encode(&model.BackupRecord{Kind: kind, Object: data})
+	})
+	if err != nil {
+		return err
+	}
+
+	trailer := &model.BackupTrailer{Counts: counts}
+	if err := encoder.Encode(&model.BackupRecord{Kind: model.BackupKindTrailer, Trailer: trailer}); err != nil {
+		return err
+	}
+
+	return buf.Flush()
+}
+
+// backupObject returns the kind and the archived form of a resource exported
+// by the store.
+func backupObject(obj interface{}, keys *backupCipher) (string, interface{}, error) {
+	switch obj := obj.(type) {
+	case *v1.User:
+		return model.BackupKindUser, obj, nil
+	case *model.Secret:
+		// the keys are encrypted in a copy, the store may still use obj.
+		copied := *obj
+		secret := &model.BackupSecret{Secret: &copied, PreviousSecretKey: obj.PreviousKey}
+		if keys != nil {
+			secret.SecretKey = keys.sealString(obj.SecretKey, obj.SecretID)
+			secret.PreviousSecretKey = keys.sealString(obj.PreviousKey, obj.SecretID)
+		}
+
+		return model.BackupKindSecret, secret, nil
+	case *v1.Policy:
+		return model.BackupKindPolicy, obj, nil
+	case *model.BackupItem:
+		return model.BackupKindItem, obj, nil
+	default:
+		return "", nil, fmt.Errorf("unexpected %T exported by the store", obj)
+	}
+}
+
+func (s *backupService) Restore(
+	ctx context.Context,
+	conflict, passphrase string,
+	r io.Reader,
+) (*model.RestoreResult, error) {
+	switch conflict {
+	case model.ConflictSkip, model.ConflictOverwrite, model.ConflictFail:
+	default:
+		return nil, errors.WithCode(code.ErrValidation,
+			"conflict must be %s, %s or %s", model.ConflictSkip, model.ConflictOverwrite, model.ConflictFail)
+	}
+
+	archive := &backupReader{decoder: json.NewDecoder(r), counts: map[string]int{}}
+	if err := archive.readHeader(passphrase); err != nil {
+		return nil, err
+	}
+
+	return s.store.Backups().Restore(ctx, conflict, archive.next)
+}
+
+// backupReader reads the records of an archive after its header.
+type backupReader struct {
+	decoder *json.Decoder
+	keys    *backupCipher
+	counts  map[string]int
+}
+
+// readHeader reads the header of the archive and checks the passphrase of the
+// encrypted ones.
+func (a *backupReader) readHeader(passphrase string) error {
+	var record model.BackupRecord
+	if err := a.decoder.Decode(&record); err != nil || record.Kind != model.BackupKindHeader || record.Header == nil {
+		return errors.WithCode(code.ErrBackupArchive, "the archive does not start with a header")
+	}
+
+	header := record.Header
+	if header.Version < 1 || header.Version > model.BackupVersion {
+		return errors.WithCode(code.ErrBackupArchive, "unsupported archive version %d, expected at most %d",
+			header.Version, model.BackupVersion)
+	}
+
+	encryption := header.Encryption
+	if encryption == nil {
+		return nil
+	}
+
+	if encryption.Algorithm != backupAlgorithm || encryption.KDF != backupKDF {
+		return errors.WithCode(code.ErrBackupArchive, "unsupported encryption %s with %s",
+			encryption.Algorithm, encryption.KDF)
+	}
+
+	if passphrase == "" {
+		return errors.WithCode(code.ErrBackupPassphrase, "the archive is encrypted, a passphrase is required")
+	}
+
+	keys, err := newBackupCipher(passphrase, encryption.Salt)
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	if check, err := keys.open(encryption.Check, ""); err != nil || string(check) != backupCheck {
+		return errors.WithCode(code.ErrBackupPassphrase, "wrong passphrase")
+	}
+
+	a.keys = keys
+
+	return nil
+}
+
+// next returns the next resource of the archive, or io.EOF after the trailer.
+func (a *backupReader) next() (interface{}, error) {
+	var record model.BackupRecord
+	if err := a.decoder.Decode(&record); err != nil {
+		if err == io.EOF {
+			return nil, errors.WithCode(code.ErrBackupArchive, "the archive is cut, its trailer is missing")
+		}
+
+		return nil, errors.WithCode(code.ErrBackupArchive, err.Error())
+	}
+
+	if record.Kind == model.BackupKindTrailer {
+		return nil, a.checkTrailer(record.Trailer)
+	}
+
+	obj, err := a.decode(&record)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrBackupArchive, "%s #%d: %s", record.Kind, a.counts[record.Kind], err.Error())
+	}
+
+	a.counts[record.Kind]++
+
+	return obj, nil
+}
+
+// decode decodes the object of a record into the form restored by the store.
+func (a *backupReader) decode(record *model.BackupRecord) (interface{}, error) {
+	switch record.Kind {
+	case model.BackupKindUser:
+		user := &v1.User{}
+
+		return user, json.Unmarshal(record.Object, user)
+	case model.BackupKindSecret:
+		secret := &model.BackupSecret{Secret: &model.Secret{}}
+		if err := json.Unmarshal(record.Object, secret); err != nil {
+			return nil, err
+		}
+
+		secret.PreviousKey = secret.PreviousSecretKey
+		if a.keys != nil {
+			var err error
+			if secret.SecretKey, err = a.keys.openString(secret.SecretKey, secret.SecretID); err != nil {
+				return nil, err
+			}
+
+			if secret.PreviousKey, err = a.keys.openString(secret.PreviousKey, secret.SecretID); err != nil {
+				return nil, err
+			}
+		}
+
+		return secret.Secret, nil
+	case model.BackupKindPolicy:
+		policy := &v1.Policy{}
+
+		return policy, json.Unmarshal(record.Object, policy)
+	case model.BackupKindItem:
+		item := &model.BackupItem{Item: &itemv1.Item{}}
+
+		return item, json.Unmarshal(record.Object, item)
+	default:
+		return nil, fmt.Errorf("unknown kind")
+	}
+}
+
+// checkTrailer makes sure nothing is missing from the archive, and nothing
+// follows the trailer. It returns io.EOF when the archive is complete.
+func (a *backupReader) checkTrailer(trailer *model.BackupTrailer) error {
+	if trailer == nil {
+		return errors.WithCode(code.ErrBackupArchive, "empty trailer")
+	}
+
+	for _, kind := range []string{
+		model.BackupKindUser, model.BackupKindSecret, model.BackupKindPolicy, model.BackupKindItem,
+	} {
+		if a.counts[kind] != trailer.Counts[kind] {
+			return errors.WithCode(code.ErrBackupArchive, "the archive holds %d records of kind %s, its trailer %d",
+				a.counts[kind], kind, trailer.Counts[kind])
+		}
+	}
+
+	if a.decoder.More() {
+		return errors.WithCode(code.ErrBackupArchive, "unexpected records after the trailer")
+	}
+
+	return io.EOF
+}
+
+// backupCipher encrypts the keys of the secrets with a key derived from the
+// passphrase of the archive.
+type backupCipher struct {
+	aead cipher.AEAD
+}
+
+func newBackupCipher(passphrase string, salt []byte) (*backupCipher, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backupCipher{aead: aead}, nil
+}
+
+// seal encrypts the plaintext, bound to the additional data, and returns the
+// nonce followed by the ciphertext.
+func (c *backupCipher) seal(plaintext []byte, additional string) []byte {
+	nonce := make([]byte, c.aead.NonceSize())
+	// crypto/rand only fails when the system has no entropy source.
+	_, _ = rand.Read(nonce)
+
+	return c.aead.Seal(nonce, nonce, plaintext, []byte(additional))
+}
+
+func (c *backupCipher) open(sealed []byte, additional string) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	return c.aead.Open(nil, sealed[:size], sealed[size:], []byte(additional))
+}
+
+// sealString encrypts a key into base64, the empty keys are left empty.
+func (c *backupCipher) sealString(key, additional string) string {
+	if key == "" {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(c.seal([]byte(key), additional))
+}
+
+func (c *backupCipher) openString(sealed, additional string) (string, error) {
+	if sealed == "" {
+		return "", nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := c.open(data, additional)
+	if err != nil {
+		return "", fmt.Errorf("decrypt the key of secret: %w", err)
+	}
+
+	return string(key), nil
+}
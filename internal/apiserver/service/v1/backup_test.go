@@ -0,0 +1,114 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+// backupFixture returns one resource of each kind, with the credentials which
+// must survive a backup.
+func backupFixture() []interface{} {
+	return []interface{}{
+		&v1.User{ObjectMeta: metav1.ObjectMeta{Name: "alice"}, Nickname: "Alice", Password: "$2a$10$hash"},
+		&model.Secret{
+			Secret: v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ci"},
+				Username:   "alice",
+				SecretID:   "id-1",
+				SecretKey:  "key-1",
+			},
+			PreviousKey: "key-0",
+		},
+		&v1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "read"}, Username: "alice"},
+		&model.BackupItem{
+			Item:   &itemv1.Item{ASIN: "B000000001"},
+			Offers: []*itemv1.ItemOfferByMarketplace{{MarketplaceID: "US"}},
+		},
+	}
+}
+
+func backupArchive(t *testing.T, srv BackupSrv, passphrase string) string {
+	var buf bytes.Buffer
+
+	ctx := tenant.NewContext(context.Background(), "shop")
+	assert.Nil(t, srv.Backup(ctx, passphrase, &buf))
+
+	return buf.String()
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		backups := &memoryBackups{objects: backupFixture()}
+		srv := newTestService(testStores{"Backups": backups}).Backups()
+
+		archive := backupArchive(t, srv, passphrase)
+		assert.Equal(t, passphrase == "", strings.Contains(archive, "key-1"))
+		assert.Contains(t, archive, `"tenant":"shop"`)
+		assert.Contains(t, archive, `"counts":{"Item":1,"Policy":1,"Secret":1,"User":1}`)
+
+		_, err := srv.Restore(context.Background(), model.ConflictFail, passphrase, strings.NewReader(archive))
+		assert.Nil(t, err)
+		assert.Len(t, backups.restored, 4)
+		assert.Equal(t, "alice", backups.restored[0].(*v1.User).Name)
+		assert.Equal(t, "$2a$10$hash", backups.restored[0].(*v1.User).Password)
+
+		secret := backups.restored[1].(*model.Secret)
+		assert.Equal(t, "key-1", secret.SecretKey)
+		assert.Equal(t, "key-0", secret.PreviousKey)
+		assert.Equal(t, "key-1", backups.objects[1].(*model.Secret).SecretKey)
+
+		assert.Equal(t, "read", backups.restored[2].(*v1.Policy).Name)
+		assert.Equal(t, backups.objects[3], backups.restored[3])
+	}
+}
+
+func TestRestorePassphrase(t *testing.T) {
+	srv := newTestService(testStores{"Backups": &memoryBackups{objects: backupFixture()}}).Backups()
+	archive := backupArchive(t, srv, "correct horse")
+
+	for _, passphrase := range []string{"", "wrong"} {
+		_, err := srv.Restore(context.Background(), model.ConflictFail, passphrase, strings.NewReader(archive))
+		assert.True(t, errors.IsCode(err, code.ErrBackupPassphrase), "%q: %v", passphrase, err)
+	}
+}
+
+func TestRestoreInvalidArchive(t *testing.T) {
+	srv := newTestService(testStores{"Backups": &memoryBackups{objects: backupFixture()}}).Backups()
+	archive := backupArchive(t, srv, "")
+	lines := strings.SplitAfter(archive, "\n")
+
+	invalid := map[string]string{
+		"empty":       "",
+		"no header":   strings.Join(lines[1:], ""),
+		"cut":         strings.Join(lines[:3], ""),
+		"no record":   lines[0] + lines[len(lines)-2],
+		"after":       archive + lines[1],
+		"version":     strings.Replace(archive, `"version":1`, `"version":2`, 1),
+		"unknown":     strings.Replace(archive, `"kind":"Policy"`, `"kind":"Group"`, 1),
+		"bad object":  strings.Replace(archive, `"kind":"User","object":{`, `"kind":"User","object":{"status":"active",`, 1),
+		"not archive": "name,title\n",
+	}
+	for name, src := range invalid {
+		_, err := srv.Restore(context.Background(), model.ConflictFail, "", strings.NewReader(src))
+		assert.True(t, errors.IsCode(err, code.ErrBackupArchive), "%s: %v", name, err)
+	}
+
+	_, err := srv.Restore(context.Background(), "replace", "", strings.NewReader(archive))
+	assert.True(t, errors.IsCode(err, code.ErrValidation))
+}
@@ -0,0 +1,392 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+const (
+	// importBatchSize is the number of rows upserted in one transaction.
+	importBatchSize = 100
+	// maxImportErrors is the number of rejected rows recorded per job, the
+	// next ones are only counted.
+	maxImportErrors = 1000
+	// exportBatchSize is the number of items read at once by the export.
+	exportBatchSize = 500
+	// staleImportJobAge is how long an unfinished job can go without progress
+	// before it is considered interrupted, e.g. by a restart of iam-apiserver.
+	staleImportJobAge = 10 * time.Minute
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CatalogSrv defines functions used to import and export the catalog.
+type CatalogSrv interface {
+	// Import starts a job which imports the catalog file in the background,
+	// src is fully read before Import returns.
+	Import(ctx context.Context, format string, src io.Reader) (*v1.ImportJob, error)
+	GetImportJob(ctx context.Context, id uint64) (*v1.ImportJob, error)
+	ListImportErrors(ctx context.Context, id uint64, opts metav1.ListOptions) (*v1.ImportRowErrorList, error)
+	// Export writes the items selected by opts to w, in the catalog file format.
+	Export(ctx context.Context, format string, opts metav1.ListOptions, w io.Writer) error
+	// FailStaleImportJobs marks failed the jobs of all the tenants which were
+	// interrupted, they would be reported as running for ever.
+	FailStaleImportJobs(ctx context.Context) error
+}
+
+type catalogService struct {
+	store store.Factory
+}
+
+var _ CatalogSrv = (*catalogService)(nil)
+
+func newCatalog(srv *service) *catalogService {
+	return &catalogService{store: srv.store}
+}
+
+func (s *catalogService) Import(ctx context.Context, format string, src io.Reader) (*v1.ImportJob, error) {
+	if format != v1.CatalogFormatCSV && format != v1.CatalogFormatJSONL {
+		return nil, errors.WithCode(code.ErrCatalogFormat, "unsupported catalog format %q", format)
+	}
+
+	// the file is spooled, so that the job does not depend on the request.
+	file, err := ioutil.TempFile("", "iam-catalog-import-*")
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	if _, err := io.Copy(file, src); err != nil {
+		closeAndRemove(file)
+
+		return nil, errors.WithCode(code.ErrBind, err.Error())
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		closeAndRemove(file)
+
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	job := &v1.ImportJob{
+		Username: operatorOf(ctx),
+		Format:   format,
+		Status:   v1.ImportJobPending,
+	}
+	if err := s.store.Catalog().CreateImportJob(ctx, job); err != nil {
+		closeAndRemove(file)
+
+		return nil, err
+	}
+
+	created := *job
+	go s.runImport(tenant.NewContext(context.Background(), job.Tenant), job, file)
+
+	return &created, nil
+}
+
+func closeAndRemove(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// importRun holds the state of a running import job.
+type importRun struct {
+	ctx    context.Context
+	store  store.CatalogStore
+	job    *v1.ImportJob
+	batch  []*v1.CatalogRow
+	lines  []int64
+	errors []*v1.ImportRowError
+}
+
+func (s *catalogService) runImport(ctx context.Context, job *v1.ImportJob, file *os.File) {
+	defer closeAndRemove(file)
+
+	run := &importRun{ctx: ctx, store: s.store.Catalog(), job: job}
+	job.Status = v1.ImportJobRunning
+	run.save()
+
+	if err := run.read(file); err != nil {
+		job.Status = v1.ImportJobFailed
+		job.Message = err.Error()
+	} else {
+		job.Status = v1.ImportJobSucceeded
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	run.save()
+
+	log.Infof("catalog import job %d %s: %d rows, %d created, %d updated, %d failed",
+		job.ID, job.Status, job.ProcessedRows, job.CreatedRows, job.UpdatedRows, job.FailedRows)
+}
+
+// read imports the rows of file, it only fails when the file can not be read
+// any further.
+func (r *importRun) read(file io.Reader) error {
+	reader, err := newCatalogReader(r.job.Format, file)
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, line, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			r.job.ProcessedRows++
+			r.reject(line, "", rowErr.err)
+
+			continue
+		}
+
+		if err != nil {
+			r.flush()
+
+			return err
+		}
+
+		r.job.ProcessedRows++
+
+		if err := validateCatalogRow(row); err != nil {
+			r.reject(line, row.Key(), err)
+
+			continue
+		}
+
+		r.batch = append(r.batch, row)
+		r.lines = append(r.lines, line)
+
+		if len(r.batch) == importBatchSize {
+			r.flush()
+		}
+	}
+
+	r.flush()
+
+	return nil
+}
+
+func (r *importRun) reject(line int64, key string, err error) {
+	r.job.FailedRows++
+
+	if r.job.FailedRows <= maxImportErrors {
+		r.errors = append(r.errors, &v1.ImportRowError{JobID: r.job.ID, Row: line, Key: key, Message: err.Error()})
+	}
+}
+
+// flush upserts the pending rows. When the batch fails, its rows are upserted
+// one by one to tell the failing rows apart.
+func (r *importRun) flush() {
+	if len(r.batch) > 0 {
+		created, updated, err := r.store.Upsert(r.ctx, r.batch)
+		if err != nil {
+			created, updated = 0, 0
+
+			for i, row := range r.batch {
+				c, u, err := r.store.Upsert(r.ctx, []*v1.CatalogRow{row})
+				if err != nil {
+					r.reject(r.lines[i], row.Key(), err)
+
+					continue
+				}
+
+				created += c
+				updated += u
+			}
+		}
+
+		r.job.CreatedRows += int64(created)
+		r.job.UpdatedRows += int64(updated)
+		r.batch, r.lines = r.batch[:0], r.lines[:0]
+	}
+
+	if err := r.store.AddImportErrors(r.ctx, r.errors); err != nil {
+		log.Warnf("record the rejected rows of catalog import job %d failed: %s", r.job.ID, err.Error())
+	}
+
+	r.errors = r.errors[:0]
+	r.save()
+}
+
+func (r *importRun) save() {
+	if err := r.store.UpdateImportJob(r.ctx, r.job); err != nil {
+		log.Warnf("record the progress of catalog import job %d failed: %s", r.job.ID, err.Error())
+	}
+}
+
+// validateCatalogRow checks a row before it is upserted.
+func validateCatalogRow(row *v1.CatalogRow) error {
+	var problems []string
+
+	if row.ASIN == "" && row.SKU == "" {
+		problems = append(problems, "asin or sku is required")
+	}
+
+	if len(row.ASIN) > 10 {
+		problems = append(problems, "asin must not be longer than 10 characters")
+	}
+
+	if row.Status != nil && *row.Status != 0 && *row.Status != 1 {
+		problems = append(problems, "status must be 0 or 1")
+	}
+
+	if a := row.Attributes; a != nil {
+		for _, value := range []float64{
+			a.ItemHeight, a.ItemLength, a.ItemWidth, a.ItemWeight,
+			a.PackageHeight, a.PackageLength, a.PackageWidth, a.PackageWeight,
+		} {
+			if value < 0 {
+				problems = append(problems, "dimensions and weights must not be negative")
+
+				break
+			}
+		}
+
+		if a.ReleaseDate != "" {
+			if _, err := time.Parse("2006-01-02", a.ReleaseDate); err != nil {
+				problems = append(problems, "release_date must be formatted as 2006-01-02")
+			}
+		}
+	}
+
+	marketplaces := make(map[string]bool, len(row.Offers))
+	for _, offer := range row.Offers {
+		switch {
+		case offer.MarketplaceID == "":
+			problems = append(problems, "marketplace_id of the offer is required")
+		case marketplaces[offer.MarketplaceID]:
+			problems = append(problems, fmt.Sprintf("marketplace %s has several offers", offer.MarketplaceID))
+		}
+
+		marketplaces[offer.MarketplaceID] = true
+
+		if offer.ListPrice < 0 || offer.PackageQuantity < 0 {
+			problems = append(problems, "list_price and package_quantity must not be negative")
+		}
+
+		if offer.CurrencyCode != "" && !currencyCode.MatchString(offer.CurrencyCode) {
+			problems = append(problems, "currency_code must be an ISO 4217 code, e.g. USD")
+		}
+	}
+
+	for _, image := range row.Images {
+		u, err := url.Parse(image)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("image %s is not an http or https URL", image))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func (s *catalogService) FailStaleImportJobs(ctx context.Context) error {
+	failed, err := s.store.Catalog().FailStaleImportJobs(ctx, time.Now().Add(-staleImportJobAge), "import interrupted")
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		log.Warnf("%d interrupted catalog import jobs marked failed", failed)
+	}
+
+	return nil
+}
+
+func (s *catalogService) GetImportJob(ctx context.Context, id uint64) (*v1.ImportJob, error) {
+	return s.store.Catalog().GetImportJob(ctx, id)
+}
+
+func (s *catalogService) ListImportErrors(
+	ctx context.Context,
+	id uint64,
+	opts metav1.ListOptions,
+) (*v1.ImportRowErrorList, error) {
+	// the job is read first, so that only the jobs of the tenant are visible.
+	if _, err := s.store.Catalog().GetImportJob(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.store.Catalog().ListImportErrors(ctx, id, opts)
+}
+
+func (s *catalogService) Export(ctx context.Context, format string, opts metav1.ListOptions, w io.Writer) error {
+	writer, err := newCatalogWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	var offset, remaining int64 = 0, -1
+	if opts.Offset != nil {
+		offset = *opts.Offset
+	}
+
+	if opts.Limit != nil {
+		remaining = *opts.Limit
+	}
+
+	for remaining != 0 {
+		limit := int64(exportBatchSize)
+		if remaining > 0 && remaining < limit {
+			limit = remaining
+		}
+
+		rows, err := s.store.Catalog().Export(ctx, metav1.ListOptions{
+			FieldSelector: opts.FieldSelector,
+			Offset:        &offset,
+			Limit:         &limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+
+		// every page is sent at once, the export is streamed to the client.
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+
+		if int64(len(rows)) < limit {
+			break
+		}
+
+		offset += limit
+		if remaining > 0 {
+			remaining -= limit
+		}
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,411 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/marmotedu/errors"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+// maxCatalogLine is the longest JSONL row accepted by the import.
+const maxCatalogLine = 1 << 20
+
+// imageSeparator separates the image URLs in the images column of the CSV files.
+const imageSeparator = "|"
+
+// rowError reports a row which can not be decoded, the next rows can still be read.
+type rowError struct {
+	line int64
+	err  error
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.err.Error())
+}
+
+// catalogReader reads the rows of a catalog file.
+type catalogReader interface {
+	// Read returns the next row and its line number, io.EOF at the end of the
+	// file. A *rowError is returned for a row which can not be decoded.
+	Read() (*v1.CatalogRow, int64, error)
+}
+
+// catalogWriter writes the rows of a catalog file.
+type catalogWriter interface {
+	Write(row *v1.CatalogRow) error
+	Flush() error
+}
+
+// newCatalogReader returns the reader of the catalog file format.
+func newCatalogReader(format string, r io.Reader) (catalogReader, error) {
+	switch format {
+	case v1.CatalogFormatCSV:
+		return newCSVCatalogReader(r)
+	case v1.CatalogFormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxCatalogLine)
+
+		return &jsonlCatalogReader{scanner: scanner}, nil
+	default:
+		return nil, errors.WithCode(code.ErrCatalogFormat, "unsupported catalog format %q", format)
+	}
+}
+
+// newCatalogWriter returns the writer of the catalog file format.
+func newCatalogWriter(format string, w io.Writer) (catalogWriter, error) {
+	switch format {
+	case v1.CatalogFormatCSV:
+		writer := &csvCatalogWriter{writer: csv.NewWriter(w)}
+
+		return writer, writer.writeHeader()
+	case v1.CatalogFormatJSONL:
+		buffered := bufio.NewWriter(w)
+
+		return &jsonlCatalogWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+	default:
+		return nil, errors.WithCode(code.ErrCatalogFormat, "unsupported catalog format %q", format)
+	}
+}
+
+type jsonlCatalogReader struct {
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func (r *jsonlCatalogReader) Read() (*v1.CatalogRow, int64, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		// unknown fields are refused, they are most likely typos.
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		row := &v1.CatalogRow{}
+		if err := decoder.Decode(row); err != nil {
+			return nil, r.line, &rowError{line: r.line, err: err}
+		}
+
+		return row, r.line, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, r.line + 1, fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+
+	return nil, r.line, io.EOF
+}
+
+type jsonlCatalogWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (w *jsonlCatalogWriter) Write(row *v1.CatalogRow) error {
+	return w.encoder.Encode(row)
+}
+
+func (w *jsonlCatalogWriter) Flush() error {
+	return w.buffered.Flush()
+}
+
+// catalogColumn is a column of the CSV files. The offer is the single offer a
+// CSV line can hold, the rows of an item with several offers are repeated.
+type catalogColumn struct {
+	name string
+	get  func(row *v1.CatalogRow, offer *v1.CatalogOffer) string
+	// set is only called with non empty values.
+	set func(row *v1.CatalogRow, offer *v1.CatalogOffer, value string) error
+	// offer tells whether the column is a field of the offer.
+	offer bool
+}
+
+func stringColumn(name string, field func(*v1.CatalogRow) *string) catalogColumn {
+	return catalogColumn{
+		name: name,
+		get: func(row *v1.CatalogRow, _ *v1.CatalogOffer) string {
+			return *field(row)
+		},
+		set: func(row *v1.CatalogRow, _ *v1.CatalogOffer, value string) error {
+			*field(row) = value
+
+			return nil
+		},
+	}
+}
+
+func attributeColumn(name string, field func(*v1.CatalogAttributes) interface{}) catalogColumn {
+	return catalogColumn{
+		name: name,
+		get: func(row *v1.CatalogRow, _ *v1.CatalogOffer) string {
+			if row.Attributes == nil {
+				return ""
+			}
+
+			return formatField(field(row.Attributes))
+		},
+		set: func(row *v1.CatalogRow, _ *v1.CatalogOffer, value string) error {
+			if row.Attributes == nil {
+				row.Attributes = &v1.CatalogAttributes{}
+			}
+
+			return parseField(field(row.Attributes), value)
+		},
+	}
+}
+
+func offerColumn(name string, field func(*v1.CatalogOffer) interface{}) catalogColumn {
+	return catalogColumn{
+		name:  name,
+		offer: true,
+		get: func(_ *v1.CatalogRow, offer *v1.CatalogOffer) string {
+			if offer == nil {
+				return ""
+			}
+
+			return formatField(field(offer))
+		},
+		set: func(_ *v1.CatalogRow, offer *v1.CatalogOffer, value string) error {
+			return parseField(field(offer), value)
+		},
+	}
+}
+
+// formatField formats a field of the attributes or of an offer, the zero
+// values are left empty like the omitted fields of the JSONL rows.
+func formatField(field interface{}) string {
+	switch f := field.(type) {
+	case *string:
+		return *f
+	case *float64:
+		if *f == 0 {
+			return ""
+		}
+
+		return strconv.FormatFloat(*f, 'f', -1, 64)
+	case *int:
+		if *f == 0 {
+			return ""
+		}
+
+		return strconv.Itoa(*f)
+	default:
+		panic(fmt.Sprintf("unsupported catalog field %T", field))
+	}
+}
+
+func parseField(field interface{}, value string) error {
+	var err error
+
+	switch f := field.(type) {
+	case *string:
+		*f = value
+	case *float64:
+		*f, err = strconv.ParseFloat(value, 64)
+	case *int:
+		*f, err = strconv.Atoi(value)
+	default:
+		panic(fmt.Sprintf("unsupported catalog field %T", field))
+	}
+
+	return err
+}
+
+// catalogColumns are the columns of the CSV files, in the order of the export.
+var catalogColumns = []catalogColumn{
+	stringColumn("asin", func(r *v1.CatalogRow) *string { return &r.ASIN }),
+	stringColumn("sku", func(r *v1.CatalogRow) *string { return &r.SKU }),
+	stringColumn("brand", func(r *v1.CatalogRow) *string { return &r.Brand }),
+	stringColumn("title", func(r *v1.CatalogRow) *string { return &r.Title }),
+	stringColumn("product_group", func(r *v1.CatalogRow) *string { return &r.ProductGroup }),
+	stringColumn("product_type", func(r *v1.CatalogRow) *string { return &r.ProductType }),
+	{
+		name: "status",
+		get: func(row *v1.CatalogRow, _ *v1.CatalogOffer) string {
+			if row.Status == nil {
+				return ""
+			}
+
+			return strconv.Itoa(*row.Status)
+		},
+		set: func(row *v1.CatalogRow, _ *v1.CatalogOffer, value string) error {
+			status, err := strconv.Atoi(value)
+			row.Status = &status
+
+			return err
+		},
+	},
+	attributeColumn("binding", func(a *v1.CatalogAttributes) interface{} { return &a.Binding }),
+	attributeColumn("item_height", func(a *v1.CatalogAttributes) interface{} { return &a.ItemHeight }),
+	attributeColumn("item_length", func(a *v1.CatalogAttributes) interface{} { return &a.ItemLength }),
+	attributeColumn("item_width", func(a *v1.CatalogAttributes) interface{} { return &a.ItemWidth }),
+	attributeColumn("item_weight", func(a *v1.CatalogAttributes) interface{} { return &a.ItemWeight }),
+	attributeColumn("item_dimensions_unit", func(a *v1.CatalogAttributes) interface{} { return &a.ItemDimensionsUnit }),
+	attributeColumn("package_height", func(a *v1.CatalogAttributes) interface{} { return &a.PackageHeight }),
+	attributeColumn("package_length", func(a *v1.CatalogAttributes) interface{} { return &a.PackageLength }),
+	attributeColumn("package_width", func(a *v1.CatalogAttributes) interface{} { return &a.PackageWidth }),
+	attributeColumn("package_weight", func(a *v1.CatalogAttributes) interface{} { return &a.PackageWeight }),
+	attributeColumn("package_dimensions_unit",
+		func(a *v1.CatalogAttributes) interface{} { return &a.PackageDimensionsUnit }),
+	attributeColumn("release_date", func(a *v1.CatalogAttributes) interface{} { return &a.ReleaseDate }),
+	offerColumn("marketplace_id", func(o *v1.CatalogOffer) interface{} { return &o.MarketplaceID }),
+	offerColumn("list_price", func(o *v1.CatalogOffer) interface{} { return &o.ListPrice }),
+	offerColumn("currency_code", func(o *v1.CatalogOffer) interface{} { return &o.CurrencyCode }),
+	offerColumn("package_quantity", func(o *v1.CatalogOffer) interface{} { return &o.PackageQuantity }),
+	offerColumn("availability_status", func(o *v1.CatalogOffer) interface{} { return &o.AvailabilityStatus }),
+	offerColumn("fulfillment_channel", func(o *v1.CatalogOffer) interface{} { return &o.FulfillmentChannel }),
+	{
+		name: "images",
+		get: func(row *v1.CatalogRow, _ *v1.CatalogOffer) string {
+			return strings.Join(row.Images, imageSeparator)
+		},
+		set: func(row *v1.CatalogRow, _ *v1.CatalogOffer, value string) error {
+			for _, url := range strings.Split(value, imageSeparator) {
+				if url = strings.TrimSpace(url); url != "" {
+					row.Images = append(row.Images, url)
+				}
+			}
+
+			return nil
+		},
+	},
+}
+
+type csvCatalogReader struct {
+	reader *csv.Reader
+	// columns are the columns of the file, nil for the ignored ones.
+	columns []*catalogColumn
+}
+
+func newCSVCatalogReader(r io.Reader) (*csvCatalogReader, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the header line is missing")
+		}
+
+		return nil, err
+	}
+
+	byName := make(map[string]*catalogColumn, len(catalogColumns))
+	for i := range catalogColumns {
+		byName[catalogColumns[i].name] = &catalogColumns[i]
+	}
+
+	columns := make([]*catalogColumn, 0, len(header))
+	hasKey := false
+
+	for _, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q in the header line", name)
+		}
+
+		columns = append(columns, column)
+		hasKey = hasKey || name == "asin" || name == "sku"
+	}
+
+	if !hasKey {
+		return nil, fmt.Errorf("the header line must have an asin or a sku column")
+	}
+
+	return &csvCatalogReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvCatalogReader) Read() (*v1.CatalogRow, int64, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, int64(parseErr.StartLine), &rowError{line: int64(parseErr.StartLine), err: parseErr.Err}
+		}
+
+		return nil, 0, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	row := &v1.CatalogRow{}
+	offer := &v1.CatalogOffer{}
+	hasOffer := false
+
+	for i, value := range record {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+
+		column := r.columns[i]
+		if err := column.set(row, offer, value); err != nil {
+			return nil, int64(line), &rowError{line: int64(line), err: fmt.Errorf("column %s: %w", column.name, err)}
+		}
+
+		hasOffer = hasOffer || column.offer
+	}
+
+	if hasOffer {
+		row.Offers = []*v1.CatalogOffer{offer}
+	}
+
+	return row, int64(line), nil
+}
+
+type csvCatalogWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvCatalogWriter) writeHeader() error {
+	header := make([]string, 0, len(catalogColumns))
+	for _, column := range catalogColumns {
+		header = append(header, column.name)
+	}
+
+	return w.writer.Write(header)
+}
+
+// Write writes a line per offer of the row, or a single line if it has none.
+func (w *csvCatalogWriter) Write(row *v1.CatalogRow) error {
+	offers := row.Offers
+	if len(offers) == 0 {
+		offers = []*v1.CatalogOffer{nil}
+	}
+
+	for _, offer := range offers {
+		record := make([]string, 0, len(catalogColumns))
+		for _, column := range catalogColumns {
+			record = append(record, column.get(row, offer))
+		}
+
+		if err := w.writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *csvCatalogWriter) Flush() error {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
@@ -0,0 +1,212 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/stretchr/testify/assert"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+func catalogFixture() []*itemv1.CatalogRow {
+	status := 1
+
+	return []*itemv1.CatalogRow{
+		{
+			ASIN:   "B000000001",
+			SKU:    "shoe-1",
+			Brand:  "acme",
+			Title:  "Running shoe, blue",
+			Status: &status,
+			Attributes: &itemv1.CatalogAttributes{
+				ItemWeight:         1.5,
+				ItemDimensionsUnit: "cm",
+				ReleaseDate:        "2023-05-01",
+			},
+			Offers: []*itemv1.CatalogOffer{
+				{MarketplaceID: "US", ListPrice: 59.9, CurrencyCode: "USD", PackageQuantity: 1},
+				{MarketplaceID: "DE", ListPrice: 54.5, CurrencyCode: "EUR"},
+			},
+			Images: []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg"},
+		},
+		{
+			SKU:    "sock-1",
+			Brand:  "acme",
+			Title:  `Socks "wool"`,
+			Status: &status,
+		},
+	}
+}
+
+func TestCatalogFormatRoundTrip(t *testing.T) {
+	for _, format := range []string{itemv1.CatalogFormatCSV, itemv1.CatalogFormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			writer, err := newCatalogWriter(format, &buf)
+			assert.Nil(t, err)
+
+			for _, row := range catalogFixture() {
+				assert.Nil(t, writer.Write(row))
+			}
+
+			assert.Nil(t, writer.Flush())
+
+			reader, err := newCatalogReader(format, &buf)
+			assert.Nil(t, err)
+
+			var rows []*itemv1.CatalogRow
+
+			for {
+				row, _, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+
+				assert.Nil(t, err)
+				rows = append(rows, row)
+			}
+
+			expected := catalogFixture()
+			if format == itemv1.CatalogFormatCSV {
+				expected = splitOffers(expected)
+			}
+
+			assert.Equal(t, expected, rows)
+		})
+	}
+}
+
+// splitOffers returns the rows as read from a CSV file, with an offer per line.
+func splitOffers(rows []*itemv1.CatalogRow) []*itemv1.CatalogRow {
+	var split []*itemv1.CatalogRow
+
+	for _, row := range rows {
+		if len(row.Offers) == 0 {
+			split = append(split, row)
+
+			continue
+		}
+
+		for _, offer := range row.Offers {
+			copied := *row
+			copied.Offers = []*itemv1.CatalogOffer{offer}
+			split = append(split, &copied)
+		}
+	}
+
+	return split
+}
+
+func TestCatalogCSVRowErrors(t *testing.T) {
+	src := "sku,title,list_price\n" +
+		"sock-1,Socks,abc\n" +
+		"sock-2,Socks,\"unterminated\n"
+
+	reader, err := newCatalogReader(itemv1.CatalogFormatCSV, strings.NewReader(src))
+	assert.Nil(t, err)
+
+	_, line, err := reader.Read()
+	assert.IsType(t, &rowError{}, err)
+	assert.Equal(t, int64(2), line)
+
+	_, _, err = reader.Read()
+	assert.IsType(t, &rowError{}, err)
+
+	_, err = newCatalogReader(itemv1.CatalogFormatCSV, strings.NewReader("name,title\n"))
+	assert.NotNil(t, err)
+
+	_, err = newCatalogReader("xml", strings.NewReader(""))
+	assert.NotNil(t, err)
+}
+
+func TestValidateCatalogRow(t *testing.T) {
+	assert.Nil(t, validateCatalogRow(catalogFixture()[0]))
+
+	invalid := []*itemv1.CatalogRow{
+		{Title: "no key"},
+		{ASIN: "B0000000001234"},
+		{SKU: "a", Offers: []*itemv1.CatalogOffer{{ListPrice: 1}}},
+		{SKU: "a", Offers: []*itemv1.CatalogOffer{{MarketplaceID: "US", CurrencyCode: "dollar"}}},
+		{SKU: "a", Attributes: &itemv1.CatalogAttributes{ItemWeight: -1}},
+		{SKU: "a", Attributes: &itemv1.CatalogAttributes{ReleaseDate: "05/01/2023"}},
+		{SKU: "a", Images: []string{"file:///etc/passwd"}},
+	}
+	for _, row := range invalid {
+		assert.NotNil(t, validateCatalogRow(row), "%+v", row)
+	}
+}
+
+func TestCatalogImport(t *testing.T) {
+	catalog := newMemoryCatalog()
+	srv := newTestService(testStores{"Catalog": catalog}).Catalog()
+	catalog.rows["sock-1"] = &itemv1.CatalogRow{SKU: "sock-1"}
+
+	src := `{"asin":"B000000001","title":"Running shoe"}
+{"sku":"sock-1","title":"Socks"}
+{"title":"no key"}
+{"sku":"hat-1","brand":"broken"}
+{"sku":"cap-1","unknown":true}
+`
+	ctx := tenant.NewContext(context.Background(), "shop")
+
+	job, err := srv.Import(ctx, itemv1.CatalogFormatJSONL, strings.NewReader(src))
+	assert.Nil(t, err)
+	assert.Equal(t, itemv1.ImportJobPending, job.Status)
+	assert.Equal(t, "shop", job.Tenant)
+
+	select {
+	case job = <-catalog.finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the import job did not finish")
+	}
+
+	assert.Equal(t, itemv1.ImportJobSucceeded, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, int64(5), job.ProcessedRows)
+	assert.Equal(t, int64(1), job.CreatedRows)
+	assert.Equal(t, int64(1), job.UpdatedRows)
+	assert.Equal(t, int64(3), job.FailedRows)
+
+	rowErrors, err := srv.ListImportErrors(ctx, job.ID, metav1.ListOptions{})
+	assert.Nil(t, err)
+
+	rows := map[int64]string{}
+	for _, rowError := range rowErrors.Items {
+		rows[rowError.Row] = rowError.Key
+	}
+
+	assert.Equal(t, map[int64]string{3: "", 4: "hat-1", 5: ""}, rows)
+	assert.Equal(t, "Running shoe", catalog.rows["B000000001"].Title)
+}
+
+func TestCatalogExport(t *testing.T) {
+	catalog := newMemoryCatalog()
+	srv := newTestService(testStores{"Catalog": catalog}).Catalog()
+
+	for i := 0; i < exportBatchSize+2; i++ {
+		sku := fmt.Sprintf("sku-%04d", i)
+		catalog.rows[sku] = &itemv1.CatalogRow{SKU: sku}
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, srv.Export(context.Background(), itemv1.CatalogFormatJSONL, metav1.ListOptions{}, &buf))
+	assert.Equal(t, exportBatchSize+2, strings.Count(buf.String(), "\n"))
+
+	limit := int64(3)
+	buf.Reset()
+	assert.Nil(t, srv.Export(context.Background(), itemv1.CatalogFormatCSV, metav1.ListOptions{Limit: &limit}, &buf))
+	assert.Equal(t, 4, strings.Count(buf.String(), "\n"))
+}
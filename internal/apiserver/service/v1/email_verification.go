@@ -0,0 +1,136 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/notifier"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// emailVerificationTTL is how long an email verification token can be used.
+const emailVerificationTTL = 24 * time.Hour
+
+// EmailVerificationSrv defines functions used to verify the emails of the users.
+type EmailVerificationSrv interface {
+	// Send sends a single-use verification token to the email of the user.
+	Send(ctx context.Context, user *v1.User) error
+	// Resend sends a new verification token to the user pending verification
+	// with the given username, or else with the given email. It succeeds for
+	// unknown users too, so that the registered accounts cannot be discovered.
+	Resend(ctx context.Context, username, email string) error
+	// Verify verifies the email the token was sent to, and activates the user
+	// if the user was pending verification.
+	Verify(ctx context.Context, token string) error
+}
+
+type emailVerificationService struct {
+	store store.Factory
+}
+
+var _ EmailVerificationSrv = (*emailVerificationService)(nil)
+
+func newEmailVerifications(srv *service) *emailVerificationService {
+	return &emailVerificationService{store: srv.store}
+}
+
+func (s *emailVerificationService) Send(ctx context.Context, user *v1.User) error {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	now := time.Now()
+	verification := &model.EmailVerification{
+		Username:  user.Name,
+		Email:     user.Email,
+		Hash:      hashOpaqueToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(emailVerificationTTL),
+	}
+	if err := s.store.EmailVerifications().Create(ctx, verification); err != nil {
+		return err
+	}
+
+	msg := &notifier.Message{
+		To:      user.Email,
+		Subject: "Verify your IAM email",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Use the token below to verify your email and activate your account, "+
+			"it can be used once until %s:\n\n%s\n\n"+
+			"If you did not create the account, you can ignore this message.",
+			user.Name, verification.ExpiresAt.Format(time.RFC1123), token),
+	}
+	if err := notifier.Client().Send(ctx, msg); err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	return nil
+}
+
+func (s *emailVerificationService) Resend(ctx context.Context, username, email string) error {
+	user, err := lookupUser(ctx, s.store, username, email)
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			log.L(ctx).Infof("email verification requested for unknown user, username: `%s`, email: `%s`",
+				username, email)
+
+			return nil
+		}
+
+		return err
+	}
+
+	if user.Status != model.UserStatusPending {
+		log.L(ctx).Infof("email verification requested for user `%s` not pending verification", user.Name)
+
+		return nil
+	}
+
+	return s.Send(ctx, user)
+}
+
+func (s *emailVerificationService) Verify(ctx context.Context, token string) error {
+	verification, err := s.store.EmailVerifications().Use(ctx, hashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.store.Users().Get(ctx, verification.Username, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			return errors.WithCode(code.ErrEmailVerificationTokenInvalid, "user %s not found", verification.Username)
+		}
+
+		return err
+	}
+
+	// the email has changed since the token was sent.
+	if user.Email != verification.Email {
+		return errors.WithCode(code.ErrEmailVerificationTokenInvalid, "email of user %s has changed", user.Name)
+	}
+
+	if user.Status != model.UserStatusPending {
+		return CheckUserActive(user)
+	}
+
+	return s.store.UserStatuses().Change(ctx, &model.UserStatusChange{
+		Username:  user.Name,
+		Status:    model.UserStatusActive,
+		Reason:    "email verified",
+		Operator:  user.Name,
+		CreatedAt: time.Now(),
+	}, model.UserStatusPending)
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"strconv"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
+)
+
+// publishUser publishes the change of the user, without the password.
+func publishUser(ctx context.Context, typ event.Type, user *v1.User) {
+	redacted := *user
+	redacted.Password = ""
+
+	event.Publish(ctx, typ, event.KindUser, user.Name, "", &redacted)
+}
+
+// publishPolicy publishes the change of the policy.
+func publishPolicy(ctx context.Context, typ event.Type, policy *v1.Policy) {
+	event.Publish(ctx, typ, event.KindPolicy, policy.Name, policy.Username, policy)
+}
+
+// publishSecret publishes the change of the secret, without its keys.
+func publishSecret(ctx context.Context, typ event.Type, secret *model.Secret) {
+	redacted := *secret
+	redacted.SecretKey, redacted.PreviousKey = "", ""
+
+	event.Publish(ctx, typ, event.KindSecret, secret.Name, secret.Username, &redacted)
+}
+
+// publishItem publishes the change of the item, the items are named by their ID.
+func publishItem(ctx context.Context, typ event.Type, item *itemv1.Item) {
+	event.Publish(ctx, typ, event.KindItem, strconv.FormatUint(item.ID, 10), "", item)
+}
+
+// publishDeletion publishes the deletion of the resource.
+func publishDeletion(ctx context.Context, kind, name, owner string) {
+	event.Publish(ctx, event.Deleted, kind, name, owner, nil)
+}
@@ -0,0 +1,507 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+// testStores holds the fakes a test needs, keyed by the store.Factory method
+// which returns them, e.g. testStores{"Users": memoryUsers{}}.
+type testStores map[string]interface{}
+
+// newTestService returns the service backed by the given fakes, the other
+// stores are not available.
+func newTestService(stores testStores) Service {
+	factory := new(store.MockFactory)
+	for method, fake := range stores {
+		factory.On(method).Return(fake)
+	}
+
+	return NewService(factory)
+}
+
+// memoryUsers implements store.UserStore in memory.
+type memoryUsers map[string]*v1.User
+
+// activeUsers returns the active users with the given names, their emails are
+// <name>@example.com.
+func activeUsers(names ...string) memoryUsers {
+	users := memoryUsers{}
+	for _, name := range names {
+		users[name] = &v1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Email:      name + "@example.com",
+			Status:     model.UserStatusActive,
+		}
+	}
+
+	return users
+}
+
+func (m memoryUsers) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
+	m[user.Name] = user
+
+	return nil
+}
+
+func (m memoryUsers) Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error {
+	m[user.Name] = user
+
+	return nil
+}
+
+func (m memoryUsers) Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error {
+	delete(m, username)
+
+	return nil
+}
+
+func (m memoryUsers) DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error {
+	for _, username := range usernames {
+		delete(m, username)
+	}
+
+	return nil
+}
+
+func (m memoryUsers) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
+	user, ok := m[username]
+	if !ok {
+		return nil, errors.WithCode(code.ErrUserNotFound, "user not found")
+	}
+
+	copied := *user
+
+	return &copied, nil
+}
+
+func (m memoryUsers) GetByEmail(ctx context.Context, email string, opts metav1.GetOptions) (*v1.User, error) {
+	for _, user := range m {
+		if user.Email == email {
+			return m.Get(ctx, user.Name, opts)
+		}
+	}
+
+	return nil, errors.WithCode(code.ErrUserNotFound, "user not found")
+}
+
+func (m memoryUsers) List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error) {
+	return &v1.UserList{}, nil
+}
+
+// memorySecrets implements store.SecretStore in memory, keyed by name.
+type memorySecrets map[string]*model.Secret
+
+func (m memorySecrets) Create(ctx context.Context, secret *model.Secret, opts metav1.CreateOptions) error {
+	m[secret.Name] = secret
+
+	return nil
+}
+
+func (m memorySecrets) Update(ctx context.Context, secret *model.Secret, opts metav1.UpdateOptions) error {
+	m[secret.Name] = secret
+
+	return nil
+}
+
+func (m memorySecrets) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	delete(m, name)
+
+	return nil
+}
+
+func (m memorySecrets) DeleteCollection(
+	ctx context.Context,
+	username string,
+	names []string,
+	opts metav1.DeleteOptions,
+) error {
+	for _, name := range names {
+		delete(m, name)
+	}
+
+	return nil
+}
+
+func (m memorySecrets) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*model.Secret, error) {
+	secret, ok := m[name]
+	if !ok || secret.Username != username {
+		return nil, errors.WithCode(code.ErrSecretNotFound, "secret not found")
+	}
+
+	copied := *secret
+
+	return &copied, nil
+}
+
+func (m memorySecrets) List(ctx context.Context, username string, opts metav1.ListOptions) (*model.SecretList, error) {
+	list := &model.SecretList{}
+	for _, secret := range m {
+		if username == "" || secret.Username == username {
+			list.Items = append(list.Items, secret)
+		}
+	}
+	list.TotalCount = int64(len(list.Items))
+
+	return list, nil
+}
+
+func (m memorySecrets) RecordUsage(ctx context.Context, lastUsed map[string]time.Time) error {
+	for _, secret := range m {
+		if usedAt, ok := lastUsed[secret.SecretID]; ok && (secret.LastUsedAt == nil || secret.LastUsedAt.Before(usedAt)) {
+			secret.LastUsedAt = &usedAt
+		}
+	}
+
+	return nil
+}
+
+// memorySessions implements store.SessionStore in memory.
+type memorySessions struct {
+	sessions map[string]*model.Session
+	tokens   map[string]*model.RefreshToken
+}
+
+func newMemorySessions() *memorySessions {
+	return &memorySessions{sessions: map[string]*model.Session{}, tokens: map[string]*model.RefreshToken{}}
+}
+
+func (m *memorySessions) Create(ctx context.Context, session *model.Session, token *model.RefreshToken) error {
+	m.sessions[session.SessionID] = session
+	m.tokens[token.Hash] = token
+
+	return nil
+}
+
+func (m *memorySessions) Get(ctx context.Context, sid string) (*model.Session, error) {
+	session, ok := m.sessions[sid]
+	if !ok {
+		return nil, errors.WithCode(code.ErrSessionNotFound, "session not found")
+	}
+
+	copied := *session
+
+	return &copied, nil
+}
+
+func (m *memorySessions) GetToken(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	token, ok := m.tokens[hash]
+	if !ok {
+		return nil, errors.WithCode(code.ErrRefreshTokenInvalid, "token not found")
+	}
+
+	copied := *token
+
+	return &copied, nil
+}
+
+func (m *memorySessions) Rotate(
+	ctx context.Context,
+	hash string,
+	token *model.RefreshToken,
+	session *model.Session,
+) error {
+	if m.tokens[hash].RotatedAt != nil {
+		return errors.WithCode(code.ErrRefreshTokenReused, "token rotated")
+	}
+
+	now := time.Now()
+	m.tokens[hash].RotatedAt = &now
+	m.tokens[token.Hash] = token
+	m.sessions[session.SessionID].ExpiresAt = session.ExpiresAt
+
+	return nil
+}
+
+func (m *memorySessions) List(ctx context.Context, username string, opts metav1.ListOptions) (*model.SessionList, error) {
+	return &model.SessionList{}, nil
+}
+
+func (m *memorySessions) Revoke(ctx context.Context, username, sid string) error {
+	now := time.Now()
+	m.sessions[sid].RevokedAt = &now
+
+	return nil
+}
+
+func (m *memorySessions) RevokeAll(ctx context.Context, username string) error {
+	now := time.Now()
+	for _, session := range m.sessions {
+		if session.Username == username && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (m *memorySessions) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memoryAccessTokens implements store.AccessTokenStore in memory, keyed by hash.
+type memoryAccessTokens struct {
+	tokens map[string]*model.AccessToken
+	usages int
+}
+
+func newMemoryAccessTokens() *memoryAccessTokens {
+	return &memoryAccessTokens{tokens: map[string]*model.AccessToken{}}
+}
+
+func (m *memoryAccessTokens) Create(ctx context.Context, token *model.AccessToken) error {
+	token.ID = uint64(len(m.tokens) + 1)
+	m.tokens[token.Hash] = token
+
+	return nil
+}
+
+func (m *memoryAccessTokens) Get(ctx context.Context, username, name string) (*model.AccessToken, error) {
+	for _, token := range m.tokens {
+		if token.Username == username && token.Name == name {
+			return token, nil
+		}
+	}
+
+	return nil, errors.WithCode(code.ErrAccessTokenNotFound, "access token not found")
+}
+
+func (m *memoryAccessTokens) GetByHash(ctx context.Context, hash string) (*model.AccessToken, error) {
+	token, ok := m.tokens[hash]
+	if !ok {
+		return nil, errors.WithCode(code.ErrAccessTokenInvalid, "access token is invalid")
+	}
+
+	copied := *token
+
+	return &copied, nil
+}
+
+func (m *memoryAccessTokens) List(
+	ctx context.Context,
+	username string,
+	opts metav1.ListOptions,
+) (*model.AccessTokenList, error) {
+	return &model.AccessTokenList{}, nil
+}
+
+func (m *memoryAccessTokens) Delete(ctx context.Context, username, name string) error {
+	for hash, token := range m.tokens {
+		if token.Username == username && token.Name == name {
+			delete(m.tokens, hash)
+
+			return nil
+		}
+	}
+
+	return errors.WithCode(code.ErrAccessTokenNotFound, "access token not found")
+}
+
+func (m *memoryAccessTokens) RecordUsage(ctx context.Context, id uint64, usedAt time.Time) error {
+	m.usages++
+
+	for _, token := range m.tokens {
+		if token.ID == id {
+			token.LastUsedAt = &usedAt
+		}
+	}
+
+	return nil
+}
+
+// memoryCatalog implements store.CatalogStore in memory, rows are keyed by
+// ASIN or SKU and the upserts of a batch fail together.
+type memoryCatalog struct {
+	mu       sync.Mutex
+	rows     map[string]*itemv1.CatalogRow
+	jobs     map[uint64]*itemv1.ImportJob
+	errors   []*itemv1.ImportRowError
+	finished chan *itemv1.ImportJob
+}
+
+func newMemoryCatalog() *memoryCatalog {
+	return &memoryCatalog{
+		rows:     map[string]*itemv1.CatalogRow{},
+		jobs:     map[uint64]*itemv1.ImportJob{},
+		finished: make(chan *itemv1.ImportJob, 1),
+	}
+}
+
+func (m *memoryCatalog) Upsert(ctx context.Context, rows []*itemv1.CatalogRow) (int, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, row := range rows {
+		if row.Brand == "broken" {
+			return 0, 0, fmt.Errorf("%s: storage failure", row.Key())
+		}
+	}
+
+	var created, updated int
+
+	for _, row := range rows {
+		if _, ok := m.rows[row.Key()]; ok {
+			updated++
+		} else {
+			created++
+		}
+
+		m.rows[row.Key()] = row
+	}
+
+	return created, updated, nil
+}
+
+func (m *memoryCatalog) Export(ctx context.Context, opts metav1.ListOptions) ([]*itemv1.CatalogRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.rows))
+	for key := range m.rows {
+		keys = append(keys, key)
+	}
+
+	// the order of the store is stable, as with the ids of the items.
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			if keys[j] < keys[i] {
+				keys[i], keys[j] = keys[j], keys[i]
+			}
+		}
+	}
+
+	var rows []*itemv1.CatalogRow
+	for i := *opts.Offset; i < int64(len(keys)) && i < *opts.Offset+*opts.Limit; i++ {
+		rows = append(rows, m.rows[keys[i]])
+	}
+
+	return rows, nil
+}
+
+func (m *memoryCatalog) CreateImportJob(ctx context.Context, job *itemv1.ImportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job.ID = uint64(len(m.jobs) + 1)
+	job.Tenant, _ = tenant.FromContext(ctx)
+	copied := *job
+	m.jobs[job.ID] = &copied
+
+	return nil
+}
+
+func (m *memoryCatalog) UpdateImportJob(ctx context.Context, job *itemv1.ImportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *job
+	m.jobs[job.ID] = &copied
+
+	if job.Finished() {
+		m.finished <- &copied
+	}
+
+	return nil
+}
+
+func (m *memoryCatalog) GetImportJob(ctx context.Context, id uint64) (*itemv1.ImportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	copied := *m.jobs[id]
+
+	return &copied, nil
+}
+
+func (m *memoryCatalog) AddImportErrors(ctx context.Context, errs []*itemv1.ImportRowError) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range errs {
+		copied := *e
+		m.errors = append(m.errors, &copied)
+	}
+
+	return nil
+}
+
+func (m *memoryCatalog) ListImportErrors(
+	ctx context.Context,
+	jobID uint64,
+	opts metav1.ListOptions,
+) (*itemv1.ImportRowErrorList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return &itemv1.ImportRowErrorList{ListMeta: metav1.ListMeta{TotalCount: int64(len(m.errors))}, Items: m.errors}, nil
+}
+
+func (m *memoryCatalog) FailStaleImportJobs(ctx context.Context, before time.Time, message string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var failed int64
+	for _, job := range m.jobs {
+		if !job.Finished() && job.UpdatedAt.Before(before) {
+			job.Status, job.Message = itemv1.ImportJobFailed, message
+			failed++
+		}
+	}
+
+	return failed, nil
+}
+
+// memoryBackups implements store.BackupStore in memory, Restore records the
+// resources read from the archive and creates all of them.
+type memoryBackups struct {
+	objects  []interface{}
+	restored []interface{}
+}
+
+func (m *memoryBackups) Export(ctx context.Context, fn func(obj interface{}) error) error {
+	for _, obj := range m.objects {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryBackups) Restore(
+	ctx context.Context,
+	conflict string,
+	next func() (interface{}, error),
+) (*model.RestoreResult, error) {
+	m.restored = nil
+
+	for {
+		obj, err := next()
+		if err == io.EOF {
+			return &model.RestoreResult{}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		m.restored = append(m.restored, obj)
+	}
+}
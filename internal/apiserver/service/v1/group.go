@@ -0,0 +1,193 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"regexp"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+// GroupSrv defines functions used to handle group request.
+type GroupSrv interface {
+	Create(ctx context.Context, group *v1.Group, opts metav1.CreateOptions) error
+	Update(ctx context.Context, group *v1.Group, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Group, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.GroupList, error)
+	AddMember(ctx context.Context, group, username string) error
+	RemoveMember(ctx context.Context, group, username string) error
+	ListMembers(ctx context.Context, group string, opts metav1.ListOptions) (*v1.GroupMemberList, error)
+	BindPolicy(ctx context.Context, group, owner, policy string) error
+	UnbindPolicy(ctx context.Context, group, owner, policy string) error
+	ListPolicies(ctx context.Context, group string, opts metav1.ListOptions) (*v1.PolicyBindingList, error)
+}
+
+type groupService struct {
+	store store.Factory
+}
+
+var _ GroupSrv = (*groupService)(nil)
+
+func newGroups(srv *service) *groupService {
+	return &groupService{store: srv.store}
+}
+
+// duplicateEntry matches the mysql error returned when an unique key is violated.
+var duplicateEntry = regexp.MustCompile("Duplicate entry '.*' for key")
+
+func (s *groupService) Create(ctx context.Context, group *v1.Group, opts metav1.CreateOptions) error {
+	if err := s.store.Groups().Create(ctx, group, opts); err != nil {
+		if duplicateEntry.MatchString(err.Error()) {
+			return errors.WithCode(code.ErrGroupAlreadyExist, err.Error())
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *groupService) Update(ctx context.Context, group *v1.Group, opts metav1.UpdateOptions) error {
+	if err := s.store.Groups().Update(ctx, group, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *groupService) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	if err := s.store.Groups().Delete(ctx, name, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *groupService) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Group, error) {
+	group, err := s.store.Groups().Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.Groups().ListMembers(ctx, name, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	group.TotalMember = members.TotalCount
+
+	return group, nil
+}
+
+func (s *groupService) List(ctx context.Context, opts metav1.ListOptions) (*v1.GroupList, error) {
+	groups, err := s.store.Groups().List(ctx, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return groups, nil
+}
+
+func (s *groupService) AddMember(ctx context.Context, group, username string) error {
+	if _, err := s.store.Groups().Get(ctx, group, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	if _, err := s.store.Users().Get(ctx, username, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	member := &v1.GroupMember{Group: group, Username: username}
+	if err := s.store.Groups().AddMember(ctx, member, metav1.CreateOptions{}); err != nil {
+		// adding an existing member is not an error.
+		if duplicateEntry.MatchString(err.Error()) {
+			return nil
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *groupService) RemoveMember(ctx context.Context, group, username string) error {
+	if err := s.store.Groups().RemoveMember(ctx, group, username, metav1.DeleteOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *groupService) ListMembers(
+	ctx context.Context,
+	group string,
+	opts metav1.ListOptions,
+) (*v1.GroupMemberList, error) {
+	if _, err := s.store.Groups().Get(ctx, group, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.Groups().ListMembers(ctx, group, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return members, nil
+}
+
+func (s *groupService) BindPolicy(ctx context.Context, group, owner, policy string) error {
+	if _, err := s.store.Groups().Get(ctx, group, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	return bindPolicy(ctx, s.store, v1.Principal(v1.PrincipalKindGroup, group), owner, policy)
+}
+
+func (s *groupService) UnbindPolicy(ctx context.Context, group, owner, policy string) error {
+	principal := v1.Principal(v1.PrincipalKindGroup, group)
+	if err := s.store.PolicyBindings().Delete(ctx, principal, owner, policy, metav1.DeleteOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *groupService) ListPolicies(
+	ctx context.Context,
+	group string,
+	opts metav1.ListOptions,
+) (*v1.PolicyBindingList, error) {
+	principal := v1.Principal(v1.PrincipalKindGroup, group)
+	bindings, err := s.store.PolicyBindings().List(ctx, principal, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return bindings, nil
+}
+
+// bindPolicy binds the policy of the owner to the principal, the policy must exist.
+func bindPolicy(ctx context.Context, s store.Factory, principal, owner, policy string) error {
+	if _, err := s.Policies().Get(ctx, owner, policy, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	binding := &v1.PolicyBinding{Principal: principal, PolicyOwner: owner, PolicyName: policy}
+	if err := s.PolicyBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+		// binding an already bound policy is not an error.
+		if duplicateEntry.MatchString(err.Error()) {
+			return nil
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
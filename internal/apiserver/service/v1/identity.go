@@ -0,0 +1,142 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	"github.com/marmotedu/component-base/pkg/auth"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// maxNicknameLength is the longest nickname a user can have.
+const maxNicknameLength = 30
+
+// ExternalIdentity defines a user authenticated by an external identity provider.
+type ExternalIdentity struct {
+	// Issuer and Subject identify the account at the provider.
+	Issuer  string
+	Subject string
+	// Username, Email and Nickname are mapped from the claims of the
+	// provider, they are only used to provision the user on first login.
+	Username string
+	Email    string
+	Nickname string
+}
+
+// IdentitySrv defines functions used to log in the users of external identity providers.
+type IdentitySrv interface {
+	// Login returns the active user linked to the external identity. A new
+	// active user is provisioned for an identity which is not linked yet when
+	// provision is true, provided its username is not taken.
+	Login(ctx context.Context, identity *ExternalIdentity, provision bool) (*v1.User, error)
+}
+
+type identityService struct {
+	store store.Factory
+}
+
+var _ IdentitySrv = (*identityService)(nil)
+
+func newIdentities(srv *service) *identityService {
+	return &identityService{store: srv.store}
+}
+
+func (s *identityService) Login(ctx context.Context, identity *ExternalIdentity, provision bool) (*v1.User, error) {
+	link, err := s.store.UserIdentities().Get(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		if !errors.IsCode(err, code.ErrIdentityNotLinked) || !provision {
+			return nil, err
+		}
+
+		return s.provision(ctx, identity)
+	}
+
+	user, err := s.store.Users().Get(ctx, link.Username, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckUserActive(user); err != nil {
+		return nil, err
+	}
+
+	user.LoginedAt = time.Now()
+	if err := s.store.Users().Update(ctx, user, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// provision creates the user of the external identity. Existing users are
+// never linked implicitly, since anyone able to choose the claims of an
+// account at the provider could take them over.
+func (s *identityService) provision(ctx context.Context, identity *ExternalIdentity) (*v1.User, error) {
+	// the user has no password, it only logs in through the provider.
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	password, err := auth.Encrypt(secret)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrEncrypt, err.Error())
+	}
+
+	nickname := []rune(identity.Nickname)
+	if len(nickname) == 0 {
+		nickname = []rune(identity.Username)
+	}
+
+	if len(nickname) > maxNicknameLength {
+		nickname = nickname[:maxNicknameLength]
+	}
+
+	user := &v1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: identity.Username},
+		Nickname:   string(nickname),
+		Password:   password,
+		Email:      identity.Email,
+		// the provider is trusted with the email of its users.
+		Status:    model.UserStatusActive,
+		LoginedAt: time.Now(),
+	}
+
+	if errs := user.ValidateUpdate(); len(errs) != 0 {
+		return nil, errors.WithCode(code.ErrValidation, "can not provision user of identity %s: %s",
+			identity.Subject, errs.ToAggregate().Error())
+	}
+
+	if err := (&userService{store: s.store}).Create(ctx, user, metav1.CreateOptions{}); err != nil {
+		if errors.IsCode(err, code.ErrUserAlreadyExist) {
+			return nil, errors.WithCode(code.ErrIdentityConflict, "user %s already exists", user.Name)
+		}
+
+		return nil, err
+	}
+
+	if err := s.store.UserIdentities().Create(ctx, &model.UserIdentity{
+		Username:  user.Name,
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	log.L(ctx).Infof("provisioned user `%s` for identity `%s` of issuer `%s`",
+		user.Name, identity.Subject, identity.Issuer)
+
+	return user, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+// memoryUserIdentities implements store.UserIdentityStore in memory.
+type memoryUserIdentities map[string]*model.UserIdentity
+
+func (m memoryUserIdentities) Create(ctx context.Context, identity *model.UserIdentity) error {
+	m[identity.Issuer+" "+identity.Subject] = identity
+
+	return nil
+}
+
+func (m memoryUserIdentities) Get(ctx context.Context, issuer, subject string) (*model.UserIdentity, error) {
+	identity, ok := m[issuer+" "+subject]
+	if !ok {
+		return nil, errors.WithCode(code.ErrIdentityNotLinked, "identity is not linked")
+	}
+
+	return identity, nil
+}
+
+// conflictingUsers fails to create the users which already exist, like the
+// unique index of the users table does.
+type conflictingUsers struct {
+	memoryUsers
+}
+
+func (c conflictingUsers) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
+	if _, ok := c.memoryUsers[user.Name]; ok {
+		return errors.New("Error 1062: Duplicate entry '" + user.Name + "' for key 'idx_name'")
+	}
+
+	return c.memoryUsers.Create(ctx, user, opts)
+}
+
+func newIdentityTest(users memoryUsers) (IdentitySrv, memoryUserIdentities) {
+	identities := memoryUserIdentities{}
+	srv := newTestService(testStores{"Users": conflictingUsers{users}, "UserIdentities": identities})
+
+	return srv.Identities(), identities
+}
+
+func TestIdentityLoginProvisions(t *testing.T) {
+	users := memoryUsers{}
+	srv, identities := newIdentityTest(users)
+	ctx := context.Background()
+
+	identity := &ExternalIdentity{
+		Issuer:   "https://idp.example.com",
+		Subject:  "248289761001",
+		Username: "colin",
+		Email:    "colin@example.com",
+		Nickname: "Colin",
+	}
+
+	user, err := srv.Login(ctx, identity, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "colin", user.Name)
+	assert.Equal(t, model.UserStatusActive, users["colin"].Status)
+	assert.Equal(t, "Colin", users["colin"].Nickname)
+	assert.NotEmpty(t, users["colin"].Password)
+	assert.Equal(t, "colin", identities["https://idp.example.com 248289761001"].Username)
+
+	// the identity is linked now, the claims are no longer used.
+	identity.Username = "maria"
+	user, err = srv.Login(ctx, identity, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "colin", user.Name)
+	assert.Len(t, users, 1)
+
+	// linked users are still subject to their status.
+	users["colin"].Status = model.UserStatusSuspended
+	_, err = srv.Login(ctx, identity, true)
+	assert.True(t, errors.IsCode(err, code.ErrUserSuspended))
+}
+
+func TestIdentityLoginRefused(t *testing.T) {
+	users := activeUsers("colin")
+	srv, identities := newIdentityTest(users)
+	ctx := context.Background()
+
+	identity := &ExternalIdentity{
+		Issuer:   "https://idp.example.com",
+		Subject:  "248289761001",
+		Username: "colin",
+		Email:    "colin@example.com",
+	}
+
+	// existing users are never taken over by an identity with the same username.
+	_, err := srv.Login(ctx, identity, true)
+	assert.True(t, errors.IsCode(err, code.ErrIdentityConflict))
+	assert.Empty(t, identities)
+
+	identity.Username = "maria"
+	_, err = srv.Login(ctx, identity, false)
+	assert.True(t, errors.IsCode(err, code.ErrIdentityNotLinked))
+	assert.NotContains(t, users, "maria")
+
+	// the claims must describe a valid user.
+	identity.Email = ""
+	_, err = srv.Login(ctx, identity, true)
+	assert.True(t, errors.IsCode(err, code.ErrValidation))
+	assert.NotContains(t, users, "maria")
+}
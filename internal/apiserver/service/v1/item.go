@@ -6,12 +6,15 @@ package v1
 
 import (
 	"context"
+	"strconv"
 
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
 	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
 )
 
 // ItemSrv defines functions used to handle item requests.
@@ -41,24 +44,37 @@ func (i *itemService) Create(ctx context.Context, item *v1.Item, opts metav1.Cre
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishItem(ctx, event.Added, item)
+	audit.Record(ctx, audit.ActionCreate, event.KindItem, strconv.FormatUint(item.ID, 10), "", nil, item)
+
 	return nil
 }
 
 // Update updates an existing item in the storage.
 func (i *itemService) Update(ctx context.Context, item *v1.Item, opts metav1.UpdateOptions) error {
+	before := i.snapshot(ctx, int(item.ID))
+
 	if err := i.store.Items().Update(ctx, item, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishItem(ctx, event.Modified, item)
+	audit.Record(ctx, audit.ActionUpdate, event.KindItem, strconv.FormatUint(item.ID, 10), "", before, item)
+
 	return nil
 }
 
 // Delete deletes an item from the storage.
 func (i *itemService) Delete(ctx context.Context, id int, opts metav1.DeleteOptions) error {
+	before := i.snapshot(ctx, id)
+
 	if err := i.store.Items().Delete(ctx, id, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishDeletion(ctx, event.KindItem, strconv.Itoa(id), "")
+	audit.Record(ctx, audit.ActionDelete, event.KindItem, strconv.Itoa(id), "", before, nil)
+
 	return nil
 }
 
@@ -81,3 +97,11 @@ func (i *itemService) List(ctx context.Context, opts metav1.ListOptions) (*v1.It
 
 	return items, nil
 }
+
+// snapshot returns the item before the request of ctx changes it, when the
+// request is audited.
+func (i *itemService) snapshot(ctx context.Context, id int) interface{} {
+	return snapshot(ctx, func() (interface{}, error) {
+		return i.store.Items().Get(ctx, id, metav1.GetOptions{})
+	})
+}
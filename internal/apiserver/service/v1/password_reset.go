@@ -0,0 +1,127 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marmotedu/component-base/pkg/auth"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/notifier"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// passwordResetTTL is how long a password reset token can be used.
+const passwordResetTTL = 30 * time.Minute
+
+// PasswordResetSrv defines functions used to handle self-service password resets.
+type PasswordResetSrv interface {
+	// Request sends a single-use reset token to the user with the given
+	// username, or else with the given email. It succeeds for unknown users
+	// too, so that the registered accounts cannot be discovered.
+	Request(ctx context.Context, username, email string) error
+	// Confirm sets the password of the user the token was sent to, and revokes
+	// all the tokens and the sessions of the user.
+	Confirm(ctx context.Context, token, password string) error
+}
+
+type passwordResetService struct {
+	store store.Factory
+}
+
+var _ PasswordResetSrv = (*passwordResetService)(nil)
+
+func newPasswordResets(srv *service) *passwordResetService {
+	return &passwordResetService{store: srv.store}
+}
+
+func (s *passwordResetService) Request(ctx context.Context, username, email string) error {
+	user, err := lookupUser(ctx, s.store, username, email)
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			log.L(ctx).Infof("password reset requested for unknown user, username: `%s`, email: `%s`", username, email)
+
+			return nil
+		}
+
+		return err
+	}
+
+	if user.Email == "" || user.Status != model.UserStatusActive {
+		log.L(ctx).Warnf("password reset requested for user `%s` without email or not active", user.Name)
+
+		return nil
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	now := time.Now()
+	reset := &model.PasswordReset{
+		Username:  user.Name,
+		Hash:      hashOpaqueToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(passwordResetTTL),
+	}
+	if err := s.store.PasswordResets().Create(ctx, reset); err != nil {
+		return err
+	}
+
+	msg := &notifier.Message{
+		To:      user.Email,
+		Subject: "Reset your IAM password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"A password reset was requested for your account. Use the token below to choose a new password, "+
+			"it can be used once until %s:\n\n%s\n\n"+
+			"If you did not request it, you can ignore this message.",
+			user.Name, reset.ExpiresAt.Format(time.RFC1123), token),
+	}
+	if err := notifier.Client().Send(ctx, msg); err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	return nil
+}
+
+func (s *passwordResetService) Confirm(ctx context.Context, token, password string) error {
+	reset, err := s.store.PasswordResets().Use(ctx, hashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.store.Users().Get(ctx, reset.Username, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			return errors.WithCode(code.ErrPasswordResetTokenInvalid, "user %s not found", reset.Username)
+		}
+
+		return err
+	}
+
+	if err := CheckUserActive(user); err != nil {
+		return err
+	}
+
+	if user.Password, err = auth.Encrypt(password); err != nil {
+		return errors.WithCode(code.ErrEncrypt, err.Error())
+	}
+
+	if err := s.store.Users().Update(ctx, user, metav1.UpdateOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	log.L(ctx).Infof("password of user `%s` has been reset", user.Name)
+
+	return revokeUser(ctx, s.store, user.Name)
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/notifier"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+)
+
+// memoryPasswordResets implements store.PasswordResetStore in memory.
+type memoryPasswordResets map[string]*model.PasswordReset
+
+func (m memoryPasswordResets) Create(ctx context.Context, reset *model.PasswordReset) error {
+	for hash, pending := range m {
+		if pending.Username == reset.Username && pending.UsedAt == nil {
+			delete(m, hash)
+		}
+	}
+
+	m[reset.Hash] = reset
+
+	return nil
+}
+
+func (m memoryPasswordResets) Use(ctx context.Context, hash string) (*model.PasswordReset, error) {
+	reset, ok := m[hash]
+	if !ok || reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+		return nil, errors.WithCode(code.ErrPasswordResetTokenInvalid, "password reset token is invalid")
+	}
+
+	now := time.Now()
+	reset.UsedAt = &now
+
+	return reset, nil
+}
+
+func (m memoryPasswordResets) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// sentMessages implements notifier.Notifier, it keeps the sent messages.
+type sentMessages struct {
+	messages []*notifier.Message
+}
+
+func (s *sentMessages) Send(ctx context.Context, msg *notifier.Message) error {
+	s.messages = append(s.messages, msg)
+
+	return nil
+}
+
+// token returns the reset token of the last sent message.
+func (s *sentMessages) token() string {
+	return strings.Split(s.messages[len(s.messages)-1].Body, "\n\n")[2]
+}
+
+// revokedUsers implements revocation.Store, only users are tracked.
+type revokedUsers map[string]time.Time
+
+func (r revokedUsers) RevokeToken(string, time.Duration) error { return nil }
+
+func (r revokedUsers) TokenRevoked(string) (bool, error) { return false, nil }
+
+func (r revokedUsers) RevokeSession(string, time.Duration) error { return nil }
+
+func (r revokedUsers) SessionRevoked(string) (bool, error) { return false, nil }
+
+func (r revokedUsers) RevokeUser(username string, before time.Time) error {
+	r[username] = before
+
+	return nil
+}
+
+func (r revokedUsers) UserRevokedBefore(username string) (time.Time, error) { return r[username], nil }
+
+func newPasswordResetTest(t *testing.T) (PasswordResetSrv, memoryUsers, memoryPasswordResets, *sentMessages) {
+	users := activeUsers("colin")
+	resets := memoryPasswordResets{}
+	srv := newTestService(testStores{"Users": users, "PasswordResets": resets, "Sessions": newMemorySessions()})
+
+	sent := &sentMessages{}
+	previousNotifier := notifier.Client()
+	notifier.SetClient(sent)
+	t.Cleanup(func() { notifier.SetClient(previousNotifier) })
+
+	previousRevocation := revocation.Client()
+	revocation.SetClient(revokedUsers{})
+	t.Cleanup(func() { revocation.SetClient(previousRevocation) })
+
+	return srv.PasswordResets(), users, resets, sent
+}
+
+func TestPasswordResetConfirm(t *testing.T) {
+	srv, users, resets, sent := newPasswordResetTest(t)
+	ctx := context.Background()
+
+	assert.Nil(t, srv.Request(ctx, "", "colin@example.com"))
+	assert.Len(t, sent.messages, 1)
+	assert.Equal(t, "colin@example.com", sent.messages[0].To)
+
+	token := sent.token()
+	assert.NotContains(t, resets, token, "reset tokens are stored hashed")
+
+	assert.Nil(t, srv.Confirm(ctx, token, "Colin_2021"))
+	assert.Nil(t, users["colin"].Compare("Colin_2021"))
+
+	revokedBefore, _ := revocation.Client().UserRevokedBefore("colin")
+	assert.False(t, revokedBefore.IsZero(), "existing tokens and sessions are revoked")
+
+	// reset tokens can only be used once.
+	err := srv.Confirm(ctx, token, "Colin_2022")
+	assert.True(t, errors.IsCode(err, code.ErrPasswordResetTokenInvalid))
+	assert.Nil(t, users["colin"].Compare("Colin_2021"))
+}
+
+func TestPasswordResetUnknownUser(t *testing.T) {
+	srv, _, resets, sent := newPasswordResetTest(t)
+
+	assert.Nil(t, srv.Request(context.Background(), "maria", ""))
+	assert.Nil(t, srv.Request(context.Background(), "", "maria@example.com"))
+	assert.Empty(t, sent.messages)
+	assert.Empty(t, resets)
+}
+
+func TestPasswordResetInvalidToken(t *testing.T) {
+	srv, _, resets, sent := newPasswordResetTest(t)
+	ctx := context.Background()
+
+	assert.Nil(t, srv.Request(ctx, "colin", ""))
+	first := sent.token()
+
+	// a new request replaces the pending token.
+	assert.Nil(t, srv.Request(ctx, "colin", ""))
+	second := sent.token()
+
+	err := srv.Confirm(ctx, first, "Colin_2021")
+	assert.True(t, errors.IsCode(err, code.ErrPasswordResetTokenInvalid))
+
+	resets[hashOpaqueToken(second)].ExpiresAt = time.Now().Add(-time.Second)
+	err = srv.Confirm(ctx, second, "Colin_2021")
+	assert.True(t, errors.IsCode(err, code.ErrPasswordResetTokenInvalid))
+
+	err = srv.Confirm(ctx, "unknown", "Colin_2021")
+	assert.True(t, errors.IsCode(err, code.ErrPasswordResetTokenInvalid))
+}
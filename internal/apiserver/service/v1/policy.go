@@ -7,7 +7,9 @@ import (
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
 )
 
 // PolicySrv defines functions used to handle policy request.
@@ -35,23 +37,36 @@ func (s *policyService) Create(ctx context.Context, policy *v1.Policy, opts meta
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishPolicy(ctx, event.Added, policy)
+	audit.Record(ctx, audit.ActionCreate, event.KindPolicy, policy.Name, policy.Username, nil, policy)
+
 	return nil
 }
 
 func (s *policyService) Update(ctx context.Context, policy *v1.Policy, opts metav1.UpdateOptions) error {
+	before := s.snapshot(ctx, policy.Username, policy.Name)
+
 	// Save changed fields.
 	if err := s.store.Policies().Update(ctx, policy, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishPolicy(ctx, event.Modified, policy)
+	audit.Record(ctx, audit.ActionUpdate, event.KindPolicy, policy.Name, policy.Username, before, policy)
+
 	return nil
 }
 
 func (s *policyService) Delete(ctx context.Context, username, name string, opts metav1.DeleteOptions) error {
+	before := s.snapshot(ctx, username, name)
+
 	if err := s.store.Policies().Delete(ctx, username, name, opts); err != nil {
 		return err
 	}
 
+	publishDeletion(ctx, event.KindPolicy, name, username)
+	audit.Record(ctx, audit.ActionDelete, event.KindPolicy, name, username, before, nil)
+
 	return nil
 }
 
@@ -61,10 +76,20 @@ func (s *policyService) DeleteCollection(
 	names []string,
 	opts metav1.DeleteOptions,
 ) error {
+	before := make([]interface{}, len(names))
+	for i, name := range names {
+		before[i] = s.snapshot(ctx, username, name)
+	}
+
 	if err := s.store.Policies().DeleteCollection(ctx, username, names, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	for i, name := range names {
+		publishDeletion(ctx, event.KindPolicy, name, username)
+		audit.Record(ctx, audit.ActionDelete, event.KindPolicy, name, username, before[i], nil)
+	}
+
 	return nil
 }
 
@@ -85,3 +110,11 @@ func (s *policyService) List(ctx context.Context, username string, opts metav1.L
 
 	return policies, nil
 }
+
+// snapshot returns the policy before the request of ctx changes it, when the
+// request is audited.
+func (s *policyService) snapshot(ctx context.Context, username, name string) interface{} {
+	return snapshot(ctx, func() (interface{}, error) {
+		return s.store.Policies().Get(ctx, username, name, metav1.GetOptions{})
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+// RoleSrv defines functions used to handle role request.
+type RoleSrv interface {
+	Create(ctx context.Context, role *v1.Role, opts metav1.CreateOptions) error
+	Update(ctx context.Context, role *v1.Role, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Role, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.RoleList, error)
+	Bind(ctx context.Context, role, kind, subject string) error
+	Unbind(ctx context.Context, role, kind, subject string) error
+	ListBindings(ctx context.Context, role string, opts metav1.ListOptions) (*v1.RoleBindingList, error)
+	BindPolicy(ctx context.Context, role, owner, policy string) error
+	UnbindPolicy(ctx context.Context, role, owner, policy string) error
+	ListPolicies(ctx context.Context, role string, opts metav1.ListOptions) (*v1.PolicyBindingList, error)
+}
+
+type roleService struct {
+	store store.Factory
+}
+
+var _ RoleSrv = (*roleService)(nil)
+
+func newRoles(srv *service) *roleService {
+	return &roleService{store: srv.store}
+}
+
+func (s *roleService) Create(ctx context.Context, role *v1.Role, opts metav1.CreateOptions) error {
+	if err := s.store.Roles().Create(ctx, role, opts); err != nil {
+		if duplicateEntry.MatchString(err.Error()) {
+			return errors.WithCode(code.ErrRoleAlreadyExist, err.Error())
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *roleService) Update(ctx context.Context, role *v1.Role, opts metav1.UpdateOptions) error {
+	if err := s.store.Roles().Update(ctx, role, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *roleService) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	if err := s.store.Roles().Delete(ctx, name, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *roleService) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Role, error) {
+	return s.store.Roles().Get(ctx, name, opts)
+}
+
+func (s *roleService) List(ctx context.Context, opts metav1.ListOptions) (*v1.RoleList, error) {
+	roles, err := s.store.Roles().List(ctx, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return roles, nil
+}
+
+func (s *roleService) Bind(ctx context.Context, role, kind, subject string) error {
+	if _, err := s.store.Roles().Get(ctx, role, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	var err error
+	switch kind {
+	case v1.SubjectKindUser:
+		_, err = s.store.Users().Get(ctx, subject, metav1.GetOptions{})
+	case v1.SubjectKindGroup:
+		_, err = s.store.Groups().Get(ctx, subject, metav1.GetOptions{})
+	default:
+		err = errors.WithCode(code.ErrValidation, "unsupported subject kind %q", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	binding := &v1.RoleBinding{Role: role, Kind: kind, Subject: subject}
+	if err := s.store.Roles().Bind(ctx, binding, metav1.CreateOptions{}); err != nil {
+		// binding an already bound subject is not an error.
+		if duplicateEntry.MatchString(err.Error()) {
+			return nil
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *roleService) Unbind(ctx context.Context, role, kind, subject string) error {
+	if err := s.store.Roles().Unbind(ctx, role, kind, subject, metav1.DeleteOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *roleService) ListBindings(
+	ctx context.Context,
+	role string,
+	opts metav1.ListOptions,
+) (*v1.RoleBindingList, error) {
+	if _, err := s.store.Roles().Get(ctx, role, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	bindings, err := s.store.Roles().ListBindings(ctx, role, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return bindings, nil
+}
+
+func (s *roleService) BindPolicy(ctx context.Context, role, owner, policy string) error {
+	if _, err := s.store.Roles().Get(ctx, role, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	return bindPolicy(ctx, s.store, v1.Principal(v1.PrincipalKindRole, role), owner, policy)
+}
+
+func (s *roleService) UnbindPolicy(ctx context.Context, role, owner, policy string) error {
+	principal := v1.Principal(v1.PrincipalKindRole, role)
+	if err := s.store.PolicyBindings().Delete(ctx, principal, owner, policy, metav1.DeleteOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *roleService) ListPolicies(
+	ctx context.Context,
+	role string,
+	opts metav1.ListOptions,
+) (*v1.PolicyBindingList, error) {
+	principal := v1.Principal(v1.PrincipalKindRole, role)
+	bindings, err := s.store.PolicyBindings().List(ctx, principal, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return bindings, nil
+}
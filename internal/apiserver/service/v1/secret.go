@@ -6,22 +6,31 @@ package v1
 
 import (
 	"context"
+	"time"
 
-	v1 "github.com/marmotedu/api/apiserver/v1"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/util/idutil"
 	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
 )
 
 // SecretSrv defines functions used to handle secret request.
 type SecretSrv interface {
-	Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error
-	Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error
+	Create(ctx context.Context, secret *model.Secret, opts metav1.CreateOptions) error
+	Update(ctx context.Context, secret *model.Secret, opts metav1.UpdateOptions) error
 	Delete(ctx context.Context, username, secretID string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, username string, secretIDs []string, opts metav1.DeleteOptions) error
-	Get(ctx context.Context, username, secretID string, opts metav1.GetOptions) (*v1.Secret, error)
-	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error)
+	Get(ctx context.Context, username, secretID string, opts metav1.GetOptions) (*model.Secret, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*model.SecretList, error)
+	// Rotate issues a new key for the secret. The current key is still accepted
+	// for the grace period, it is revoked at once when the grace period is 0.
+	Rotate(ctx context.Context, username, name string, grace time.Duration) (*model.Secret, error)
+	RecordUsage(ctx context.Context, lastUsed map[string]time.Time) error
 }
 
 type secretService struct {
@@ -34,28 +43,41 @@ func newSecrets(srv *service) *secretService {
 	return &secretService{store: srv.store}
 }
 
-func (s *secretService) Create(ctx context.Context, secret *v1.Secret, opts metav1.CreateOptions) error {
+func (s *secretService) Create(ctx context.Context, secret *model.Secret, opts metav1.CreateOptions) error {
 	if err := s.store.Secrets().Create(ctx, secret, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishSecret(ctx, event.Added, secret)
+	audit.Record(ctx, audit.ActionCreate, event.KindSecret, secret.Name, secret.Username, nil, secret)
+
 	return nil
 }
 
-func (s *secretService) Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error {
+func (s *secretService) Update(ctx context.Context, secret *model.Secret, opts metav1.UpdateOptions) error {
+	before := s.snapshot(ctx, secret.Username, secret.Name)
+
 	// Save changed fields.
 	if err := s.store.Secrets().Update(ctx, secret, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishSecret(ctx, event.Modified, secret)
+	audit.Record(ctx, audit.ActionUpdate, event.KindSecret, secret.Name, secret.Username, before, secret)
+
 	return nil
 }
 
 func (s *secretService) Delete(ctx context.Context, username, secretID string, opts metav1.DeleteOptions) error {
+	before := s.snapshot(ctx, username, secretID)
+
 	if err := s.store.Secrets().Delete(ctx, username, secretID, opts); err != nil {
 		return err
 	}
 
+	publishDeletion(ctx, event.KindSecret, secretID, username)
+	audit.Record(ctx, audit.ActionDelete, event.KindSecret, secretID, username, before, nil)
+
 	return nil
 }
 
@@ -65,10 +87,20 @@ func (s *secretService) DeleteCollection(
 	secretIDs []string,
 	opts metav1.DeleteOptions,
 ) error {
+	before := make([]interface{}, len(secretIDs))
+	for i, secretID := range secretIDs {
+		before[i] = s.snapshot(ctx, username, secretID)
+	}
+
 	if err := s.store.Secrets().DeleteCollection(ctx, username, secretIDs, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	for i, secretID := range secretIDs {
+		publishDeletion(ctx, event.KindSecret, secretID, username)
+		audit.Record(ctx, audit.ActionDelete, event.KindSecret, secretID, username, before[i], nil)
+	}
+
 	return nil
 }
 
@@ -76,7 +108,7 @@ func (s *secretService) Get(
 	ctx context.Context,
 	username, secretID string,
 	opts metav1.GetOptions,
-) (*v1.Secret, error) {
+) (*model.Secret, error) {
 	secret, err := s.store.Secrets().Get(ctx, username, secretID, opts)
 	if err != nil {
 		return nil, err
@@ -85,7 +117,7 @@ func (s *secretService) Get(
 	return secret, nil
 }
 
-func (s *secretService) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error) {
+func (s *secretService) List(ctx context.Context, username string, opts metav1.ListOptions) (*model.SecretList, error) {
 	secrets, err := s.store.Secrets().List(ctx, username, opts)
 	if err != nil {
 		return nil, errors.WithCode(code.ErrDatabase, err.Error())
@@ -93,3 +125,47 @@ func (s *secretService) List(ctx context.Context, username string, opts metav1.L
 
 	return secrets, nil
 }
+
+func (s *secretService) Rotate(
+	ctx context.Context,
+	username, name string,
+	grace time.Duration,
+) (*model.Secret, error) {
+	secret, err := s.store.Secrets().Get(ctx, username, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	before := *secret
+
+	// a key replaced by an earlier rotation is revoked even if its grace
+	// period is not over, only the last key is kept.
+	secret.PreviousKey, secret.PreviousKeyExpires = "", 0
+	if grace > 0 {
+		secret.PreviousKey = secret.SecretKey
+		secret.PreviousKeyExpires = time.Now().Add(grace).Unix()
+	}
+
+	secret.SecretKey = idutil.NewSecretKey()
+
+	if err := s.store.Secrets().Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	publishSecret(ctx, event.Modified, secret)
+	audit.Record(ctx, audit.ActionUpdate, event.KindSecret, secret.Name, secret.Username, &before, secret)
+
+	return secret, nil
+}
+
+func (s *secretService) RecordUsage(ctx context.Context, lastUsed map[string]time.Time) error {
+	return s.store.Secrets().RecordUsage(ctx, lastUsed)
+}
+
+// snapshot returns the secret before the request of ctx changes it, when the
+// request is audited.
+func (s *secretService) snapshot(ctx context.Context, username, name string) interface{} {
+	return snapshot(ctx, func() (interface{}, error) {
+		return s.store.Secrets().Get(ctx, username, name, metav1.GetOptions{})
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+func TestSecretRotate(t *testing.T) {
+	secrets := memorySecrets{}
+	srv := newTestService(testStores{"Secrets": secrets}).Secrets()
+	ctx := context.Background()
+
+	secret := &model.Secret{}
+	secret.Name, secret.Username, secret.SecretID, secret.SecretKey = "secret0", "colin", "id0", "key0"
+	assert.Nil(t, srv.Create(ctx, secret, metav1.CreateOptions{}))
+
+	rotated, err := srv.Rotate(ctx, "colin", "secret0", time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, "id0", rotated.SecretID)
+	assert.NotEqual(t, "key0", rotated.SecretKey)
+	assert.Equal(t, "key0", secrets["secret0"].PreviousKey)
+	assert.True(t, secrets["secret0"].PreviousKeyValid(time.Now()))
+	assert.False(t, secrets["secret0"].PreviousKeyValid(time.Now().Add(2*time.Hour)))
+
+	// only the last replaced key is kept, and no grace period revokes it at once.
+	_, err = srv.Rotate(ctx, "colin", "secret0", 0)
+	assert.Nil(t, err)
+	assert.Empty(t, secrets["secret0"].PreviousKey)
+	assert.False(t, secrets["secret0"].PreviousKeyValid(time.Now()))
+
+	// users can only rotate their own secrets.
+	_, err = srv.Rotate(ctx, "maria", "secret0", time.Hour)
+	assert.True(t, errors.IsCode(err, code.ErrSecretNotFound))
+}
+
+func TestSecretRecordUsage(t *testing.T) {
+	secrets := memorySecrets{}
+	srv := newTestService(testStores{"Secrets": secrets}).Secrets()
+	ctx := context.Background()
+
+	secret := &model.Secret{}
+	secret.Name, secret.Username, secret.SecretID = "secret0", "colin", "id0"
+	assert.Nil(t, srv.Create(ctx, secret, metav1.CreateOptions{}))
+
+	now := time.Now()
+	assert.Nil(t, srv.RecordUsage(ctx, map[string]time.Time{"id0": now}))
+	assert.Nil(t, srv.RecordUsage(ctx, map[string]time.Time{"id0": now.Add(-time.Hour), "unknown": now}))
+	assert.Equal(t, now, *secrets["secret0"].LastUsedAt)
+}
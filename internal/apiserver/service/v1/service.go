@@ -13,9 +13,22 @@ type Service interface {
 	Users() UserSrv
 	Secrets() SecretSrv
 	Policies() PolicySrv
+	Groups() GroupSrv
+	Roles() RoleSrv
+	Tenants() TenantSrv
+	SigningKeys() SigningKeySrv
+	Sessions() SessionSrv
+	TOTPs() TOTPSrv
+	PasswordResets() PasswordResetSrv
+	EmailVerifications() EmailVerificationSrv
+	Identities() IdentitySrv
+	AccessTokens() AccessTokenSrv
 	Items() ItemSrv
 	ItemAttributes() ItemAttributesSrv
 	ItemImage() ItemImageSrv
+	Catalog() CatalogSrv
+	Backups() BackupSrv
+	AuditLogs() AuditLogSrv
 }
 
 type service struct {
@@ -41,6 +54,46 @@ func (s *service) Policies() PolicySrv {
 	return newPolicies(s)
 }
 
+func (s *service) Groups() GroupSrv {
+	return newGroups(s)
+}
+
+func (s *service) Roles() RoleSrv {
+	return newRoles(s)
+}
+
+func (s *service) Tenants() TenantSrv {
+	return newTenants(s)
+}
+
+func (s *service) SigningKeys() SigningKeySrv {
+	return newSigningKeys(s)
+}
+
+func (s *service) Sessions() SessionSrv {
+	return newSessions(s)
+}
+
+func (s *service) TOTPs() TOTPSrv {
+	return newTOTPs(s)
+}
+
+func (s *service) PasswordResets() PasswordResetSrv {
+	return newPasswordResets(s)
+}
+
+func (s *service) EmailVerifications() EmailVerificationSrv {
+	return newEmailVerifications(s)
+}
+
+func (s *service) Identities() IdentitySrv {
+	return newIdentities(s)
+}
+
+func (s *service) AccessTokens() AccessTokenSrv {
+	return newAccessTokens(s)
+}
+
 func (s *service) Items() ItemSrv {
 	return newItems(s)
 }
@@ -52,3 +105,15 @@ func (s *service) ItemAttributes() ItemAttributesSrv {
 func (s *service) ItemImage() ItemImageSrv {
 	return newItemImages(s)
 }
+
+func (s *service) Catalog() CatalogSrv {
+	return newCatalog(s)
+}
+
+func (s *service) Backups() BackupSrv {
+	return newBackups(s)
+}
+
+func (s *service) AuditLogs() AuditLogSrv {
+	return newAuditLogs(s)
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/component-base/pkg/util/idutil"
+	"github.com/marmotedu/errors"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// SessionSrv defines functions used to handle login session requests.
+type SessionSrv interface {
+	// Create starts a new session for session.Username, valid for ttl unless
+	// refreshed, and returns its first refresh token.
+	Create(ctx context.Context, session *v1.Session, ttl time.Duration) (string, error)
+	// Refresh rotates the refresh token and extends its session by ttl, the
+	// client metadata of the session is updated from client. Presenting a
+	// token which has already been rotated revokes the whole session.
+	Refresh(ctx context.Context, refreshToken string, client *v1.Session, ttl time.Duration) (*v1.Session, string, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SessionList, error)
+	// Revoke revokes the session of the user, together with the access tokens issued for it.
+	Revoke(ctx context.Context, username, sid string) error
+}
+
+type sessionService struct {
+	store store.Factory
+}
+
+var _ SessionSrv = (*sessionService)(nil)
+
+func newSessions(srv *service) *sessionService {
+	return &sessionService{store: srv.store}
+}
+
+func (s *sessionService) Create(ctx context.Context, session *v1.Session, ttl time.Duration) (string, error) {
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return "", errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	now := time.Now()
+	session.SessionID = idutil.GetUUID36("")
+	session.CreatedAt = now
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(ttl)
+
+	token := &v1.RefreshToken{
+		Hash:      hashOpaqueToken(refreshToken),
+		SessionID: session.SessionID,
+		CreatedAt: now,
+	}
+	if err := s.store.Sessions().Create(ctx, session, token); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+func (s *sessionService) Refresh(
+	ctx context.Context,
+	refreshToken string,
+	client *v1.Session,
+	ttl time.Duration,
+) (*v1.Session, string, error) {
+	hash := hashOpaqueToken(refreshToken)
+
+	token, err := s.store.Sessions().GetToken(ctx, hash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	session, err := s.store.Sessions().Get(ctx, token.SessionID)
+	if err != nil {
+		if errors.IsCode(err, code.ErrSessionNotFound) {
+			return nil, "", errors.WithCode(code.ErrRefreshTokenInvalid, err.Error())
+		}
+
+		return nil, "", err
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || session.ExpiresAt.Before(now) {
+		return nil, "", errors.WithCode(code.ErrRefreshTokenInvalid, "session %s is over", session.SessionID)
+	}
+
+	// a rotated token is presented by whoever got a copy of it, the legitimate
+	// client or not, so nobody can be trusted with the session anymore.
+	if token.RotatedAt != nil {
+		return nil, "", s.reused(ctx, session)
+	}
+
+	// sessions started before a password change or an account disabling are over.
+	before, err := revocation.Client().UserRevokedBefore(session.Username)
+	if err != nil {
+		return nil, "", errors.WithCode(code.ErrUnknown, "get revocation watermark of user %s failed: %s",
+			session.Username, err.Error())
+	}
+
+	if !before.IsZero() && session.CreatedAt.Before(before) {
+		return nil, "", errors.WithCode(code.ErrRefreshTokenInvalid, "session %s has been revoked", session.SessionID)
+	}
+
+	newToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	session.UserAgent = client.UserAgent
+	session.ClientIP = client.ClientIP
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(ttl)
+
+	next := &v1.RefreshToken{
+		Hash:      hashOpaqueToken(newToken),
+		SessionID: session.SessionID,
+		CreatedAt: now,
+	}
+	if err := s.store.Sessions().Rotate(ctx, hash, next, session); err != nil {
+		// the token has been rotated by a concurrent refresh in the meantime.
+		if errors.IsCode(err, code.ErrRefreshTokenReused) {
+			return nil, "", s.reused(ctx, session)
+		}
+
+		return nil, "", err
+	}
+
+	return session, newToken, nil
+}
+
+func (s *sessionService) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SessionList, error) {
+	return s.store.Sessions().List(ctx, username, opts)
+}
+
+func (s *sessionService) Revoke(ctx context.Context, username, sid string) error {
+	session, err := s.store.Sessions().Get(ctx, sid)
+	if err != nil {
+		return err
+	}
+
+	// do not tell the sessions of other users apart from missing ones.
+	if session.Username != username {
+		return errors.WithCode(code.ErrSessionNotFound, "session %s not found", sid)
+	}
+
+	return s.revoke(ctx, session)
+}
+
+// revoke revokes the session in the database, so that it can no longer be
+// refreshed, and in the revocation store, so that its access tokens are rejected.
+// Access tokens never outlive their session, so the revocation lasts as long
+// as the session would have.
+func (s *sessionService) revoke(ctx context.Context, session *v1.Session) error {
+	if err := s.store.Sessions().Revoke(ctx, session.Username, session.SessionID); err != nil {
+		return err
+	}
+
+	if err := revocation.Client().RevokeSession(session.SessionID, time.Until(session.ExpiresAt)); err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	return nil
+}
+
+// reused revokes the session of a reused refresh token and returns the error
+// reported to the client.
+func (s *sessionService) reused(ctx context.Context, session *v1.Session) error {
+	log.L(ctx).Warnf("refresh token reused, revoke session %s of user %s", session.SessionID, session.Username)
+
+	if err := s.revoke(ctx, session); err != nil && !errors.IsCode(err, code.ErrSessionNotFound) {
+		return err
+	}
+
+	return errors.WithCode(code.ErrRefreshTokenReused, "session %s has been revoked", session.SessionID)
+}
+
+// newOpaqueToken returns a new random opaque token, like a refresh token.
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashOpaqueToken returns the hash an opaque token is stored as. The tokens
+// are random, so a plain sha256 is enough.
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
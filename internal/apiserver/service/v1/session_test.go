@@ -0,0 +1,82 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+)
+
+// revokedSessions implements revocation.Store, only sessions are tracked.
+type revokedSessions map[string]bool
+
+func (r revokedSessions) RevokeToken(string, time.Duration) error { return nil }
+
+func (r revokedSessions) TokenRevoked(string) (bool, error) { return false, nil }
+
+func (r revokedSessions) RevokeSession(sid string, ttl time.Duration) error {
+	r[sid] = true
+
+	return nil
+}
+
+func (r revokedSessions) SessionRevoked(sid string) (bool, error) { return r[sid], nil }
+
+func (r revokedSessions) RevokeUser(string, time.Time) error { return nil }
+
+func (r revokedSessions) UserRevokedBefore(string) (time.Time, error) { return time.Time{}, nil }
+
+func TestSessionRefresh(t *testing.T) {
+	sessions := newMemorySessions()
+	srv := newTestService(testStores{"Sessions": sessions}).Sessions()
+
+	revoked := revokedSessions{}
+	defer revocation.SetClient(revocation.Client())
+	revocation.SetClient(revoked)
+
+	ctx := context.Background()
+
+	session := &v1.Session{Username: "colin"}
+	first, err := srv.Create(ctx, session, time.Hour)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, session.SessionID)
+	assert.NotContains(t, sessions.tokens, first, "refresh tokens are stored hashed")
+
+	refreshed, second, err := srv.Refresh(ctx, first, &v1.Session{}, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, session.SessionID, refreshed.SessionID)
+	assert.NotEqual(t, first, second)
+
+	// replaying the rotated token revokes the whole session.
+	_, _, err = srv.Refresh(ctx, first, &v1.Session{}, time.Hour)
+	assert.True(t, errors.IsCode(err, code.ErrRefreshTokenReused))
+	assert.True(t, revoked[session.SessionID])
+
+	_, _, err = srv.Refresh(ctx, second, &v1.Session{}, time.Hour)
+	assert.True(t, errors.IsCode(err, code.ErrRefreshTokenInvalid))
+
+	_, _, err = srv.Refresh(ctx, "unknown", &v1.Session{}, time.Hour)
+	assert.True(t, errors.IsCode(err, code.ErrRefreshTokenInvalid))
+}
+
+func TestSessionRevokeOtherUser(t *testing.T) {
+	sessions := newMemorySessions()
+	srv := newTestService(testStores{"Sessions": sessions}).Sessions()
+	session := &v1.Session{Username: "colin"}
+	_, err := srv.Create(context.Background(), session, time.Hour)
+	assert.Nil(t, err)
+
+	err = srv.Revoke(context.Background(), "maria", session.SessionID)
+	assert.True(t, errors.IsCode(err, code.ErrSessionNotFound))
+	assert.Nil(t, sessions.sessions[session.SessionID].RevokedAt)
+}
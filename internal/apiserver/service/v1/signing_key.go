@@ -0,0 +1,134 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/marmotedu/component-base/pkg/util/idutil"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// SigningKeySrv defines functions used to manage the jwt signing keys.
+type SigningKeySrv interface {
+	// Keys returns the keys which are not expired yet, from the oldest to the newest.
+	Keys(ctx context.Context) ([]*jwks.Key, error)
+	// Active returns the newest active key, nil if there is none.
+	Active(ctx context.Context) (*v1.SigningKey, error)
+	// Rotate creates a new key with the given algorithm and retires the active
+	// keys, they keep verifying tokens during the retention period.
+	Rotate(ctx context.Context, alg string, retention time.Duration) error
+	// DeleteExpired deletes the retired keys whose retention period is over.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type signingKeyService struct {
+	store store.Factory
+}
+
+var _ SigningKeySrv = (*signingKeyService)(nil)
+
+func newSigningKeys(srv *service) *signingKeyService {
+	return &signingKeyService{store: srv.store}
+}
+
+func (s *signingKeyService) Keys(ctx context.Context) ([]*jwks.Key, error) {
+	keys, err := s.store.SigningKeys().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ret := make([]*jwks.Key, 0, len(keys))
+	for _, key := range keys {
+		if key.ExpiresAt != nil && key.ExpiresAt.Before(now) {
+			continue
+		}
+
+		signer, err := jwks.ParsePrivateKey(key.PrivateKey)
+		if err != nil {
+			log.L(ctx).Errorf("parse signing key %s failed: %s", key.KID, err.Error())
+
+			continue
+		}
+
+		ret = append(ret, &jwks.Key{
+			ID:        key.KID,
+			Algorithm: key.Algorithm,
+			Signer:    signer,
+			Retired:   key.RetiredAt != nil,
+		})
+	}
+
+	return ret, nil
+}
+
+func (s *signingKeyService) Active(ctx context.Context) (*v1.SigningKey, error) {
+	keys, err := s.store.SigningKeys().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].RetiredAt == nil {
+			return keys[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *signingKeyService) Rotate(ctx context.Context, alg string, retention time.Duration) error {
+	signer, err := jwks.GenerateKey(alg)
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	data, err := jwks.MarshalPrivateKey(signer)
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	keys, err := s.store.SigningKeys().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	// create the new key first, so that there is always an active key.
+	key := &v1.SigningKey{
+		KID:        idutil.GetUUID36(""),
+		Algorithm:  alg,
+		PrivateKey: data,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.store.SigningKeys().Create(ctx, key); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(retention)
+	for _, old := range keys {
+		if old.RetiredAt != nil {
+			continue
+		}
+
+		if err := s.store.SigningKeys().Retire(ctx, old.KID, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	log.L(ctx).Infof("jwt signing key rotated, new key: %s (%s)", key.KID, alg)
+
+	return nil
+}
+
+func (s *signingKeyService) DeleteExpired(ctx context.Context) (int64, error) {
+	return s.store.SigningKeys().DeleteExpired(ctx, time.Now())
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+// TenantSrv defines functions used to handle tenant request.
+type TenantSrv interface {
+	Create(ctx context.Context, tenant *v1.Tenant, opts metav1.CreateOptions) error
+	Update(ctx context.Context, tenant *v1.Tenant, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Tenant, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.TenantList, error)
+	AddMember(ctx context.Context, tenant, username string, isAdmin bool) error
+	RemoveMember(ctx context.Context, tenant, username string) error
+	ListMembers(ctx context.Context, tenant string, opts metav1.ListOptions) (*v1.TenantMemberList, error)
+}
+
+type tenantService struct {
+	store store.Factory
+}
+
+var _ TenantSrv = (*tenantService)(nil)
+
+func newTenants(srv *service) *tenantService {
+	return &tenantService{store: srv.store}
+}
+
+func (s *tenantService) Create(ctx context.Context, tn *v1.Tenant, opts metav1.CreateOptions) error {
+	if err := s.store.Tenants().Create(ctx, tn, opts); err != nil {
+		if duplicateEntry.MatchString(err.Error()) {
+			return errors.WithCode(code.ErrTenantAlreadyExist, err.Error())
+		}
+
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *tenantService) Update(ctx context.Context, tn *v1.Tenant, opts metav1.UpdateOptions) error {
+	if err := s.store.Tenants().Update(ctx, tn, opts); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Delete deletes an empty tenant. The users of a tenant must be moved to another
+// tenant first, otherwise they would silently fall back to the default tenant.
+func (s *tenantService) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	members, err := s.store.Tenants().ListMembers(ctx, name, metav1.ListOptions{})
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	if members.TotalCount > 0 {
+		return errors.WithCode(code.ErrTenantNotEmpty, "tenant %s still has %d members", name, members.TotalCount)
+	}
+
+	return s.store.Tenants().Delete(ctx, name, opts)
+}
+
+func (s *tenantService) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Tenant, error) {
+	tn, err := s.store.Tenants().Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.Tenants().ListMembers(ctx, name, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	tn.TotalMember = members.TotalCount
+
+	return tn, nil
+}
+
+func (s *tenantService) List(ctx context.Context, opts metav1.ListOptions) (*v1.TenantList, error) {
+	tenants, err := s.store.Tenants().List(ctx, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return tenants, nil
+}
+
+// AddMember moves a user to the tenant, the user leaves the groups and roles of
+// its previous tenant.
+func (s *tenantService) AddMember(ctx context.Context, name, username string, isAdmin bool) error {
+	if _, err := s.store.Tenants().Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	// the user may belong to any tenant yet.
+	if _, err := s.store.Users().Get(tenant.Unscoped(ctx), username, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	member := &v1.TenantMember{Tenant: name, Username: username}
+	if isAdmin {
+		member.IsAdmin = 1
+	}
+
+	if err := s.store.Tenants().AddMember(ctx, member, metav1.CreateOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *tenantService) RemoveMember(ctx context.Context, name, username string) error {
+	if err := s.store.Tenants().RemoveMember(ctx, name, username, metav1.DeleteOptions{}); err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *tenantService) ListMembers(
+	ctx context.Context,
+	name string,
+	opts metav1.ListOptions,
+) (*v1.TenantMemberList, error) {
+	if _, err := s.store.Tenants().Get(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.Tenants().ListMembers(ctx, name, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return members, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/marmotedu/errors"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+	// recoveryCodeAlphabet has 32 symbols without the ambiguous 0, o, 1 and l.
+	recoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+// totpGenerator generates and validates the codes, tests replace its clock.
+var totpGenerator = totp.New()
+
+// TOTPSrv defines functions used to handle two-factor authentication requests.
+type TOTPSrv interface {
+	// Get returns the enrollment of the user.
+	Get(ctx context.Context, username string) (*v1.TOTP, error)
+	// Enroll starts a new enrollment of the user, replacing a pending one. It is
+	// enabled once confirmed by a first code.
+	Enroll(ctx context.Context, username, issuer string) (*v1.TOTPEnrollment, error)
+	// Confirm enables the pending enrollment of the user.
+	Confirm(ctx context.Context, username, passcode string) error
+	// Verify checks a code of the user, or consumes one of the recovery codes.
+	Verify(ctx context.Context, username, passcode string) error
+	// Disable disables two-factor authentication, a code or a recovery code is required.
+	Disable(ctx context.Context, username, passcode string) error
+	// RegenerateRecoveryCodes replaces the recovery codes of the user, a code is required.
+	RegenerateRecoveryCodes(ctx context.Context, username, passcode string) ([]string, error)
+}
+
+type totpService struct {
+	store store.Factory
+}
+
+var _ TOTPSrv = (*totpService)(nil)
+
+func newTOTPs(srv *service) *totpService {
+	return &totpService{store: srv.store}
+}
+
+func (s *totpService) Get(ctx context.Context, username string) (*v1.TOTP, error) {
+	return s.store.TOTPs().Get(ctx, username)
+}
+
+func (s *totpService) Enroll(ctx context.Context, username, issuer string) (*v1.TOTPEnrollment, error) {
+	current, err := s.store.TOTPs().Get(ctx, username)
+	if err != nil && !errors.IsCode(err, code.ErrTOTPNotEnabled) {
+		return nil, err
+	}
+
+	if current.Enabled() {
+		return nil, errors.WithCode(code.ErrTOTPAlreadyEnabled, "user %s is already enrolled", username)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	enrollment := &v1.TOTP{
+		Username:      username,
+		Secret:        secret,
+		RecoveryCodes: hashes,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.store.TOTPs().Create(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	return &v1.TOTPEnrollment{
+		Secret:        secret,
+		URI:           totpGenerator.URI(issuer, username, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *totpService) Confirm(ctx context.Context, username, passcode string) error {
+	enrollment, err := s.store.TOTPs().Get(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if enrollment.Enabled() {
+		return errors.WithCode(code.ErrTOTPAlreadyEnabled, "user %s is already enrolled", username)
+	}
+
+	step, ok := totpGenerator.Validate(enrollment.Secret, passcode, enrollment.LastStep)
+	if !ok {
+		return errors.WithCode(code.ErrTOTPCodeInvalid, "invalid code")
+	}
+
+	now := time.Now()
+	enrollment.EnabledAt = &now
+	enrollment.LastStep = step
+
+	return s.store.TOTPs().Update(ctx, enrollment)
+}
+
+func (s *totpService) Verify(ctx context.Context, username, passcode string) error {
+	enrollment, err := s.enabled(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := totpGenerator.Validate(enrollment.Secret, passcode, enrollment.LastStep); ok {
+		return s.store.TOTPs().UseStep(ctx, username, step)
+	}
+
+	hashes := strings.Split(enrollment.RecoveryCodes, ",")
+	hash := hashRecoveryCode(passcode)
+	for i := range hashes {
+		if hashes[i] != hash {
+			continue
+		}
+
+		remaining := strings.Join(append(hashes[:i:i], hashes[i+1:]...), ",")
+		if err := s.store.TOTPs().UseRecoveryCode(ctx, username, enrollment.RecoveryCodes, remaining); err != nil {
+			return err
+		}
+
+		log.L(ctx).Infof("recovery code used by user %s, %d left", username, len(hashes)-1)
+
+		return nil
+	}
+
+	return errors.WithCode(code.ErrTOTPCodeInvalid, "invalid code")
+}
+
+func (s *totpService) Disable(ctx context.Context, username, passcode string) error {
+	if err := s.Verify(ctx, username, passcode); err != nil {
+		return err
+	}
+
+	return s.store.TOTPs().Delete(ctx, username)
+}
+
+func (s *totpService) RegenerateRecoveryCodes(ctx context.Context, username, passcode string) ([]string, error) {
+	enrollment, err := s.enabled(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := totpGenerator.Validate(enrollment.Secret, passcode, enrollment.LastStep)
+	if !ok {
+		return nil, errors.WithCode(code.ErrTOTPCodeInvalid, "invalid code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	enrollment.RecoveryCodes = hashes
+	enrollment.LastStep = step
+	if err := s.store.TOTPs().Update(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// enabled returns the enrollment of the user, if it has been confirmed.
+func (s *totpService) enabled(ctx context.Context, username string) (*v1.TOTP, error) {
+	enrollment, err := s.store.TOTPs().Get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enrollment.Enabled() {
+		return nil, errors.WithCode(code.ErrTOTPNotEnabled, "enrollment of user %s is not confirmed", username)
+	}
+
+	return enrollment, nil
+}
+
+// newRecoveryCodes returns new random recovery codes, formatted as xxxxx-xxxxx,
+// together with their comma separated hashes.
+func newRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+
+		// 256 is a multiple of the alphabet size, so the symbols are uniform.
+		for j := range b {
+			b[j] = recoveryCodeAlphabet[int(b[j])%len(recoveryCodeAlphabet)]
+		}
+
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, strings.Join(hashes, ","), nil
+}
+
+// hashRecoveryCode returns the hash a recovery code is stored as, regardless of
+// its case and separators.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/totp"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+// memoryTOTPs implements store.TOTPStore in memory.
+type memoryTOTPs map[string]*v1.TOTP
+
+func (m memoryTOTPs) Create(ctx context.Context, t *v1.TOTP) error {
+	m[t.Username] = t
+
+	return nil
+}
+
+func (m memoryTOTPs) Get(ctx context.Context, username string) (*v1.TOTP, error) {
+	t, ok := m[username]
+	if !ok {
+		return nil, errors.WithCode(code.ErrTOTPNotEnabled, "not enrolled")
+	}
+
+	copied := *t
+
+	return &copied, nil
+}
+
+func (m memoryTOTPs) Update(ctx context.Context, t *v1.TOTP) error {
+	m[t.Username] = t
+
+	return nil
+}
+
+func (m memoryTOTPs) UseStep(ctx context.Context, username string, step int64) error {
+	if m[username].LastStep >= step {
+		return errors.WithCode(code.ErrTOTPCodeInvalid, "code has already been used")
+	}
+
+	m[username].LastStep = step
+
+	return nil
+}
+
+func (m memoryTOTPs) UseRecoveryCode(ctx context.Context, username, codes, remaining string) error {
+	if m[username].RecoveryCodes != codes {
+		return errors.WithCode(code.ErrTOTPCodeInvalid, "recovery code has already been used")
+	}
+
+	m[username].RecoveryCodes = remaining
+
+	return nil
+}
+
+func (m memoryTOTPs) Delete(ctx context.Context, username string) error {
+	delete(m, username)
+
+	return nil
+}
+
+func TestTOTPEnrollment(t *testing.T) {
+	totps := memoryTOTPs{}
+	srv := newTestService(testStores{"TOTPs": totps}).TOTPs()
+
+	clock := &fakeClock{now: time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)}
+	defer func(g *totp.TOTP) { totpGenerator = g }(totpGenerator)
+	totpGenerator = totp.New()
+	totpGenerator.Clock = clock
+
+	ctx := context.Background()
+
+	enrollment, err := srv.Enroll(ctx, "colin", "iam")
+	assert.Nil(t, err)
+	assert.Len(t, enrollment.RecoveryCodes, recoveryCodeCount)
+	u, _ := url.Parse(enrollment.URI)
+	assert.Equal(t, enrollment.Secret, u.Query().Get("secret"))
+
+	// codes are refused until the enrollment is confirmed.
+	current, _ := totpGenerator.Code(enrollment.Secret, clock.now)
+	assert.True(t, errors.IsCode(srv.Verify(ctx, "colin", current), code.ErrTOTPNotEnabled))
+
+	assert.True(t, errors.IsCode(srv.Confirm(ctx, "colin", "000000"), code.ErrTOTPCodeInvalid))
+	assert.Nil(t, srv.Confirm(ctx, "colin", current))
+	assert.True(t, totps["colin"].Enabled())
+
+	_, err = srv.Enroll(ctx, "colin", "iam")
+	assert.True(t, errors.IsCode(err, code.ErrTOTPAlreadyEnabled))
+
+	// the confirmation code cannot be replayed, the next one is accepted.
+	assert.True(t, errors.IsCode(srv.Verify(ctx, "colin", current), code.ErrTOTPCodeInvalid))
+	clock.now = clock.now.Add(totp.DefaultPeriod)
+	next, _ := totpGenerator.Code(enrollment.Secret, clock.now)
+	assert.Nil(t, srv.Verify(ctx, "colin", next))
+
+	// recovery codes are accepted once, whatever their case.
+	recovery := enrollment.RecoveryCodes[0]
+	assert.Nil(t, srv.Verify(ctx, "colin", "  "+recovery[:5]+recovery[6:]))
+	assert.True(t, errors.IsCode(srv.Verify(ctx, "colin", recovery), code.ErrTOTPCodeInvalid))
+
+	assert.Nil(t, srv.Disable(ctx, "colin", enrollment.RecoveryCodes[1]))
+	assert.NotContains(t, totps, "colin")
+}
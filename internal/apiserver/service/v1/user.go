@@ -4,12 +4,19 @@ import (
 	"context"
 	"regexp"
 	"sync"
+	"time"
 
 	v1 "github.com/marmotedu/api/apiserver/v1"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/event"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/lockout"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
@@ -23,6 +30,15 @@ type UserSrv interface {
 	List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error)
 	ListWithBadPerformance(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error)
 	ChangePassword(ctx context.Context, user *v1.User) error
+	// Unlock clears the failed logins and the lockout of the user, and of the
+	// given client IPs the user logs in from.
+	Unlock(ctx context.Context, username string, clientIPs []string) error
+	// Suspend suspends the user for the given reason, and revokes all the
+	// tokens and the sessions of the user.
+	Suspend(ctx context.Context, username, reason string) error
+	// Reactivate activates the suspended, disabled or pending user again.
+	Reactivate(ctx context.Context, username string) error
+	ListStatusChanges(ctx context.Context, username string, opts metav1.ListOptions) (*model.UserStatusChangeList, error)
 }
 
 type userService struct {
@@ -147,24 +163,66 @@ func (u *userService) Create(ctx context.Context, user *v1.User, opts metav1.Cre
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishUser(ctx, event.Added, user)
+	audit.Record(ctx, audit.ActionCreate, event.KindUser, user.Name, "", nil, user)
+
+	if user.Status == model.UserStatusPending {
+		// the user is created anyway, the verification can be sent again.
+		if err := (&emailVerificationService{store: u.store}).Send(ctx, user); err != nil {
+			log.L(ctx).Errorf("send email verification to user `%s` failed: %s", user.Name, err.Error())
+		}
+	}
+
 	return nil
 }
 
 func (u *userService) DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error {
+	before := make([]interface{}, len(usernames))
+	for i, username := range usernames {
+		before[i] = u.snapshot(ctx, username)
+		if err := u.recordDeletion(ctx, username); err != nil {
+			return err
+		}
+	}
 
 	if err := u.store.Users().DeleteCollection(ctx, usernames, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	for i, username := range usernames {
+		publishDeletion(ctx, event.KindUser, username, "")
+		audit.Record(ctx, audit.ActionDelete, event.KindUser, username, "", before[i], nil)
+	}
+
 	return nil
 }
 
 func (u *userService) Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error {
+	before := u.snapshot(ctx, username)
+
+	if err := u.recordDeletion(ctx, username); err != nil {
+		return err
+	}
 
 	if err := u.store.Users().Delete(ctx, username, opts); err != nil {
 		return err
 	}
 
+	publishDeletion(ctx, event.KindUser, username, "")
+	audit.Record(ctx, audit.ActionDelete, event.KindUser, username, "", before, nil)
+
+	return nil
+}
+
+// recordDeletion records the deletion in the status changes, which outlive the
+// user. Nothing is recorded for missing users, deleting them is not an error.
+func (u *userService) recordDeletion(ctx context.Context, username string) error {
+	err := u.changeStatus(ctx, username, model.UserStatusDeleted, "",
+		model.UserStatusDisabled, model.UserStatusActive, model.UserStatusPending, model.UserStatusSuspended)
+	if err != nil && !errors.IsCode(err, code.ErrUserStatusInvalid) {
+		return err
+	}
+
 	return nil
 }
 
@@ -179,18 +237,172 @@ func (u *userService) Get(ctx context.Context, username string, opts metav1.GetO
 }
 
 func (u *userService) Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error {
+	before := u.snapshot(ctx, user.Name)
+
 	if err := u.store.Users().Update(ctx, user, opts); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishUser(ctx, event.Modified, user)
+	audit.Record(ctx, audit.ActionUpdate, event.KindUser, user.Name, "", before, user)
+
 	return nil
 }
 
 func (u *userService) ChangePassword(ctx context.Context, user *v1.User) error {
+	before := u.snapshot(ctx, user.Name)
+
 	// Save changed fields.
 	if err := u.store.Users().Update(ctx, user, metav1.UpdateOptions{}); err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	publishUser(ctx, event.Modified, user)
+	audit.Record(ctx, audit.ActionUpdate, event.KindUser, user.Name, "", before, user)
+
+	// Tokens and sessions started with the old password must not be accepted anymore.
+	return revokeUser(ctx, u.store, user.Name)
+}
+
+func (u *userService) Unlock(ctx context.Context, username string, clientIPs []string) error {
+	// make sure the user exists, and belongs to the tenant of the request.
+	if _, err := u.store.Users().Get(ctx, username, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	if err := lockout.Unlock(lockout.Client(), username, clientIPs...); err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
 	return nil
 }
+
+func (u *userService) Suspend(ctx context.Context, username, reason string) error {
+	// tell missing users apart from the users which can not be suspended.
+	before, err := u.store.Users().Get(ctx, username, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = u.changeStatus(ctx, username, model.UserStatusSuspended, reason,
+		model.UserStatusDisabled, model.UserStatusActive, model.UserStatusPending)
+	if err != nil {
+		return err
+	}
+
+	u.publishStatus(ctx, before)
+
+	return revokeUser(ctx, u.store, username)
+}
+
+func (u *userService) Reactivate(ctx context.Context, username string) error {
+	before, err := u.store.Users().Get(ctx, username, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = u.changeStatus(ctx, username, model.UserStatusActive, "reactivated",
+		model.UserStatusDisabled, model.UserStatusPending, model.UserStatusSuspended)
+	if err != nil {
+		return err
+	}
+
+	u.publishStatus(ctx, before)
+
+	return nil
+}
+
+func (u *userService) ListStatusChanges(
+	ctx context.Context,
+	username string,
+	opts metav1.ListOptions,
+) (*model.UserStatusChangeList, error) {
+	if _, err := u.store.Users().Get(ctx, username, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	changes, err := u.store.UserStatuses().List(ctx, username, opts)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return changes, nil
+}
+
+// changeStatus moves the user from one of the from statuses to status, the
+// change is made by the user of the request.
+func (u *userService) changeStatus(ctx context.Context, username string, status int, reason string, from ...int) error {
+	return u.store.UserStatuses().Change(ctx, &model.UserStatusChange{
+		Username:  username,
+		Status:    status,
+		Reason:    reason,
+		Operator:  operatorOf(ctx),
+		CreatedAt: time.Now(),
+	}, from...)
+}
+
+// publishStatus publishes and audits the user after a change of its status,
+// before is the user before the change.
+func (u *userService) publishStatus(ctx context.Context, before *v1.User) {
+	user, err := u.store.Users().Get(ctx, before.Name, metav1.GetOptions{})
+	if err != nil {
+		log.L(ctx).Errorf("get user `%s` to publish its status failed: %s", before.Name, err.Error())
+
+		return
+	}
+
+	publishUser(ctx, event.Modified, user)
+	audit.Record(ctx, audit.ActionUpdate, event.KindUser, user.Name, "", before, user)
+}
+
+// snapshot returns the user before the request of ctx changes it, when the
+// request is audited.
+func (u *userService) snapshot(ctx context.Context, username string) interface{} {
+	return snapshot(ctx, func() (interface{}, error) {
+		return u.store.Users().Get(ctx, username, metav1.GetOptions{})
+	})
+}
+
+// CheckUserActive returns nil if the user can log in, otherwise the error
+// telling why the user can not.
+func CheckUserActive(user *v1.User) error {
+	switch user.Status {
+	case model.UserStatusActive:
+		return nil
+	case model.UserStatusPending:
+		return errors.WithCode(code.ErrUserPendingVerification, "user %s has not verified the email", user.Name)
+	case model.UserStatusSuspended:
+		return errors.WithCode(code.ErrUserSuspended, "user %s has been suspended", user.Name)
+	case model.UserStatusDisabled:
+		return errors.WithCode(code.ErrUserDisabled, "user %s has been disabled for inactivity", user.Name)
+	default:
+		return errors.WithCode(code.ErrUserNotFound, "user %s not found", user.Name)
+	}
+}
+
+// operatorOf returns the user who makes the request, empty if anonymous.
+func operatorOf(ctx context.Context) string {
+	username, _ := ctx.Value(middleware.UsernameKey).(string)
+
+	return username
+}
+
+// lookupUser returns the user with the username, or else with the email.
+func lookupUser(ctx context.Context, s store.Factory, username, email string) (*v1.User, error) {
+	if username != "" {
+		return s.Users().Get(ctx, username, metav1.GetOptions{})
+	}
+
+	return s.Users().GetByEmail(ctx, email, metav1.GetOptions{})
+}
+
+// revokeUser revokes all the access tokens and the sessions of the user. The
+// revocation watermark already rejects them, the sessions are also revoked in
+// the database so that they are no longer listed.
+func revokeUser(ctx context.Context, s store.Factory, username string) error {
+	if err := revocation.Client().RevokeUser(username, time.Now()); err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	return s.Sessions().RevokeAll(ctx, username)
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/notifier"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+)
+
+// memoryUserStatuses implements store.UserStatusStore in memory.
+type memoryUserStatuses struct {
+	users   memoryUsers
+	changes []*model.UserStatusChange
+}
+
+func (m *memoryUserStatuses) Change(ctx context.Context, change *model.UserStatusChange, from ...int) error {
+	user, ok := m.users[change.Username]
+	if !ok {
+		return errors.WithCode(code.ErrUserStatusInvalid, "user not found")
+	}
+
+	for _, status := range from {
+		if user.Status == status {
+			user.Status = change.Status
+			m.changes = append(m.changes, change)
+
+			return nil
+		}
+	}
+
+	return errors.WithCode(code.ErrUserStatusInvalid, "user can not change status")
+}
+
+func (m *memoryUserStatuses) List(
+	ctx context.Context,
+	username string,
+	opts metav1.ListOptions,
+) (*model.UserStatusChangeList, error) {
+	return &model.UserStatusChangeList{Items: m.changes}, nil
+}
+
+// memoryEmailVerifications implements store.EmailVerificationStore in memory.
+type memoryEmailVerifications map[string]*model.EmailVerification
+
+func (m memoryEmailVerifications) Create(ctx context.Context, verification *model.EmailVerification) error {
+	m[verification.Hash] = verification
+
+	return nil
+}
+
+func (m memoryEmailVerifications) Use(ctx context.Context, hash string) (*model.EmailVerification, error) {
+	verification, ok := m[hash]
+	if !ok || verification.UsedAt != nil || !verification.ExpiresAt.After(time.Now()) {
+		return nil, errors.WithCode(code.ErrEmailVerificationTokenInvalid, "email verification token is invalid")
+	}
+
+	now := time.Now()
+	verification.UsedAt = &now
+
+	return verification, nil
+}
+
+func (m memoryEmailVerifications) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newUserLifecycleTest(t *testing.T, users memoryUsers) (Service, *memoryUserStatuses, *sentMessages) {
+	statuses := &memoryUserStatuses{users: users}
+	srv := newTestService(testStores{
+		"Users":              users,
+		"UserStatuses":       statuses,
+		"EmailVerifications": memoryEmailVerifications{},
+		"Sessions":           newMemorySessions(),
+	})
+
+	sent := &sentMessages{}
+	previousNotifier := notifier.Client()
+	notifier.SetClient(sent)
+	t.Cleanup(func() { notifier.SetClient(previousNotifier) })
+
+	previousRevocation := revocation.Client()
+	revocation.SetClient(revokedUsers{})
+	t.Cleanup(func() { revocation.SetClient(previousRevocation) })
+
+	return srv, statuses, sent
+}
+
+func TestEmailVerification(t *testing.T) {
+	users := memoryUsers{}
+	srv, statuses, sent := newUserLifecycleTest(t, users)
+	ctx := context.Background()
+
+	user := &v1.User{ObjectMeta: metav1.ObjectMeta{Name: "colin"}, Email: "colin@example.com", Status: model.UserStatusPending}
+	assert.Nil(t, srv.Users().Create(ctx, user, metav1.CreateOptions{}))
+	assert.Len(t, sent.messages, 1)
+	assert.Equal(t, "colin@example.com", sent.messages[0].To)
+	assert.True(t, errors.IsCode(CheckUserActive(users["colin"]), code.ErrUserPendingVerification))
+
+	token := sent.token()
+	assert.Nil(t, srv.EmailVerifications().Verify(ctx, token))
+	assert.Equal(t, model.UserStatusActive, users["colin"].Status)
+	assert.Equal(t, "colin", statuses.changes[0].Operator)
+
+	err := srv.EmailVerifications().Verify(ctx, token)
+	assert.True(t, errors.IsCode(err, code.ErrEmailVerificationTokenInvalid))
+
+	// active users are not sent a verification again.
+	assert.Nil(t, srv.EmailVerifications().Resend(ctx, "colin", ""))
+	assert.Len(t, sent.messages, 1)
+}
+
+func TestEmailVerificationChangedEmail(t *testing.T) {
+	users := memoryUsers{"colin": {
+		ObjectMeta: metav1.ObjectMeta{Name: "colin"},
+		Email:      "colin@example.com",
+		Status:     model.UserStatusPending,
+	}}
+	srv, _, sent := newUserLifecycleTest(t, users)
+	ctx := context.Background()
+
+	assert.Nil(t, srv.EmailVerifications().Resend(ctx, "", "colin@example.com"))
+	users["colin"].Email = "colin@example.org"
+
+	err := srv.EmailVerifications().Verify(ctx, sent.token())
+	assert.True(t, errors.IsCode(err, code.ErrEmailVerificationTokenInvalid))
+	assert.Equal(t, model.UserStatusPending, users["colin"].Status)
+}
+
+func TestUserSuspendReactivate(t *testing.T) {
+	users := activeUsers("colin")
+	srv, statuses, _ := newUserLifecycleTest(t, users)
+	// nolint: staticcheck // gin.Context stores the username with a plain string key.
+	ctx := context.WithValue(context.Background(), middleware.UsernameKey, "admin")
+
+	assert.Nil(t, srv.Users().Suspend(ctx, "colin", "fraud"))
+	assert.True(t, errors.IsCode(CheckUserActive(users["colin"]), code.ErrUserSuspended))
+	assert.Equal(t, "fraud", statuses.changes[0].Reason)
+	assert.Equal(t, "admin", statuses.changes[0].Operator)
+
+	revokedBefore, _ := revocation.Client().UserRevokedBefore("colin")
+	assert.False(t, revokedBefore.IsZero(), "tokens and sessions of suspended users are revoked")
+
+	err := srv.Users().Suspend(ctx, "colin", "fraud")
+	assert.True(t, errors.IsCode(err, code.ErrUserStatusInvalid))
+
+	assert.Nil(t, srv.Users().Reactivate(ctx, "colin"))
+	assert.Nil(t, CheckUserActive(users["colin"]))
+
+	err = srv.Users().Reactivate(ctx, "colin")
+	assert.True(t, errors.IsCode(err, code.ErrUserStatusInvalid))
+
+	err = srv.Users().Suspend(ctx, "maria", "fraud")
+	assert.True(t, errors.IsCode(err, code.ErrUserNotFound))
+}
+
+func TestUserDeleteRecordsStatusChange(t *testing.T) {
+	users := memoryUsers{"colin": {ObjectMeta: metav1.ObjectMeta{Name: "colin"}, Status: model.UserStatusDisabled}}
+	srv, statuses, _ := newUserLifecycleTest(t, users)
+
+	assert.Nil(t, srv.Users().Delete(context.Background(), "colin", metav1.DeleteOptions{}))
+	assert.NotContains(t, users, "colin")
+	assert.Equal(t, model.UserStatusDeleted, statuses.changes[0].Status)
+
+	// deleting missing users is not an error.
+	assert.Nil(t, srv.Users().Delete(context.Background(), "maria", metav1.DeleteOptions{}))
+	assert.Len(t, statuses.changes, 1)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apiserver
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/spf13/viper"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+)
+
+// sessionManager implements auth.SessionManager with the session service.
+// A session is over when its refresh token has not been used for jwt.max-refresh.
+type sessionManager struct {
+	ttl time.Duration
+}
+
+var _ auth.SessionManager = sessionManager{}
+
+func newSessionManager() auth.SessionManager {
+	return sessionManager{ttl: viper.GetDuration("jwt.max-refresh")}
+}
+
+func (m sessionManager) Start(c *gin.Context, data interface{}) (*auth.Session, error) {
+	user, ok := data.(*v1.User)
+	if !ok {
+		return nil, errors.WithCode(code.ErrUnknown, "unexpected authenticated data %T", data)
+	}
+
+	session := &model.Session{
+		Username:  user.Name,
+		UserAgent: c.Request.UserAgent(),
+		ClientIP:  c.ClientIP(),
+	}
+
+	token, err := srvv1.NewService(store.Client()).Sessions().Create(c, session, m.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Session{ID: session.SessionID, RefreshToken: token, ExpiresAt: session.ExpiresAt, Data: user}, nil
+}
+
+func (m sessionManager) Refresh(c *gin.Context, refreshToken string) (*auth.Session, error) {
+	client := &model.Session{
+		UserAgent: c.Request.UserAgent(),
+		ClientIP:  c.ClientIP(),
+	}
+
+	session, token, err := srvv1.NewService(store.Client()).Sessions().Refresh(c, refreshToken, client, m.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := store.Client().Users().Get(c, session.Username, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := srvv1.CheckUserActive(user); err != nil {
+		return nil, err
+	}
+
+	return &auth.Session{ID: session.SessionID, RefreshToken: token, ExpiresAt: session.ExpiresAt, Data: user}, nil
+}
+
+func (m sessionManager) End(c *gin.Context, username, sid string) error {
+	return srvv1.NewService(store.Client()).Sessions().Revoke(c, username, sid)
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// AccessTokenStore defines the personal access token storage interface.
+type AccessTokenStore interface {
+	Create(ctx context.Context, token *v1.AccessToken) error
+	Get(ctx context.Context, username, name string) (*v1.AccessToken, error)
+	// GetByHash returns the token with the given hash, it fails with
+	// code.ErrAccessTokenInvalid if there is none.
+	GetByHash(ctx context.Context, hash string) (*v1.AccessToken, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.AccessTokenList, error)
+	Delete(ctx context.Context, username, name string) error
+	// RecordUsage records that the token was used at the given time, it never
+	// moves the last used time of the token backwards.
+	RecordUsage(ctx context.Context, id uint64, usedAt time.Time) error
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// AuditLogStore defines the audit log storage interface.
+type AuditLogStore interface {
+	Create(ctx context.Context, logs ...*v1.AuditLog) error
+	// Get returns the audit log of the tenant of the request by its id.
+	Get(ctx context.Context, id uint64) (*v1.AuditLog, error)
+	// List returns the audit logs of the tenant of the request matching opts,
+	// the latest first.
+	List(ctx context.Context, opts v1.AuditLogListOptions) (*v1.AuditLogList, error)
+	// ClearOutdated deletes the audit logs older than maxReserveDays days.
+	ClearOutdated(ctx context.Context, maxReserveDays int) (int64, error)
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// BackupStore defines the storage interface of the backup and restore of a tenant.
+type BackupStore interface {
+	// Export reads the users, secrets, policies and items of the tenant of the
+	// request in one consistent snapshot, and passes them to fn in this order
+	// as *v1.User, *model.Secret, *v1.Policy and *model.BackupItem.
+	Export(ctx context.Context, fn func(obj interface{}) error) error
+	// Restore writes the resources returned by next into the tenant of the
+	// request, in one transaction, until next returns io.EOF. The resources
+	// which already exist are handled according to the conflict policy.
+	Restore(ctx context.Context, conflict string, next func() (interface{}, error)) (*model.RestoreResult, error)
+}
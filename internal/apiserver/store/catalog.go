@@ -0,0 +1,35 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+)
+
+// CatalogStore defines the storage interface of the catalog import and export.
+type CatalogStore interface {
+	// Upsert creates or updates the items of the rows together with their
+	// attributes, offers and images, in one transaction. It returns how many
+	// items were created and updated.
+	Upsert(ctx context.Context, rows []*v1.CatalogRow) (created, updated int, err error)
+	// Export returns a page of the items selected by opts as catalog rows, with
+	// the same field selectors as ItemStore.List.
+	Export(ctx context.Context, opts metav1.ListOptions) ([]*v1.CatalogRow, error)
+
+	CreateImportJob(ctx context.Context, job *v1.ImportJob) error
+	UpdateImportJob(ctx context.Context, job *v1.ImportJob) error
+	GetImportJob(ctx context.Context, id uint64) (*v1.ImportJob, error)
+	AddImportErrors(ctx context.Context, errs []*v1.ImportRowError) error
+	ListImportErrors(ctx context.Context, jobID uint64, opts metav1.ListOptions) (*v1.ImportRowErrorList, error)
+	// FailStaleImportJobs marks failed the pending and running jobs of all the
+	// tenants which have not been updated since before. It returns how many
+	// jobs were failed.
+	FailStaleImportJobs(ctx context.Context, before time.Time, message string) (int64, error)
+}
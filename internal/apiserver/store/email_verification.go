@@ -0,0 +1,24 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// EmailVerificationStore defines the email verification storage interface.
+type EmailVerificationStore interface {
+	// Create creates a new email verification, replacing the pending ones of the user.
+	Create(ctx context.Context, verification *v1.EmailVerification) error
+	// Use marks the unused and unexpired email verification with the given
+	// hash as used and returns it. It fails with
+	// code.ErrEmailVerificationTokenInvalid otherwise.
+	Use(ctx context.Context, hash string) (*v1.EmailVerification, error)
+	// DeleteExpired deletes the email verifications which expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// GroupStore defines the group storage interface.
+type GroupStore interface {
+	Create(ctx context.Context, group *v1.Group, opts metav1.CreateOptions) error
+	Update(ctx context.Context, group *v1.Group, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Group, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.GroupList, error)
+	AddMember(ctx context.Context, member *v1.GroupMember, opts metav1.CreateOptions) error
+	RemoveMember(ctx context.Context, group, username string, opts metav1.DeleteOptions) error
+	// ListMembers returns the members of the given group, or of all the groups if group is empty.
+	ListMembers(ctx context.Context, group string, opts metav1.ListOptions) (*v1.GroupMemberList, error)
+}
@@ -45,6 +45,16 @@ func (m *MockFactory) ItemImage() ItemImageStore {
 	return args.Get(0).(ItemImageStore)
 }
 
+func (m *MockFactory) Catalog() CatalogStore {
+	args := m.Called()
+	return args.Get(0).(CatalogStore)
+}
+
+func (m *MockFactory) Backups() BackupStore {
+	args := m.Called()
+	return args.Get(0).(BackupStore)
+}
+
 func (m *MockFactory) Users() UserStore {
 	args := m.Called()
 	return args.Get(0).(UserStore)
@@ -65,6 +75,71 @@ func (m *MockFactory) PolicyAudits() PolicyAuditStore {
 	return args.Get(0).(PolicyAuditStore)
 }
 
+func (m *MockFactory) Groups() GroupStore {
+	args := m.Called()
+	return args.Get(0).(GroupStore)
+}
+
+func (m *MockFactory) Roles() RoleStore {
+	args := m.Called()
+	return args.Get(0).(RoleStore)
+}
+
+func (m *MockFactory) PolicyBindings() PolicyBindingStore {
+	args := m.Called()
+	return args.Get(0).(PolicyBindingStore)
+}
+
+func (m *MockFactory) Tenants() TenantStore {
+	args := m.Called()
+	return args.Get(0).(TenantStore)
+}
+
+func (m *MockFactory) SigningKeys() SigningKeyStore {
+	args := m.Called()
+	return args.Get(0).(SigningKeyStore)
+}
+
+func (m *MockFactory) Sessions() SessionStore {
+	args := m.Called()
+	return args.Get(0).(SessionStore)
+}
+
+func (m *MockFactory) TOTPs() TOTPStore {
+	args := m.Called()
+	return args.Get(0).(TOTPStore)
+}
+
+func (m *MockFactory) PasswordResets() PasswordResetStore {
+	args := m.Called()
+	return args.Get(0).(PasswordResetStore)
+}
+
+func (m *MockFactory) AuditLogs() AuditLogStore {
+	args := m.Called()
+	return args.Get(0).(AuditLogStore)
+}
+
+func (m *MockFactory) UserStatuses() UserStatusStore {
+	args := m.Called()
+	return args.Get(0).(UserStatusStore)
+}
+
+func (m *MockFactory) EmailVerifications() EmailVerificationStore {
+	args := m.Called()
+	return args.Get(0).(EmailVerificationStore)
+}
+
+func (m *MockFactory) UserIdentities() UserIdentityStore {
+	args := m.Called()
+	return args.Get(0).(UserIdentityStore)
+}
+
+func (m *MockFactory) AccessTokens() AccessTokenStore {
+	args := m.Called()
+	return args.Get(0).(AccessTokenStore)
+}
+
 type MockItemStore struct {
 	mock.Mock
 }
@@ -0,0 +1,111 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+)
+
+type accessTokens struct {
+	db *gorm.DB
+}
+
+func newAccessTokens(ds *datastore) *accessTokens {
+	return &accessTokens{ds.db}
+}
+
+// Create creates a new personal access token.
+func (a *accessTokens) Create(ctx context.Context, token *v1.AccessToken) error {
+	return a.db.Create(token).Error
+}
+
+// Get returns the access token of the user by its name.
+func (a *accessTokens) Get(ctx context.Context, username, name string) (*v1.AccessToken, error) {
+	token := &v1.AccessToken{}
+	err := a.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name = ?", username, name).
+		First(token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrAccessTokenNotFound, "access token %s not found", name)
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return token, nil
+}
+
+// GetByHash returns the access token with the given hash.
+func (a *accessTokens) GetByHash(ctx context.Context, hash string) (*v1.AccessToken, error) {
+	token := &v1.AccessToken{}
+	if err := a.db.Where("hash = ?", hash).First(token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrAccessTokenInvalid, "access token is unknown or has been revoked")
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return token, nil
+}
+
+// List returns the access tokens of the user.
+func (a *accessTokens) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.AccessTokenList, error) {
+	ret := &v1.AccessTokenList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	d := a.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ?", username).
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+	if d.Error != nil {
+		return nil, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return ret, nil
+}
+
+// Delete revokes the access token of the user by deleting it.
+func (a *accessTokens) Delete(ctx context.Context, username, name string) error {
+	d := a.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name = ?", username, name).
+		Delete(&v1.AccessToken{})
+	if d.Error != nil {
+		return errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	if d.RowsAffected == 0 {
+		return errors.WithCode(code.ErrAccessTokenNotFound, "access token %s not found", name)
+	}
+
+	return nil
+}
+
+// RecordUsage records that the access token was used at the given time.
+func (a *accessTokens) RecordUsage(ctx context.Context, id uint64, usedAt time.Time) error {
+	err := a.db.Model(&v1.AccessToken{}).
+		Where("id = ? and (lastUsedAt is null or lastUsedAt < ?)", id, usedAt).
+		UpdateColumn("lastUsedAt", usedAt).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+)
+
+type auditLogs struct {
+	db *gorm.DB
+}
+
+func newAuditLogs(ds *datastore) *auditLogs {
+	return &auditLogs{ds.db}
+}
+
+// Create stores the audit logs of a request at once.
+func (a *auditLogs) Create(ctx context.Context, logs ...*model.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	return a.db.Create(logs).Error
+}
+
+// Get returns the audit log of the tenant of the request by its id.
+func (a *auditLogs) Get(ctx context.Context, id uint64) (*model.AuditLog, error) {
+	log := &model.AuditLog{}
+
+	err := a.db.Scopes(byTenant(ctx)).Where("id = ?", id).First(log).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrAuditLogNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return log, nil
+}
+
+// List returns the audit logs of the tenant of the request matching opts, the
+// latest first.
+func (a *auditLogs) List(ctx context.Context, opts model.AuditLogListOptions) (*model.AuditLogList, error) {
+	ret := &model.AuditLogList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := a.db.Scopes(byTenant(ctx))
+
+	for column, value := range map[string]string{
+		"actor":     opts.Actor,
+		"action":    opts.Action,
+		"kind":      opts.Kind,
+		"name":      opts.Name,
+		"requestID": opts.RequestID,
+	} {
+		if value != "" {
+			db = db.Where(column+" = ?", value)
+		}
+	}
+
+	if opts.Since != nil {
+		db = db.Where("createdAt >= ?", *opts.Since)
+	}
+
+	if opts.Until != nil {
+		db = db.Where("createdAt < ?", *opts.Until)
+	}
+
+	d := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+	if d.Error != nil {
+		return nil, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return ret, nil
+}
+
+// ClearOutdated deletes the audit logs older than maxReserveDays days.
+func (a *auditLogs) ClearOutdated(ctx context.Context, maxReserveDays int) (int64, error) {
+	date := time.Now().AddDate(0, 0, -maxReserveDays)
+
+	d := a.db.Where("createdAt < ?", date).Delete(&model.AuditLog{})
+
+	return d.RowsAffected, d.Error
+}
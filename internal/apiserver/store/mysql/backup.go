@@ -0,0 +1,503 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+// backupBatchSize is the number of rows read at once by the export.
+const backupBatchSize = 500
+
+// What the restore did with a resource.
+const (
+	restoreCreated = iota
+	restoreOverwritten
+	restoreSkipped
+)
+
+type backups struct {
+	db *gorm.DB
+}
+
+func newBackups(ds *datastore) *backups {
+	return &backups{ds.db}
+}
+
+// Export reads the tenant of the request in a read only, repeatable read
+// transaction, so all the tables are read from the same snapshot.
+func (b *backups) Export(ctx context.Context, fn func(obj interface{}) error) error {
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		var users []*v1.User
+		if err := exportBatches(tx.Scopes(byTenantUser(ctx, "name")), &users, func() error {
+			for _, user := range users {
+				if err := fn(user); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		var secrets []*model.Secret
+		if err := exportBatches(tx.Scopes(byTenantUser(ctx, "username")), &secrets, func() error {
+			for _, secret := range secrets {
+				if err := fn(secret); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		var policies []*v1.Policy
+		if err := exportBatches(tx.Scopes(byTenantUser(ctx, "username")), &policies, func() error {
+			for _, policy := range policies {
+				if err := fn(policy); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		var items []*itemv1.Item
+
+		return exportBatches(tx.Scopes(byTenant(ctx)), &items, func() error {
+			backupItems, err := loadItemTables(tx, items)
+			if err != nil {
+				return err
+			}
+
+			for _, item := range backupItems {
+				if err := fn(item); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// exportBatches reads the rows of the query into dest in batches, in the order
+// of their ids, and calls fn after each batch.
+func exportBatches(query *gorm.DB, dest interface{}, fn func() error) error {
+	return query.FindInBatches(dest, backupBatchSize, func(tx *gorm.DB, batch int) error {
+		return fn()
+	}).Error
+}
+
+// loadItemTables returns the items together with the rows of the item tables
+// which belong to them.
+func loadItemTables(tx *gorm.DB, items []*itemv1.Item) ([]*model.BackupItem, error) {
+	ids := make([]uint64, 0, len(items))
+	byID := make(map[uint64]*model.BackupItem, len(items))
+	backupItems := make([]*model.BackupItem, 0, len(items))
+
+	for _, item := range items {
+		ids = append(ids, item.ID)
+		byID[item.ID] = &model.BackupItem{Item: item}
+		backupItems = append(backupItems, byID[item.ID])
+	}
+
+	var (
+		attributes []*itemv1.ItemAttributes
+		images     []*itemv1.ItemImage
+		summaries  []*itemv1.ItemSummaryByMarketplace
+		issues     []*itemv1.Issue
+		offers     []*itemv1.ItemOfferByMarketplace
+	)
+
+	for _, related := range []interface{}{&attributes, &images, &summaries, &issues, &offers} {
+		if err := tx.Where("item_id in ?", ids).Order("id").Find(related).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range attributes {
+		byID[a.ItemID].Attributes = append(byID[a.ItemID].Attributes, a)
+	}
+
+	for _, i := range images {
+		byID[i.ItemID].Images = append(byID[i.ItemID].Images, i)
+	}
+
+	for _, s := range summaries {
+		byID[s.ItemID].Summaries = append(byID[s.ItemID].Summaries, s)
+	}
+
+	for _, i := range issues {
+		byID[i.ItemID].Issues = append(byID[i.ItemID].Issues, i)
+	}
+
+	for _, o := range offers {
+		byID[o.ItemID].Offers = append(byID[o.ItemID].Offers, o)
+	}
+
+	return backupItems, nil
+}
+
+// Restore writes the restored resources in one transaction, a failure rolls
+// back the whole restore.
+func (b *backups) Restore(
+	ctx context.Context,
+	conflict string,
+	next func() (interface{}, error),
+) (*model.RestoreResult, error) {
+	var result *model.RestoreResult
+
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		r := &restorer{
+			ctx:      ctx,
+			tx:       tx,
+			conflict: conflict,
+			owners:   map[string]bool{},
+			result: &model.RestoreResult{
+				Created:     map[string]int{},
+				Overwritten: map[string]int{},
+				Skipped:     map[string]int{},
+			},
+		}
+
+		for {
+			obj, err := next()
+			if err == io.EOF {
+				result = r.result
+
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := r.restore(obj); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// restorer restores the resources of a restore transaction.
+type restorer struct {
+	ctx      context.Context
+	tx       *gorm.DB
+	conflict string
+	// owners caches whether the owners of the secrets and policies are users
+	// of the tenant.
+	owners map[string]bool
+	result *model.RestoreResult
+}
+
+func (r *restorer) restore(obj interface{}) error {
+	var kind string
+	var action int
+	var err error
+
+	switch obj := obj.(type) {
+	case *v1.User:
+		kind = model.BackupKindUser
+		action, err = r.restoreUser(obj)
+	case *model.Secret:
+		kind = model.BackupKindSecret
+		action, err = r.restoreSecret(obj)
+	case *v1.Policy:
+		kind = model.BackupKindPolicy
+		action, err = r.restorePolicy(obj)
+	case *model.BackupItem:
+		kind = model.BackupKindItem
+		action, err = r.restoreItem(obj)
+	default:
+		return errors.WithCode(code.ErrBackupArchive, "unexpected %T in the archive", obj)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case restoreCreated:
+		r.result.Created[kind]++
+	case restoreOverwritten:
+		r.result.Overwritten[kind]++
+	default:
+		r.result.Skipped[kind]++
+	}
+
+	return nil
+}
+
+// resolve tells what to do with a restored resource which already exists, or
+// returns an error when the restore must fail.
+func (r *restorer) resolve(kind, name string) (int, error) {
+	switch r.conflict {
+	case model.ConflictOverwrite:
+		return restoreOverwritten, nil
+	case model.ConflictSkip:
+		return restoreSkipped, nil
+	default:
+		return 0, errors.WithCode(code.ErrRestoreConflict, "%s %s already exists", strings.ToLower(kind), name)
+	}
+}
+
+func (r *restorer) restoreUser(user *v1.User) (int, error) {
+	existing := &v1.User{}
+	if err := r.tx.Where("name = ?", user.Name).Limit(1).Find(existing).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	if existing.ID == 0 {
+		user.ID, user.InstanceID = 0, ""
+		if err := r.tx.Create(user).Error; err != nil {
+			return 0, databaseError(err)
+		}
+
+		member := &model.TenantMember{Tenant: tenantOf(r.ctx), Username: user.Name}
+		if err := r.tx.Create(member).Error; err != nil {
+			return 0, databaseError(err)
+		}
+
+		return restoreCreated, nil
+	}
+
+	// the names of the users are unique among all the tenants.
+	inTenant, err := r.isOwner(user.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	if !inTenant && r.conflict != model.ConflictSkip {
+		return 0, errors.WithCode(code.ErrRestoreConflict, "user %s already exists in another tenant", user.Name)
+	}
+
+	action, err := r.resolve(model.BackupKindUser, user.Name)
+	if err != nil || action == restoreSkipped {
+		return action, err
+	}
+
+	user.ID, user.InstanceID = existing.ID, existing.InstanceID
+	if err := r.tx.Save(user).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	return action, nil
+}
+
+func (r *restorer) restoreSecret(secret *model.Secret) (int, error) {
+	if err := r.checkOwner(model.BackupKindSecret, secret.Name, secret.Username); err != nil {
+		return 0, err
+	}
+
+	existing := &model.Secret{}
+	if err := r.tx.Where("username = ? and name = ?", secret.Username, secret.Name).
+		Limit(1).Find(existing).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	if existing.ID == 0 {
+		secret.ID, secret.InstanceID = 0, ""
+		if err := r.tx.Create(secret).Error; err != nil {
+			return 0, databaseError(err)
+		}
+
+		return restoreCreated, nil
+	}
+
+	action, err := r.resolve(model.BackupKindSecret, secret.Username+"/"+secret.Name)
+	if err != nil || action == restoreSkipped {
+		return action, err
+	}
+
+	secret.ID, secret.InstanceID = existing.ID, existing.InstanceID
+	if err := r.tx.Save(secret).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	return action, nil
+}
+
+func (r *restorer) restorePolicy(policy *v1.Policy) (int, error) {
+	if err := r.checkOwner(model.BackupKindPolicy, policy.Name, policy.Username); err != nil {
+		return 0, err
+	}
+
+	existing := &v1.Policy{}
+	if err := r.tx.Where("username = ? and name = ?", policy.Username, policy.Name).
+		Limit(1).Find(existing).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	if existing.ID == 0 {
+		policy.ID, policy.InstanceID = 0, ""
+		if err := r.tx.Create(policy).Error; err != nil {
+			return 0, databaseError(err)
+		}
+
+		return restoreCreated, nil
+	}
+
+	action, err := r.resolve(model.BackupKindPolicy, policy.Username+"/"+policy.Name)
+	if err != nil || action == restoreSkipped {
+		return action, err
+	}
+
+	policy.ID, policy.InstanceID = existing.ID, existing.InstanceID
+	if err := r.tx.Save(policy).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	return action, nil
+}
+
+// restoreItem restores an item with its own identifier, the rows of the item
+// tables replace the existing ones.
+func (r *restorer) restoreItem(item *model.BackupItem) (int, error) {
+	if item.Item == nil {
+		return 0, errors.WithCode(code.ErrBackupArchive, "item without its item fields")
+	}
+
+	existing := &itemv1.Item{}
+	if err := r.tx.Where("id = ?", item.ID).Limit(1).Find(existing).Error; err != nil {
+		return 0, databaseError(err)
+	}
+
+	item.Tenant = tenantOf(r.ctx)
+	action := restoreCreated
+
+	if existing.ID == 0 {
+		if err := r.tx.Create(item.Item).Error; err != nil {
+			return 0, databaseError(err)
+		}
+	} else {
+		name := fmt.Sprint(item.ID)
+		if existing.Tenant != item.Tenant && r.conflict != model.ConflictSkip {
+			return 0, errors.WithCode(code.ErrRestoreConflict, "item %s already exists in another tenant", name)
+		}
+
+		var err error
+		if action, err = r.resolve(model.BackupKindItem, name); err != nil || action == restoreSkipped {
+			return action, err
+		}
+
+		for _, table := range []interface{}{
+			&itemv1.ItemAttributes{}, &itemv1.ItemImage{}, &itemv1.ItemSummaryByMarketplace{},
+			&itemv1.Issue{}, &itemv1.ItemOfferByMarketplace{},
+		} {
+			if err := r.tx.Where("item_id = ?", item.ID).Delete(table).Error; err != nil {
+				return 0, databaseError(err)
+			}
+		}
+
+		if err := r.tx.Save(item.Item).Error; err != nil {
+			return 0, databaseError(err)
+		}
+	}
+
+	for _, a := range item.Attributes {
+		a.ID, a.ItemID = 0, item.ID
+	}
+
+	for _, i := range item.Images {
+		i.ID, i.ItemID = 0, item.ID
+	}
+
+	for _, s := range item.Summaries {
+		s.ID, s.ItemID = 0, item.ID
+	}
+
+	for _, i := range item.Issues {
+		i.ID, i.ItemID = 0, item.ID
+	}
+
+	for _, o := range item.Offers {
+		o.ID, o.ItemID = 0, item.ID
+	}
+
+	for _, table := range []struct {
+		rows interface{}
+		len  int
+	}{
+		{item.Attributes, len(item.Attributes)},
+		{item.Images, len(item.Images)},
+		{item.Summaries, len(item.Summaries)},
+		{item.Issues, len(item.Issues)},
+		{item.Offers, len(item.Offers)},
+	} {
+		if table.len == 0 {
+			continue
+		}
+
+		if err := r.tx.CreateInBatches(table.rows, backupBatchSize).Error; err != nil {
+			return 0, databaseError(err)
+		}
+	}
+
+	return action, nil
+}
+
+// checkOwner makes sure the owner of a secret or a policy is a user of the tenant.
+func (r *restorer) checkOwner(kind, name, username string) error {
+	ok, err := r.isOwner(username)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.WithCode(code.ErrRestoreConflict, "%s %s belongs to %s, which is not a user of the tenant",
+			strings.ToLower(kind), name, username)
+	}
+
+	return nil
+}
+
+// isOwner tells whether the user exists in the tenant of the request.
+func (r *restorer) isOwner(username string) (bool, error) {
+	if ok, found := r.owners[username]; found {
+		return ok, nil
+	}
+
+	var count int64
+	if err := r.tx.Model(&v1.User{}).Scopes(byTenantUser(r.ctx, "name")).
+		Where("name = ?", username).Count(&count).Error; err != nil {
+		return false, databaseError(err)
+	}
+
+	r.owners[username] = count > 0
+
+	return count > 0, nil
+}
+
+func databaseError(err error) error {
+	return errors.WithCode(code.ErrDatabase, err.Error())
+}
@@ -0,0 +1,375 @@
+// Copyright 2023 Tal Huang <talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+)
+
+// releaseDateLayout is the layout of the release dates in the catalog rows.
+const releaseDateLayout = "2006-01-02"
+
+type catalog struct {
+	db  *gorm.DB
+	ids *snowflake.Node
+}
+
+func newCatalog(ds *datastore) *catalog {
+	// node 1 is always valid, the same node as the items created by the API.
+	ids, _ := snowflake.NewNode(1)
+
+	return &catalog{db: ds.db, ids: ids}
+}
+
+// Upsert creates or updates the items of the rows in the tenant of the request.
+func (c *catalog) Upsert(ctx context.Context, rows []*v1.CatalogRow) (created, updated int, err error) {
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		created, updated = 0, 0
+
+		for _, row := range rows {
+			isNew, err := c.upsertRow(ctx, tx, row)
+			if err != nil {
+				return fmt.Errorf("%s: %w", row.Key(), err)
+			}
+
+			if isNew {
+				created++
+			} else {
+				updated++
+			}
+		}
+
+		return nil
+	})
+
+	return created, updated, err
+}
+
+func (c *catalog) upsertRow(ctx context.Context, tx *gorm.DB, row *v1.CatalogRow) (bool, error) {
+	query := tx.Scopes(byTenant(ctx))
+	switch {
+	case row.ASIN != "" && row.SKU != "":
+		query = query.Where("asin = ? or sku = ?", row.ASIN, row.SKU)
+	case row.ASIN != "":
+		query = query.Where("asin = ?", row.ASIN)
+	default:
+		query = query.Where("sku = ?", row.SKU)
+	}
+
+	var matched []*v1.Item
+	if err := query.Limit(2).Find(&matched).Error; err != nil {
+		return false, err
+	}
+
+	if len(matched) > 1 {
+		return false, fmt.Errorf("asin %s and sku %s belong to different items", row.ASIN, row.SKU)
+	}
+
+	item := &v1.Item{Status: 1}
+	isNew := len(matched) == 0
+	if isNew {
+		item.ID = uint64(c.ids.Generate().Int64())
+		idStr := strconv.FormatUint(item.ID, 10)
+		item.InstanceID = "item-" + idStr
+		item.Name = "sku-" + idStr
+		item.SKU = item.Name
+		item.Tenant = tenantOf(ctx)
+	} else {
+		item = matched[0]
+	}
+
+	// the empty fields of the row leave the item untouched.
+	setString(&item.ASIN, row.ASIN)
+	setString(&item.SKU, row.SKU)
+	setString(&item.Brand, row.Brand)
+	setString(&item.Title, row.Title)
+	setString(&item.ProductGroup, row.ProductGroup)
+	setString(&item.ProductType, row.ProductType)
+	if row.Status != nil {
+		item.Status = *row.Status
+	}
+
+	if err := tx.Save(item).Error; err != nil {
+		return false, err
+	}
+
+	if err := upsertAttributes(tx, item.ID, row.Attributes); err != nil {
+		return false, err
+	}
+
+	for _, offer := range row.Offers {
+		if err := upsertOffer(tx, item.ID, offer); err != nil {
+			return false, err
+		}
+	}
+
+	for _, url := range row.Images {
+		var count int64
+		if err := tx.Model(&v1.ItemImage{}).Where("item_id = ? and image_url = ?", item.ID, url).
+			Count(&count).Error; err != nil {
+			return false, err
+		}
+
+		if count == 0 {
+			if err := tx.Create(&v1.ItemImage{ItemID: item.ID, ImageURL: url}).Error; err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return isNew, nil
+}
+
+func upsertAttributes(tx *gorm.DB, itemID uint64, row *v1.CatalogAttributes) error {
+	if row == nil {
+		return nil
+	}
+
+	attributes := &v1.ItemAttributes{}
+	if err := tx.Where("item_id = ?", itemID).Limit(1).Find(attributes).Error; err != nil {
+		return err
+	}
+
+	var releaseDate time.Time
+	if row.ReleaseDate != "" {
+		var err error
+		if releaseDate, err = time.Parse(releaseDateLayout, row.ReleaseDate); err != nil {
+			return err
+		}
+	}
+
+	attributes.ItemID = itemID
+	attributes.Binding = row.Binding
+	attributes.ItemHeight = row.ItemHeight
+	attributes.ItemLength = row.ItemLength
+	attributes.ItemWidth = row.ItemWidth
+	attributes.ItemWeight = row.ItemWeight
+	attributes.ItemDimensionsUnit = row.ItemDimensionsUnit
+	attributes.PackageHeight = row.PackageHeight
+	attributes.PackageLength = row.PackageLength
+	attributes.PackageWidth = row.PackageWidth
+	attributes.PackageWeight = row.PackageWeight
+	attributes.PackageDimensionsUnit = row.PackageDimensionsUnit
+	attributes.ReleaseDate = releaseDate
+
+	return tx.Save(attributes).Error
+}
+
+func upsertOffer(tx *gorm.DB, itemID uint64, row *v1.CatalogOffer) error {
+	offer := &v1.ItemOfferByMarketplace{}
+	if err := tx.Where("item_id = ? and marketplace_id = ?", itemID, row.MarketplaceID).
+		Limit(1).Find(offer).Error; err != nil {
+		return err
+	}
+
+	offer.ItemID = itemID
+	offer.MarketplaceID = row.MarketplaceID
+	offer.ListPrice = row.ListPrice
+	offer.CurrencyCode = row.CurrencyCode
+	offer.PackageQuantity = row.PackageQuantity
+	offer.AvailabilityStatus = row.AvailabilityStatus
+	offer.FulfillmentChannel = row.FulfillmentChannel
+
+	return tx.Save(offer).Error
+}
+
+func setString(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// Export returns a page of the items selected by opts as catalog rows.
+func (c *catalog) Export(ctx context.Context, opts metav1.ListOptions) ([]*v1.CatalogRow, error) {
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	var items []*v1.Item
+	if err := selectItems(ctx, c.db, opts.FieldSelector).
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id").
+		Find(&items).Error; err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	var (
+		attributes []*v1.ItemAttributes
+		offers     []*v1.ItemOfferByMarketplace
+		images     []*v1.ItemImage
+	)
+
+	for _, related := range []interface{}{&attributes, &offers, &images} {
+		if err := c.db.Where("item_id in ?", ids).Order("id").Find(related).Error; err != nil {
+			return nil, errors.WithCode(code.ErrDatabase, err.Error())
+		}
+	}
+
+	rows := make([]*v1.CatalogRow, 0, len(items))
+	byID := make(map[uint64]*v1.CatalogRow, len(items))
+
+	for _, item := range items {
+		status := item.Status
+		row := &v1.CatalogRow{
+			ASIN:         item.ASIN,
+			SKU:          item.SKU,
+			Brand:        item.Brand,
+			Title:        item.Title,
+			ProductGroup: item.ProductGroup,
+			ProductType:  item.ProductType,
+			Status:       &status,
+		}
+		rows = append(rows, row)
+		byID[item.ID] = row
+	}
+
+	for _, a := range attributes {
+		row := &v1.CatalogAttributes{
+			Binding:               a.Binding,
+			ItemHeight:            a.ItemHeight,
+			ItemLength:            a.ItemLength,
+			ItemWidth:             a.ItemWidth,
+			ItemWeight:            a.ItemWeight,
+			ItemDimensionsUnit:    a.ItemDimensionsUnit,
+			PackageHeight:         a.PackageHeight,
+			PackageLength:         a.PackageLength,
+			PackageWidth:          a.PackageWidth,
+			PackageWeight:         a.PackageWeight,
+			PackageDimensionsUnit: a.PackageDimensionsUnit,
+		}
+		if !a.ReleaseDate.IsZero() {
+			row.ReleaseDate = a.ReleaseDate.Format(releaseDateLayout)
+		}
+
+		byID[a.ItemID].Attributes = row
+	}
+
+	for _, o := range offers {
+		byID[o.ItemID].Offers = append(byID[o.ItemID].Offers, &v1.CatalogOffer{
+			MarketplaceID:      o.MarketplaceID,
+			ListPrice:          o.ListPrice,
+			CurrencyCode:       o.CurrencyCode,
+			PackageQuantity:    o.PackageQuantity,
+			AvailabilityStatus: o.AvailabilityStatus,
+			FulfillmentChannel: o.FulfillmentChannel,
+		})
+	}
+
+	for _, i := range images {
+		byID[i.ItemID].Images = append(byID[i.ItemID].Images, i.ImageURL)
+	}
+
+	return rows, nil
+}
+
+// CreateImportJob creates an import job in the tenant of the request.
+func (c *catalog) CreateImportJob(ctx context.Context, job *v1.ImportJob) error {
+	job.Tenant = tenantOf(ctx)
+
+	if err := c.db.Create(job).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// UpdateImportJob records the progress of an import job.
+func (c *catalog) UpdateImportJob(ctx context.Context, job *v1.ImportJob) error {
+	if err := c.db.Save(job).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// GetImportJob returns an import job of the tenant of the request.
+func (c *catalog) GetImportJob(ctx context.Context, id uint64) (*v1.ImportJob, error) {
+	job := &v1.ImportJob{}
+
+	err := c.db.Scopes(byTenant(ctx)).Where("id = ?", id).First(job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrImportJobNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return job, nil
+}
+
+// AddImportErrors records the rejected rows of an import job.
+func (c *catalog) AddImportErrors(ctx context.Context, errs []*v1.ImportRowError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	if err := c.db.CreateInBatches(errs, 100).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// ListImportErrors returns the rejected rows of an import job, in the order of the file.
+func (c *catalog) ListImportErrors(
+	ctx context.Context,
+	jobID uint64,
+	opts metav1.ListOptions,
+) (*v1.ImportRowErrorList, error) {
+	ret := &v1.ImportRowErrorList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	d := c.db.Where("job_id = ?", jobID).
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("`row`").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+	if d.Error != nil {
+		return nil, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return ret, nil
+}
+
+// FailStaleImportJobs marks failed the unfinished jobs which have not been
+// updated since before.
+func (c *catalog) FailStaleImportJobs(ctx context.Context, before time.Time, message string) (int64, error) {
+	d := c.db.Model(&v1.ImportJob{}).
+		Where("status in ? and updated_at < ?", []string{v1.ImportJobPending, v1.ImportJobRunning}, before).
+		Updates(map[string]interface{}{
+			"status":      v1.ImportJobFailed,
+			"message":     message,
+			"finished_at": time.Now(),
+		})
+	if d.Error != nil {
+		return 0, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return d.RowsAffected, nil
+}
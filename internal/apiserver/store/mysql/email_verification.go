@@ -0,0 +1,73 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+type emailVerifications struct {
+	db *gorm.DB
+}
+
+func newEmailVerifications(ds *datastore) *emailVerifications {
+	return &emailVerifications{ds.db}
+}
+
+// Create creates a new email verification, replacing the pending ones of the user.
+func (e *emailVerifications) Create(ctx context.Context, verification *v1.EmailVerification) error {
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("username = ? and usedAt is null", verification.Username).
+			Delete(&v1.EmailVerification{}).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if err := tx.Create(verification).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// Use marks the unused and unexpired email verification with the given hash as used.
+func (e *emailVerifications) Use(ctx context.Context, hash string) (*v1.EmailVerification, error) {
+	now := time.Now()
+	d := e.db.Model(&v1.EmailVerification{}).
+		Where("hash = ? and usedAt is null and expiresAt > ?", hash, now).
+		Update("usedAt", now)
+	if d.Error != nil {
+		return nil, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	if d.RowsAffected == 0 {
+		return nil, errors.WithCode(code.ErrEmailVerificationTokenInvalid,
+			"email verification token is invalid or expired")
+	}
+
+	verification := &v1.EmailVerification{}
+	if err := e.db.Where("hash = ?", hash).First(verification).Error; err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return verification, nil
+}
+
+// DeleteExpired deletes the email verifications which expired before the given time.
+func (e *emailVerifications) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	d := e.db.Where("expiresAt < ?", before).Delete(&v1.EmailVerification{})
+	if d.Error != nil {
+		return 0, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return d.RowsAffected, nil
+}
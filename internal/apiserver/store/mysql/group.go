@@ -0,0 +1,136 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+
+	"github.com/marmotedu/component-base/pkg/fields"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+	"gorm.io/gorm"
+)
+
+type groups struct {
+	db *gorm.DB
+}
+
+func newGroups(ds *datastore) *groups {
+	return &groups{ds.db}
+}
+
+// Create creates a new group in the tenant of the request.
+func (g *groups) Create(ctx context.Context, group *v1.Group, opts metav1.CreateOptions) error {
+	group.Tenant = tenantOf(ctx)
+
+	return g.db.Create(&group).Error
+}
+
+// Update updates a group information.
+func (g *groups) Update(ctx context.Context, group *v1.Group, opts metav1.UpdateOptions) error {
+	return g.db.Save(group).Error
+}
+
+// Delete deletes the group by the group name, together with its members and policy bindings.
+func (g *groups) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		d := tx.Scopes(byTenant(ctx)).Where("name = ?", name).Delete(&v1.Group{})
+		if d.Error != nil && !errors.Is(d.Error, gorm.ErrRecordNotFound) {
+			return errors.WithCode(code.ErrDatabase, d.Error.Error())
+		}
+
+		// the group does not exist or belongs to another tenant.
+		if d.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Scopes(byTenant(ctx)).Where("groupName = ?", name).Delete(&v1.GroupMember{}).Error; err != nil {
+			return err
+		}
+
+		principal := v1.Principal(v1.PrincipalKindGroup, name)
+		if err := tx.Scopes(byTenant(ctx)).Where("principal = ?", principal).Delete(&v1.PolicyBinding{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Scopes(byTenant(ctx)).
+			Where("kind = ? and subject = ?", v1.SubjectKindGroup, name).
+			Delete(&v1.RoleBinding{}).Error
+	})
+}
+
+// Get return a group by the group name.
+func (g *groups) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Group, error) {
+	group := &v1.Group{}
+	err := g.db.Scopes(byTenant(ctx)).Where("name = ?", name).First(&group).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrGroupNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return group, nil
+}
+
+// List return all groups.
+func (g *groups) List(ctx context.Context, opts metav1.ListOptions) (*v1.GroupList, error) {
+	ret := &v1.GroupList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	selector, _ := fields.ParseSelector(opts.FieldSelector)
+	name, _ := selector.RequiresExactMatch("name")
+
+	d := g.db.Scopes(byTenant(ctx)).
+		Where("name like ?", "%"+name+"%").
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
+
+// AddMember adds a user to a group of the tenant of the request.
+func (g *groups) AddMember(ctx context.Context, member *v1.GroupMember, opts metav1.CreateOptions) error {
+	member.Tenant = tenantOf(ctx)
+
+	return g.db.Create(&member).Error
+}
+
+// RemoveMember removes a user from a group.
+func (g *groups) RemoveMember(ctx context.Context, group, username string, opts metav1.DeleteOptions) error {
+	return g.db.Scopes(byTenant(ctx)).
+		Where("groupName = ? and username = ?", group, username).
+		Delete(&v1.GroupMember{}).Error
+}
+
+// ListMembers return the members of a group.
+func (g *groups) ListMembers(ctx context.Context, group string, opts metav1.ListOptions) (*v1.GroupMemberList, error) {
+	ret := &v1.GroupMemberList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := g.db.Scopes(byTenant(ctx))
+	if group != "" {
+		db = db.Where("groupName = ?", group)
+	}
+
+	d := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
@@ -25,13 +25,21 @@ func newItems(ds *datastore) *items {
 	return &items{ds.db}
 }
 
-// Create creates a new item.
+// Create creates a new item in the tenant of the request.
 func (i *items) Create(ctx context.Context, item *v1.Item, opts metav1.CreateOptions) error {
+	item.Tenant = tenantOf(ctx)
+
 	return i.db.Create(&item).Error
 }
 
-// Update updates an item.
+// Update updates an item, the item must belong to the tenant of the request.
 func (i *items) Update(ctx context.Context, item *v1.Item, opts metav1.UpdateOptions) error {
+	current, err := i.Get(ctx, int(item.ID), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	item.Tenant = current.Tenant
+
 	return i.db.Save(item).Error
 }
 
@@ -41,7 +49,7 @@ func (i *items) Delete(ctx context.Context, id int, opts metav1.DeleteOptions) e
 		i.db = i.db.Unscoped()
 	}
 
-	err := i.db.Where("id = ?", id).Delete(&v1.Item{}).Error
+	err := i.db.Scopes(byTenant(ctx)).Where("id = ?", id).Delete(&v1.Item{}).Error
 	if err != nil {
 		return err
 	}
@@ -52,7 +60,7 @@ func (i *items) Delete(ctx context.Context, id int, opts metav1.DeleteOptions) e
 // Get returns an item by the item identifier.
 func (i *items) Get(ctx context.Context, id int, opts metav1.GetOptions) (*v1.Item, error) {
 	item := &v1.Item{}
-	err := i.db.Where("id = ?", id).First(&item).Error
+	err := i.db.Scopes(byTenant(ctx)).Where("id = ?", id).First(&item).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrUnknown, err.Error()) // code.ErrItemNotFound
@@ -68,10 +76,26 @@ func (i *items) List(ctx context.Context, opts metav1.ListOptions) (*v1.ItemList
 	ret := &v1.ItemList{}
 	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
 
-	selector, _ := fields.ParseSelector(opts.FieldSelector)
+	// Execute the query
+	d := selectItems(ctx, i.db, opts.FieldSelector).
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
+
+// selectItems returns the query of the listed items of the tenant of the
+// request which match the field selector.
+func selectItems(ctx context.Context, db *gorm.DB, fieldSelector string) *gorm.DB {
+	selector, _ := fields.ParseSelector(fieldSelector)
 
 	// Build the query
-	query := i.db.Where("status = 1")
+	query := db.Scopes(byTenant(ctx)).Where("status = 1")
 
 	for _, req := range selector.Requirements() {
 		switch req.Field {
@@ -90,15 +114,5 @@ func (i *items) List(ctx context.Context, opts metav1.ListOptions) (*v1.ItemList
 		}
 	}
 
-	// Execute the query
-	d := query.
-		Offset(ol.Offset).
-		Limit(ol.Limit).
-		Order("id desc").
-		Find(&ret.Items).
-		Offset(-1).
-		Limit(-1).
-		Count(&ret.TotalCount)
-
-	return ret, d.Error
+	return query
 }
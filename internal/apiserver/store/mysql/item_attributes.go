@@ -23,13 +23,25 @@ func newItemAttributes(ds *datastore) *itemAttributes {
 	return &itemAttributes{ds.db}
 }
 
-// Create creates a new item attribute.
+// Create creates a new item attribute, the item must belong to the tenant of the request.
 func (i *itemAttributes) Create(ctx context.Context, itemAttribute *v1.ItemAttributes, opts metav1.CreateOptions) error {
+	if _, err := newItems(&datastore{i.db}).Get(ctx, int(itemAttribute.ItemID), metav1.GetOptions{}); err != nil {
+		return err
+	}
+
 	return i.db.Create(itemAttribute).Error
 }
 
-// Update updates an item attribute.
+// Update updates an item attribute, the item must belong to the tenant of the request.
 func (i *itemAttributes) Update(ctx context.Context, itemAttribute *v1.ItemAttributes, opts metav1.UpdateOptions) error {
+	if _, err := i.Get(ctx, int(itemAttribute.ID), metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	if _, err := newItems(&datastore{i.db}).Get(ctx, int(itemAttribute.ItemID), metav1.GetOptions{}); err != nil {
+		return err
+	}
+
 	return i.db.Save(itemAttribute).Error
 }
 
@@ -39,7 +51,9 @@ func (i *itemAttributes) Delete(ctx context.Context, id int, opts metav1.DeleteO
 		i.db = i.db.Unscoped()
 	}
 
-	err := i.db.Where("id = ?", id).Delete(&v1.ItemAttributes{}).Error
+	err := i.db.Scopes(byTenantParent(ctx, "item_id", &v1.Item{}, "id")).
+		Where("id = ?", id).
+		Delete(&v1.ItemAttributes{}).Error
 	if err != nil {
 		return err
 	}
@@ -50,7 +64,9 @@ func (i *itemAttributes) Delete(ctx context.Context, id int, opts metav1.DeleteO
 // Get returns an item attribute by the attribute identifier.
 func (i *itemAttributes) Get(ctx context.Context, id int, opts metav1.GetOptions) (*v1.ItemAttributes, error) {
 	itemAttribute := &v1.ItemAttributes{}
-	err := i.db.Where("id = ?", id).First(itemAttribute).Error
+	err := i.db.Scopes(byTenantParent(ctx, "item_id", &v1.Item{}, "id")).
+		Where("id = ?", id).
+		First(itemAttribute).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrUnknown, err.Error()) // code.ErrItemAttributeNotFound
@@ -68,7 +84,7 @@ func (i *itemAttributes) FindByAttributes(ctx context.Context, attributes *v1.It
 	// Here is a very basic example:
 
 	items := &v1.ItemList{}
-	err := i.db.Where(attributes).Find(items).Error
+	err := i.db.Scopes(byTenant(ctx)).Where(attributes).Find(items).Error
 	if err != nil {
 		return nil, errors.WithCode(code.ErrDatabase, err.Error())
 	}
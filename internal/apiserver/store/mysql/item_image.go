@@ -20,13 +20,25 @@ func newItemImages(ds *datastore) *itemImages {
 	return &itemImages{ds.db}
 }
 
-// Create creates a new item image.
+// Create creates a new item image, the item must belong to the tenant of the request.
 func (i *itemImages) Create(ctx context.Context, itemImage *v1.ItemImage, opts metav1.CreateOptions) error {
+	if _, err := newItems(&datastore{i.db}).Get(ctx, int(itemImage.ItemID), metav1.GetOptions{}); err != nil {
+		return err
+	}
+
 	return i.db.Create(itemImage).Error
 }
 
-// Update updates an item image.
+// Update updates an item image, the item must belong to the tenant of the request.
 func (i *itemImages) Update(ctx context.Context, itemImage *v1.ItemImage, opts metav1.UpdateOptions) error {
+	if _, err := i.Get(ctx, itemImage.ID, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	if _, err := newItems(&datastore{i.db}).Get(ctx, int(itemImage.ItemID), metav1.GetOptions{}); err != nil {
+		return err
+	}
+
 	return i.db.Save(itemImage).Error
 }
 
@@ -36,7 +48,9 @@ func (i *itemImages) Delete(ctx context.Context, id uint64, opts metav1.DeleteOp
 		i.db = i.db.Unscoped()
 	}
 
-	err := i.db.Where("id = ?", id).Delete(&v1.ItemImage{}).Error
+	err := i.db.Scopes(byTenantParent(ctx, "item_id", &v1.Item{}, "id")).
+		Where("id = ?", id).
+		Delete(&v1.ItemImage{}).Error
 	if err != nil {
 		return err
 	}
@@ -47,7 +61,9 @@ func (i *itemImages) Delete(ctx context.Context, id uint64, opts metav1.DeleteOp
 // Get returns an item image by the image ID.
 func (i *itemImages) Get(ctx context.Context, id uint64, opts metav1.GetOptions) (*v1.ItemImage, error) {
 	itemImage := &v1.ItemImage{}
-	err := i.db.Where("id = ?", id).First(itemImage).Error
+	err := i.db.Scopes(byTenantParent(ctx, "item_id", &v1.Item{}, "id")).
+		Where("id = ?", id).
+		First(itemImage).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrUnknown, err.Error()) // code.ErrItemImageNotFound
@@ -63,7 +79,9 @@ func (i *itemImages) Get(ctx context.Context, id uint64, opts metav1.GetOptions)
 
 func (s *itemImages) List(ctx context.Context, itemID uint64, opts metav1.ListOptions) ([]*v1.ItemImage, error) {
 	var itemImages []*v1.ItemImage
-	err := s.db.Where("item_id = ?", itemID).Find(&itemImages).Error
+	err := s.db.Scopes(byTenantParent(ctx, "item_id", &v1.Item{}, "id")).
+		Where("item_id = ?", itemID).
+		Find(&itemImages).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrUnknown, err.Error()) // code.ErrItemImageNotFound
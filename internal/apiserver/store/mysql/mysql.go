@@ -40,6 +40,58 @@ func (ds *datastore) PolicyAudits() store.PolicyAuditStore {
 	return newPolicyAudits(ds)
 }
 
+func (ds *datastore) Groups() store.GroupStore {
+	return newGroups(ds)
+}
+
+func (ds *datastore) Roles() store.RoleStore {
+	return newRoles(ds)
+}
+
+func (ds *datastore) PolicyBindings() store.PolicyBindingStore {
+	return newPolicyBindings(ds)
+}
+
+func (ds *datastore) Tenants() store.TenantStore {
+	return newTenants(ds)
+}
+
+func (ds *datastore) SigningKeys() store.SigningKeyStore {
+	return newSigningKeys(ds)
+}
+
+func (ds *datastore) Sessions() store.SessionStore {
+	return newSessions(ds)
+}
+
+func (ds *datastore) TOTPs() store.TOTPStore {
+	return newTOTPs(ds)
+}
+
+func (ds *datastore) PasswordResets() store.PasswordResetStore {
+	return newPasswordResets(ds)
+}
+
+func (ds *datastore) AuditLogs() store.AuditLogStore {
+	return newAuditLogs(ds)
+}
+
+func (ds *datastore) UserStatuses() store.UserStatusStore {
+	return newUserStatuses(ds)
+}
+
+func (ds *datastore) EmailVerifications() store.EmailVerificationStore {
+	return newEmailVerifications(ds)
+}
+
+func (ds *datastore) UserIdentities() store.UserIdentityStore {
+	return newUserIdentities(ds)
+}
+
+func (ds *datastore) AccessTokens() store.AccessTokenStore {
+	return newAccessTokens(ds)
+}
+
 func (ds *datastore) Items() store.ItemStore {
 	return newItems(ds) // Make sure to implement this function
 }
@@ -52,6 +104,14 @@ func (ds *datastore) ItemImage() store.ItemImageStore {
 	return newItemImages(ds)
 }
 
+func (ds *datastore) Catalog() store.CatalogStore {
+	return newCatalog(ds)
+}
+
+func (ds *datastore) Backups() store.BackupStore {
+	return newBackups(ds)
+}
+
 func (ds *datastore) Close() error {
 	db, err := ds.db.DB()
 	if err != nil {
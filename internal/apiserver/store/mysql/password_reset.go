@@ -0,0 +1,72 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+type passwordResets struct {
+	db *gorm.DB
+}
+
+func newPasswordResets(ds *datastore) *passwordResets {
+	return &passwordResets{ds.db}
+}
+
+// Create creates a new password reset, replacing the pending ones of the user.
+func (p *passwordResets) Create(ctx context.Context, reset *v1.PasswordReset) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("username = ? and usedAt is null", reset.Username).
+			Delete(&v1.PasswordReset{}).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if err := tx.Create(reset).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// Use marks the unused and unexpired password reset with the given hash as used.
+func (p *passwordResets) Use(ctx context.Context, hash string) (*v1.PasswordReset, error) {
+	now := time.Now()
+	d := p.db.Model(&v1.PasswordReset{}).
+		Where("hash = ? and usedAt is null and expiresAt > ?", hash, now).
+		Update("usedAt", now)
+	if d.Error != nil {
+		return nil, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	if d.RowsAffected == 0 {
+		return nil, errors.WithCode(code.ErrPasswordResetTokenInvalid, "password reset token is invalid or expired")
+	}
+
+	reset := &v1.PasswordReset{}
+	if err := p.db.Where("hash = ?", hash).First(reset).Error; err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return reset, nil
+}
+
+// DeleteExpired deletes the password resets which expired before the given time.
+func (p *passwordResets) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	d := p.db.Where("expiresAt < ?", before).Delete(&v1.PasswordReset{})
+	if d.Error != nil {
+		return 0, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return d.RowsAffected, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/marmotedu/component-base/pkg/fields"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	model "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
 	"gorm.io/gorm"
@@ -40,11 +41,21 @@ func (p *policies) Delete(ctx context.Context, username, name string, opts metav
 		p.db = p.db.Unscoped()
 	}
 
-	err := p.db.Where("username = ? and name = ?", username, name).Delete(&v1.Policy{}).Error
+	err := p.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name = ?", username, name).
+		Delete(&v1.Policy{}).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 
+	// unbind the policy from all the groups and roles.
+	err = p.db.Scopes(byTenantUser(ctx, "policyOwner")).
+		Where("policyOwner = ? and policyName = ?", username, name).
+		Delete(&model.PolicyBinding{}).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
 	return nil
 }
 
@@ -54,7 +65,7 @@ func (p *policies) DeleteByUser(ctx context.Context, username string, opts metav
 		p.db = p.db.Unscoped()
 	}
 
-	return p.db.Where("username = ?", username).Delete(&v1.Policy{}).Error
+	return p.db.Scopes(byTenantUser(ctx, "username")).Where("username = ?", username).Delete(&v1.Policy{}).Error
 }
 
 // DeleteCollection batch deletes policies by policies ids.
@@ -68,7 +79,16 @@ func (p *policies) DeleteCollection(
 		p.db = p.db.Unscoped()
 	}
 
-	return p.db.Where("username = ? and name in (?)", username, names).Delete(&v1.Policy{}).Error
+	if err := p.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name in (?)", username, names).
+		Delete(&v1.Policy{}).Error; err != nil {
+		return err
+	}
+
+	// unbind the policies from all the groups and roles.
+	return p.db.Scopes(byTenantUser(ctx, "policyOwner")).
+		Where("policyOwner = ? and policyName in (?)", username, names).
+		Delete(&model.PolicyBinding{}).Error
 }
 
 // DeleteCollectionByUser batch deletes policies usernames.
@@ -77,13 +97,15 @@ func (p *policies) DeleteCollectionByUser(ctx context.Context, usernames []strin
 		p.db = p.db.Unscoped()
 	}
 
-	return p.db.Where("username in (?)", usernames).Delete(&v1.Policy{}).Error
+	return p.db.Scopes(byTenantUser(ctx, "username")).Where("username in (?)", usernames).Delete(&v1.Policy{}).Error
 }
 
 // Get return policy by the policy identifier.
 func (p *policies) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Policy, error) {
 	policy := &v1.Policy{}
-	err := p.db.Where("username = ? and name = ?", username, name).First(&policy).Error
+	err := p.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name = ?", username, name).
+		First(&policy).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrPolicyNotFound, err.Error())
@@ -107,7 +129,8 @@ func (p *policies) List(ctx context.Context, username string, opts metav1.ListOp
 	selector, _ := fields.ParseSelector(opts.FieldSelector)
 	name, _ := selector.RequiresExactMatch("name")
 
-	d := p.db.Where("name like ?", "%"+name+"%").
+	d := p.db.Scopes(byTenantUser(ctx, "username")).
+		Where("name like ?", "%"+name+"%").
 		Offset(ol.Offset).
 		Limit(ol.Limit).
 		Order("id desc").
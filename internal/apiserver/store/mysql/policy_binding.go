@@ -0,0 +1,61 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+	"gorm.io/gorm"
+)
+
+type policyBindings struct {
+	db *gorm.DB
+}
+
+func newPolicyBindings(ds *datastore) *policyBindings {
+	return &policyBindings{ds.db}
+}
+
+// Create binds a policy to a group or a role of the tenant of the request.
+func (p *policyBindings) Create(ctx context.Context, binding *v1.PolicyBinding, opts metav1.CreateOptions) error {
+	binding.Tenant = tenantOf(ctx)
+
+	return p.db.Create(&binding).Error
+}
+
+// Delete unbinds a policy from a group or a role.
+func (p *policyBindings) Delete(ctx context.Context, principal, owner, name string, opts metav1.DeleteOptions) error {
+	return p.db.Scopes(byTenant(ctx)).
+		Where("principal = ? and policyOwner = ? and policyName = ?", principal, owner, name).
+		Delete(&v1.PolicyBinding{}).Error
+}
+
+// List return the policies bound to a principal.
+func (p *policyBindings) List(
+	ctx context.Context,
+	principal string,
+	opts metav1.ListOptions,
+) (*v1.PolicyBindingList, error) {
+	ret := &v1.PolicyBindingList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := p.db.Scopes(byTenant(ctx))
+	if principal != "" {
+		db = db.Where("principal = ?", principal)
+	}
+
+	d := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
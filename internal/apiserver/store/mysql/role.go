@@ -0,0 +1,131 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+
+	"github.com/marmotedu/component-base/pkg/fields"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+	"gorm.io/gorm"
+)
+
+type roles struct {
+	db *gorm.DB
+}
+
+func newRoles(ds *datastore) *roles {
+	return &roles{ds.db}
+}
+
+// Create creates a new role in the tenant of the request.
+func (r *roles) Create(ctx context.Context, role *v1.Role, opts metav1.CreateOptions) error {
+	role.Tenant = tenantOf(ctx)
+
+	return r.db.Create(&role).Error
+}
+
+// Update updates a role information.
+func (r *roles) Update(ctx context.Context, role *v1.Role, opts metav1.UpdateOptions) error {
+	return r.db.Save(role).Error
+}
+
+// Delete deletes the role by the role name, together with its bindings.
+func (r *roles) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		d := tx.Scopes(byTenant(ctx)).Where("name = ?", name).Delete(&v1.Role{})
+		if d.Error != nil && !errors.Is(d.Error, gorm.ErrRecordNotFound) {
+			return errors.WithCode(code.ErrDatabase, d.Error.Error())
+		}
+
+		// the role does not exist or belongs to another tenant.
+		if d.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Scopes(byTenant(ctx)).Where("roleName = ?", name).Delete(&v1.RoleBinding{}).Error; err != nil {
+			return err
+		}
+
+		principal := v1.Principal(v1.PrincipalKindRole, name)
+
+		return tx.Scopes(byTenant(ctx)).Where("principal = ?", principal).Delete(&v1.PolicyBinding{}).Error
+	})
+}
+
+// Get return a role by the role name.
+func (r *roles) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Role, error) {
+	role := &v1.Role{}
+	err := r.db.Scopes(byTenant(ctx)).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrRoleNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return role, nil
+}
+
+// List return all roles.
+func (r *roles) List(ctx context.Context, opts metav1.ListOptions) (*v1.RoleList, error) {
+	ret := &v1.RoleList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	selector, _ := fields.ParseSelector(opts.FieldSelector)
+	name, _ := selector.RequiresExactMatch("name")
+
+	d := r.db.Scopes(byTenant(ctx)).
+		Where("name like ?", "%"+name+"%").
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
+
+// Bind binds a role to a user or a group of the tenant of the request.
+func (r *roles) Bind(ctx context.Context, binding *v1.RoleBinding, opts metav1.CreateOptions) error {
+	binding.Tenant = tenantOf(ctx)
+
+	return r.db.Create(&binding).Error
+}
+
+// Unbind removes a role from a user or a group.
+func (r *roles) Unbind(ctx context.Context, role, kind, subject string, opts metav1.DeleteOptions) error {
+	return r.db.Scopes(byTenant(ctx)).
+		Where("roleName = ? and kind = ? and subject = ?", role, kind, subject).
+		Delete(&v1.RoleBinding{}).Error
+}
+
+// ListBindings return the bindings of a role.
+func (r *roles) ListBindings(ctx context.Context, role string, opts metav1.ListOptions) (*v1.RoleBindingList, error) {
+	ret := &v1.RoleBindingList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := r.db.Scopes(byTenant(ctx))
+	if role != "" {
+		db = db.Where("roleName = ?", role)
+	}
+
+	d := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
@@ -6,14 +6,16 @@ package mysql
 
 import (
 	"context"
+	"time"
 
-	v1 "github.com/marmotedu/api/apiserver/v1"
 	"github.com/marmotedu/component-base/pkg/fields"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
-	"gorm.io/gorm"
 )
 
 type secrets struct {
@@ -29,9 +31,10 @@ func (s *secrets) Create(ctx context.Context, secret *v1.Secret, opts metav1.Cre
 	return s.db.Create(&secret).Error
 }
 
-// Update updates an secret information by the secret identifier.
+// Update updates an secret information by the secret identifier. The last used
+// time is left to RecordUsage, which may have moved it since the secret was read.
 func (s *secrets) Update(ctx context.Context, secret *v1.Secret, opts metav1.UpdateOptions) error {
-	return s.db.Save(secret).Error
+	return s.db.Omit("lastUsedAt").Save(secret).Error
 }
 
 // Delete deletes the secret by the secret identifier.
@@ -40,7 +43,9 @@ func (s *secrets) Delete(ctx context.Context, username, name string, opts metav1
 		s.db = s.db.Unscoped()
 	}
 
-	err := s.db.Where("username = ? and name = ?", username, name).Delete(&v1.Secret{}).Error
+	err := s.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name = ?", username, name).
+		Delete(&v1.Secret{}).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
@@ -59,13 +64,17 @@ func (s *secrets) DeleteCollection(
 		s.db = s.db.Unscoped()
 	}
 
-	return s.db.Where("username = ? and name in (?)", username, names).Delete(&v1.Secret{}).Error
+	return s.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name in (?)", username, names).
+		Delete(&v1.Secret{}).Error
 }
 
 // Get return an secret by the secret identifier.
 func (s *secrets) Get(ctx context.Context, username, name string, opts metav1.GetOptions) (*v1.Secret, error) {
 	secret := &v1.Secret{}
-	err := s.db.Where("username = ? and name= ?", username, name).First(&secret).Error
+	err := s.db.Scopes(byTenantUser(ctx, "username")).
+		Where("username = ? and name= ?", username, name).
+		First(&secret).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrSecretNotFound, err.Error())
@@ -89,7 +98,8 @@ func (s *secrets) List(ctx context.Context, username string, opts metav1.ListOpt
 	selector, _ := fields.ParseSelector(opts.FieldSelector)
 	name, _ := selector.RequiresExactMatch("name")
 
-	d := s.db.Where(" name like ?", "%"+name+"%").
+	d := s.db.Scopes(byTenantUser(ctx, "username")).
+		Where(" name like ?", "%"+name+"%").
 		Offset(ol.Offset).
 		Limit(ol.Limit).
 		Order("id desc").
@@ -100,3 +110,22 @@ func (s *secrets) List(ctx context.Context, username string, opts metav1.ListOpt
 
 	return ret, d.Error
 }
+
+// RecordUsage records when the secrets were last used.
+func (s *secrets) RecordUsage(ctx context.Context, lastUsed map[string]time.Time) error {
+	for secretID, usedAt := range lastUsed {
+		// using a secret does not modify it, updatedAt is assigned its own value
+		// so that neither gorm nor mysql touch it.
+		err := s.db.Model(&v1.Secret{}).
+			Where("secretID = ? and (lastUsedAt is null or lastUsedAt < ?)", secretID, usedAt).
+			UpdateColumns(map[string]interface{}{
+				"lastUsedAt": usedAt,
+				"updatedAt":  gorm.Expr("updatedAt"),
+			}).Error
+		if err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+	}
+
+	return nil
+}
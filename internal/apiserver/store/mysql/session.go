@@ -0,0 +1,172 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+)
+
+type sessions struct {
+	db *gorm.DB
+}
+
+func newSessions(ds *datastore) *sessions {
+	return &sessions{ds.db}
+}
+
+// Create creates a new session together with its first refresh token.
+func (s *sessions) Create(ctx context.Context, session *v1.Session, token *v1.RefreshToken) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if err := tx.Create(token).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// Get returns the session by the session identifier.
+func (s *sessions) Get(ctx context.Context, sid string) (*v1.Session, error) {
+	session := &v1.Session{}
+	if err := s.db.Where("sessionID = ?", sid).First(session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrSessionNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return session, nil
+}
+
+// GetToken returns the refresh token with the given hash.
+func (s *sessions) GetToken(ctx context.Context, hash string) (*v1.RefreshToken, error) {
+	token := &v1.RefreshToken{}
+	if err := s.db.Where("hash = ?", hash).First(token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrRefreshTokenInvalid, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return token, nil
+}
+
+// Rotate marks the refresh token as rotated, stores its successor and updates
+// the session metadata, all or nothing.
+func (s *sessions) Rotate(ctx context.Context, hash string, token *v1.RefreshToken, session *v1.Session) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// the rotatedAt condition makes concurrent refreshes with the same token
+		// fail, only one of them can win.
+		d := tx.Model(&v1.RefreshToken{}).
+			Where("hash = ? and rotatedAt is null", hash).
+			Update("rotatedAt", time.Now())
+		if d.Error != nil {
+			return errors.WithCode(code.ErrDatabase, d.Error.Error())
+		}
+
+		if d.RowsAffected == 0 {
+			return errors.WithCode(code.ErrRefreshTokenReused, "refresh token has already been rotated")
+		}
+
+		if err := tx.Create(token).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		err := tx.Model(&v1.Session{}).
+			Where("sessionID = ?", session.SessionID).
+			Updates(map[string]interface{}{
+				"userAgent":  session.UserAgent,
+				"clientIP":   session.ClientIP,
+				"lastUsedAt": session.LastUsedAt,
+				"expiresAt":  session.ExpiresAt,
+			}).Error
+		if err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// List returns the sessions of the user which are neither revoked nor expired.
+func (s *sessions) List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SessionList, error) {
+	ret := &v1.SessionList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	d := s.db.Where("username = ? and revokedAt is null and expiresAt > ?", username, time.Now()).
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
+
+// Revoke revokes the session of the user, its refresh tokens can no longer be used.
+func (s *sessions) Revoke(ctx context.Context, username, sid string) error {
+	d := s.db.Model(&v1.Session{}).
+		Where("sessionID = ? and username = ? and revokedAt is null", sid, username).
+		Update("revokedAt", time.Now())
+	if d.Error != nil {
+		return errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	if d.RowsAffected == 0 {
+		return errors.WithCode(code.ErrSessionNotFound, "session %s not found", sid)
+	}
+
+	return nil
+}
+
+// RevokeAll revokes all the active sessions of the user.
+func (s *sessions) RevokeAll(ctx context.Context, username string) error {
+	if err := s.db.Model(&v1.Session{}).
+		Where("username = ? and revokedAt is null", username).
+		Update("revokedAt", time.Now()).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the sessions which expired before the given time, together with their refresh tokens.
+func (s *sessions) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&v1.Session{}).Select("sessionID").Where("expiresAt < ?", before)
+		if err := tx.Where("sessionID in (?)", expired).Delete(&v1.RefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		d := tx.Where("expiresAt < ?", before).Delete(&v1.Session{})
+		affected = d.RowsAffected
+
+		return d.Error
+	})
+	if err != nil {
+		return 0, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"gorm.io/gorm"
+)
+
+type signingKeys struct {
+	db *gorm.DB
+}
+
+func newSigningKeys(ds *datastore) *signingKeys {
+	return &signingKeys{ds.db}
+}
+
+// Create creates a new signing key.
+func (s *signingKeys) Create(ctx context.Context, key *v1.SigningKey) error {
+	if err := s.db.Create(key).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// List returns all the signing keys, from the oldest to the newest.
+func (s *signingKeys) List(ctx context.Context) ([]*v1.SigningKey, error) {
+	var keys []*v1.SigningKey
+	if err := s.db.Order("id asc").Find(&keys).Error; err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return keys, nil
+}
+
+// Retire retires the signing key by the key identifier.
+func (s *signingKeys) Retire(ctx context.Context, kid string, expiresAt time.Time) error {
+	err := s.db.Model(&v1.SigningKey{}).
+		Where("kid = ? and retiredAt is null", kid).
+		Updates(map[string]interface{}{"retiredAt": time.Now(), "expiresAt": expiresAt}).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the retired signing keys which expired before the given time.
+func (s *signingKeys) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	d := s.db.Where("retiredAt is not null and expiresAt < ?", before).Delete(&v1.SigningKey{})
+	if d.Error != nil {
+		return 0, errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	return d.RowsAffected, nil
+}
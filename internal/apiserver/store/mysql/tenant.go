@@ -0,0 +1,212 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+
+	"github.com/marmotedu/component-base/pkg/fields"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+	"gorm.io/gorm"
+)
+
+type tenants struct {
+	db *gorm.DB
+}
+
+func newTenants(ds *datastore) *tenants {
+	return &tenants{ds.db}
+}
+
+// Create creates a new tenant.
+func (t *tenants) Create(ctx context.Context, tn *v1.Tenant, opts metav1.CreateOptions) error {
+	return t.db.Create(&tn).Error
+}
+
+// Update updates a tenant information.
+func (t *tenants) Update(ctx context.Context, tn *v1.Tenant, opts metav1.UpdateOptions) error {
+	return t.db.Save(tn).Error
+}
+
+// Delete deletes the tenant by the tenant name.
+func (t *tenants) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	err := t.db.Where("name = ?", name).Delete(&v1.Tenant{}).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Get return a tenant by the tenant name.
+func (t *tenants) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Tenant, error) {
+	tn := &v1.Tenant{}
+	err := t.db.Where("name = ?", name).First(&tn).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrTenantNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return tn, nil
+}
+
+// List return all tenants.
+func (t *tenants) List(ctx context.Context, opts metav1.ListOptions) (*v1.TenantList, error) {
+	ret := &v1.TenantList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	selector, _ := fields.ParseSelector(opts.FieldSelector)
+	name, _ := selector.RequiresExactMatch("name")
+
+	d := t.db.Where("name like ?", "%"+name+"%").
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
+
+// AddMember adds a user to a tenant, the previous membership of the user is replaced.
+func (t *tenants) AddMember(ctx context.Context, member *v1.TenantMember, opts metav1.CreateOptions) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		previous := tenant.Default
+		var current v1.TenantMember
+		err := tx.Where("username = ?", member.Username).First(&current).Error
+		switch {
+		case err == nil:
+			previous = current.Tenant
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		// the groups and roles of the previous tenant do not apply any more.
+		if previous != member.Tenant {
+			if err := tx.Where("username = ?", member.Username).Delete(&v1.GroupMember{}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where("kind = ? and subject = ?", v1.SubjectKindUser, member.Username).
+				Delete(&v1.RoleBinding{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("username = ?", member.Username).Delete(&v1.TenantMember{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&member).Error
+	})
+}
+
+// RemoveMember removes a user from a tenant, the user falls back to the default tenant.
+func (t *tenants) RemoveMember(ctx context.Context, tn, username string, opts metav1.DeleteOptions) error {
+	return t.db.Where("tenantName = ? and username = ?", tn, username).Delete(&v1.TenantMember{}).Error
+}
+
+// GetMember return the tenant membership of a user.
+func (t *tenants) GetMember(ctx context.Context, username string, opts metav1.GetOptions) (*v1.TenantMember, error) {
+	member := &v1.TenantMember{}
+	err := t.db.Where("username = ?", username).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrTenantNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return member, nil
+}
+
+// ListMembers return the members of a tenant.
+func (t *tenants) ListMembers(ctx context.Context, tn string, opts metav1.ListOptions) (*v1.TenantMemberList, error) {
+	ret := &v1.TenantMemberList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := t.db
+	if tn != "" {
+		db = db.Where("tenantName = ?", tn)
+	}
+
+	d := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
+
+// tenantOf returns the tenant of the request, requests without tenant belong to
+// the default tenant.
+func tenantOf(ctx context.Context) string {
+	if name, ok := tenant.FromContext(ctx); ok {
+		return name
+	}
+
+	return tenant.Default
+}
+
+// byTenant limits the query to the rows of the tenant of the request. Requests
+// without tenant, i.e. the internal ones, are not limited.
+func byTenant(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		name, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db
+		}
+
+		return db.Where("tenant = ?", name)
+	}
+}
+
+// byTenantUser limits the query to the rows whose column holds the name of a user
+// of the tenant of the request. Requests without tenant are not limited.
+func byTenantUser(ctx context.Context, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		name, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db
+		}
+
+		members := db.Session(&gorm.Session{NewDB: true}).Model(&v1.TenantMember{}).Select("username")
+		// users without membership belong to the default tenant.
+		if name == tenant.Default {
+			return db.Where(column+" not in (?)", members.Where("tenantName <> ?", name))
+		}
+
+		return db.Where(column+" in (?)", members.Where("tenantName = ?", name))
+	}
+}
+
+// byTenantParent limits the query to the rows whose column references a row of the
+// given parent table which belongs to the tenant of the request.
+func byTenantParent(ctx context.Context, column string, parent interface{}, key string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		name, ok := tenant.FromContext(ctx)
+		if !ok {
+			return db
+		}
+
+		parents := db.Session(&gorm.Session{NewDB: true}).Model(parent).Select(key).Where("tenant = ?", name)
+
+		return db.Where(column+" in (?)", parents)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+type totps struct {
+	db *gorm.DB
+}
+
+func newTOTPs(ds *datastore) *totps {
+	return &totps{ds.db}
+}
+
+// Create creates a new enrollment, replacing the previous one of the user.
+func (t *totps) Create(ctx context.Context, totp *v1.TOTP) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("username = ?", totp.Username).Delete(&v1.TOTP{}).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		if err := tx.Create(totp).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// Get returns the enrollment of the user.
+func (t *totps) Get(ctx context.Context, username string) (*v1.TOTP, error) {
+	totp := &v1.TOTP{}
+	if err := t.db.Where("username = ?", username).First(totp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrTOTPNotEnabled, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return totp, nil
+}
+
+// Update updates the enrollment of the user.
+func (t *totps) Update(ctx context.Context, totp *v1.TOTP) error {
+	if err := t.db.Save(totp).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// UseStep records the time step of an accepted code, unless a code of the same
+// or a later step has been accepted concurrently.
+func (t *totps) UseStep(ctx context.Context, username string, step int64) error {
+	d := t.db.Model(&v1.TOTP{}).
+		Where("username = ? and lastStep < ?", username, step).
+		Update("lastStep", step)
+	if d.Error != nil {
+		return errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	if d.RowsAffected == 0 {
+		return errors.WithCode(code.ErrTOTPCodeInvalid, "code has already been used")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode replaces the recovery codes of the user with the remaining
+// ones, unless they have been changed concurrently.
+func (t *totps) UseRecoveryCode(ctx context.Context, username, codes, remaining string) error {
+	d := t.db.Model(&v1.TOTP{}).
+		Where("username = ? and recoveryCodes = ?", username, codes).
+		Update("recoveryCodes", remaining)
+	if d.Error != nil {
+		return errors.WithCode(code.ErrDatabase, d.Error.Error())
+	}
+
+	if d.RowsAffected == 0 {
+		return errors.WithCode(code.ErrTOTPCodeInvalid, "recovery code has already been used")
+	}
+
+	return nil
+}
+
+// Delete deletes the enrollment of the user.
+func (t *totps) Delete(ctx context.Context, username string) error {
+	if err := t.db.Where("username = ?", username).Delete(&v1.TOTP{}).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
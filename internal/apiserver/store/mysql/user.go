@@ -11,6 +11,7 @@ import (
 	"github.com/marmotedu/component-base/pkg/fields"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	model "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
 	gorm "gorm.io/gorm"
@@ -24,9 +25,15 @@ func newUsers(ds *datastore) *users {
 	return &users{ds.db}
 }
 
-// Create creates a new user account.
+// Create creates a new user account in the tenant of the request.
 func (u *users) Create(ctx context.Context, user *v1.User, opts metav1.CreateOptions) error {
-	return u.db.Create(&user).Error
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.TenantMember{Tenant: tenantOf(ctx), Username: user.Name}).Error
+	})
 }
 
 // Update updates an user account information.
@@ -46,7 +53,7 @@ func (u *users) Delete(ctx context.Context, username string, opts metav1.DeleteO
 		u.db = u.db.Unscoped()
 	}
 
-	err := u.db.Where("name = ?", username).Delete(&v1.User{}).Error
+	err := u.db.Scopes(byTenantUser(ctx, "name")).Where("name = ?", username).Delete(&v1.User{}).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
@@ -66,13 +73,33 @@ func (u *users) DeleteCollection(ctx context.Context, usernames []string, opts m
 		u.db = u.db.Unscoped()
 	}
 
-	return u.db.Where("name in (?)", usernames).Delete(&v1.User{}).Error
+	return u.db.Scopes(byTenantUser(ctx, "name")).Where("name in (?)", usernames).Delete(&v1.User{}).Error
 }
 
-// Get return an user by the user identifier.
+// Get return an user by the user identifier, whatever its status.
 func (u *users) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
 	user := &v1.User{}
-	err := u.db.Where("name = ? and status = 1", username).First(&user).Error
+	err := u.db.Scopes(byTenantUser(ctx, "name")).
+		Where("name = ? and status <> ?", username, model.UserStatusDeleted).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrUserNotFound, err.Error())
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return user, nil
+}
+
+// GetByEmail return the first user registered with the email, whatever its status.
+func (u *users) GetByEmail(ctx context.Context, email string, opts metav1.GetOptions) (*v1.User, error) {
+	user := &v1.User{}
+	err := u.db.Scopes(byTenantUser(ctx, "name")).
+		Where("email = ? and status <> ?", email, model.UserStatusDeleted).
+		Order("id").
+		First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrUserNotFound, err.Error())
@@ -91,7 +118,8 @@ func (u *users) List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList
 
 	selector, _ := fields.ParseSelector(opts.FieldSelector)
 	username, _ := selector.RequiresExactMatch("name")
-	d := u.db.Where("name like ? and status = 1", "%"+username+"%").
+	d := u.db.Scopes(byTenantUser(ctx, "name")).
+		Where("name like ? and status <> ?", "%"+username+"%", model.UserStatusDeleted).
 		Offset(ol.Offset).
 		Limit(ol.Limit).
 		Order("id desc").
@@ -118,7 +146,8 @@ func (u *users) ListOptional(ctx context.Context, opts metav1.ListOptions) (*v1.
 		where.Name = username
 	}
 
-	d := u.db.Where(where).
+	d := u.db.Scopes(byTenantUser(ctx, "name")).
+		Where(where).
 		Not(whereNot).
 		Offset(ol.Offset).
 		Limit(ol.Limit).
@@ -0,0 +1,47 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+type userIdentities struct {
+	db *gorm.DB
+}
+
+func newUserIdentities(ds *datastore) *userIdentities {
+	return &userIdentities{ds.db}
+}
+
+// Create links the user to the external identity.
+func (u *userIdentities) Create(ctx context.Context, identity *v1.UserIdentity) error {
+	if err := u.db.Create(identity).Error; err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Get returns the link of the external identity.
+func (u *userIdentities) Get(ctx context.Context, issuer, subject string) (*v1.UserIdentity, error) {
+	identity := &v1.UserIdentity{}
+	err := u.db.Where("issuer = ? and subject = ?", issuer, subject).First(identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrIdentityNotLinked, "identity %s of issuer %s is not linked", subject, issuer)
+		}
+
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return identity, nil
+}
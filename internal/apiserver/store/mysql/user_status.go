@@ -0,0 +1,80 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"gorm.io/gorm"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/util/gormutil"
+)
+
+type userStatuses struct {
+	db *gorm.DB
+}
+
+func newUserStatuses(ds *datastore) *userStatuses {
+	return &userStatuses{ds.db}
+}
+
+// Change sets the status of the user and records the change, as long as the
+// user is in one of the from statuses.
+func (u *userStatuses) Change(ctx context.Context, change *model.UserStatusChange, from ...int) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"status": change.Status}
+		// activated users count as logged in now, otherwise the inactivity
+		// watcher would disable them again right away.
+		if change.Status == model.UserStatusActive {
+			updates["loginedAt"] = time.Now()
+		}
+
+		d := tx.Model(&v1.User{}).
+			Scopes(byTenantUser(ctx, "name")).
+			Where("name = ? and status in (?)", change.Username, from).
+			Updates(updates)
+		if d.Error != nil {
+			return errors.WithCode(code.ErrDatabase, d.Error.Error())
+		}
+
+		if d.RowsAffected == 0 {
+			return errors.WithCode(code.ErrUserStatusInvalid, "user %s can not become %s",
+				change.Username, model.UserStatusName(change.Status))
+		}
+
+		if err := tx.Create(change).Error; err != nil {
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// List returns the status changes of the user, the latest first.
+func (u *userStatuses) List(
+	ctx context.Context,
+	username string,
+	opts metav1.ListOptions,
+) (*model.UserStatusChangeList, error) {
+	ret := &model.UserStatusChangeList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	d := u.db.Where("username = ?", username).
+		Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount)
+
+	return ret, d.Error
+}
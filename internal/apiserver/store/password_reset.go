@@ -0,0 +1,24 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// PasswordResetStore defines the password reset storage interface.
+type PasswordResetStore interface {
+	// Create creates a new password reset, replacing the pending ones of the user.
+	Create(ctx context.Context, reset *v1.PasswordReset) error
+	// Use marks the unused and unexpired password reset with the given hash as
+	// used and returns it. It fails with code.ErrPasswordResetTokenInvalid
+	// otherwise, so that a token can only be used once.
+	Use(ctx context.Context, hash string) (*v1.PasswordReset, error)
+	// DeleteExpired deletes the password resets which expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,20 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// PolicyBindingStore defines the storage interface of the policies bound to groups and roles.
+type PolicyBindingStore interface {
+	Create(ctx context.Context, binding *v1.PolicyBinding, opts metav1.CreateOptions) error
+	Delete(ctx context.Context, principal, owner, name string, opts metav1.DeleteOptions) error
+	// List returns the policies bound to the given principal, or to all the principals if principal is empty.
+	List(ctx context.Context, principal string, opts metav1.ListOptions) (*v1.PolicyBindingList, error)
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// RoleStore defines the role storage interface.
+type RoleStore interface {
+	Create(ctx context.Context, role *v1.Role, opts metav1.CreateOptions) error
+	Update(ctx context.Context, role *v1.Role, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Role, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.RoleList, error)
+	Bind(ctx context.Context, binding *v1.RoleBinding, opts metav1.CreateOptions) error
+	Unbind(ctx context.Context, role, kind, subject string, opts metav1.DeleteOptions) error
+	// ListBindings returns the bindings of the given role, or of all the roles if role is empty.
+	ListBindings(ctx context.Context, role string, opts metav1.ListOptions) (*v1.RoleBindingList, error)
+}
@@ -6,9 +6,11 @@ package store
 
 import (
 	"context"
+	"time"
 
-	v1 "github.com/marmotedu/api/apiserver/v1"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 )
 
 // SecretStore defines the secret storage interface.
@@ -19,4 +21,7 @@ type SecretStore interface {
 	DeleteCollection(ctx context.Context, username string, secretIDs []string, opts metav1.DeleteOptions) error
 	Get(ctx context.Context, username, secretID string, opts metav1.GetOptions) (*v1.Secret, error)
 	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error)
+	// RecordUsage records when the secrets were last used, it never moves the
+	// last used time of a secret backwards.
+	RecordUsage(ctx context.Context, lastUsed map[string]time.Time) error
 }
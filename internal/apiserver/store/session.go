@@ -0,0 +1,34 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// SessionStore defines the login session storage interface.
+type SessionStore interface {
+	// Create creates a new session together with its first refresh token.
+	Create(ctx context.Context, session *v1.Session, token *v1.RefreshToken) error
+	Get(ctx context.Context, sid string) (*v1.Session, error)
+	// GetToken returns the refresh token with the given hash.
+	GetToken(ctx context.Context, hash string) (*v1.RefreshToken, error)
+	// Rotate marks the refresh token with the given hash as rotated and stores
+	// its successor. It fails with code.ErrTokenReused if the token has already
+	// been rotated.
+	Rotate(ctx context.Context, hash string, token *v1.RefreshToken, session *v1.Session) error
+	// List returns the active sessions of the user.
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SessionList, error)
+	Revoke(ctx context.Context, username, sid string) error
+	// RevokeAll revokes all the active sessions of the user.
+	RevokeAll(ctx context.Context, username string) error
+	// DeleteExpired deletes the sessions which expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// SigningKeyStore defines the jwt signing key storage interface.
+type SigningKeyStore interface {
+	Create(ctx context.Context, key *v1.SigningKey) error
+	// List returns all the keys, from the oldest to the newest.
+	List(ctx context.Context) ([]*v1.SigningKey, error)
+	// Retire stops the key from signing tokens, it is deleted after expiresAt.
+	Retire(ctx context.Context, kid string, expiresAt time.Time) error
+	// DeleteExpired deletes the retired keys which expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -16,9 +16,24 @@ type Factory interface {
 	Secrets() SecretStore
 	Policies() PolicyStore
 	PolicyAudits() PolicyAuditStore
+	AuditLogs() AuditLogStore
+	Groups() GroupStore
+	Roles() RoleStore
+	PolicyBindings() PolicyBindingStore
+	Tenants() TenantStore
+	SigningKeys() SigningKeyStore
+	Sessions() SessionStore
+	TOTPs() TOTPStore
+	PasswordResets() PasswordResetStore
+	UserStatuses() UserStatusStore
+	EmailVerifications() EmailVerificationStore
+	UserIdentities() UserIdentityStore
+	AccessTokens() AccessTokenStore
 	Items() ItemStore
 	ItemAttributes() ItemAttributesStore
 	ItemImage() ItemImageStore
+	Catalog() CatalogStore
+	Backups() BackupStore
 	Close() error
 }
 
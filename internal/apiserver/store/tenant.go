@@ -0,0 +1,28 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// TenantStore defines the tenant storage interface.
+type TenantStore interface {
+	Create(ctx context.Context, tenant *v1.Tenant, opts metav1.CreateOptions) error
+	Update(ctx context.Context, tenant *v1.Tenant, opts metav1.UpdateOptions) error
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.Tenant, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.TenantList, error)
+	// AddMember adds a user to a tenant, the previous membership of the user is replaced.
+	AddMember(ctx context.Context, member *v1.TenantMember, opts metav1.CreateOptions) error
+	RemoveMember(ctx context.Context, tenant, username string, opts metav1.DeleteOptions) error
+	// GetMember returns the tenant membership of the given user.
+	GetMember(ctx context.Context, username string, opts metav1.GetOptions) (*v1.TenantMember, error)
+	// ListMembers returns the members of the given tenant, or of all the tenants if tenant is empty.
+	ListMembers(ctx context.Context, tenant string, opts metav1.ListOptions) (*v1.TenantMemberList, error)
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// TOTPStore defines the two-factor authentication enrollment storage interface.
+type TOTPStore interface {
+	// Create creates a new enrollment, replacing the previous one of the user.
+	Create(ctx context.Context, totp *v1.TOTP) error
+	Get(ctx context.Context, username string) (*v1.TOTP, error)
+	Update(ctx context.Context, totp *v1.TOTP) error
+	// UseStep records the time step of an accepted code. It fails with
+	// code.ErrTOTPCodeInvalid if a code of the same or a later step has already been used.
+	UseStep(ctx context.Context, username string, step int64) error
+	// UseRecoveryCode replaces the recovery codes of the user with the remaining
+	// ones. It fails with code.ErrTOTPCodeInvalid if the recovery codes are not
+	// the given ones any more, that is the code has been used concurrently.
+	UseRecoveryCode(ctx context.Context, username, codes, remaining string) error
+	Delete(ctx context.Context, username string) error
+}
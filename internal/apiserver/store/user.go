@@ -17,6 +17,9 @@ type UserStore interface {
 	Update(ctx context.Context, user *v1.User, opts metav1.UpdateOptions) error
 	Delete(ctx context.Context, username string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, usernames []string, opts metav1.DeleteOptions) error
+	// Get returns the user whatever its status, only the deleted users are not found.
 	Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error)
+	// GetByEmail returns the first user registered with the email.
+	GetByEmail(ctx context.Context, email string, opts metav1.GetOptions) (*v1.User, error)
 	List(ctx context.Context, opts metav1.ListOptions) (*v1.UserList, error)
 }
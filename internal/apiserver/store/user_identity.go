@@ -0,0 +1,21 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// UserIdentityStore defines the storage interface of the links between the
+// users and their external identities.
+type UserIdentityStore interface {
+	// Create links the user to the external identity.
+	Create(ctx context.Context, identity *v1.UserIdentity) error
+	// Get returns the link of the external identity, it fails with
+	// code.ErrIdentityNotLinked if the identity is not linked to any user.
+	Get(ctx context.Context, issuer, subject string) (*v1.UserIdentity, error)
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"context"
+
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+)
+
+// UserStatusStore defines the user lifecycle storage interface.
+type UserStatusStore interface {
+	// Change sets the status of the user to change.Status and records the
+	// change, as long as the user is in one of the from statuses. It fails
+	// with code.ErrUserStatusInvalid otherwise.
+	Change(ctx context.Context, change *v1.UserStatusChange, from ...int) error
+	// List returns the status changes of the user, the latest first.
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.UserStatusChangeList, error)
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/spf13/viper"
+
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/lockout"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
+)
+
+const (
+	challengeKeyPrefix   = "iam-2fa-challenge-"
+	attemptsKeyPrefix    = "iam-2fa-attempts-"
+	challengeTTL         = 5 * time.Minute
+	maxChallengeAttempts = 5
+)
+
+// challenge defines a pending login, stored in redis under the hash of its token.
+type challenge struct {
+	Username string `json:"username"`
+	Enroll   bool   `json:"enroll"`
+}
+
+// secondFactor implements auth.SecondFactor with TOTP codes. The wrong codes
+// count as failed logins of the user, so that the codes cannot be guessed by
+// logging in again with the password.
+type secondFactor struct {
+	redis            storage.RedisCluster
+	limiter          *lockout.Limiter
+	issuer           string
+	requireForAdmins bool
+}
+
+var _ auth.SecondFactor = &secondFactor{}
+
+func newSecondFactor() *secondFactor {
+	return &secondFactor{
+		limiter:          newLoginLimiter(),
+		issuer:           viper.GetString("two-factor.issuer"),
+		requireForAdmins: viper.GetBool("two-factor.require-for-admins"),
+	}
+}
+
+// Challenge challenges the enrolled users, and the administrators who have
+// to enroll when two-factor authentication is required for them.
+func (s *secondFactor) Challenge(c *gin.Context, data interface{}) (*auth.Challenge, error) {
+	user, ok := data.(*v1.User)
+	if !ok {
+		return nil, errors.WithCode(code.ErrUnknown, "unexpected authenticated data %T", data)
+	}
+
+	enrolled, required, err := s.required(c, user)
+	if err != nil || !required {
+		return nil, err
+	}
+
+	token, err := s.newChallenge(&challenge{Username: user.Name, Enroll: !enrolled})
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	return &auth.Challenge{Token: token, Enroll: !enrolled}, nil
+}
+
+// Verify checks the code of the challenged user. Administrators who had to
+// enroll confirm their enrollment with their first code.
+func (s *secondFactor) Verify(c *gin.Context, token, passcode string) (interface{}, error) {
+	ch, err := s.getChallenge(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.limiter.Allow(ch.Username, c.ClientIP()); err != nil {
+		return nil, err
+	}
+
+	srv := srvv1.NewService(store.Client()).TOTPs()
+	if ch.Enroll {
+		err = srv.Confirm(c, ch.Username, passcode)
+	} else {
+		err = srv.Verify(c, ch.Username, passcode)
+	}
+
+	if err != nil {
+		if errors.IsCode(err, code.ErrTOTPCodeInvalid) {
+			s.failChallenge(token)
+			s.limiter.Failed(ch.Username, c.ClientIP())
+		}
+
+		return nil, err
+	}
+
+	s.redis.DeleteKey(challengeKeyPrefix + hashToken(token))
+	s.limiter.Succeeded(ch.Username, c.ClientIP())
+
+	user, err := store.Client().Users().Get(c, ch.Username, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// the user may have been suspended since the password was checked.
+	if err := srvv1.CheckUserActive(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// required tells whether the user is enrolled, and whether a second factor is required.
+func (s *secondFactor) required(ctx context.Context, user *v1.User) (bool, bool, error) {
+	enrollment, err := store.Client().TOTPs().Get(ctx, user.Name)
+	if err != nil && !errors.IsCode(err, code.ErrTOTPNotEnabled) {
+		return false, false, err
+	}
+
+	if enrollment.Enabled() {
+		return true, true, nil
+	}
+
+	return false, s.requireForAdmins && isAdministrator(ctx, user), nil
+}
+
+// enrollHandler starts the enrollment of an administrator challenged at login,
+// the enrollment is confirmed by answering the challenge.
+func (s *secondFactor) enrollHandler(c *gin.Context) {
+	var r struct {
+		MFAToken string `json:"mfaToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	ch, err := s.getChallenge(r.MFAToken)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if !ch.Enroll {
+		core.WriteResponse(c, errors.WithCode(code.ErrTOTPAlreadyEnabled, "user %s is already enrolled", ch.Username), nil)
+
+		return
+	}
+
+	enrollment, err := srvv1.NewService(store.Client()).TOTPs().Enroll(c, ch.Username, s.issuer)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, enrollment)
+}
+
+func (s *secondFactor) newChallenge(ch *challenge) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	value, _ := json.Marshal(ch)
+
+	if err := s.redis.SetKey(challengeKeyPrefix+hashToken(token), string(value), challengeTTL); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *secondFactor) getChallenge(token string) (*challenge, error) {
+	value, err := s.redis.GetKey(challengeKeyPrefix + hashToken(token))
+	if err != nil {
+		return nil, errors.WithCode(code.ErrTOTPChallengeInvalid, err.Error())
+	}
+
+	var ch challenge
+	if err := json.Unmarshal([]byte(value), &ch); err != nil {
+		return nil, errors.WithCode(code.ErrTOTPChallengeInvalid, err.Error())
+	}
+
+	return &ch, nil
+}
+
+// failChallenge counts a wrong code, the challenge is dropped after too many
+// of them so that the codes cannot be guessed.
+func (s *secondFactor) failChallenge(token string) {
+	hash := hashToken(token)
+	if s.redis.IncrememntWithExpire(attemptsKeyPrefix+hash, int64(challengeTTL/time.Second)) >= maxChallengeAttempts {
+		log.Warnf("too many wrong two-factor codes, drop the challenge")
+		s.redis.DeleteKey(challengeKeyPrefix + hash)
+	}
+}
+
+// secondFactorRequired tells whether the user must pass a second factor, so
+// that password only authentication such as basic authentication is refused.
+func secondFactorRequired(ctx context.Context, user *v1.User) bool {
+	_, required, err := newSecondFactor().required(ctx, user)
+	if err != nil {
+		log.L(ctx).Errorf("get two-factor enrollment of user %s failed: %s", user.Name, err.Error())
+
+		return true
+	}
+
+	return required
+}
+
+// isAdministrator tells whether the user is an active platform administrator or
+// an active administrator of its tenant.
+func isAdministrator(ctx context.Context, user *v1.User) bool {
+	if srvv1.CheckUserActive(user) != nil {
+		return false
+	}
+
+	if user.IsAdmin == 1 {
+		return true
+	}
+
+	member, err := store.Client().Tenants().GetMember(ctx, user.Name, metav1.GetOptions{})
+
+	return err == nil && member.IsAdmin == 1
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
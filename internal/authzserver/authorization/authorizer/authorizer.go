@@ -56,9 +56,9 @@ func (auth *Authorization) Get(id string) (*ladon.DefaultPolicy, error) {
 	return &ladon.DefaultPolicy{}, nil
 }
 
-// List returns all the policies under the username.
-func (auth *Authorization) List(username string) ([]*ladon.DefaultPolicy, error) {
-	return auth.getter.GetPolicy(username)
+// List returns all the policies under the tenant qualified username.
+func (auth *Authorization) List(key string) ([]*ladon.DefaultPolicy, error) {
+	return auth.getter.GetPolicy(key)
 }
 
 // LogRejectedAccessRequest write rejected subject access to redis.
@@ -7,6 +7,7 @@ package authorization
 import (
 	"github.com/ory/ladon"
 	"github.com/pkg/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
 )
 
 // PolicyManager is a mysql implementation for Manager to store
@@ -52,13 +53,17 @@ func (*PolicyManager) GetAll(limit, offset int64) (ladon.Policies, error) {
 // a set that exactly matches the request, or a superset of it. If an error occurs, it returns nil and
 // the error.
 func (m *PolicyManager) FindRequestCandidates(r *ladon.Request) (ladon.Policies, error) {
-	username := ""
+	username, name := "", ""
 
 	if user, ok := r.Context["username"].(string); ok {
 		username = user
 	}
 
-	policies, err := m.client.List(username)
+	if t, ok := r.Context[tenant.Key].(string); ok {
+		name = t
+	}
+
+	policies, err := m.client.List(tenant.Qualify(name, username))
 	if err != nil {
 		return nil, errors.Wrap(err, "list policies failed")
 	}
@@ -13,7 +13,8 @@ type AuthorizationInterface interface {
 	Delete(id string) error
 	DeleteCollection(idList []string) error
 	Get(id string) (*ladon.DefaultPolicy, error)
-	List(username string) ([]*ladon.DefaultPolicy, error)
+	// List returns the policies of the tenant qualified username, see tenant.Qualify.
+	List(key string) ([]*ladon.DefaultPolicy, error)
 
 	// The following two functions tracks denied and granted authorizations.
 	LogRejectedAccessRequest(request *ladon.Request, pool ladon.Policies, deciders ladon.Policies)
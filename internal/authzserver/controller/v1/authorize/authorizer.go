@@ -7,12 +7,16 @@ package authorize
 
 import (
 	"github.com/gin-gonic/gin"
+	authzv1 "github.com/marmotedu/api/authz/v1"
 	"github.com/marmotedu/component-base/pkg/core"
 	"github.com/marmotedu/errors"
 	"github.com/ory/ladon"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/authorization"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/authorization/authorizer"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/decision"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
 )
 
 // AuthzController create a authorize handler used to handle authorize request.
@@ -37,13 +41,74 @@ func (a *AuthzController) Authorize(c *gin.Context) {
 		return
 	}
 
-	auth := authorization.NewAuthorizer(authorizer.NewAuthorization(a.store))
 	if r.Context == nil {
 		r.Context = ladon.Context{}
 	}
 
-	r.Context["username"] = c.GetString("username")
-	rsp := auth.Authorize(&r)
+	username := c.GetString("username")
+	r.Context["username"] = username
+
+	// the tenant comes from the secret, a request can only match the policies of its own tenant.
+	r.Context[tenant.Key] = tenant.Default
+	if name, ok := tenant.FromContext(c); ok {
+		r.Context[tenant.Key] = name
+	}
+
+	// a secret restricted to some scopes can not authorize anything outside of
+	// them, whatever the policies of its user allow.
+	if scopes, ok := scope.FromContext(c); ok && !scope.Allowed(scopes, r.Resource, r.Action) {
+		core.WriteResponse(c, nil, &authzv1.Response{
+			Denied: true,
+			Reason: "request is out of the scopes of the secret",
+		})
+
+		return
+	}
+
+	authz := authorizer.NewAuthorization(a.store)
+
+	decisions := decision.GetCache()
+	if decisions != nil {
+		if d, ok := decisions.Get(username, &r); ok {
+			// the cached decisions are recorded by analytics as the evaluated ones.
+			if d.Response.Allowed {
+				authz.LogGrantedAccessRequest(&r, d.Policies, d.Deciders)
+			} else {
+				authz.LogRejectedAccessRequest(&r, d.Policies, d.Deciders)
+			}
+
+			core.WriteResponse(c, nil, d.Response)
+
+			return
+		}
+	}
+
+	recorder := &decisionRecorder{AuthorizationInterface: authz}
+	rsp := authorization.NewAuthorizer(recorder).Authorize(&r)
+
+	// the decisions ladon failed to make, e.g. when the policies can not be
+	// listed, are not cached.
+	if decisions != nil && recorder.decision != nil {
+		recorder.decision.Response = rsp
+		decisions.Set(username, &r, recorder.decision)
+	}
 
 	core.WriteResponse(c, nil, rsp)
 }
+
+// decisionRecorder keeps the policies ladon decided with, so that the
+// decision can be cached.
+type decisionRecorder struct {
+	authorization.AuthorizationInterface
+	decision *decision.Decision
+}
+
+func (d *decisionRecorder) LogRejectedAccessRequest(r *ladon.Request, p ladon.Policies, deciders ladon.Policies) {
+	d.decision = &decision.Decision{Policies: p, Deciders: deciders}
+	d.AuthorizationInterface.LogRejectedAccessRequest(r, p, deciders)
+}
+
+func (d *decisionRecorder) LogGrantedAccessRequest(r *ladon.Request, p ladon.Policies, deciders ladon.Policies) {
+	d.decision = &decision.Decision{Policies: p, Deciders: deciders}
+	d.AuthorizationInterface.LogGrantedAccessRequest(r, p, deciders)
+}
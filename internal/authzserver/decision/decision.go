@@ -0,0 +1,187 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package decision
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	authzv1 "github.com/marmotedu/api/authz/v1"
+	"github.com/ory/ladon"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	hitsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "iam_authz_decision_cache_hits_total",
+		Help: "Number of authorization requests answered from the decision cache.",
+	})
+	missesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "iam_authz_decision_cache_misses_total",
+		Help: "Number of authorization requests that had to be evaluated by ladon.",
+	})
+	invalidationsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "iam_authz_decision_cache_invalidations_total",
+		Help: "Number of times cached decisions of a user were flushed after a policy change.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(hitsTotal, missesTotal, invalidationsTotal)
+}
+
+var decisions *Cache
+
+// Decision is a cached authorization decision, with the policies it was made
+// from, so that the requests answered from the cache are recorded by
+// analytics as well.
+type Decision struct {
+	Response *authzv1.Response
+	Policies ladon.Policies
+	Deciders ladon.Policies
+}
+
+// entry is a decision and the time it was cached at.
+type entry struct {
+	decision *Decision
+	cachedAt time.Time
+}
+
+// Cache keeps authorization decisions for a short time. Entries are keyed by
+// username, subject, resource, action and a hash of the request context.
+// The decisions of a user cached before the user was last invalidated are
+// ignored, invalidations are forgotten once all these decisions have expired.
+type Cache struct {
+	lock        sync.RWMutex
+	invalidated map[string]time.Time
+	store       *ristretto.Cache
+	ttl         time.Duration
+}
+
+// NewCache returns a new decision cache instance.
+func NewCache(options *DecisionOptions) (*Cache, error) {
+	store, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: options.MaxCost * 10, // number of keys to track frequency of.
+		MaxCost:     options.MaxCost,
+		BufferItems: 64, // number of keys per Get buffer.
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decisions = &Cache{
+		invalidated: make(map[string]time.Time),
+		store:       store,
+		ttl:         options.TTL,
+	}
+
+	return decisions, nil
+}
+
+// GetCache returns the existed decision cache instance.
+// Returns nil when the decision cache is not enabled.
+func GetCache() *Cache {
+	return decisions
+}
+
+// Get returns the cached decision for the given request and user.
+func (c *Cache) Get(username string, r *ladon.Request) (*Decision, bool) {
+	key, ok := key(username, r)
+	if !ok {
+		missesTotal.Inc()
+
+		return nil, false
+	}
+
+	value, ok := c.store.Get(key)
+	if !ok || c.stale(username, value.(*entry)) {
+		missesTotal.Inc()
+
+		return nil, false
+	}
+
+	hitsTotal.Inc()
+
+	return value.(*entry).decision, true
+}
+
+// Set caches the decision for the given request and user.
+func (c *Cache) Set(username string, r *ladon.Request, d *Decision) {
+	key, ok := key(username, r)
+	if !ok {
+		return
+	}
+
+	c.store.SetWithTTL(key, &entry{decision: d, cachedAt: time.Now()}, 1, c.ttl)
+}
+
+// Invalidate flushes all the cached decisions of the given users.
+func (c *Cache) Invalidate(usernames ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for username, at := range c.invalidated {
+		if now.Sub(at) > c.ttl {
+			delete(c.invalidated, username)
+		}
+	}
+
+	for _, username := range usernames {
+		c.invalidated[username] = now
+		invalidationsTotal.Inc()
+	}
+}
+
+// Clear flushes all the cached decisions.
+func (c *Cache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.store.Clear()
+	c.invalidated = make(map[string]time.Time)
+}
+
+// Wait blocks until all the buffered writes have been applied.
+func (c *Cache) Wait() {
+	c.store.Wait()
+}
+
+// stale tells whether the entry was cached before its user was invalidated.
+func (c *Cache) stale(username string, e *entry) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	at, ok := c.invalidated[username]
+
+	return ok && !e.cachedAt.After(at)
+}
+
+func key(username string, r *ladon.Request) (string, bool) {
+	// encoding/json sorts map keys, so equal contexts always hash the same.
+	ctx, err := json.Marshal(r.Context)
+	if err != nil {
+		return "", false
+	}
+	ctxHash := sha256.Sum256(ctx)
+
+	hash := sha256.New()
+	for _, field := range []string{
+		username,
+		r.Subject,
+		r.Resource,
+		r.Action,
+		hex.EncodeToString(ctxHash[:]),
+	} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
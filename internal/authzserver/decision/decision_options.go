@@ -0,0 +1,65 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package decision
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// DecisionOptions contains configuration items related to the decision cache.
+type DecisionOptions struct {
+	Enable  bool          `json:"enable"   mapstructure:"enable"`
+	TTL     time.Duration `json:"ttl"      mapstructure:"ttl"`
+	MaxCost int64         `json:"max-cost" mapstructure:"max-cost"`
+}
+
+// NewDecisionOptions creates a DecisionOptions object with default parameters.
+func NewDecisionOptions() *DecisionOptions {
+	return &DecisionOptions{
+		Enable:  false,
+		TTL:     5 * time.Second,
+		MaxCost: 100000,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *DecisionOptions) Validate() []error {
+	if o == nil {
+		return nil
+	}
+	errors := []error{}
+
+	if o.Enable && o.TTL <= 0 {
+		errors = append(errors, fmt.Errorf("--decision-cache.ttl %v must be greater than 0", o.TTL))
+	}
+
+	if o.Enable && o.MaxCost <= 0 {
+		errors = append(errors, fmt.Errorf("--decision-cache.max-cost %v must be greater than 0", o.MaxCost))
+	}
+
+	return errors
+}
+
+// AddFlags adds flags related to the decision cache for a specific authz server to the
+// specified FlagSet.
+func (o *DecisionOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.BoolVar(&o.Enable, "decision-cache.enable", o.Enable, ""+
+		"Cache authorization decisions locally so identical requests skip ladon matching. "+
+		"Cached decisions are still recorded to analytics.")
+
+	fs.DurationVar(&o.TTL, "decision-cache.ttl", o.TTL,
+		"How long a cached authorization decision stays valid.")
+
+	fs.Int64Var(&o.MaxCost, "decision-cache.max-cost", o.MaxCost,
+		"Maximum number of authorization decisions kept in the cache.")
+}
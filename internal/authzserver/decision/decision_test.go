@@ -0,0 +1,67 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package decision
+
+import (
+	"testing"
+	"time"
+
+	authzv1 "github.com/marmotedu/api/authz/v1"
+	"github.com/ory/ladon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c, err := NewCache(&DecisionOptions{Enable: true, TTL: time.Minute, MaxCost: 100})
+	assert.Nil(t, err)
+
+	r := &ladon.Request{
+		Subject:  "users:maria",
+		Resource: "resources:articles:ladon-introduction",
+		Action:   "delete",
+		Context:  ladon.Context{"username": "maria", "remoteIPAddress": "192.168.0.5"},
+	}
+
+	_, ok := c.Get("maria", r)
+	assert.False(t, ok)
+
+	c.Set("maria", r, &Decision{Response: &authzv1.Response{Allowed: true}})
+	c.Wait()
+
+	d, ok := c.Get("maria", r)
+	assert.True(t, ok)
+	assert.True(t, d.Response.Allowed)
+
+	// a different context must not share the decision.
+	other := *r
+	other.Context = ladon.Context{"username": "maria", "remoteIPAddress": "10.0.0.1"}
+	_, ok = c.Get("maria", &other)
+	assert.False(t, ok)
+
+	// invalidating another user keeps the decision.
+	c.Invalidate("colin")
+	_, ok = c.Get("maria", r)
+	assert.True(t, ok)
+
+	c.Invalidate("maria")
+	_, ok = c.Get("maria", r)
+	assert.False(t, ok)
+}
+
+func TestCacheForgetsInvalidations(t *testing.T) {
+	c, err := NewCache(&DecisionOptions{Enable: true, TTL: 50 * time.Millisecond, MaxCost: 100})
+	assert.Nil(t, err)
+
+	c.Invalidate("colin", "maria")
+	assert.Len(t, c.invalidated, 2)
+
+	// the decisions cached before an invalidation have all expired after the ttl.
+	time.Sleep(60 * time.Millisecond)
+	c.Invalidate("maria")
+	assert.Len(t, c.invalidated, 1)
+
+	c.Clear()
+	assert.Len(t, c.invalidated, 0)
+}
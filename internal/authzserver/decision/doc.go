@@ -0,0 +1,7 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package decision implements a short-lived local cache of authorization
+// decisions, used to skip ladon matching for identical repeated requests.
+package decision
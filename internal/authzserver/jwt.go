@@ -5,14 +5,38 @@
 package authzserver
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/load/cache"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware/auth"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
 )
 
+// jwksRefreshInterval defines how long the keys published by iam-apiserver are cached.
+const jwksRefreshInterval = 10 * time.Minute
+
 func newCacheAuth() middleware.AuthStrategy {
-	return auth.NewCacheStrategy(getSecretFunc())
+	var keys jwks.PublicKeyGetter
+	if url := viper.GetString("jwks-url"); url != "" {
+		keys = jwks.NewRemoteKeySet(url, jwksRefreshInterval)
+	}
+
+	store := revocation.Client()
+	if viper.GetBool("revocation-fail-open") {
+		store = revocation.FailOpen(store)
+	}
+
+	var used func(string)
+	if usages != nil {
+		used = usages.Record
+	}
+
+	return auth.NewCacheStrategy(getSecretFunc(), keys, store, used)
 }
 
 func getSecretFunc() func(string) (auth.Secret, error) {
@@ -29,9 +53,14 @@ func getSecretFunc() func(string) (auth.Secret, error) {
 
 		return auth.Secret{
 			Username: secret.Username,
-			ID:       secret.SecretId,
+			Tenant:   cli.GetTenant(secret.Username),
+			ID:       secret.SecretID,
 			Key:      secret.SecretKey,
 			Expires:  secret.Expires,
+
+			Scopes:             secret.Scopes,
+			PreviousKey:        secret.PreviousKey,
+			PreviousKeyExpires: secret.PreviousKeyExpires,
 		}, nil
 	}
 }
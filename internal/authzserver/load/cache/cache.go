@@ -5,21 +5,34 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"sync"
 
 	"github.com/dgraph-io/ristretto"
-	pb "github.com/marmotedu/api/proto/apiserver/v1"
 	"github.com/marmotedu/errors"
 	"github.com/ory/ladon"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
 )
 
-// Cache is used to store secrets and policies.
+// PolicyChangeHandler is called after a reload with the users whose policies changed.
+type PolicyChangeHandler func(usernames ...string)
+
+// Cache is used to store secrets and policies. Policies are partitioned by tenant,
+// the key of a policy entry is the tenant qualified username.
 type Cache struct {
 	lock     *sync.RWMutex
 	cli      store.Factory
 	secrets  *ristretto.Cache
 	policies *ristretto.Cache
+	// tenants maps a username to the tenant of the user.
+	tenants *rpc.ListMembershipsResponse
+	// digests holds a fingerprint of every user's policies of the last reload.
+	digests  map[string]string
+	handlers []PolicyChangeHandler
 }
 
 var (
@@ -65,6 +78,7 @@ func GetCacheInsOr(cli store.Factory) (*Cache, error) {
 				lock:     new(sync.RWMutex),
 				secrets:  secretCache,
 				policies: policyCache,
+				digests:  make(map[string]string),
 			}
 		})
 	}
@@ -73,7 +87,7 @@ func GetCacheInsOr(cli store.Factory) (*Cache, error) {
 }
 
 // GetSecret return secret detail for the given key.
-func (c *Cache) GetSecret(key string) (*pb.SecretInfo, error) {
+func (c *Cache) GetSecret(key string) (*rpc.SecretInfo, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -82,10 +96,24 @@ func (c *Cache) GetSecret(key string) (*pb.SecretInfo, error) {
 		return nil, ErrSecretNotFound
 	}
 
-	return value.(*pb.SecretInfo), nil
+	return value.(*rpc.SecretInfo), nil
 }
 
-// GetPolicy return user's ladon policies for the given user.
+// GetTenant return the tenant of the given user.
+func (c *Cache) GetTenant(username string) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.tenants == nil {
+		return tenant.Default
+	}
+
+	return c.tenants.TenantOf(username)
+}
+
+// GetPolicy return user's effective ladon policies for the given tenant qualified
+// username, see tenant.Qualify. It includes the policies bound to the groups and
+// roles of the user.
 func (c *Cache) GetPolicy(key string) ([]*ladon.DefaultPolicy, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -98,6 +126,15 @@ func (c *Cache) GetPolicy(key string) ([]*ladon.DefaultPolicy, error) {
 	return value.([]*ladon.DefaultPolicy), nil
 }
 
+// OnPolicyChange registers a handler which is notified when the policies of
+// some users changed during a reload.
+func (c *Cache) OnPolicyChange(handler PolicyChangeHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.handlers = append(c.handlers, handler)
+}
+
 // Reload reload secrets and policies.
 func (c *Cache) Reload() error {
 	c.lock.Lock()
@@ -120,10 +157,97 @@ func (c *Cache) Reload() error {
 		return errors.Wrap(err, "list policies failed")
 	}
 
+	// reload group and role memberships
+	memberships, err := c.cli.Memberships().List()
+	if err != nil {
+		return errors.Wrap(err, "list memberships failed")
+	}
+
+	effective := resolvePolicies(policies, memberships)
+
 	c.policies.Clear()
-	for key, val := range policies {
-		c.policies.Set(key, val, 1)
+	for username, val := range effective {
+		c.policies.Set(tenant.Qualify(memberships.TenantOf(username), username), val, 1)
 	}
+	c.tenants = memberships
+
+	c.notifyPolicyChanges(effective)
 
 	return nil
 }
+
+// notifyPolicyChanges compares the loaded policies with the ones of the previous
+// reload and notifies the registered handlers about the users whose policies changed.
+func (c *Cache) notifyPolicyChanges(policies map[string][]*ladon.DefaultPolicy) {
+	digests := make(map[string]string, len(policies))
+	for key, val := range policies {
+		data, _ := json.Marshal(val)
+		sum := sha256.Sum256(data)
+		digests[key] = hex.EncodeToString(sum[:])
+	}
+
+	changed := []string{}
+	for key, digest := range digests {
+		if c.digests[key] != digest {
+			changed = append(changed, key)
+		}
+	}
+	for key := range c.digests {
+		if _, ok := digests[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	c.digests = digests
+
+	if len(changed) == 0 {
+		return
+	}
+
+	for _, handler := range c.handlers {
+		handler(changed...)
+	}
+}
+
+// resolvePolicies returns the effective policies of every user, the union of the
+// policies owned by the user and the policies bound to the user's groups and roles.
+// Policies owned by users of another tenant are never included.
+func resolvePolicies(
+	policies map[string][]*ladon.DefaultPolicy,
+	memberships *rpc.ListMembershipsResponse,
+) map[string][]*ladon.DefaultPolicy {
+	effective := make(map[string][]*ladon.DefaultPolicy, len(policies))
+	for username, pols := range policies {
+		effective[username] = pols
+	}
+
+	for username, principals := range memberships.Principals {
+		seen := make(map[*ladon.DefaultPolicy]struct{})
+		pols := append([]*ladon.DefaultPolicy{}, policies[username]...)
+		for _, pol := range pols {
+			seen[pol] = struct{}{}
+		}
+
+		for _, principal := range principals {
+			for _, ref := range memberships.Policies[principal] {
+				if memberships.TenantOf(ref.Username) != memberships.TenantOf(username) {
+					continue
+				}
+
+				for _, pol := range policies[ref.Username] {
+					if pol.GetID() != ref.Name {
+						continue
+					}
+
+					if _, ok := seen[pol]; !ok {
+						seen[pol] = struct{}{}
+						pols = append(pols, pol)
+					}
+				}
+			}
+		}
+
+		effective[username] = pols
+	}
+
+	return effective
+}
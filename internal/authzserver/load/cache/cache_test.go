@@ -0,0 +1,46 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/ory/ladon"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePolicies(t *testing.T) {
+	own := &ladon.DefaultPolicy{ID: "own"}
+	shared := &ladon.DefaultPolicy{ID: "shared"}
+	foreign := &ladon.DefaultPolicy{ID: "foreign"}
+	policies := map[string][]*ladon.DefaultPolicy{
+		"maria": {own},
+		"admin": {shared},
+		"jane":  {foreign},
+	}
+
+	memberships := &rpc.ListMembershipsResponse{
+		Principals: map[string][]string{
+			"maria": {"default/group:sellers", "default/role:editor"},
+			"colin": {"default/role:editor"},
+		},
+		Policies: map[string][]rpc.PolicyRef{
+			"default/group:sellers": {{Username: "admin", Name: "shared"}},
+			"default/role:editor": {
+				{Username: "admin", Name: "shared"},
+				{Username: "admin", Name: "missing"},
+				{Username: "jane", Name: "foreign"},
+			},
+		},
+		Tenants: map[string]string{"jane": "shop"},
+	}
+
+	effective := resolvePolicies(policies, memberships)
+	assert.Equal(t, []*ladon.DefaultPolicy{own, shared}, effective["maria"])
+	assert.Equal(t, []*ladon.DefaultPolicy{shared}, effective["colin"])
+	assert.Equal(t, []*ladon.DefaultPolicy{shared}, effective["admin"])
+	assert.Equal(t, []*ladon.DefaultPolicy{own}, policies["maria"])
+}
@@ -6,6 +6,7 @@ import (
 	cliflag "github.com/marmotedu/component-base/pkg/cli/flag"
 	"github.com/marmotedu/log"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/analytics"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/decision"
 	genericoptions "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/options"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/server"
 )
@@ -14,6 +15,8 @@ import (
 type Options struct {
 	RPCServer               string                                 `json:"rpcserver"      mapstructure:"rpcserver"`
 	ClientCA                string                                 `json:"client-ca-file" mapstructure:"client-ca-file"`
+	JwksURL                 string                                 `json:"jwks-url"       mapstructure:"jwks-url"`
+	RevocationFailOpen      bool                                   `json:"revocation-fail-open" mapstructure:"revocation-fail-open"`
 	GenericServerRunOptions *genericoptions.ServerRunOptions       `json:"server"         mapstructure:"server"`
 	InsecureServing         *genericoptions.InsecureServingOptions `json:"insecure"       mapstructure:"insecure"`
 	SecureServing           *genericoptions.SecureServingOptions   `json:"secure"         mapstructure:"secure"`
@@ -21,6 +24,7 @@ type Options struct {
 	FeatureOptions          *genericoptions.FeatureOptions         `json:"feature"        mapstructure:"feature"`
 	Log                     *log.Options                           `json:"log"            mapstructure:"log"`
 	AnalyticsOptions        *analytics.AnalyticsOptions            `json:"analytics"      mapstructure:"analytics"`
+	DecisionOptions         *decision.DecisionOptions              `json:"decision-cache" mapstructure:"decision-cache"`
 }
 
 // NewOptions creates a new Options object with default parameters.
@@ -35,6 +39,7 @@ func NewOptions() *Options {
 		FeatureOptions:          genericoptions.NewFeatureOptions(),
 		Log:                     log.NewOptions(),
 		AnalyticsOptions:        analytics.NewAnalyticsOptions(),
+		DecisionOptions:         decision.NewDecisionOptions(),
 	}
 
 	return &o
@@ -49,6 +54,7 @@ func (o *Options) ApplyTo(c *server.Config) error {
 func (o *Options) Flags() (fss cliflag.NamedFlagSets) {
 	o.GenericServerRunOptions.AddFlags(fss.FlagSet("generic"))
 	o.AnalyticsOptions.AddFlags(fss.FlagSet("analytics"))
+	o.DecisionOptions.AddFlags(fss.FlagSet("decision cache"))
 	o.RedisOptions.AddFlags(fss.FlagSet("redis"))
 	o.FeatureOptions.AddFlags(fss.FlagSet("features"))
 	o.InsecureServing.AddFlags(fss.FlagSet("insecure serving"))
@@ -64,6 +70,12 @@ func (o *Options) Flags() (fss cliflag.NamedFlagSets) {
 		"If set, any request presenting a client certificate signed by one of "+
 		"the authorities in the client-ca-file is authenticated with an identity "+
 		"corresponding to the CommonName of the client certificate.")
+	fs.StringVar(&o.JwksURL, "jwks-url", o.JwksURL, ""+
+		"If set, the tokens signed by iam-apiserver with an asymmetric key are verified offline "+
+		"with the keys published at this url, e.g. https://127.0.0.1:8443/.well-known/jwks.json.")
+	fs.BoolVar(&o.RevocationFailOpen, "revocation-fail-open", o.RevocationFailOpen, ""+
+		"Accept the tokens verified with the jwks-url keys when the revocation store is unavailable, "+
+		"instead of rejecting them.")
 
 	return fss
 }
@@ -11,9 +11,12 @@ import (
 
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/analytics"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/config"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/decision"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/load"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/load/cache"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/store/apiserver"
+	// register the custom ladon conditions used by iam policies.
+	_ "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/condition"
 	genericoptions "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/options"
 	genericapiserver "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/server"
 )
@@ -28,6 +31,7 @@ type authzServer struct {
 	redisOptions     *genericoptions.RedisOptions
 	genericAPIServer *genericapiserver.GenericAPIServer
 	analyticsOptions *analytics.AnalyticsOptions
+	decisionOptions  *decision.DecisionOptions
 	redisCancelFunc  context.CancelFunc
 }
 
@@ -54,6 +58,7 @@ func createAuthzServer(cfg *config.Config) (*authzServer, error) {
 		gs:               gs,
 		redisOptions:     cfg.RedisOptions,
 		analyticsOptions: cfg.AnalyticsOptions,
+		decisionOptions:  cfg.DecisionOptions,
 		rpcServer:        cfg.RPCServer,
 		clientCA:         cfg.ClientCA,
 		genericAPIServer: genericServer,
@@ -78,11 +83,26 @@ func (s *authzServer) initialize() error {
 	go storage.ConnectToRedis(ctx, s.buildStorageConfig())
 
 	// cron to reload all secrets and policies from iam-apiserver
-	cacheIns, err := cache.GetCacheInsOr(apiserver.GetAPIServerFactoryOrDie(s.rpcServer, s.clientCA))
+	factory := apiserver.GetAPIServerFactoryOrDie(s.rpcServer, s.clientCA)
+	cacheIns, err := cache.GetCacheInsOr(factory)
 	if err != nil {
 		return errors.Wrap(err, "get cache instance failed")
 	}
 
+	// report when the secrets were last used to iam-apiserver
+	usages = newSecretUsage(factory.Secrets())
+	usages.Start()
+
+	// flush cached decisions of users whose policies changed on reload
+	if s.decisionOptions.Enable {
+		decisionIns, err := decision.NewCache(s.decisionOptions)
+		if err != nil {
+			return errors.Wrap(err, "create decision cache failed")
+		}
+
+		cacheIns.OnPolicyChange(decisionIns.Invalidate)
+	}
+
 	load.NewLoader(ctx, cacheIns).Start()
 
 	// start analytics service
@@ -111,6 +131,9 @@ func (s preparedAuthzServer) Run() error {
 	// please ensure the following graceful shutdown sequence
 	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
 		s.genericAPIServer.Close()
+		if usages != nil {
+			usages.Stop()
+		}
 		if s.analyticsOptions.Enable {
 			analytics.GetAnalytics().Stop()
 		}
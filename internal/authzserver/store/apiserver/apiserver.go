@@ -6,12 +6,14 @@ import (
 	pb "github.com/marmotedu/api/proto/apiserver/v1"
 	"github.com/marmotedu/log"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
 
 type datastore struct {
 	cli pb.CacheClient
+	dir rpc.DirectoryClient
 }
 
 func (ds *datastore) Secrets() store.SecretStore {
@@ -22,6 +24,10 @@ func (ds *datastore) Policies() store.PolicyStore {
 	return newPolicies(ds)
 }
 
+func (ds *datastore) Memberships() store.MembershipStore {
+	return newMemberships(ds)
+}
+
 var (
 	apiServerFactory store.Factory
 	once             sync.Once
@@ -46,7 +52,10 @@ func GetAPIServerFactoryOrDie(address string, clientCA string) store.Factory {
 			log.Panicf("Connect to grpc server failed, error: %s", err.Error())
 		}
 
-		apiServerFactory = &datastore{pb.NewCacheClient(conn)}
+		apiServerFactory = &datastore{
+			cli: pb.NewCacheClient(conn),
+			dir: rpc.NewDirectoryClient(conn),
+		}
 		log.Infof("Connected to grpc server, address: %s", address)
 	})
 
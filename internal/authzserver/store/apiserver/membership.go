@@ -0,0 +1,43 @@
+package apiserver
+
+import (
+	"context"
+
+	"github.com/avast/retry-go"
+	"github.com/marmotedu/log"
+	"github.com/pkg/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
+)
+
+type memberships struct {
+	cli rpc.DirectoryClient
+}
+
+func newMemberships(ds *datastore) *memberships {
+	return &memberships{ds.dir}
+}
+
+// List returns the group and role memberships of all users.
+func (m *memberships) List() (*rpc.ListMembershipsResponse, error) {
+	log.Info("Loading memberships")
+
+	var resp *rpc.ListMembershipsResponse
+	err := retry.Do(
+		func() error {
+			var listErr error
+			resp, listErr = m.cli.ListMemberships(context.Background(), &rpc.ListMembershipsRequest{})
+			if listErr != nil {
+				return listErr
+			}
+
+			return nil
+		}, retry.Attempts(3),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "list memberships failed")
+	}
+
+	log.Infof("Memberships found (%d users, %d principals with policies)", len(resp.Principals), len(resp.Policies))
+
+	return resp, nil
+}
@@ -3,38 +3,31 @@ package apiserver
 import (
 	"context"
 
-	"github.com/AlekSi/pointer"
 	"github.com/avast/retry-go"
-
-	pb "github.com/marmotedu/api/proto/apiserver/v1"
 	"github.com/marmotedu/log"
 	"github.com/pkg/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
 )
 
 type secrets struct {
-	cli pb.CacheClient
+	cli rpc.DirectoryClient
 }
 
 func newSecrets(ds *datastore) *secrets {
-	return &secrets{ds.cli}
+	return &secrets{ds.dir}
 }
 
 // List returns all the authorization secrets.
-func (s *secrets) List() (map[string]*pb.SecretInfo, error) {
-	secrets := make(map[string]*pb.SecretInfo)
+func (s *secrets) List() (map[string]*rpc.SecretInfo, error) {
+	secrets := make(map[string]*rpc.SecretInfo)
 
 	log.Info("Loading secrets")
 
-	req := &pb.ListSecretsRequest{
-		Offset: pointer.ToInt64(0),
-		Limit:  pointer.ToInt64(-1),
-	}
-
-	var resp *pb.ListSecretsResponse
+	var resp *rpc.ListSecretKeysResponse
 	err := retry.Do(
 		func() error {
 			var listErr error
-			resp, listErr = s.cli.ListSecrets(context.Background(), req)
+			resp, listErr = s.cli.ListSecretKeys(context.Background(), &rpc.ListSecretKeysRequest{})
 			if listErr != nil {
 				return listErr
 			}
@@ -49,9 +42,16 @@ func (s *secrets) List() (map[string]*pb.SecretInfo, error) {
 	log.Infof("Secrets found (%d total):", len(resp.Items))
 
 	for _, v := range resp.Items {
-		log.Infof(" - %s:%s", v.Username, v.SecretId)
-		secrets[v.SecretId] = v
+		log.Infof(" - %s:%s", v.Username, v.SecretID)
+		secrets[v.SecretID] = v
 	}
 
 	return secrets, nil
 }
+
+// ReportUsage reports when the secrets were last used.
+func (s *secrets) ReportUsage(lastUsed map[string]int64) error {
+	_, err := s.cli.ReportSecretUsage(context.Background(), &rpc.ReportSecretUsageRequest{LastUsed: lastUsed})
+
+	return errors.Wrap(err, "report secret usage failed")
+}
@@ -0,0 +1,12 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package store
+
+import "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
+
+// MembershipStore defines the group and role membership storage interface.
+type MembershipStore interface {
+	List() (*rpc.ListMembershipsResponse, error)
+}
@@ -4,10 +4,11 @@
 
 package store
 
-import pb "github.com/marmotedu/api/proto/apiserver/v1"
+import "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/rpc"
 
 // SecretStore defines the secret storage interface.
 type SecretStore interface {
-	// List(ctx context.Context, username string, opts metav1.ListOptions) (*v1.SecretList, error)
-	List() (map[string]*pb.SecretInfo, error)
+	List() (map[string]*rpc.SecretInfo, error)
+	// ReportUsage reports the unix time every secret was last used.
+	ReportUsage(lastUsed map[string]int64) error
 }
@@ -12,6 +12,7 @@ var client Factory
 type Factory interface {
 	Policies() PolicyStore
 	Secrets() SecretStore
+	Memberships() MembershipStore
 }
 
 // Client return the store client instance.
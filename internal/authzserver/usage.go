@@ -0,0 +1,92 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authzserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marmotedu/log"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/store"
+)
+
+// usageReportInterval defines how often the usage of the secrets is reported to iam-apiserver.
+const usageReportInterval = time.Minute
+
+// secretUsage collects when the secrets were last used, and reports it to
+// iam-apiserver in batches rather than on every request.
+type secretUsage struct {
+	store store.SecretStore
+
+	lock     sync.Mutex
+	lastUsed map[string]int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// usages is set once the server is initialized, the secrets are not tracked before.
+var usages *secretUsage
+
+func newSecretUsage(store store.SecretStore) *secretUsage {
+	return &secretUsage{
+		store:    store,
+		lastUsed: make(map[string]int64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Record records that the secret is used now.
+func (u *secretUsage) Record(secretID string) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.lastUsed[secretID] = time.Now().Unix()
+}
+
+// Start reports the usage periodically until Stop is called.
+func (u *secretUsage) Start() {
+	go func() {
+		defer close(u.done)
+
+		ticker := time.NewTicker(usageReportInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				u.flush()
+			case <-u.stop:
+				u.flush()
+
+				return
+			}
+		}
+	}()
+}
+
+// Stop reports the usage collected since the last report and stops reporting.
+func (u *secretUsage) Stop() {
+	close(u.stop)
+	<-u.done
+}
+
+func (u *secretUsage) flush() {
+	u.lock.Lock()
+	lastUsed := u.lastUsed
+	u.lastUsed = make(map[string]int64)
+	u.lock.Unlock()
+
+	if len(lastUsed) == 0 {
+		return
+	}
+
+	if err := u.store.ReportUsage(lastUsed); err != nil {
+		// the usage is only informative, it is reported again on the next use.
+		log.Warnf("report usage of %d secrets failed: %s", len(lastUsed), err.Error())
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package apply manages users, secrets and policies declaratively from manifests.
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// ManifestOptions holds the flags shared by apply and diff.
+type ManifestOptions struct {
+	Filenames []string
+	Recursive bool
+	Prune     bool
+
+	username string
+	client   *restclient.RESTClient
+}
+
+// AddFlags adds the manifest flags to cmd.
+func (o *ManifestOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&o.Filenames, "filename", "f", o.Filenames,
+		"Manifest files, or directories of manifests (*.yaml, *.yml, *.json).")
+	cmd.Flags().BoolVarP(&o.Recursive, "recursive", "R", o.Recursive,
+		"Read the subdirectories of the directories given with -f.")
+	cmd.Flags().BoolVar(&o.Prune, "prune", o.Prune,
+		"Delete the resources missing from the manifests, only for the kinds found in the manifests.")
+}
+
+// Complete completes the manifest options.
+func (o *ManifestOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	if len(o.Filenames) == 0 {
+		return cmdutil.UsageErrorf(cmd, "at least one manifest is required, use -f")
+	}
+
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.username = config.Username
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// plan reads the manifests and compares them with iam-apiserver.
+func (o *ManifestOptions) plan(ctx context.Context) ([]*change, error) {
+	desired, err := readManifests(o.Filenames, o.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := listLive(ctx, o.client, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan(desired, live, planOptions{Prune: o.Prune, Username: o.username})
+}
+
+// ApplyOptions is an options struct to support apply command.
+type ApplyOptions struct {
+	ManifestOptions
+
+	genericclioptions.IOStreams
+}
+
+var (
+	applyLong = templates.LongDesc(`Apply manifests of users, secrets and policies.
+
+A manifest is the API object with its kind, several manifests can be separated by '---'
+lines in a file. The resources missing from iam-apiserver are created and the ones which
+differ from their manifest are updated, so applying the same manifests again changes
+nothing. With --prune, the resources missing from the manifests are deleted too.
+
+Only the fields set by the manifests are managed: the nickname, email and phone of the
+users, the description, expiration and scopes of the secrets, and the policy of the
+policies. The password of a user is only used to create it, the keys of the secrets
+are generated by iam-apiserver. Secrets and policies belong to the user running iamctl,
+and the administrators and the user running iamctl are never pruned.`)
+
+	applyExample = templates.Examples(`
+		# Apply the manifests of a directory
+		iamctl apply -f iam/
+
+		# Apply the manifests of a directory and its subdirectories, deleting the others
+		iamctl apply -R -f iam/ --prune
+
+		# Show the changes before applying them
+		iamctl diff -f iam/`)
+)
+
+// NewApplyOptions returns an initialized ApplyOptions instance.
+func NewApplyOptions(ioStreams genericclioptions.IOStreams) *ApplyOptions {
+	return &ApplyOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdApply returns new initialized instance of 'apply' sub command.
+func NewCmdApply(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewApplyOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "apply -f FILENAME",
+		DisableFlagsInUseLine: true,
+		Short:                 "Apply manifests of users, secrets and policies",
+		TraverseChildren:      true,
+		Long:                  applyLong,
+		Example:               applyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.AddFlags(cmd)
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ApplyOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes an apply command using the specified options.
+func (o *ApplyOptions) Run(args []string) error {
+	ctx := context.TODO()
+
+	// every manifest is checked before the first change.
+	changes, err := o.plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		switch c.Action {
+		case actionCreate:
+			err = c.Kind.create(ctx, o.client, c.desired)
+		case actionUpdate:
+			err = c.Kind.update(ctx, o.client, c.desired)
+		case actionDelete:
+			err = c.Kind.delete(ctx, o.client, c.Name)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.key(), err)
+		}
+
+		fmt.Fprintf(o.Out, "%s %s\n", c.key(), pastTense[c.Action])
+	}
+
+	return nil
+}
+
+// pastTense are the words printed when the actions are done.
+var pastTense = map[string]string{
+	actionCreate:    "created",
+	actionUpdate:    "configured",
+	actionDelete:    "pruned",
+	actionUnchanged: "unchanged",
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// DiffOptions is an options struct to support diff command.
+type DiffOptions struct {
+	ManifestOptions
+
+	genericclioptions.IOStreams
+}
+
+var (
+	diffLong = templates.LongDesc(`Diff manifests of users, secrets and policies against iam-apiserver.
+
+The output is a unified diff of the fields managed by 'iamctl apply', from the live
+resources to the manifests. Nothing is printed when applying the manifests would change
+nothing.`)
+
+	diffExample = templates.Examples(`
+		# Show the changes of the manifests of a directory
+		iamctl diff -f iam/
+
+		# Also show the resources which would be pruned
+		iamctl diff -f iam/ --prune`)
+)
+
+// NewDiffOptions returns an initialized DiffOptions instance.
+func NewDiffOptions(ioStreams genericclioptions.IOStreams) *DiffOptions {
+	return &DiffOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdDiff returns new initialized instance of 'diff' sub command.
+func NewCmdDiff(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewDiffOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "diff -f FILENAME",
+		DisableFlagsInUseLine: true,
+		Short:                 "Diff manifests against iam-apiserver",
+		TraverseChildren:      true,
+		Long:                  diffLong,
+		Example:               diffExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.AddFlags(cmd)
+
+	return cmd
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *DiffOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes a diff command using the specified options.
+func (o *DiffOptions) Run(args []string) error {
+	changes, err := o.plan(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if c.Action == actionUnchanged {
+			continue
+		}
+
+		diff, err := c.diff()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(o.Out, diff)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apply
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// manifestExtensions are the extensions of the files read from a directory.
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// readManifests reads the resources of the files, the directories are read
+// file by file and, when recursive is set, with their subdirectories.
+func readManifests(paths []string, recursive bool) ([]resource, error) {
+	var files []string
+
+	for _, path := range paths {
+		found, err := manifestFiles(path, recursive)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, found...)
+	}
+
+	var resources []resource
+
+	seen := map[string]string{}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := decodeManifests(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		for _, r := range decoded {
+			key := resourceKey(r)
+			if previous, ok := seen[key]; ok {
+				return nil, fmt.Errorf("%s: %s is already defined in %s", file, key, previous)
+			}
+
+			seen[key] = file
+			resources = append(resources, r)
+		}
+	}
+
+	return resources, nil
+}
+
+// manifestFiles returns path if it is a file, or the manifests it holds if it
+// is a directory.
+func manifestFiles(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if file != path && !recursive {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if manifestExtensions[strings.ToLower(filepath.Ext(file))] {
+			files = append(files, file)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// decodeManifests decodes the documents of a YAML or JSON file, the documents
+// are separated by '---' lines.
+func decodeManifests(data []byte) ([]resource, error) {
+	var resources []resource
+
+	for i, doc := range splitDocuments(data) {
+		jsonData, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		// empty documents are commonly left around the separators.
+		if bytes.Equal(bytes.TrimSpace(jsonData), []byte("null")) {
+			continue
+		}
+
+		r, err := decodeResource(jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+func splitDocuments(data []byte) [][]byte {
+	var (
+		docs    [][]byte
+		current bytes.Buffer
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimRight(line, " \t") == "---" {
+			docs = append(docs, append([]byte(nil), current.Bytes()...))
+			current.Reset()
+
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+
+	return append(docs, current.Bytes())
+}
+
+// decodeResource decodes a manifest, which is the API object with a kind.
+func decodeResource(data []byte) (resource, error) {
+	var meta struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+
+	k, ok := kindOf(meta.Kind)
+	if !ok {
+		return nil, fmt.Errorf("unsupported kind %q, expected one of: %s", meta.Kind, strings.Join(kindNames(), ", "))
+	}
+
+	r, err := k.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", k.Name, err)
+	}
+
+	if r.Name() == "" {
+		return nil, fmt.Errorf("%s: metadata.name is required", k.Name)
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", resourceKey(r), err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// The actions which bring a live resource to its manifest.
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionDelete    = "delete"
+	actionUnchanged = "unchanged"
+)
+
+// change is the action planned for a resource, live is nil when the resource
+// is created and desired is nil when it is pruned.
+type change struct {
+	Action  string
+	Kind    kind
+	Name    string
+	live    resource
+	desired resource
+}
+
+// planOptions tells which live resources may be pruned.
+type planOptions struct {
+	Prune bool
+	// Username is the user running iamctl, it is never pruned.
+	Username string
+}
+
+// listLive lists the live resources of the kinds found in the manifests.
+func listLive(ctx context.Context, client *restclient.RESTClient, desired []resource) (map[string][]resource, error) {
+	live := map[string][]resource{}
+
+	for _, k := range kinds {
+		if !hasKind(desired, k.Name) {
+			continue
+		}
+
+		resources, err := k.list(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		live[k.Name] = resources
+	}
+
+	return live, nil
+}
+
+func hasKind(resources []resource, kind string) bool {
+	for _, r := range resources {
+		if r.Kind() == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// plan compares the manifests with the live resources. Only the kinds found in
+// the manifests are pruned, so that a directory of policies never deletes users.
+func plan(desired []resource, live map[string][]resource, opts planOptions) ([]*change, error) {
+	var changes, prunes []*change
+
+	for _, k := range kinds {
+		current := map[string]resource{}
+		for _, r := range live[k.Name] {
+			current[r.Name()] = r
+		}
+
+		wanted := map[string]bool{}
+
+		for _, r := range desired {
+			if r.Kind() != k.Name {
+				continue
+			}
+
+			wanted[r.Name()] = true
+			c := &change{Kind: k, Name: r.Name(), live: current[r.Name()], desired: r}
+
+			switch {
+			case c.live == nil:
+				c.Action = actionCreate
+
+				if user, ok := r.(userResource); ok {
+					if err := user.validateCreate(); err != nil {
+						return nil, fmt.Errorf("%s can not be created: %w", resourceKey(r), err)
+					}
+				}
+			default:
+				same, err := sameManaged(c.live, r)
+				if err != nil {
+					return nil, err
+				}
+
+				c.Action = actionUpdate
+				if same {
+					c.Action = actionUnchanged
+				}
+			}
+
+			changes = append(changes, c)
+		}
+
+		if !opts.Prune || !hasKind(desired, k.Name) {
+			continue
+		}
+
+		var names []string
+		for name, r := range current {
+			if !wanted[name] && !protected(r, opts) {
+				names = append(names, name)
+			}
+		}
+
+		sort.Strings(names)
+
+		// the kinds applied last are pruned first.
+		kindPrunes := make([]*change, 0, len(names))
+		for _, name := range names {
+			kindPrunes = append(kindPrunes, &change{Action: actionDelete, Kind: k, Name: name, live: current[name]})
+		}
+
+		prunes = append(kindPrunes, prunes...)
+	}
+
+	return append(changes, prunes...), nil
+}
+
+// sameManaged compares the managed fields as rendered by diff, so that an
+// empty list and a missing one are the same.
+func sameManaged(live, desired resource) (bool, error) {
+	a, err := managedYAML(live)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := managedYAML(desired)
+
+	return a == b, err
+}
+
+// protected tells whether a live resource must survive the prune.
+func protected(r resource, opts planOptions) bool {
+	user, ok := r.(userResource)
+
+	return ok && (user.isAdmin() || user.Name() == opts.Username)
+}
+
+// key identifies the resource of the change, e.g. policy/foo.
+func (c *change) key() string {
+	if c.desired != nil {
+		return resourceKey(c.desired)
+	}
+
+	return resourceKey(c.live)
+}
+
+// diff returns the unified diff of the managed fields of the resource.
+func (c *change) diff() (string, error) {
+	live, err := managedYAML(c.live)
+	if err != nil {
+		return "", err
+	}
+
+	desired, err := managedYAML(c.desired)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(live),
+		B:        difflib.SplitLines(desired),
+		FromFile: "live/" + c.key(),
+		ToFile:   "manifest/" + c.key(),
+		Context:  3,
+	})
+}
+
+func managedYAML(r resource) (string, error) {
+	if r == nil {
+		return "", nil
+	}
+
+	data, err := yaml.Marshal(r.Managed())
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
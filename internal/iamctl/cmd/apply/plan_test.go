@@ -0,0 +1,180 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apply
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const manifests = `
+kind: User
+metadata:
+  name: colin
+nickname: colin
+email: colin@example.com
+password: Colin@2023
+---
+kind: Secret
+metadata:
+  name: ci
+description: used by the ci
+expires: 0
+---
+kind: Policy
+metadata:
+  name: articles
+policy:
+  description: read the articles
+  subjects: ["users:colin"]
+  actions: ["get"]
+  effect: allow
+  resources: ["resources:articles:<.*>"]
+---
+`
+
+func decodeLive(t *testing.T, data string) []resource {
+	resources, err := decodeManifests([]byte(data))
+	assert.Nil(t, err)
+
+	return resources
+}
+
+func TestReadManifests(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "nested"), 0o700))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "iam.yaml"), []byte(manifests), 0o600))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# iam"), 0o600))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "nested", "user.yml"),
+		[]byte("kind: user\nmetadata:\n  name: peter\nnickname: peter\nemail: peter@example.com\n"), 0o600))
+
+	resources, err := readManifests([]string{dir}, false)
+	assert.Nil(t, err)
+	assert.Len(t, resources, 3)
+
+	resources, err = readManifests([]string{dir}, true)
+	assert.Nil(t, err)
+	assert.Len(t, resources, 4)
+
+	_, err = readManifests([]string{filepath.Join(dir, "iam.yaml"), filepath.Join(dir, "iam.yaml")}, false)
+	assert.Contains(t, err.Error(), "user/colin is already defined")
+
+	for _, invalid := range []string{
+		"kind: Group\nmetadata:\n  name: admins\n",
+		"kind: Policy\npolicy:\n  effect: allow\n",
+		"kind: User\nmetadata:\n  name: tom\nemail: tom@example.com\n",
+	} {
+		_, err := decodeManifests([]byte(invalid))
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	desired := decodeLive(t, manifests)
+	live := map[string][]resource{
+		KindUser: decodeLive(t, `
+kind: User
+metadata:
+  name: colin
+nickname: colin
+email: colin@example.com
+---
+kind: User
+metadata:
+  name: admin
+nickname: admin
+email: admin@example.com
+isAdmin: 1
+---
+kind: User
+metadata:
+  name: peter
+nickname: peter
+email: peter@example.com
+`),
+		KindPolicy: decodeLive(t, `
+kind: Policy
+metadata:
+  name: articles
+policy:
+  id: articles
+  description: read the articles
+  subjects: ["users:colin"]
+  actions: ["get", "delete"]
+  effect: allow
+  resources: ["resources:articles:<.*>"]
+`),
+	}
+
+	changes, err := plan(desired, live, planOptions{})
+	assert.Nil(t, err)
+
+	actions := map[string]string{}
+	for _, c := range changes {
+		actions[c.key()] = c.Action
+	}
+
+	assert.Equal(t, map[string]string{
+		"user/colin":      actionUnchanged,
+		"secret/ci":       actionCreate,
+		"policy/articles": actionUpdate,
+	}, actions)
+
+	diff, err := changes[2].diff()
+	assert.Nil(t, err)
+	assert.Contains(t, diff, "--- live/policy/articles")
+	assert.Contains(t, diff, "-- delete")
+
+	changes, err = plan(desired, live, planOptions{Prune: true, Username: "colin"})
+	assert.Nil(t, err)
+	assert.Len(t, changes, 4)
+	assert.Equal(t, actionDelete, changes[3].Action)
+	assert.Equal(t, "user/peter", changes[3].key())
+
+	// the users created by apply need a password.
+	desired = decodeLive(t, strings.Replace(manifests, "password: Colin@2023\n", "", 1))
+	_, err = plan(desired, map[string][]resource{}, planOptions{})
+	assert.NotNil(t, err)
+}
+
+func TestPlanDroppedScopes(t *testing.T) {
+	desired := decodeLive(t, manifests)
+	live := map[string][]resource{
+		KindSecret: decodeLive(t, `
+kind: Secret
+metadata:
+  name: ci
+description: used by the ci
+expires: 0
+scopes:
+- resources: ["resources:articles"]
+  actions: ["get"]
+`),
+	}
+
+	changes, err := plan(desired, live, planOptions{})
+	assert.Nil(t, err)
+
+	var update *change
+	for _, c := range changes {
+		if c.key() == "secret/ci" {
+			update = c
+		}
+	}
+	assert.NotNil(t, update)
+	assert.Equal(t, actionUpdate, update.Action)
+
+	// the scopes are omitted from the secret when empty, they must be sent
+	// anyway to be removed.
+	body, err := json.Marshal(updateBody(update.desired))
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `"scopes":[]`)
+	assert.Contains(t, string(body), `"description":"used by the ci"`)
+}
@@ -0,0 +1,274 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+)
+
+// The kinds of the resources managed by apply.
+const (
+	KindUser   = "User"
+	KindSecret = "Secret"
+	KindPolicy = "Policy"
+)
+
+// listPageSize is the number of resources listed at once.
+const listPageSize = 500
+
+// resource is an object of a manifest, or of iam-apiserver.
+type resource interface {
+	Kind() string
+	Name() string
+	// Managed returns the fields owned by the manifests, the only ones compared
+	// by diff and sent by apply.
+	Managed() interface{}
+	// Validate checks a resource read from a manifest.
+	Validate() error
+}
+
+// kind tells how to manage the resources of a kind on iam-apiserver.
+type kind struct {
+	Name string
+	// Path is the path of the resources on iam-apiserver.
+	Path   string
+	decode func(data []byte) (resource, error)
+}
+
+// kinds are ordered as they are applied, they are pruned the other way
+// round: secrets and policies belong to users.
+var kinds = []kind{
+	{Name: KindUser, Path: "/v1/users", decode: decodeUser},
+	{Name: KindSecret, Path: "/v1/secrets", decode: decodeSecret},
+	{Name: KindPolicy, Path: "/v1/policies", decode: decodePolicy},
+}
+
+func kindOf(name string) (kind, bool) {
+	for _, k := range kinds {
+		if strings.EqualFold(k.Name, name) {
+			return k, true
+		}
+	}
+
+	return kind{}, false
+}
+
+func kindNames() []string {
+	names := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		names = append(names, k.Name)
+	}
+
+	return names
+}
+
+// resourceKey identifies a resource in the messages, e.g. policy/foo.
+func resourceKey(r resource) string {
+	return strings.ToLower(r.Kind()) + "/" + r.Name()
+}
+
+// list returns all the resources of the kind visible to the user of iamctl.
+func (k kind) list(ctx context.Context, client *restclient.RESTClient) ([]resource, error) {
+	var resources []resource
+
+	for offset := 0; ; offset += listPageSize {
+		var page struct {
+			TotalCount int64             `json:"totalCount"`
+			Items      []json.RawMessage `json:"items"`
+		}
+
+		if err := client.Get().AbsPath(k.Path).
+			Param("offset", fmt.Sprint(offset)).
+			Param("limit", fmt.Sprint(listPageSize)).
+			Do(ctx).
+			Into(&page); err != nil {
+			return nil, fmt.Errorf("list %s: %w", strings.ToLower(k.Name), err)
+		}
+
+		for _, item := range page.Items {
+			r, err := k.decode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			resources = append(resources, r)
+		}
+
+		if len(page.Items) < listPageSize {
+			return resources, nil
+		}
+	}
+}
+
+func (k kind) create(ctx context.Context, client *restclient.RESTClient, r resource) error {
+	return client.Post().AbsPath(k.Path).Body(r).Do(ctx).Error()
+}
+
+func (k kind) update(ctx context.Context, client *restclient.RESTClient, r resource) error {
+	return client.Put().AbsPath(k.Path, r.Name()).Body(updateBody(r)).Do(ctx).Error()
+}
+
+// updateBody returns the body of the request updating r.
+func updateBody(r resource) interface{} {
+	switch r := r.(type) {
+	case userResource:
+		// the password of the manifest is only the initial one.
+		copied := *r.User
+		copied.Password = ""
+
+		return userResource{&copied}
+	case secretResource:
+		// iam-apiserver keeps the scopes when they are omitted, an empty list
+		// is sent so that the scopes dropped from the manifest are removed.
+		scopes := r.Scopes
+		if scopes == nil {
+			scopes = []scope.Scope{}
+		}
+
+		return secretUpdate{r.Secret, scopes}
+	}
+
+	return r
+}
+
+func (k kind) delete(ctx context.Context, client *restclient.RESTClient, name string) error {
+	return client.Delete().AbsPath(k.Path, name).Do(ctx).Error()
+}
+
+// userResource is a user, its password is only sent when the user is created.
+type userResource struct {
+	*v1.User `json:",inline"`
+}
+
+type userFields struct {
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone,omitempty"`
+}
+
+func decodeUser(data []byte) (resource, error) {
+	user := &v1.User{}
+	if err := json.Unmarshal(data, user); err != nil {
+		return nil, err
+	}
+
+	return userResource{user}, nil
+}
+
+func (u userResource) Kind() string { return KindUser }
+func (u userResource) Name() string { return u.User.Name }
+
+func (u userResource) Managed() interface{} {
+	return userFields{Nickname: u.Nickname, Email: u.Email, Phone: u.Phone}
+}
+
+// Validate checks the fields which can be updated, the password is only
+// checked when the user is created.
+func (u userResource) Validate() error {
+	if u.Nickname == "" || u.Email == "" {
+		return fmt.Errorf("nickname and email are required")
+	}
+
+	return nil
+}
+
+// validateCreate checks the user can be created, the password is required.
+func (u userResource) validateCreate() error {
+	if errs := u.User.Validate(); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	return nil
+}
+
+// isAdmin tells whether the user is an administrator, they are never pruned.
+func (u userResource) isAdmin() bool {
+	return u.IsAdmin == 1
+}
+
+// secretResource is a secret, its key is generated by iam-apiserver.
+type secretResource struct {
+	*model.Secret `json:",inline"`
+}
+
+// secretUpdate always sends the scopes of a secret.
+type secretUpdate struct {
+	*model.Secret `json:",inline"`
+
+	Scopes []scope.Scope `json:"scopes"`
+}
+
+type secretFields struct {
+	Description string        `json:"description,omitempty"`
+	Expires     int64         `json:"expires"`
+	Scopes      []scope.Scope `json:"scopes,omitempty"`
+}
+
+func decodeSecret(data []byte) (resource, error) {
+	secret := &model.Secret{}
+	if err := json.Unmarshal(data, secret); err != nil {
+		return nil, err
+	}
+
+	return secretResource{secret}, nil
+}
+
+func (s secretResource) Kind() string { return KindSecret }
+func (s secretResource) Name() string { return s.Secret.Name }
+
+func (s secretResource) Managed() interface{} {
+	return secretFields{Description: s.Description, Expires: s.Expires, Scopes: s.Scopes}
+}
+
+func (s secretResource) Validate() error {
+	if errs := s.Secret.Secret.Validate(); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	return scope.Validate(s.Scopes)
+}
+
+// policyResource is an authorization policy.
+type policyResource struct {
+	*v1.Policy `json:",inline"`
+}
+
+func decodePolicy(data []byte) (resource, error) {
+	policy := &v1.Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+
+	return policyResource{policy}, nil
+}
+
+func (p policyResource) Kind() string { return KindPolicy }
+func (p policyResource) Name() string { return p.Policy.Name }
+
+// Managed returns the ladon policy, its ID is always the name of the policy.
+func (p policyResource) Managed() interface{} {
+	policy := p.Policy.Policy
+	policy.ID = ""
+
+	return policy
+}
+
+func (p policyResource) Validate() error {
+	if errs := p.Policy.Validate(); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	return policylint.Validate(&p.Policy.Policy.DefaultPolicy)
+}
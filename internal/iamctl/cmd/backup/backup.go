@@ -0,0 +1,294 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package backup implements the iamctl backup and restore commands.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	// passphraseEnv is the environment variable read when no passphrase file is given.
+	passphraseEnv = "IAMCTL_BACKUP_PASSPHRASE"
+	// passphraseHeader is the header which sends the passphrase to iam-apiserver.
+	passphraseHeader = "X-Backup-Passphrase"
+	// tenantHeader selects the tenant of the request, for platform administrators.
+	tenantHeader = "X-Tenant"
+)
+
+// Options is an options struct to support backup subcommands.
+type Options struct {
+	File           string
+	Tenant         string
+	PassphraseFile string
+
+	passphrase string
+	config     *restclient.Config
+	client     *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	backupLong = templates.LongDesc(`
+		Back up the users, secrets, policies and items of a tenant.
+
+		The archive is read by iam-apiserver from one consistent snapshot of the database and
+		streamed as JSON lines, it is compressed with gzip when the file name ends with .gz. It
+		holds the password hashes of the users and the keys of the secrets, which are encrypted
+		with AES-256-GCM when a passphrase is given with --passphrase-file or the
+		IAMCTL_BACKUP_PASSPHRASE environment variable.
+
+		Only platform administrators can back up and restore, the tenant of the request is
+		backed up unless --tenant is given. The file is only written once the whole archive is
+		received.`)
+
+	backupExample = templates.Examples(`
+		# Back up the default tenant with encrypted secret keys
+		iamctl backup -f iam.jsonl.gz --passphrase-file passphrase.txt
+
+		# Back up a tenant to the standard output
+		IAMCTL_BACKUP_PASSPHRASE=secret iamctl backup --tenant shop`)
+)
+
+// NewOptions returns an initialized Options instance.
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdBackup returns new initialized instance of backup sub command.
+func NewCmdBackup(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "backup",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Back up the users, secrets, policies and items of a tenant",
+		TraverseChildren:      true,
+		Long:                  backupLong,
+		Example:               backupExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVarP(&o.File, "filename", "f", o.File,
+		"File to write the archive to, the standard output by default. Compressed when it ends with .gz.")
+	addFlags(cmd, &o.Tenant, &o.PassphraseFile)
+
+	return cmd
+}
+
+// addFlags adds the flags shared by backup and restore.
+func addFlags(cmd *cobra.Command, tenant, passphraseFile *string) {
+	cmd.Flags().StringVar(tenant, "tenant", *tenant, "The tenant to back up or restore, the tenant of the user by default.")
+	cmd.Flags().StringVar(passphraseFile, "passphrase-file", *passphraseFile,
+		"File holding the passphrase of the secret keys, "+passphraseEnv+" is used by default.")
+}
+
+// Complete completes all the required options.
+func (o *Options) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
+	if o.passphrase, err = readPassphrase(o.PassphraseFile); err != nil {
+		return err
+	}
+
+	if o.config, err = f.ToRESTConfig(); err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *Options) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	return nil
+}
+
+// Run executes a backup subcommand using the specified options.
+func (o *Options) Run(args []string) error {
+	if o.passphrase == "" {
+		fmt.Fprintln(o.ErrOut, "warning: no passphrase given, the secret keys are not encrypted")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, o.client.Get().AbsPath("/v1/backup").URL().String(), nil)
+	if err != nil {
+		return err
+	}
+
+	setHeaders(req, o.Tenant, o.passphrase)
+
+	// the archive is streamed, a timeout would cut large backups.
+	config := *o.config
+	config.Timeout = 0
+
+	resp, err := cmdutil.DoRaw(&config, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("backup: %w", cmdutil.ResponseError(resp))
+	}
+
+	if o.File == "" {
+		return copyArchive(o.Out, resp.Body)
+	}
+
+	return o.writeFile(resp.Body)
+}
+
+// writeFile writes the archive to a temporary file which replaces the file
+// once the whole archive is written.
+func (o *Options) writeFile(archive io.Reader) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(o.File), ".iamctl-backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	var w io.Writer = tmp
+	var zw *gzip.Writer
+
+	if strings.HasSuffix(o.File, ".gz") {
+		zw = gzip.NewWriter(tmp)
+		w = zw
+	}
+
+	err = copyArchive(w, archive)
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), o.File); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.ErrOut, "backup written to %s\n", o.File)
+
+	return nil
+}
+
+// copyArchive copies the archive and makes sure it ends with its trailer, the
+// archive is cut when iam-apiserver fails after the first records.
+func copyArchive(w io.Writer, archive io.Reader) error {
+	last := &lastLine{}
+	if _, err := io.Copy(io.MultiWriter(w, last), archive); err != nil {
+		return err
+	}
+
+	var record struct {
+		Kind string `json:"kind"`
+	}
+	if json.Unmarshal(last.line(), &record) != nil || record.Kind != v1.BackupKindTrailer {
+		return fmt.Errorf("the archive is incomplete, iam-apiserver failed during the backup")
+	}
+
+	return nil
+}
+
+// lastLine keeps the last line written to it, as long as it is short.
+type lastLine struct {
+	current  bytes.Buffer
+	previous []byte
+}
+
+// maxTrailerSize is the size of the longest trailer kept by lastLine.
+const maxTrailerSize = 4096
+
+func (l *lastLine) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b == '\n' {
+			if l.current.Len() > 0 {
+				l.previous = append(l.previous[:0], l.current.Bytes()...)
+			}
+			l.current.Reset()
+
+			continue
+		}
+
+		if l.current.Len() <= maxTrailerSize {
+			l.current.WriteByte(b)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (l *lastLine) line() []byte {
+	if l.current.Len() > 0 {
+		return l.current.Bytes()
+	}
+
+	return l.previous
+}
+
+// readPassphrase reads the passphrase from the file, or else from the environment.
+func readPassphrase(file string) (string, error) {
+	if file == "" {
+		return os.Getenv(passphraseEnv), nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	passphrase := strings.TrimRight(string(data), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("the passphrase file %s is empty", file)
+	}
+
+	return passphrase, nil
+}
+
+// setHeaders sets the tenant and the passphrase of a backup or restore request.
+func setHeaders(req *http.Request, tenant, passphrase string) {
+	if tenant != "" {
+		req.Header.Set(tenantHeader, tenant)
+	}
+
+	if passphrase != "" {
+		req.Header.Set(passphraseHeader, passphrase)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	v1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const restoreUsageStr = "restore FILE"
+
+// RestoreOptions is an options struct to support restore subcommands.
+type RestoreOptions struct {
+	File           string
+	Conflict       string
+	Tenant         string
+	PassphraseFile string
+
+	passphrase string
+	config     *restclient.Config
+	client     *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	restoreLong = templates.LongDesc(`
+		Restore an archive written by iamctl backup.
+
+		The archive is restored in one transaction: either every resource is restored, or
+		none is. The --conflict flag tells what to do with the resources which already exist:
+		fail stops the restore, skip keeps the existing resources and overwrite replaces them.
+		The resources owned by another tenant are never overwritten.
+
+		The passphrase of the backup is required when the secret keys are encrypted. Compressed
+		archives are detected, and FILE may be - to read the archive from the standard input.`)
+
+	restoreExample = templates.Examples(`
+		# Restore an archive in the default tenant
+		iamctl restore iam.jsonl.gz --passphrase-file passphrase.txt
+
+		# Restore an archive in another tenant, keeping the existing resources
+		iamctl restore iam.jsonl --tenant shop --conflict skip`)
+
+	restoreUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nFILE is required for the restore command",
+		restoreUsageStr,
+	)
+)
+
+// NewRestoreOptions returns an initialized RestoreOptions instance.
+func NewRestoreOptions(ioStreams genericclioptions.IOStreams) *RestoreOptions {
+	return &RestoreOptions{
+		Conflict:  v1.ConflictFail,
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdRestore returns new initialized instance of restore sub command.
+func NewCmdRestore(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewRestoreOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   restoreUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Restore an archive written by iamctl backup",
+		TraverseChildren:      true,
+		Long:                  restoreLong,
+		Example:               restoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Conflict, "conflict", o.Conflict,
+		"What to do with the existing resources, one of: fail|skip|overwrite.")
+	addFlags(cmd, &o.Tenant, &o.PassphraseFile)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *RestoreOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, restoreUsageErrStr)
+	}
+
+	o.File = args[0]
+
+	if o.passphrase, err = readPassphrase(o.PassphraseFile); err != nil {
+		return err
+	}
+
+	if o.config, err = f.ToRESTConfig(); err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *RestoreOptions) Validate(cmd *cobra.Command, args []string) error {
+	switch o.Conflict {
+	case v1.ConflictFail, v1.ConflictSkip, v1.ConflictOverwrite:
+		return nil
+	default:
+		return cmdutil.UsageErrorf(cmd, "unsupported conflict %q, expected one of: fail|skip|overwrite", o.Conflict)
+	}
+}
+
+// Run executes a restore subcommand using the specified options.
+func (o *RestoreOptions) Run(args []string) error {
+	var src io.Reader = o.In
+
+	if o.File != "-" {
+		f, err := os.Open(o.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		src = f
+	}
+
+	archive, err := decompress(src)
+	if err != nil {
+		return err
+	}
+
+	url := o.client.Post().AbsPath("/v1/restore").Param("conflict", o.Conflict).URL().String()
+
+	req, err := http.NewRequest(http.MethodPost, url, archive)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	setHeaders(req, o.Tenant, o.passphrase)
+
+	// the archive is streamed, a timeout would cut large restores.
+	config := *o.config
+	config.Timeout = 0
+
+	resp, err := cmdutil.DoRaw(&config, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("restore: %w", cmdutil.ResponseError(resp))
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	result := &v1.RestoreResult{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return err
+	}
+
+	o.printResult(result)
+
+	return nil
+}
+
+// printResult prints the number of restored resources of each kind.
+func (o *RestoreOptions) printResult(result *v1.RestoreResult) {
+	kinds := map[string]bool{}
+	for _, counts := range []map[string]int{result.Created, result.Overwritten, result.Skipped} {
+		for kind := range counts {
+			kinds[kind] = true
+		}
+	}
+
+	names := make([]string, 0, len(kinds))
+	for kind := range kinds {
+		names = append(names, kind)
+	}
+
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		fmt.Fprintln(o.Out, "the archive is empty, nothing was restored")
+
+		return
+	}
+
+	for _, kind := range names {
+		fmt.Fprintf(o.Out, "%s: %d created, %d overwritten, %d skipped\n",
+			kind, result.Created[kind], result.Overwritten[kind], result.Skipped[kind])
+	}
+}
+
+// decompress returns the archive of r, which is decompressed when it starts
+// with the gzip magic number.
+func decompress(r io.Reader) (io.Reader, error) {
+	reader := bufio.NewReader(r)
+
+	magic, err := reader.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(reader)
+	}
+
+	return reader, nil
+}
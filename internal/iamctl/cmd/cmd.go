@@ -7,19 +7,27 @@ package cmd
 
 import (
 	"flag"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	cliflag "github.com/marmotedu/component-base/pkg/cli/flag"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/apply"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/backup"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/color"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/completion"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/config"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/info"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/item"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/jwt"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/login"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/new"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/options"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/plugin"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/policy"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/secret"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/set"
@@ -34,11 +42,57 @@ import (
 
 // NewDefaultIAMCtlCommand creates the `iamctl` command with default arguments.
 func NewDefaultIAMCtlCommand() *cobra.Command {
-	return NewIAMCtlCommand(os.Stdin, os.Stdout, os.Stderr)
+	return NewDefaultIAMCtlCommandWithArgs(
+		plugin.NewDefaultHandler(plugin.ValidPrefixes),
+		os.Args,
+		os.Stdin,
+		os.Stdout,
+		os.Stderr,
+	)
+}
+
+// NewDefaultIAMCtlCommandWithArgs creates the `iamctl` command with arguments.
+// The commands which are not built in are run by the plugins of pluginHandler.
+func NewDefaultIAMCtlCommandWithArgs(
+	pluginHandler plugin.Handler,
+	args []string,
+	in io.Reader,
+	out, errOut io.Writer,
+) *cobra.Command {
+	cmd, f := newIAMCtlCommand(in, out, errOut)
+
+	// only the first argument is checked, the flags of iamctl are not parsed
+	// before the plugins.
+	if pluginHandler == nil || len(args) < 2 || strings.HasPrefix(args[1], "-") {
+		return cmd
+	}
+
+	if _, _, err := cmd.Find(args[1:]); err == nil {
+		return cmd
+	}
+
+	environ := func() []string {
+		genericapiserver.LoadConfig(viper.GetString(genericclioptions.FlagIAMConfig), "iamctl")
+
+		return plugin.Environ(f, errOut)
+	}
+	if err := plugin.HandleCommand(pluginHandler, args[1:], environ); err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	return cmd
 }
 
 // NewIAMCtlCommand returns new initialized instance of 'iamctl' root command.
 func NewIAMCtlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
+	cmd, _ := newIAMCtlCommand(in, out, err)
+
+	return cmd
+}
+
+// newIAMCtlCommand returns the 'iamctl' root command and the factory of its subcommands.
+func newIAMCtlCommand(in io.Reader, out, err io.Writer) (*cobra.Command, cmdutil.Factory) {
 	// Parent command to which all subcommands are added.
 	cmds := &cobra.Command{
 		Use:   "iamctl",
@@ -94,6 +148,7 @@ func NewIAMCtlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				color.NewCmdColor(f, ioStreams),
 				new.NewCmdNew(f, ioStreams),
 				jwt.NewCmdJWT(f, ioStreams),
+				login.NewCmdLogin(f, ioStreams),
 			},
 		},
 		{
@@ -104,6 +159,21 @@ func NewIAMCtlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				policy.NewCmdPolicy(f, ioStreams),
 			},
 		},
+		{
+			Message: "Catalog Commands:",
+			Commands: []*cobra.Command{
+				item.NewCmdItem(f, ioStreams),
+			},
+		},
+		{
+			Message: "Advanced Commands:",
+			Commands: []*cobra.Command{
+				apply.NewCmdApply(f, ioStreams),
+				apply.NewCmdDiff(f, ioStreams),
+				backup.NewCmdBackup(f, ioStreams),
+				backup.NewCmdRestore(f, ioStreams),
+			},
+		},
 		{
 			Message: "Troubleshooting and Debugging Commands:",
 			Commands: []*cobra.Command{
@@ -113,7 +183,9 @@ func NewIAMCtlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 		{
 			Message: "Settings Commands:",
 			Commands: []*cobra.Command{
+				config.NewCmdConfig(f, ioStreams),
 				set.NewCmdSet(f, ioStreams),
+				plugin.NewCmdPlugin(f, ioStreams),
 				completion.NewCmdCompletion(ioStreams.Out, ""),
 			},
 		},
@@ -126,7 +198,7 @@ func NewIAMCtlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 	cmds.AddCommand(version.NewCmdVersion(f, ioStreams))
 	cmds.AddCommand(options.NewCmdOptions(ioStreams.Out))
 
-	return cmds
+	return cmds, f
 }
 
 func runHelp(cmd *cobra.Command, args []string) {
@@ -0,0 +1,102 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package config provides functions to manage the contexts of the iamconfig file.
+package config
+
+import (
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+var configLong = templates.LongDesc(`
+	Modify iamconfig files using subcommands like "iamctl config set-context my-context".
+
+	A context is a named iam-apiserver with its credentials, such as dev, staging or prod.
+	The iamconfig file used is, in order of precedence:
+
+	1. --iamconfig flag
+	2. iamctl.yaml in the current directory, $HOME/.iam or /etc/iam
+
+	The context used is the one given by --context, or else the current-context of the iamconfig file.`)
+
+// NewCmdConfig returns new initialized instance of 'config' sub command.
+func NewCmdConfig(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "config SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Modify iamconfig files",
+		Long:                  configLong,
+		Run:                   cmdutil.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdGetContexts(f, ioStreams))
+	cmd.AddCommand(NewCmdUseContext(f, ioStreams))
+	cmd.AddCommand(NewCmdSetContext(f, ioStreams))
+
+	return cmd
+}
+
+// contextView is a context as listed by get-contexts, without its credentials.
+type contextView struct {
+	Current bool   `json:"current"`
+	Name    string `json:"name"`
+	Server  string `json:"server"`
+	Auth    string `json:"auth"`
+	Output  string `json:"output,omitempty"`
+}
+
+// contextColumns are the columns of the context tables.
+var contextColumns = []printers.Column{
+	{Header: "Current", Value: func(obj interface{}) string {
+		if obj.(*contextView).Current {
+			return "*"
+		}
+
+		return ""
+	}},
+	{Header: "Name", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*contextView).Name
+	}},
+	{Header: "Server", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*contextView).Server
+	}},
+	{Header: "Auth", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		return obj.(*contextView).Auth
+	}},
+	{Header: "Output", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*contextView).Output
+	}},
+}
+
+// newPrintFlags returns the print flags of the config commands.
+func newPrintFlags() *printers.PrintFlags {
+	flags := printers.NewPrintFlags("context", contextColumns...)
+	flags.Name = func(obj interface{}) string {
+		return obj.(*contextView).Name
+	}
+
+	return flags
+}
+
+// authOf describes how a context authenticates, without revealing the credentials.
+func authOf(user genericclioptions.ContextUser) string {
+	switch {
+	case user.Token != "":
+		return "token"
+	case user.SecretID != "":
+		return "secret/" + user.SecretID
+	case user.Username != "":
+		return "basic/" + user.Username
+	case user.ClientCertificate != "":
+		return "certificate"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// GetContextsOptions is an options struct to support get-contexts subcommands.
+type GetContextsOptions struct {
+	PrintFlags *printers.PrintFlags
+
+	config *genericclioptions.IAMConfigFile
+	genericclioptions.IOStreams
+}
+
+var getContextsExample = templates.Examples(`
+		# List all the contexts in your iamconfig file
+		iamctl config get-contexts
+
+		# List the names of the contexts
+		iamctl config get-contexts -o name`)
+
+// NewGetContextsOptions returns an initialized GetContextsOptions instance.
+func NewGetContextsOptions(ioStreams genericclioptions.IOStreams) *GetContextsOptions {
+	return &GetContextsOptions{
+		PrintFlags: newPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdGetContexts returns new initialized instance of get-contexts sub command.
+func NewCmdGetContexts(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewGetContextsOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "get-contexts",
+		DisableFlagsInUseLine: true,
+		Short:                 "Describe the contexts in the iamconfig file",
+		TraverseChildren:      true,
+		Long:                  "Describe the contexts in the iamconfig file, the credentials are not displayed.",
+		Example:               getContextsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *GetContextsOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.config, err = genericclioptions.LoadIAMConfigFile(genericclioptions.IAMConfigFilePath())
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *GetContextsOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
+	return nil
+}
+
+// Run executes a get-contexts subcommand using the specified options.
+func (o *GetContextsOptions) Run(args []string) error {
+	current := genericclioptions.CurrentContextName()
+	contexts := make([]*contextView, 0, len(o.config.Contexts))
+
+	for _, c := range o.config.Contexts {
+		contexts = append(contexts, &contextView{
+			Current: c.Name == current,
+			Name:    c.Name,
+			Server:  c.Server.Address,
+			Auth:    authOf(c.User),
+			Output:  c.Output,
+		})
+	}
+
+	return o.PrintFlags.Print(contexts, o.Out)
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	setContextUsageStr = "set-context [CONTEXT_NAME] [--server=address] [--username=name] [--secret-id=id] [--output=format]"
+)
+
+// SetContextOptions is an options struct to support set-context subcommands.
+type SetContextOptions struct {
+	Name    string
+	Context genericclioptions.Context
+
+	changed func(name string) bool
+	config  *genericclioptions.IAMConfigFile
+	genericclioptions.IOStreams
+}
+
+var (
+	setContextLong = templates.LongDesc(`
+		Set a context entry in the iamconfig file.
+
+		Specifying a name that already exists will merge new fields on top of existing values for those fields.
+		The current context is set when CONTEXT_NAME is omitted.`)
+
+	setContextExample = templates.Examples(`
+		# Add a staging context which authenticates with a secret
+		iamctl config set-context staging --server=https://iam.staging.example.com:8443 --secret-id=xxx --secret-key=yyy
+
+		# Display the get and list results of the current context in yaml by default
+		iamctl config set-context --output=yaml`)
+)
+
+// NewSetContextOptions returns an initialized SetContextOptions instance.
+func NewSetContextOptions(ioStreams genericclioptions.IOStreams) *SetContextOptions {
+	return &SetContextOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdSetContext returns new initialized instance of set-context sub command.
+func NewCmdSetContext(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewSetContextOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   setContextUsageStr,
+		DisableFlagsInUseLine: true,
+		Short:                 "Set a context entry in the iamconfig file",
+		TraverseChildren:      true,
+		Long:                  setContextLong,
+		Example:               setContextExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	server, user := &o.Context.Server, &o.Context.User
+	cmd.Flags().StringVar(&server.Address, "server", "", "The address of the iam-apiserver of the context.")
+	cmd.Flags().StringVar(&server.CertificateAuthority, "certificate-authority", "",
+		"Path to a cert file for the certificate authority.")
+	cmd.Flags().BoolVar(&server.InsecureSkipTLSVerify, "insecure-skip-tls-verify", false,
+		"If true, the server's certificate will not be checked for validity.")
+	cmd.Flags().StringVar(&server.TLSServerName, "tls-server-name", "",
+		"Server name to use for server certificate validation.")
+	cmd.Flags().DurationVar(&server.Timeout, "timeout", 0, "The length of time to wait before giving up on a request.")
+	cmd.Flags().StringVar(&user.Token, "token", "", "Bearer token for authentication to the iam-apiserver.")
+	cmd.Flags().StringVar(&user.Username, "username", "", "Username for basic authentication and iamctl login.")
+	cmd.Flags().StringVar(&user.Password, "password", "", "Password for basic authentication and iamctl login.")
+	cmd.Flags().StringVar(&user.SecretID, "secret-id", "", "SecretID for JWT authentication.")
+	cmd.Flags().StringVar(&user.SecretKey, "secret-key", "", "SecretKey for JWT authentication.")
+	cmd.Flags().StringVar(&user.ClientCertificate, "client-certificate", "", "Path to a client certificate file for TLS.")
+	cmd.Flags().StringVar(&user.ClientKey, "client-key", "", "Path to a client key file for TLS.")
+	cmd.Flags().StringVar(&o.Context.Output, "output", "", "The default output format of the get and list commands.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *SetContextOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.config, err = genericclioptions.LoadIAMConfigFile(genericclioptions.IAMConfigFilePath())
+	if err != nil {
+		return err
+	}
+
+	o.Name = o.config.CurrentContext
+	if len(args) > 0 {
+		o.Name = args[0]
+	}
+
+	o.changed = cmd.Flags().Changed
+
+	return nil
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *SetContextOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args[1:])
+	}
+
+	if o.Name == "" {
+		return cmdutil.UsageErrorf(cmd, "CONTEXT_NAME is required when there is no current context")
+	}
+
+	// the existing contexts keep their names, whatever they are.
+	if o.config.Context(o.Name) == nil {
+		if err := genericclioptions.ValidateContextName(o.Name); err != nil {
+			return cmdutil.UsageErrorf(cmd, "%v", err)
+		}
+	}
+
+	if o.Context.Output != "" {
+		if err := (&printers.PrintFlags{OutputFormat: o.Context.Output}).Validate(); err != nil {
+			return cmdutil.UsageErrorf(cmd, "%v", err)
+		}
+	}
+
+	return nil
+}
+
+// Run executes a set-context subcommand using the specified options.
+func (o *SetContextOptions) Run(args []string) error {
+	context, exists := o.config.Context(o.Name), true
+	if context == nil {
+		context, exists = &genericclioptions.Context{Name: o.Name}, false
+	}
+
+	o.merge(context)
+	o.config.SetContext(context)
+
+	if err := o.config.Save(); err != nil {
+		return err
+	}
+
+	if exists {
+		fmt.Fprintf(o.Out, "Context %q modified.\n", o.Name)
+	} else {
+		fmt.Fprintf(o.Out, "Context %q created.\n", o.Name)
+	}
+
+	return nil
+}
+
+// merge sets the fields given on the command line into the context.
+func (o *SetContextOptions) merge(context *genericclioptions.Context) {
+	server, user := &context.Server, &context.User
+
+	for flag, merge := range map[string]func(){
+		"server":                   func() { server.Address = o.Context.Server.Address },
+		"certificate-authority":    func() { server.CertificateAuthority = o.Context.Server.CertificateAuthority },
+		"insecure-skip-tls-verify": func() { server.InsecureSkipTLSVerify = o.Context.Server.InsecureSkipTLSVerify },
+		"tls-server-name":          func() { server.TLSServerName = o.Context.Server.TLSServerName },
+		"timeout":                  func() { server.Timeout = o.Context.Server.Timeout },
+		"token":                    func() { user.Token = o.Context.User.Token },
+		"username":                 func() { user.Username = o.Context.User.Username },
+		"password":                 func() { user.Password = o.Context.User.Password },
+		"secret-id":                func() { user.SecretID = o.Context.User.SecretID },
+		"secret-key":               func() { user.SecretKey = o.Context.User.SecretKey },
+		"client-certificate":       func() { user.ClientCertificate = o.Context.User.ClientCertificate },
+		"client-key":               func() { user.ClientKey = o.Context.User.ClientKey },
+		"output":                   func() { context.Output = o.Context.Output },
+	} {
+		if o.changed(flag) {
+			merge()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	useContextUsageStr = "use-context CONTEXT_NAME"
+)
+
+// UseContextOptions is an options struct to support use-context subcommands.
+type UseContextOptions struct {
+	Name string
+
+	config *genericclioptions.IAMConfigFile
+	genericclioptions.IOStreams
+}
+
+var (
+	useContextExample = templates.Examples(`
+		# Use the context for the staging iam-apiserver
+		iamctl config use-context staging`)
+
+	useContextUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nCONTEXT_NAME is required arguments for the use-context command",
+		useContextUsageStr,
+	)
+)
+
+// NewUseContextOptions returns an initialized UseContextOptions instance.
+func NewUseContextOptions(ioStreams genericclioptions.IOStreams) *UseContextOptions {
+	return &UseContextOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdUseContext returns new initialized instance of use-context sub command.
+func NewCmdUseContext(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewUseContextOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   useContextUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"use"},
+		Short:                 "Set the current-context in the iamconfig file",
+		TraverseChildren:      true,
+		Long:                  "Set the current-context in the iamconfig file.",
+		Example:               useContextExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *UseContextOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) != 1 {
+		return cmdutil.UsageErrorf(cmd, useContextUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	o.config, err = genericclioptions.LoadIAMConfigFile(genericclioptions.IAMConfigFilePath())
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *UseContextOptions) Validate(cmd *cobra.Command, args []string) error {
+	if o.config.Context(o.Name) == nil {
+		return fmt.Errorf("no context exists with the name: %q", o.Name)
+	}
+
+	return nil
+}
+
+// Run executes a use-context subcommand using the specified options.
+func (o *UseContextOptions) Run(args []string) error {
+	o.config.CurrentContext = o.Name
+	if err := o.config.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Switched to context %q.\n", o.Name)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+var attributesLong = templates.LongDesc(`
+	Item attributes management commands.
+
+	This commands allow you to manage the binding, dimensions and weights of the items.`)
+
+// NewCmdAttributes returns new initialized instance of 'item attributes' sub command.
+func NewCmdAttributes(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "attributes SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"attris"},
+		Short:                 "Manage the attributes of the items",
+		Long:                  attributesLong,
+		Run:                   cmdutil.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdAttributesCreate(f, ioStreams))
+	cmd.AddCommand(NewCmdAttributesGet(f, ioStreams))
+	cmd.AddCommand(NewCmdAttributesUpdate(f, ioStreams))
+	cmd.AddCommand(NewCmdAttributesDelete(f, ioStreams))
+
+	return cmd
+}
+
+// attributesColumns are the columns of the item attributes tables.
+var attributesColumns = []printers.Column{
+	{Header: "ID", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*itemv1.ItemAttributes).ID, 10)
+	}},
+	{Header: "ItemID", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*itemv1.ItemAttributes).ItemID, 10)
+	}},
+	{Header: "Binding", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.ItemAttributes).Binding
+	}},
+	{Header: "ItemDimensions", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		a := obj.(*itemv1.ItemAttributes)
+
+		return dimensions(a.ItemHeight, a.ItemLength, a.ItemWidth, a.ItemDimensionsUnit)
+	}},
+	{Header: "ItemWeight", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return fmt.Sprint(obj.(*itemv1.ItemAttributes).ItemWeight)
+	}},
+	{Header: "PackageDimensions", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		a := obj.(*itemv1.ItemAttributes)
+
+		return dimensions(a.PackageHeight, a.PackageLength, a.PackageWidth, a.PackageDimensionsUnit)
+	}},
+	{Header: "PackageWeight", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return fmt.Sprint(obj.(*itemv1.ItemAttributes).PackageWeight)
+	}},
+	{Header: "ReleaseDate", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.ItemAttributes).ReleaseDate.Format("2006-01-02")
+	}},
+	{Header: "Updated", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.ItemAttributes).UpdatedAt.Format("2006-01-02 15:04:05")
+	}},
+}
+
+// newAttributesPrintFlags returns the print flags of the item attributes commands.
+func newAttributesPrintFlags() *printers.PrintFlags {
+	return printers.NewPrintFlags("attributes", attributesColumns...)
+}
+
+func dimensions(height, length, width float64, unit string) string {
+	return fmt.Sprintf("%gx%gx%g %s", height, length, width, unit)
+}
+
+// getAttributes returns the item attributes with the identifier.
+func getAttributes(client *restclient.RESTClient, id uint64) (*itemv1.ItemAttributes, error) {
+	attributes := &itemv1.ItemAttributes{}
+	if err := client.Get().AbsPath("/v2/itemAttris", fmt.Sprint(id)).Do(context.TODO()).Into(attributes); err != nil {
+		return nil, err
+	}
+
+	return attributes, nil
+}
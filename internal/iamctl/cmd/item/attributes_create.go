@@ -0,0 +1,132 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	attributesCreateUsageStr = "create ITEM_ID -f FILENAME"
+)
+
+// AttributesCreateOptions is an options struct to support attributes create subcommands.
+type AttributesCreateOptions struct {
+	ItemID   uint64
+	Filename string
+
+	PrintFlags *printers.PrintFlags
+
+	Attributes *itemv1.ItemAttributes
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	attributesCreateLong = templates.LongDesc(`Create the attributes of an item.
+
+The attributes are read from the YAML or JSON file given by --filename, with the
+same fields as the API, e.g. binding, item_height, item_dimensions_unit.`)
+
+	attributesCreateExample = templates.Examples(`
+		# Create the attributes of an item
+		iamctl item attributes create 1735371396125511680 -f attributes.yaml`)
+
+	attributesCreateUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nITEM_ID and FILENAME are required arguments for the create command",
+		attributesCreateUsageStr,
+	)
+)
+
+// NewAttributesCreateOptions returns an initialized AttributesCreateOptions instance.
+func NewAttributesCreateOptions(ioStreams genericclioptions.IOStreams) *AttributesCreateOptions {
+	return &AttributesCreateOptions{
+		PrintFlags: newAttributesPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdAttributesCreate returns new initialized instance of attributes create sub command.
+func NewCmdAttributesCreate(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewAttributesCreateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   attributesCreateUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Create the attributes of an item",
+		TraverseChildren:      true,
+		Long:                  attributesCreateLong,
+		Example:               attributesCreateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "YAML or JSON file which contains the attributes.")
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *AttributesCreateOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 || o.Filename == "" {
+		return cmdutil.UsageErrorf(cmd, attributesCreateUsageErrStr)
+	}
+
+	o.ItemID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.Attributes = &itemv1.ItemAttributes{}
+	if err := readObject(o.Filename, o.Attributes); err != nil {
+		return err
+	}
+
+	o.Attributes.ID = 0
+	o.Attributes.ItemID = o.ItemID
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *AttributesCreateOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an attributes create subcommand using the specified options.
+func (o *AttributesCreateOptions) Run(args []string) error {
+	var attributes itemv1.ItemAttributes
+	if err := o.client.Post().AbsPath("/v2/itemAttris").Body(*o.Attributes).Do(context.TODO()).Into(&attributes); err != nil {
+		return err
+	}
+
+	if o.PrintFlags.OutputFormat != "" {
+		return o.PrintFlags.Print(&attributes, o.Out)
+	}
+
+	fmt.Fprintf(o.Out, "attributes/%d created\n", attributes.ID)
+
+	return nil
+}
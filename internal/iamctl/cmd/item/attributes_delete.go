@@ -0,0 +1,103 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	attributesDeleteUsageStr = "delete ATTRIBUTES_ID"
+)
+
+// AttributesDeleteOptions is an options struct to support attributes delete subcommands.
+type AttributesDeleteOptions struct {
+	ID uint64
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	attributesDeleteExample = templates.Examples(`
+		# Delete the specified item attributes
+		iamctl item attributes delete 12`)
+
+	attributesDeleteUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nATTRIBUTES_ID is required arguments for the delete command",
+		attributesDeleteUsageStr,
+	)
+)
+
+// NewAttributesDeleteOptions returns an initialized AttributesDeleteOptions instance.
+func NewAttributesDeleteOptions(ioStreams genericclioptions.IOStreams) *AttributesDeleteOptions {
+	return &AttributesDeleteOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdAttributesDelete returns new initialized instance of attributes delete sub command.
+func NewCmdAttributesDelete(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewAttributesDeleteOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   attributesDeleteUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Delete the attributes of an item",
+		TraverseChildren:      true,
+		Long:                  "Delete the attributes of an item.",
+		Example:               attributesDeleteExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *AttributesDeleteOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, attributesDeleteUsageErrStr)
+	}
+
+	o.ID, err = parseID(cmd, "attributes", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *AttributesDeleteOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes an attributes delete subcommand using the specified options.
+func (o *AttributesDeleteOptions) Run(args []string) error {
+	if err := o.client.Delete().AbsPath("/v2/itemAttris", fmt.Sprint(o.ID)).Do(context.TODO()).Error(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "attributes/%d deleted\n", o.ID)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	attributesGetUsageStr = "get ATTRIBUTES_ID"
+)
+
+// AttributesGetOptions is an options struct to support attributes get subcommands.
+type AttributesGetOptions struct {
+	ID uint64
+
+	PrintFlags *printers.PrintFlags
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	attributesGetExample = templates.Examples(`
+		# Get the specified item attributes
+		iamctl item attributes get 12
+
+		# Get the specified item attributes in JSON
+		iamctl item attributes get 12 -o json`)
+
+	attributesGetUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nATTRIBUTES_ID is required arguments for the get command",
+		attributesGetUsageStr,
+	)
+)
+
+// NewAttributesGetOptions returns an initialized AttributesGetOptions instance.
+func NewAttributesGetOptions(ioStreams genericclioptions.IOStreams) *AttributesGetOptions {
+	return &AttributesGetOptions{
+		PrintFlags: newAttributesPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdAttributesGet returns new initialized instance of attributes get sub command.
+func NewCmdAttributesGet(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewAttributesGetOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   attributesGetUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Display the attributes of an item",
+		TraverseChildren:      true,
+		Long:                  "Display the attributes of an item.",
+		Example:               attributesGetExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *AttributesGetOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, attributesGetUsageErrStr)
+	}
+
+	o.ID, err = parseID(cmd, "attributes", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *AttributesGetOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an attributes get subcommand using the specified options.
+func (o *AttributesGetOptions) Run(args []string) error {
+	attributes, err := getAttributes(o.client, o.ID)
+	if err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Print(attributes, o.Out)
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	attributesUpdateUsageStr = "update ATTRIBUTES_ID -f FILENAME"
+)
+
+// AttributesUpdateOptions is an options struct to support attributes update subcommands.
+type AttributesUpdateOptions struct {
+	ID       uint64
+	Filename string
+
+	PrintFlags *printers.PrintFlags
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	attributesUpdateLong = templates.LongDesc(`Update the attributes of an item.
+
+The fields given in the YAML or JSON file of --filename replace the current ones,
+the other fields are left untouched.`)
+
+	attributesUpdateExample = templates.Examples(`
+		# Update the weight of the specified item attributes
+		echo 'item_weight: 0.8' > weight.yaml
+		iamctl item attributes update 12 -f weight.yaml`)
+
+	attributesUpdateUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nATTRIBUTES_ID and FILENAME are required arguments for the update command",
+		attributesUpdateUsageStr,
+	)
+)
+
+// NewAttributesUpdateOptions returns an initialized AttributesUpdateOptions instance.
+func NewAttributesUpdateOptions(ioStreams genericclioptions.IOStreams) *AttributesUpdateOptions {
+	return &AttributesUpdateOptions{
+		PrintFlags: newAttributesPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdAttributesUpdate returns new initialized instance of attributes update sub command.
+func NewCmdAttributesUpdate(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewAttributesUpdateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   attributesUpdateUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Update the attributes of an item",
+		TraverseChildren:      true,
+		Long:                  attributesUpdateLong,
+		Example:               attributesUpdateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "YAML or JSON file which contains the changed fields.")
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *AttributesUpdateOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 || o.Filename == "" {
+		return cmdutil.UsageErrorf(cmd, attributesUpdateUsageErrStr)
+	}
+
+	o.ID, err = parseID(cmd, "attributes", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *AttributesUpdateOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an attributes update subcommand using the specified options.
+func (o *AttributesUpdateOptions) Run(args []string) error {
+	attributes, err := getAttributes(o.client, o.ID)
+	if err != nil {
+		return err
+	}
+
+	// decoding the file over the current attributes only replaces the given fields.
+	if err := readObject(o.Filename, attributes); err != nil {
+		return err
+	}
+
+	var ret itemv1.ItemAttributes
+	if err := o.client.Put().AbsPath("/v2/itemAttris", fmt.Sprint(o.ID)).
+		Body(*attributes).Do(context.TODO()).Into(&ret); err != nil {
+		return err
+	}
+
+	if o.PrintFlags.OutputFormat != "" {
+		return o.PrintFlags.Print(&ret, o.Out)
+	}
+
+	fmt.Fprintf(o.Out, "attributes/%d updated\n", ret.ID)
+
+	return nil
+}
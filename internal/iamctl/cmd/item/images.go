@@ -0,0 +1,76 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+var imagesLong = templates.LongDesc(`
+	Item images management commands.
+
+	This commands allow you to upload, list, download and delete the images of the items.
+	The images are kept by the file storage of iam-apiserver.`)
+
+// NewCmdImages returns new initialized instance of 'item images' sub command.
+func NewCmdImages(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "images SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Manage the images of the items",
+		Long:                  imagesLong,
+		Run:                   cmdutil.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdImagesUpload(f, ioStreams))
+	cmd.AddCommand(NewCmdImagesList(f, ioStreams))
+	cmd.AddCommand(NewCmdImagesDownload(f, ioStreams))
+	cmd.AddCommand(NewCmdImagesDelete(f, ioStreams))
+
+	return cmd
+}
+
+// imagesColumns are the columns of the item images tables.
+var imagesColumns = []printers.Column{
+	{Header: "ID", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*itemv1.ItemImage).ID, 10)
+	}},
+	{Header: "ItemID", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*itemv1.ItemImage).ItemID, 10)
+	}},
+	{Header: "URL", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.ItemImage).ImageURL
+	}},
+	{Header: "Created", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.ItemImage).CreatedAt.Format("2006-01-02 15:04:05")
+	}},
+}
+
+// newImagesPrintFlags returns the print flags of the item images commands.
+func newImagesPrintFlags() *printers.PrintFlags {
+	return printers.NewPrintFlags("image", imagesColumns...)
+}
+
+// listImages returns the images of the item.
+func listImages(client *restclient.RESTClient, itemID uint64) ([]*itemv1.ItemImage, error) {
+	var images []*itemv1.ItemImage
+	if err := client.Get().AbsPath("/v2/items", fmt.Sprint(itemID), "images").Do(context.TODO()).Into(&images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
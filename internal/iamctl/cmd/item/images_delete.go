@@ -0,0 +1,113 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	imagesDeleteUsageStr = "delete IMAGE_ID..."
+)
+
+// ImagesDeleteOptions is an options struct to support images delete subcommands.
+type ImagesDeleteOptions struct {
+	IDs []uint64
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	imagesDeleteLong = templates.LongDesc(`Delete images of the items.
+
+The images are removed from the file storage as well.`)
+
+	imagesDeleteExample = templates.Examples(`
+		# Delete two images
+		iamctl item images delete 1735371396125511701 1735371396125511702`)
+
+	imagesDeleteUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nIMAGE_ID is required arguments for the delete command",
+		imagesDeleteUsageStr,
+	)
+)
+
+// NewImagesDeleteOptions returns an initialized ImagesDeleteOptions instance.
+func NewImagesDeleteOptions(ioStreams genericclioptions.IOStreams) *ImagesDeleteOptions {
+	return &ImagesDeleteOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdImagesDelete returns new initialized instance of images delete sub command.
+func NewCmdImagesDelete(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewImagesDeleteOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   imagesDeleteUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Delete images of the items",
+		TraverseChildren:      true,
+		Long:                  imagesDeleteLong,
+		Example:               imagesDeleteExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ImagesDeleteOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, imagesDeleteUsageErrStr)
+	}
+
+	for _, arg := range args {
+		id, err := parseID(cmd, "image", arg)
+		if err != nil {
+			return err
+		}
+
+		o.IDs = append(o.IDs, id)
+	}
+
+	var err error
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ImagesDeleteOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes an images delete subcommand using the specified options.
+func (o *ImagesDeleteOptions) Run(args []string) error {
+	for _, id := range o.IDs {
+		if err := o.client.Delete().AbsPath("/v2/itemImages", fmt.Sprint(id)).Do(context.TODO()).Error(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(o.Out, "image/%d deleted\n", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	imagesDownloadUsageStr = "download ITEM_ID [IMAGE_ID...]"
+)
+
+// ImagesDownloadOptions is an options struct to support images download subcommands.
+type ImagesDownloadOptions struct {
+	ItemID   uint64
+	ImageIDs map[uint64]bool
+	Dir      string
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	imagesDownloadLong = templates.LongDesc(`Download the images of an item.
+
+All the images of the item are downloaded unless IMAGE_IDs are given. The images are
+fetched from the file storage by their URLs, each one is saved in --dir as
+<IMAGE_ID>-<name of the stored file>.`)
+
+	imagesDownloadExample = templates.Examples(`
+		# Download all the images of an item in the current directory
+		iamctl item images download 1735371396125511680
+
+		# Download two images of an item in the photos directory
+		iamctl item images download 1735371396125511680 1735371396125511701 1735371396125511702 --dir=photos`)
+
+	imagesDownloadUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nITEM_ID is required arguments for the download command",
+		imagesDownloadUsageStr,
+	)
+)
+
+// NewImagesDownloadOptions returns an initialized ImagesDownloadOptions instance.
+func NewImagesDownloadOptions(ioStreams genericclioptions.IOStreams) *ImagesDownloadOptions {
+	return &ImagesDownloadOptions{
+		Dir:       ".",
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdImagesDownload returns new initialized instance of images download sub command.
+func NewCmdImagesDownload(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewImagesDownloadOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   imagesDownloadUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Download the images of an item",
+		TraverseChildren:      true,
+		Long:                  imagesDownloadLong,
+		Example:               imagesDownloadExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", o.Dir, "Directory to save the images in, created if missing.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ImagesDownloadOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, imagesDownloadUsageErrStr)
+	}
+
+	o.ItemID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.ImageIDs = make(map[uint64]bool, len(args)-1)
+	for _, arg := range args[1:] {
+		id, err := parseID(cmd, "image", arg)
+		if err != nil {
+			return err
+		}
+
+		o.ImageIDs[id] = true
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ImagesDownloadOptions) Validate(cmd *cobra.Command, args []string) error {
+	if o.Dir == "" {
+		return cmdutil.UsageErrorf(cmd, "--dir must not be empty")
+	}
+
+	return nil
+}
+
+// Run executes an images download subcommand using the specified options.
+func (o *ImagesDownloadOptions) Run(args []string) error {
+	images, err := listImages(o.client, o.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(o.Dir, 0o755); err != nil {
+		return err
+	}
+
+	downloaded := 0
+	for _, image := range images {
+		if len(o.ImageIDs) > 0 && !o.ImageIDs[image.ID] {
+			continue
+		}
+
+		file, err := downloadImage(image, o.Dir)
+		if err != nil {
+			return fmt.Errorf("download image %d: %w", image.ID, err)
+		}
+
+		fmt.Fprintf(o.Out, "image/%d downloaded to %s\n", image.ID, file)
+		downloaded++
+	}
+
+	if downloaded < len(o.ImageIDs) {
+		return fmt.Errorf("%d of the images do not belong to item %d", len(o.ImageIDs)-downloaded, o.ItemID)
+	}
+
+	return nil
+}
+
+// downloadImage saves the image in dir and returns the file. The file storage
+// is not iam-apiserver, so the request does not carry the iam credentials.
+func downloadImage(image *itemv1.ItemImage, dir string) (string, error) {
+	u, err := url.Parse(image.ImageURL)
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, fmt.Sprintf("%d-%s", image.ID, path.Base(u.Path)))
+
+	resp, err := http.Get(image.ImageURL) // nolint: gosec,noctx
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", image.ImageURL, resp.Status)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(file)
+
+		return "", err
+	}
+
+	return file, f.Close()
+}
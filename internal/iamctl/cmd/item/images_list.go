@@ -0,0 +1,107 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	imagesListUsageStr = "list ITEM_ID"
+)
+
+// ImagesListOptions is an options struct to support images list subcommands.
+type ImagesListOptions struct {
+	ItemID uint64
+
+	PrintFlags *printers.PrintFlags
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	imagesListExample = templates.Examples(`
+		# List the images of an item
+		iamctl item images list 1735371396125511680`)
+
+	imagesListUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nITEM_ID is required arguments for the list command",
+		imagesListUsageStr,
+	)
+)
+
+// NewImagesListOptions returns an initialized ImagesListOptions instance.
+func NewImagesListOptions(ioStreams genericclioptions.IOStreams) *ImagesListOptions {
+	return &ImagesListOptions{
+		PrintFlags: newImagesPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdImagesList returns new initialized instance of images list sub command.
+func NewCmdImagesList(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewImagesListOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   imagesListUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Display the images of an item",
+		TraverseChildren:      true,
+		Long:                  "Display the images of an item.",
+		Example:               imagesListExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ImagesListOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, imagesListUsageErrStr)
+	}
+
+	o.ItemID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ImagesListOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an images list subcommand using the specified options.
+func (o *ImagesListOptions) Run(args []string) error {
+	images, err := listImages(o.client, o.ItemID)
+	if err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Print(images, o.Out)
+}
@@ -0,0 +1,213 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	imagesUploadUsageStr = "upload ITEM_ID FILE..."
+)
+
+// ImagesUploadOptions is an options struct to support images upload subcommands.
+type ImagesUploadOptions struct {
+	ItemID uint64
+	Files  []string
+
+	PrintFlags *printers.PrintFlags
+
+	config *restclient.Config
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	imagesUploadLong = templates.LongDesc(`Upload images of an item.
+
+All the files are uploaded in a single multipart request, iam-apiserver stores them
+in its file storage and records their URLs.`)
+
+	imagesUploadExample = templates.Examples(`
+		# Upload the images of an item
+		iamctl item images upload 1735371396125511680 front.jpg back.jpg
+
+		# Upload all the jpg images in a directory
+		iamctl item images upload 1735371396125511680 photos/*.jpg`)
+
+	imagesUploadUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nITEM_ID and at least one FILE are required arguments for the upload command",
+		imagesUploadUsageStr,
+	)
+)
+
+// NewImagesUploadOptions returns an initialized ImagesUploadOptions instance.
+func NewImagesUploadOptions(ioStreams genericclioptions.IOStreams) *ImagesUploadOptions {
+	return &ImagesUploadOptions{
+		PrintFlags: newImagesPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdImagesUpload returns new initialized instance of images upload sub command.
+func NewCmdImagesUpload(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewImagesUploadOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   imagesUploadUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Upload images of an item",
+		TraverseChildren:      true,
+		Long:                  imagesUploadLong,
+		Example:               imagesUploadExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ImagesUploadOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) < 2 {
+		return cmdutil.UsageErrorf(cmd, imagesUploadUsageErrStr)
+	}
+
+	o.ItemID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.Files = args[1:]
+
+	o.config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ImagesUploadOptions) Validate(cmd *cobra.Command, args []string) error {
+	for _, file := range o.Files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", file)
+		}
+	}
+
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an images upload subcommand using the specified options.
+func (o *ImagesUploadOptions) Run(args []string) error {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	// the files are streamed, so large images are not loaded in memory.
+	go func() {
+		writer.CloseWithError(writeImagesForm(form, o.ItemID, o.Files))
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, o.client.Post().AbsPath("/v2/itemImages").URL().String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := cmdutil.DoRaw(o.config, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("upload images: %s", data)
+	}
+
+	var images []*itemv1.ItemImage
+	if err := json.Unmarshal(data, &images); err != nil {
+		return err
+	}
+
+	if o.PrintFlags.OutputFormat != "" {
+		return o.PrintFlags.Print(images, o.Out)
+	}
+
+	for _, image := range images {
+		fmt.Fprintf(o.Out, "image/%d uploaded\n", image.ID)
+	}
+
+	return nil
+}
+
+// writeImagesForm writes the multipart form expected by iam-apiserver.
+func writeImagesForm(form *multipart.Writer, itemID uint64, files []string) error {
+	if err := form.WriteField("item_id", fmt.Sprint(itemID)); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := writeImageFile(form, file); err != nil {
+			return err
+		}
+	}
+
+	return form.Close()
+}
+
+func writeImageFile(form *multipart.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	part, err := form.CreateFormFile("upload[]", filepath.Base(file))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, f)
+
+	return err
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteImagesForm(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "front.jpg"), filepath.Join(dir, "back.jpg")}
+	for _, file := range files {
+		assert.Nil(t, os.WriteFile(file, []byte(filepath.Base(file)), 0o600))
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	assert.Nil(t, writeImagesForm(form, 42, files))
+
+	parsed, err := multipart.NewReader(&body, form.Boundary()).ReadForm(1 << 20)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"42"}, parsed.Value["item_id"])
+
+	uploaded := parsed.File["upload[]"]
+	assert.Len(t, uploaded, 2)
+
+	for i, header := range uploaded {
+		assert.Equal(t, filepath.Base(files[i]), header.Filename)
+
+		f, err := header.Open()
+		assert.Nil(t, err)
+
+		data, err := ioutil.ReadAll(f)
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Base(files[i]), string(data))
+		f.Close()
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package item provides functions to manage the catalog items on iam platform.
+package item
+
+import (
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+var itemLong = templates.LongDesc(`
+	Item management commands.
+
+	This commands allow you to manage the catalog items, their attributes and their images on iam platform.`)
+
+// NewCmdItem returns new initialized instance of 'item' sub command.
+func NewCmdItem(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "item SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Manage catalog items on iam platform",
+		Long:                  itemLong,
+		Run:                   cmdutil.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdCreate(f, ioStreams))
+	cmd.AddCommand(NewCmdGet(f, ioStreams))
+	cmd.AddCommand(NewCmdList(f, ioStreams))
+	cmd.AddCommand(NewCmdDelete(f, ioStreams))
+	cmd.AddCommand(NewCmdUpdate(f, ioStreams))
+	cmd.AddCommand(NewCmdAttributes(f, ioStreams))
+	cmd.AddCommand(NewCmdImages(f, ioStreams))
+	cmd.AddCommand(NewCmdImport(f, ioStreams))
+	cmd.AddCommand(NewCmdExport(f, ioStreams))
+
+	return cmd
+}
+
+// itemColumns are the columns of the item tables.
+var itemColumns = []printers.Column{
+	{Header: "ID", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*itemv1.Item).ID, 10)
+	}},
+	{Header: "SKU", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).SKU
+	}},
+	{Header: "ASIN", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).ASIN
+	}},
+	{Header: "Brand", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).Brand
+	}},
+	{Header: "Title", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).Title
+	}},
+	{Header: "ProductGroup", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).ProductGroup
+	}},
+	{Header: "ProductType", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).ProductType
+	}},
+	{Header: "Status", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return strconv.Itoa(obj.(*itemv1.Item).Status)
+	}},
+	{Header: "Created", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*itemv1.Item).CreatedAt.Format("2006-01-02 15:04:05")
+	}},
+}
+
+// newItemPrintFlags returns the print flags of the item commands, the items
+// are named by their identifier.
+func newItemPrintFlags() *printers.PrintFlags {
+	flags := printers.NewPrintFlags("item", itemColumns...)
+	flags.Name = func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*itemv1.Item).ID, 10)
+	}
+
+	return flags
+}
+
+// validatePrintFlags makes sure the output format is supported.
+func validatePrintFlags(cmd *cobra.Command, flags *printers.PrintFlags) error {
+	if err := flags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
+	return nil
+}
+
+// parseID parses the identifier given as argument of a command.
+func parseID(cmd *cobra.Command, kind, arg string) (uint64, error) {
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, cmdutil.UsageErrorf(cmd, "%s %q is not a valid identifier", kind, arg)
+	}
+
+	return id, nil
+}
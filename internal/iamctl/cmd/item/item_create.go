@@ -0,0 +1,166 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// CreateOptions is an options struct to support create subcommands.
+type CreateOptions struct {
+	Filename     string
+	ASIN         string
+	Brand        string
+	Title        string
+	ProductGroup string
+	ProductType  string
+	Status       int
+
+	PrintFlags *printers.PrintFlags
+
+	Item *itemv1.Item
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	createLong = templates.LongDesc(`Create an item resource.
+
+The item is read from the YAML or JSON file given by --filename, the other flags
+override its fields. The ID and the SKU of the item are generated by iam-apiserver.`)
+
+	createExample = templates.Examples(`
+		# Create an item
+		iamctl item create --asin=B07XJ8C8F5 --brand=acme --title="Acme running shoes" --product-type=SHOES
+
+		# Create an item from a file
+		iamctl item create -f item.yaml`)
+)
+
+// NewCreateOptions returns an initialized CreateOptions instance.
+func NewCreateOptions(ioStreams genericclioptions.IOStreams) *CreateOptions {
+	return &CreateOptions{
+		Status:     1,
+		PrintFlags: newItemPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdCreate returns new initialized instance of create sub command.
+func NewCmdCreate(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewCreateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "create",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Create an item resource",
+		TraverseChildren:      true,
+		Long:                  createLong,
+		Example:               createExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "YAML or JSON file which contains the item.")
+	cmd.Flags().StringVar(&o.ASIN, "asin", o.ASIN, "The ASIN of the item.")
+	cmd.Flags().StringVar(&o.Brand, "brand", o.Brand, "The brand of the item.")
+	cmd.Flags().StringVar(&o.Title, "title", o.Title, "The title of the item.")
+	cmd.Flags().StringVar(&o.ProductGroup, "product-group", o.ProductGroup, "The product group of the item.")
+	cmd.Flags().StringVar(&o.ProductType, "product-type", o.ProductType, "The product type of the item.")
+	cmd.Flags().IntVar(&o.Status, "status", o.Status, "The status of the item, only the items with status 1 are listed.")
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *CreateOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	o.Item = &itemv1.Item{Status: o.Status}
+	if o.Filename != "" {
+		if err := readObject(o.Filename, o.Item); err != nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("status") && o.Item.Status != 0 {
+			o.Status = o.Item.Status
+		}
+	}
+
+	setIfChanged(cmd, "asin", &o.Item.ASIN, o.ASIN)
+	setIfChanged(cmd, "brand", &o.Item.Brand, o.Brand)
+	setIfChanged(cmd, "title", &o.Item.Title, o.Title)
+	setIfChanged(cmd, "product-group", &o.Item.ProductGroup, o.ProductGroup)
+	setIfChanged(cmd, "product-type", &o.Item.ProductType, o.ProductType)
+	o.Item.Status = o.Status
+
+	var err error
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *CreateOptions) Validate(cmd *cobra.Command, args []string) error {
+	if o.Item.Title == "" {
+		return cmdutil.UsageErrorf(cmd, "the title of the item is required")
+	}
+
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes a create subcommand using the specified options.
+func (o *CreateOptions) Run(args []string) error {
+	var item itemv1.Item
+	if err := o.client.Post().AbsPath("/v2/items").Body(*o.Item).Do(context.TODO()).Into(&item); err != nil {
+		return err
+	}
+
+	if o.PrintFlags.OutputFormat != "" {
+		return o.PrintFlags.Print(&item, o.Out)
+	}
+
+	fmt.Fprintf(o.Out, "item/%d created\n", item.ID)
+
+	return nil
+}
+
+// readObject decodes a YAML or JSON file into obj.
+func readObject(file string, obj interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("decode %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// setIfChanged sets field to value if the flag is given on the command line.
+func setIfChanged(cmd *cobra.Command, flag string, field *string, value string) {
+	if cmd.Flags().Changed(flag) {
+		*field = value
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	deleteUsageStr = "delete ITEM_ID"
+)
+
+// DeleteOptions is an options struct to support delete subcommands.
+type DeleteOptions struct {
+	ID uint64
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	deleteExample = templates.Examples(`
+		# Delete an item
+		iamctl item delete 1735371396125511680`)
+
+	deleteUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nITEM_ID is required arguments for the delete command",
+		deleteUsageStr,
+	)
+)
+
+// NewDeleteOptions returns an initialized DeleteOptions instance.
+func NewDeleteOptions(ioStreams genericclioptions.IOStreams) *DeleteOptions {
+	return &DeleteOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdDelete returns new initialized instance of delete sub command.
+func NewCmdDelete(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewDeleteOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   deleteUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Delete an item resource",
+		TraverseChildren:      true,
+		Long:                  "Delete an item resource.",
+		Example:               deleteExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *DeleteOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, deleteUsageErrStr)
+	}
+
+	o.ID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *DeleteOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes a delete subcommand using the specified options.
+func (o *DeleteOptions) Run(args []string) error {
+	if err := o.client.Delete().AbsPath("/v2/items", fmt.Sprint(o.ID)).Do(context.TODO()).Error(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "item/%d deleted\n", o.ID)
+
+	return nil
+}
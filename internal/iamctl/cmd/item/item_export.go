@@ -0,0 +1,167 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// ExportOptions is an options struct to support export subcommands.
+type ExportOptions struct {
+	File          string
+	Format        string
+	FieldSelector string
+
+	config *restclient.Config
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	exportLong = templates.LongDesc(`Export the active items to a catalog file.
+
+The file is written in the format read by 'iamctl item import', so a catalog can be
+exported, edited and imported back. The items can be filtered by --field-selector,
+as with 'iamctl item list'.`)
+
+	exportExample = templates.Examples(`
+		# Export the catalog to a CSV file
+		iamctl item export -f catalog.csv
+
+		# Export the items of a brand in JSON lines to the standard output
+		iamctl item export --format=jsonl --field-selector=brand=acme`)
+)
+
+// NewExportOptions returns an initialized ExportOptions instance.
+func NewExportOptions(ioStreams genericclioptions.IOStreams) *ExportOptions {
+	return &ExportOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdExport returns new initialized instance of export sub command.
+func NewCmdExport(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewExportOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "export",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Export items to a CSV or JSON lines file",
+		TraverseChildren:      true,
+		Long:                  exportLong,
+		Example:               exportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVarP(&o.File, "filename", "f", o.File, "File to write the catalog to, the standard output by default.")
+	cmd.Flags().StringVar(&o.Format, "format", o.Format,
+		"Format of the file, one of: csv|jsonl. Detected from the file extension, csv by default.")
+	cmd.Flags().StringVar(&o.FieldSelector, "field-selector", o.FieldSelector,
+		"Selector to filter on, supports '=' and '==' (e.g. --field-selector brand=acme,product_type=SHOES).")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ExportOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	if o.Format == "" {
+		o.Format = formatOf(o.File)
+	}
+
+	if o.Format == "" {
+		o.Format = itemv1.CatalogFormatCSV
+	}
+
+	o.config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ExportOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validateFormat(cmd, o.Format)
+}
+
+// Run executes an export subcommand using the specified options.
+func (o *ExportOptions) Run(args []string) error {
+	req := o.client.Get().AbsPath("/v2/itemExports").Param("format", o.Format)
+	if o.FieldSelector != "" {
+		req = req.Param("fieldSelector", o.FieldSelector)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodGet, req.URL().String(), nil)
+	if err != nil {
+		return err
+	}
+
+	// the catalog is streamed, a timeout would cut large exports.
+	config := *o.config
+	config.Timeout = 0
+
+	resp, err := cmdutil.DoRaw(&config, httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		return fmt.Errorf("export catalog: %s", data)
+	}
+
+	if o.File == "" {
+		_, err = io.Copy(o.Out, resp.Body)
+
+		return err
+	}
+
+	out, err := os.Create(o.File)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.ErrOut, "catalog exported to %s\n", o.File)
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	getUsageStr = "get ITEM_ID"
+)
+
+// GetOptions is an options struct to support get subcommands.
+type GetOptions struct {
+	ID uint64
+
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	getExample = templates.Examples(`
+		# Get a specified item information
+		iamctl item get 1735371396125511680
+
+		# Get a specified item information in YAML
+		iamctl item get 1735371396125511680 -o yaml`)
+
+	getUsageErrStr = fmt.Sprintf("expected '%s'.\nITEM_ID is required arguments for the get command", getUsageStr)
+)
+
+// NewGetOptions returns an initialized GetOptions instance.
+func NewGetOptions(ioStreams genericclioptions.IOStreams) *GetOptions {
+	return &GetOptions{
+		PrintFlags: newItemPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdGet returns new initialized instance of get sub command.
+func NewCmdGet(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewGetOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   getUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Display an item resource",
+		TraverseChildren:      true,
+		Long:                  "Display an item resource.",
+		Example:               getExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *GetOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, getUsageErrStr)
+	}
+
+	o.ID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+	if err != nil {
+		return err
+	}
+
+	return o.WatchFlags.Complete(f)
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *GetOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes a get subcommand using the specified options.
+func (o *GetOptions) Run(args []string) error {
+	watcher, err := o.WatchFlags.Start("item", fmt.Sprint(o.ID))
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
+
+	return watcher.Print(o.PrintFlags, o.Out)
+}
+
+// printCurrent prints the item, unless only the changes are watched.
+func (o *GetOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
+	}
+
+	item, err := getItem(o.client, o.ID)
+	if err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Print(item, o.Out)
+}
+
+// getItem returns the item with the identifier.
+func getItem(client *restclient.RESTClient, id uint64) (*itemv1.Item, error) {
+	item := &itemv1.Item{}
+	if err := client.Get().AbsPath("/v2/items", fmt.Sprint(id)).Do(context.TODO()).Into(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,340 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	importUsageStr = "import (FILE | --job=ID)"
+	// importPollInterval is the interval between two polls of the job progress.
+	importPollInterval = 2 * time.Second
+)
+
+// ImportOptions is an options struct to support import subcommands.
+type ImportOptions struct {
+	File   string
+	Format string
+	Wait   bool
+	JobID  uint64
+
+	PrintFlags *printers.PrintFlags
+
+	config *restclient.Config
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	importLong = templates.LongDesc(`Import a catalog file.
+
+The file holds one item per row, in CSV or in JSON lines. Items are matched by ASIN,
+or by SKU when the ASIN is empty: the matched items are updated and the others are
+created, together with their attributes, their offers and their images.
+
+The import runs in the background on iam-apiserver. Use --wait to follow it until it
+finishes, or --job to show the progress and the rejected rows of a previous import.`)
+
+	importExample = templates.Examples(`
+		# Import a CSV catalog and wait for the result
+		iamctl item import catalog.csv --wait
+
+		# Import a JSON lines catalog in the background
+		iamctl item import catalog.jsonl
+
+		# Show the progress of an import job
+		iamctl item import --job=12`)
+
+	importUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nFILE or --job is required for the import command",
+		importUsageStr,
+	)
+)
+
+// NewImportOptions returns an initialized ImportOptions instance.
+func NewImportOptions(ioStreams genericclioptions.IOStreams) *ImportOptions {
+	return &ImportOptions{
+		PrintFlags: newImportPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdImport returns new initialized instance of import sub command.
+func NewCmdImport(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewImportOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   importUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Import items from a CSV or JSON lines file",
+		TraverseChildren:      true,
+		Long:                  importLong,
+		Example:               importExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Format, "format", o.Format,
+		"Format of the file, one of: csv|jsonl. Detected from the file extension by default.")
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "Wait for the import to finish and show the rejected rows.")
+	cmd.Flags().Uint64Var(&o.JobID, "job", o.JobID, "Show the progress of the import job instead of importing a file.")
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ImportOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
+	switch {
+	case len(args) == 1 && o.JobID == 0:
+		o.File = args[0]
+	case len(args) == 0 && o.JobID != 0:
+	default:
+		return cmdutil.UsageErrorf(cmd, importUsageErrStr)
+	}
+
+	if o.File != "" && o.Format == "" {
+		o.Format = formatOf(o.File)
+	}
+
+	o.config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ImportOptions) Validate(cmd *cobra.Command, args []string) error {
+	if o.File != "" {
+		if err := validateFormat(cmd, o.Format); err != nil {
+			return err
+		}
+	}
+
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an import subcommand using the specified options.
+func (o *ImportOptions) Run(args []string) error {
+	if o.JobID != 0 {
+		job, err := o.getJob()
+		if err != nil {
+			return err
+		}
+
+		return o.printJob(job)
+	}
+
+	job, err := o.upload()
+	if err != nil {
+		return err
+	}
+
+	if !o.Wait {
+		if o.PrintFlags.OutputFormat != "" {
+			return o.PrintFlags.Print(&importJobResult{ImportJob: job}, o.Out)
+		}
+
+		fmt.Fprintf(o.Out, "import/%d started, run 'iamctl item import --job=%d' to follow it\n", job.ID, job.ID)
+
+		return nil
+	}
+
+	o.JobID = job.ID
+	for !job.Finished() {
+		if o.PrintFlags.OutputFormat == "" {
+			fmt.Fprintf(o.ErrOut, "import/%d %s: %d rows processed\n", job.ID, job.Status, job.ProcessedRows)
+		}
+
+		time.Sleep(importPollInterval)
+
+		if job, err = o.getJob(); err != nil {
+			return err
+		}
+	}
+
+	return o.printJob(job)
+}
+
+// upload sends the file to iam-apiserver and returns the started job.
+func (o *ImportOptions) upload() (*itemv1.ImportJob, error) {
+	f, err := os.Open(o.File)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	url := o.client.Post().AbsPath("/v2/itemImports").Param("format", o.Format).URL().String()
+
+	req, err := http.NewRequest(http.MethodPost, url, f)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cmdutil.DoRaw(o.config, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("import catalog: %s", data)
+	}
+
+	job := &itemv1.ImportJob{}
+
+	return job, json.Unmarshal(data, job)
+}
+
+func (o *ImportOptions) getJob() (*itemv1.ImportJob, error) {
+	job := &itemv1.ImportJob{}
+
+	err := o.client.Get().AbsPath("/v2/itemImports", strconv.FormatUint(o.JobID, 10)).
+		Do(context.TODO()).
+		Into(job)
+
+	return job, err
+}
+
+// printJob prints the progress of the job, followed by its rejected rows.
+func (o *ImportOptions) printJob(job *itemv1.ImportJob) error {
+	rowErrors := &itemv1.ImportRowErrorList{}
+	if job.FailedRows > 0 {
+		if err := o.client.Get().AbsPath("/v2/itemImports", strconv.FormatUint(job.ID, 10), "errors").
+			Do(context.TODO()).
+			Into(rowErrors); err != nil {
+			return err
+		}
+	}
+
+	if o.PrintFlags.OutputFormat != "" {
+		return o.PrintFlags.Print(&importJobResult{job, rowErrors.Items}, o.Out)
+	}
+
+	fmt.Fprintf(o.Out, "import/%d %s: %d rows processed, %d created, %d updated, %d failed\n",
+		job.ID, job.Status, job.ProcessedRows, job.CreatedRows, job.UpdatedRows, job.FailedRows)
+
+	if job.Message != "" {
+		fmt.Fprintln(o.Out, job.Message)
+	}
+
+	if len(rowErrors.Items) == 0 {
+		return nil
+	}
+
+	printer := &printers.TablePrinter{Columns: rowErrorColumns, NoHeaders: o.PrintFlags.NoHeaders}
+
+	return printer.PrintObj(rowErrors, o.Out)
+}
+
+// importJobResult is an import job printed together with its rejected rows.
+type importJobResult struct {
+	*itemv1.ImportJob `json:",inline"`
+
+	Errors []*itemv1.ImportRowError `json:"errors,omitempty"`
+}
+
+// importColumns are the columns of the import jobs printed by -o wide.
+var importColumns = []printers.Column{
+	{Header: "ID", Value: func(obj interface{}) string {
+		return strconv.FormatUint(obj.(*importJobResult).ID, 10)
+	}},
+	{Header: "Status", Value: func(obj interface{}) string {
+		return obj.(*importJobResult).Status
+	}},
+	{Header: "Processed", Value: func(obj interface{}) string {
+		return strconv.FormatInt(obj.(*importJobResult).ProcessedRows, 10)
+	}},
+	{Header: "Created", Value: func(obj interface{}) string {
+		return strconv.FormatInt(obj.(*importJobResult).CreatedRows, 10)
+	}},
+	{Header: "Updated", Value: func(obj interface{}) string {
+		return strconv.FormatInt(obj.(*importJobResult).UpdatedRows, 10)
+	}},
+	{Header: "Failed", Value: func(obj interface{}) string {
+		return strconv.FormatInt(obj.(*importJobResult).FailedRows, 10)
+	}},
+	{Header: "Message", Wide: true, Value: func(obj interface{}) string {
+		return obj.(*importJobResult).Message
+	}},
+}
+
+// rowErrorColumns are the columns of the rejected rows.
+var rowErrorColumns = []printers.Column{
+	{Header: "Row", Value: func(obj interface{}) string {
+		return strconv.FormatInt(obj.(*itemv1.ImportRowError).Row, 10)
+	}},
+	{Header: "Key", Value: func(obj interface{}) string {
+		return obj.(*itemv1.ImportRowError).Key
+	}},
+	{Header: "Error", Value: func(obj interface{}) string {
+		return obj.(*itemv1.ImportRowError).Message
+	}},
+}
+
+// newImportPrintFlags returns the print flags of the import command, the jobs
+// are printed with their rejected rows.
+func newImportPrintFlags() *printers.PrintFlags {
+	return printers.NewPrintFlags("import", importColumns...)
+}
+
+// formatOf returns the catalog format of a file from its extension.
+func formatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return itemv1.CatalogFormatCSV
+	case ".jsonl", ".ndjson":
+		return itemv1.CatalogFormatJSONL
+	default:
+		return ""
+	}
+}
+
+// validateFormat makes sure the catalog format is supported.
+func validateFormat(cmd *cobra.Command, format string) error {
+	switch format {
+	case itemv1.CatalogFormatCSV, itemv1.CatalogFormatJSONL:
+		return nil
+	case "":
+		return cmdutil.UsageErrorf(cmd, "can not detect the format of the file, use --format")
+	default:
+		return cmdutil.UsageErrorf(cmd, "unsupported format %q, expected one of: csv|jsonl", format)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	defaltLimit = 1000
+)
+
+// ListOptions is an options struct to support list subcommands.
+type ListOptions struct {
+	Offset        int64
+	Limit         int64
+	FieldSelector string
+
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	listLong = templates.LongDesc(`Display the active item resources.
+
+The items can be filtered by --field-selector, the supported fields are asin, sku,
+brand, title, product_group and product_type. The title matches any item whose
+title contains the value.`)
+
+	listExample = templates.Examples(`
+		# List all items
+		iamctl item list
+
+		# List the shoes of a brand
+		iamctl item list --field-selector=brand=acme,product_type=SHOES
+
+		# List items with limit and offset in JSON
+		iamctl item list --offset=0 --limit=5 -o json
+
+		# Watch the changes of the items as JSON events
+		iamctl item list --watch-only -o json`)
+)
+
+// NewListOptions returns an initialized ListOptions instance.
+func NewListOptions(ioStreams genericclioptions.IOStreams) *ListOptions {
+	return &ListOptions{
+		IOStreams:  ioStreams,
+		Offset:     0,
+		Limit:      defaltLimit,
+		PrintFlags: newItemPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+	}
+}
+
+// NewCmdList returns new initialized instance of list sub command.
+func NewCmdList(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewListOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "list",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Display all item resources",
+		TraverseChildren:      true,
+		Long:                  listLong,
+		Example:               listExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().Int64Var(&o.Offset, "offset", o.Offset, "Specify the offset of the first row to be returned.")
+	cmd.Flags().Int64VarP(&o.Limit, "limit", "l", o.Limit, "Specify the amount records to be returned.")
+	cmd.Flags().StringVar(&o.FieldSelector, "field-selector", o.FieldSelector,
+		"Selector to filter on, supports '=' and '==' (e.g. --field-selector brand=acme,product_type=SHOES).")
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ListOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	o.client, err = f.RESTClient()
+	if err != nil {
+		return err
+	}
+
+	return o.WatchFlags.Complete(f)
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes a list subcommand using the specified options.
+func (o *ListOptions) Run(args []string) error {
+	watcher, err := o.WatchFlags.Start("item", "")
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
+
+	return watcher.Print(o.PrintFlags, o.Out)
+}
+
+// printCurrent prints the items, unless only the changes are watched.
+func (o *ListOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
+	}
+
+	items := &itemv1.ItemList{}
+
+	req := o.client.Get().AbsPath("/v2/items").
+		Param("offset", fmt.Sprint(o.Offset)).
+		Param("limit", fmt.Sprint(o.Limit))
+	if o.FieldSelector != "" {
+		req = req.Param("fieldSelector", o.FieldSelector)
+	}
+
+	if err := req.Do(context.TODO()).Into(items); err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Print(items, o.Out)
+}
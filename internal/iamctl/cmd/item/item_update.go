@@ -0,0 +1,150 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package item
+
+import (
+	"context"
+	"fmt"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	itemv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/item/v1/model"
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	updateUsageStr = "update ITEM_ID"
+)
+
+// UpdateOptions is an options struct to support update subcommands.
+type UpdateOptions struct {
+	ID           uint64
+	Brand        string
+	Title        string
+	ProductGroup string
+	ProductType  string
+
+	PrintFlags *printers.PrintFlags
+
+	changed func(flag string) bool
+	client  *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	updateLong = templates.LongDesc(`Update an item resource.
+
+Can only update brand, title, product group and product type, the fields whose
+flag is not given are left untouched.`)
+
+	updateExample = templates.Examples(`
+		# Update the title of an item
+		iamctl item update 1735371396125511680 --title="Acme trail running shoes"`)
+
+	updateUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nITEM_ID is required arguments for the update command",
+		updateUsageStr,
+	)
+)
+
+// NewUpdateOptions returns an initialized UpdateOptions instance.
+func NewUpdateOptions(ioStreams genericclioptions.IOStreams) *UpdateOptions {
+	return &UpdateOptions{
+		PrintFlags: newItemPrintFlags(),
+		IOStreams:  ioStreams,
+	}
+}
+
+// NewCmdUpdate returns new initialized instance of update sub command.
+func NewCmdUpdate(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewUpdateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   updateUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Update an item resource",
+		TraverseChildren:      true,
+		Long:                  updateLong,
+		Example:               updateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Brand, "brand", o.Brand, "The brand of the item.")
+	cmd.Flags().StringVar(&o.Title, "title", o.Title, "The title of the item.")
+	cmd.Flags().StringVar(&o.ProductGroup, "product-group", o.ProductGroup, "The product group of the item.")
+	cmd.Flags().StringVar(&o.ProductType, "product-type", o.ProductType, "The product type of the item.")
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *UpdateOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, updateUsageErrStr)
+	}
+
+	o.ID, err = parseID(cmd, "item", args[0])
+	if err != nil {
+		return err
+	}
+
+	o.changed = cmd.Flags().Changed
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *UpdateOptions) Validate(cmd *cobra.Command, args []string) error {
+	return validatePrintFlags(cmd, o.PrintFlags)
+}
+
+// Run executes an update subcommand using the specified options.
+func (o *UpdateOptions) Run(args []string) error {
+	// iam-apiserver replaces all the updatable fields, so the item is read first
+	// to keep the fields which are not given.
+	item, err := getItem(o.client, o.ID)
+	if err != nil {
+		return err
+	}
+
+	if o.changed("brand") {
+		item.Brand = o.Brand
+	}
+	if o.changed("title") {
+		item.Title = o.Title
+	}
+	if o.changed("product-group") {
+		item.ProductGroup = o.ProductGroup
+	}
+	if o.changed("product-type") {
+		item.ProductType = o.ProductType
+	}
+
+	var ret itemv1.Item
+	if err := o.client.Put().AbsPath("/v2/items", fmt.Sprint(o.ID)).Body(*item).Do(context.TODO()).Into(&ret); err != nil {
+		return err
+	}
+
+	if o.PrintFlags.OutputFormat != "" {
+		return o.PrintFlags.Print(&ret, o.Out)
+	}
+
+	fmt.Fprintf(o.Out, "item/%d updated\n", ret.ID)
+
+	return nil
+}
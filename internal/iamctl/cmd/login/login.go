@@ -0,0 +1,190 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package login logs in to the iam-apiserver of the context in use.
+package login
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// LoginOptions is an options struct to support login commands.
+type LoginOptions struct {
+	Username string
+	Password string
+	Code     string
+
+	context string
+	client  *genericclioptions.TokenClient
+	server  string
+	reader  *bufio.Reader
+	genericclioptions.IOStreams
+}
+
+var (
+	loginLong = templates.LongDesc(`
+		Log in to the iam-apiserver of the context in use.
+
+		The token returned is cached in $HOME/.iam/cache/tokens for the context, the following
+		commands authenticate with it and refresh it before it expires, until it can no longer be
+		refreshed. The username and the password are read from the flags, else from the context,
+		else from the standard input. Users with a second factor are asked for its code.`)
+
+	loginExample = templates.Examples(`
+		# Log in to the iam-apiserver of the current context
+		iamctl login
+
+		# Log in to the iam-apiserver of the prod context as admin
+		iamctl login --context=prod --username=admin`)
+)
+
+// NewLoginOptions returns an initialized LoginOptions instance.
+func NewLoginOptions(ioStreams genericclioptions.IOStreams) *LoginOptions {
+	return &LoginOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdLogin returns new initialized instance of login command.
+func NewCmdLogin(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewLoginOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "login [--username=name] [--code=code]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Log in to the iam-apiserver of the context in use",
+		TraverseChildren:      true,
+		Long:                  loginLong,
+		Example:               loginExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Username, "username", o.Username, "The name of the user to log in.")
+	cmd.Flags().StringVar(&o.Password, "password", o.Password, "The password of the user to log in.")
+	cmd.Flags().StringVar(&o.Code, "code", o.Code, "The code of the second factor of the user.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *LoginOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	o.context = genericclioptions.CurrentContextName()
+	if o.context == "" {
+		o.context = genericclioptions.DefaultContextName
+	}
+
+	// the cached token is replaced by the login, it must not be refreshed
+	// while the client is configured.
+	if err := genericclioptions.DeleteToken(o.context); err != nil {
+		return err
+	}
+
+	config, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.server = config.Host
+	o.client, err = genericclioptions.NewTokenClient(config)
+	if err != nil {
+		return err
+	}
+
+	o.reader = bufio.NewReader(o.In)
+
+	if o.Username == "" {
+		o.Username = config.Username
+	}
+
+	if o.Username == "" {
+		if o.Username, err = o.prompt("Username: "); err != nil {
+			return err
+		}
+	}
+
+	if o.Password == "" && o.Username == config.Username {
+		o.Password = config.Password
+	}
+
+	if o.Password == "" {
+		if o.Password, err = o.prompt("Password: "); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *LoginOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	if o.Username == "" || o.Password == "" {
+		return cmdutil.UsageErrorf(cmd, "the username and the password are required")
+	}
+
+	return nil
+}
+
+// Run executes a login command using the specified options.
+func (o *LoginOptions) Run(args []string) error {
+	result, err := o.client.Login(o.Username, o.Password)
+	if err != nil {
+		return err
+	}
+
+	token := &result.Token
+	if result.MFARequired {
+		if result.EnrollRequired {
+			return fmt.Errorf("user %s must enroll a second factor before logging in", o.Username)
+		}
+
+		if o.Code == "" {
+			if o.Code, err = o.prompt("Code: "); err != nil {
+				return err
+			}
+		}
+
+		if token, err = o.client.SecondFactor(result.MFAToken, o.Code); err != nil {
+			return err
+		}
+	}
+
+	token.Server = o.server
+	if err := genericclioptions.SaveToken(o.context, token); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Logged in to %s as %s, the token of context %q expires at %s.\n",
+		o.server, o.Username, o.context, token.Expire.Local().Format("2006-01-02 15:04:05"))
+
+	return nil
+}
+
+// prompt reads a line of the standard input, it is echoed as iamctl does not
+// depend on a terminal library.
+func (o *LoginOptions) prompt(label string) (string, error) {
+	fmt.Fprint(o.ErrOut, label)
+
+	line, err := o.reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read %s%w", strings.ToLower(label), err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 
@@ -27,11 +28,18 @@ const (
 	newUsageStr = "new CMD_NAME | CMD_NAME CMD_DESCRIPTION"
 )
 
+// pluginName matches the names of the plugins, the dashes separate the subcommands.
+var pluginName = regexp.MustCompile(`^[a-z0-9]+([-_][a-z0-9]+)*$`)
+
 var (
 	newLong = templates.LongDesc(`Used to generate demo command source code file.
 
 Can use this command generate a command template file, and do some modify based on your needs.
-This can improve your R&D efficiency.`)
+This can improve your R&D efficiency.
+
+With --plugin, the skeleton of a plugin is generated instead: a standalone program which lists
+the users with the server and the token passed by iamctl. Build it as iamctl-CMD_NAME and put it
+on PATH to run it as 'iamctl CMD_NAME', see 'iamctl plugin -h'.`)
 
 	newExample = templates.Examples(`
 		# Create a default 'test' command file without a description
@@ -44,7 +52,10 @@ This can improve your R&D efficiency.`)
 		iamctl new test "This is a test command"
 
 		# Create command 'test' with two subcommands
-		iamctl new -g test "This is a test command with two subcommands"`)
+		iamctl new -g test "This is a test command with two subcommands"
+
+		# Create the plugin 'catalog-qa', run as 'iamctl catalog qa', in ./iamctl-catalog-qa/
+		iamctl new --plugin catalog-qa "Check the quality of the catalog"`)
 
 	newUsageErrStr = fmt.Sprintf(
 		"expected '%s'.\nat least CMD_NAME is a required argument for the new command",
@@ -490,18 +501,174 @@ func (o *SubCmd2Options) Run(args []string) error {
 		o.StringOption, o.StringSliceOption, o.IntOption, o.BoolOption)
 	return nil
 }
+`
+
+	// the plugins only use the standard library, they are built on their own.
+	pluginModTemplate = `module iamctl-{{.CommandName}}
+
+go 1.18
+`
+
+	pluginTemplate = `// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// iamctl-{{.CommandName}} is an iamctl plugin, run as 'iamctl {{.PluginCommand}}'.
+//
+// iamctl passes the context in use in the environment: IAMCTL_SERVER is the address of
+// iam-apiserver, IAMCTL_TOKEN a bearer token, IAMCTL_CERTIFICATE_AUTHORITY the file of the
+// certificate authority and IAMCTL_INSECURE_SKIP_TLS_VERIFY is true when the certificate of
+// iam-apiserver is not verified.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+func main() {
+	flags := flag.NewFlagSet("iamctl {{.PluginCommand}}", flag.ExitOnError)
+	limit := flags.Int("limit", 10, "Number of users to list.")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "{{.CommandDescription}}.\n\nUsage:\n  iamctl {{.PluginCommand}} [options]\n\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	if err := run(*limit); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(limit int) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	var users struct {
+		TotalCount int64 {{.Dot}}json:"totalCount"{{.Dot}}
+		Items      []struct {
+			Metadata struct {
+				Name string {{.Dot}}json:"name"{{.Dot}}
+			} {{.Dot}}json:"metadata"{{.Dot}}
+			Email string {{.Dot}}json:"email"{{.Dot}}
+		} {{.Dot}}json:"items"{{.Dot}}
+	}
+
+	if err := c.get("/v1/users", url.Values{"limit": {strconv.Itoa(limit)}}, &users); err != nil {
+		return err
+	}
+
+	for _, user := range users.Items {
+		fmt.Printf("%s\t%s\n", user.Metadata.Name, user.Email)
+	}
+
+	fmt.Printf("%d of %d users\n", len(users.Items), users.TotalCount)
+
+	return nil
+}
+
+// client calls iam-apiserver with the server and the credentials passed by iamctl.
+type client struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+func newClient() (*client, error) {
+	server := os.Getenv("IAMCTL_SERVER")
+	if server == "" {
+		return nil, errors.New("IAMCTL_SERVER is not set, run the plugin as 'iamctl {{.PluginCommand}}' with a server configured")
+	}
+
+	tlsConfig := &tls.Config{
+		// nolint: gosec // set by the context in use
+		InsecureSkipVerify: os.Getenv("IAMCTL_INSECURE_SKIP_TLS_VERIFY") == "true",
+	}
+
+	if file := os.Getenv("IAMCTL_CERTIFICATE_AUTHORITY"); file != "" {
+		ca, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in %s", file)
+		}
+	}
+
+	return &client{
+		server: server,
+		token:  os.Getenv("IAMCTL_TOKEN"),
+		http: &http.Client{
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+			Timeout:   30 * time.Second,
+		},
+	}, nil
+}
+
+// get decodes the response of iam-apiserver to a GET request into obj.
+func (c *client) get(path string, query url.Values, obj interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.server+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string {{.Dot}}json:"message"{{.Dot}}
+		}
+		if json.Unmarshal(data, &failure) != nil || failure.Message == "" {
+			failure.Message = resp.Status
+		}
+
+		return fmt.Errorf("GET %s: %s", path, failure.Message)
+	}
+
+	return json.Unmarshal(data, obj)
+}
 `
 )
 
 // NewOptions is an options struct to support 'new' sub command.
 type NewOptions struct {
 	Group  bool
+	Plugin bool
 	Outdir string
 
 	// command template options, will render to command template
 	CommandName         string
 	CommandDescription  string
 	CommandFunctionName string
+	PluginCommand       string
 	Dot                 string
 
 	genericclioptions.IOStreams
@@ -538,6 +705,7 @@ func NewCmdNew(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.
 	}
 
 	cmd.Flags().BoolVarP(&o.Group, "group", "g", o.Group, "Generate two subcommands.")
+	cmd.Flags().BoolVar(&o.Plugin, "plugin", o.Plugin, "Generate the skeleton of a plugin, in the iamctl-CMD_NAME directory.")
 	cmd.Flags().StringVarP(&o.Outdir, "outdir", "d", o.Outdir, "Where to create demo command files.")
 
 	return cmd
@@ -555,17 +723,35 @@ func (o *NewOptions) Complete(cmd *cobra.Command, args []string) error {
 	}
 
 	o.CommandFunctionName = cases.Title(language.English).String(o.CommandName)
+	o.PluginCommand = strings.ReplaceAll(strings.ReplaceAll(o.CommandName, "-", " "), "_", "-")
 
 	return nil
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *NewOptions) Validate(cmd *cobra.Command) error {
+	if !o.Plugin {
+		return nil
+	}
+
+	if o.Group {
+		return cmdutil.UsageErrorf(cmd, "--group and --plugin can not be used together")
+	}
+
+	if !pluginName.MatchString(o.CommandName) {
+		return cmdutil.UsageErrorf(cmd,
+			"invalid plugin name %q, expected lowercase letters, digits, dashes and underscores", o.CommandName)
+	}
+
 	return nil
 }
 
 // Run executes a new sub command using the specified options.
 func (o *NewOptions) Run(args []string) error {
+	if o.Plugin {
+		return o.CreatePlugin()
+	}
+
 	if o.Group {
 		return o.CreateCommandWithSubCommands()
 	}
@@ -578,6 +764,24 @@ func (o *NewOptions) CreateCommand() error {
 	return o.GenerateGoCode(o.CommandName+".go", cmdTemplate)
 }
 
+// CreatePlugin creates the skeleton of a plugin in its own directory, as the
+// main package of the plugin executable.
+func (o *NewOptions) CreatePlugin() error {
+	o.Outdir = filepath.Join(o.Outdir, "iamctl-"+o.CommandName)
+	if err := o.GenerateGoCode("go.mod", pluginModTemplate); err != nil {
+		return err
+	}
+
+	if err := o.GenerateGoCode("main.go", pluginTemplate); err != nil {
+		return err
+	}
+
+	fmt.Printf("Build it with 'cd %s && go build' and put iamctl-%s on PATH to run 'iamctl %s'\n",
+		o.Outdir, o.CommandName, o.PluginCommand)
+
+	return nil
+}
+
 // CreateCommandWithSubCommands create sub commands with options.
 func (o *NewOptions) CreateCommandWithSubCommands() error {
 	if err := o.GenerateGoCode(o.CommandName+".go", maincmdTemplate); err != nil {
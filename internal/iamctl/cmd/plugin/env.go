@@ -0,0 +1,118 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// The environment variables set for the plugins.
+const (
+	// EnvContext is the name of the context in use.
+	EnvContext = "IAMCTL_CONTEXT"
+	// EnvServer is the address of iam-apiserver, e.g. https://iam.example.com:8443.
+	EnvServer = "IAMCTL_SERVER"
+	// EnvToken is a bearer token for iam-apiserver, it is not set for the
+	// contexts authenticated with a username and a password.
+	EnvToken = "IAMCTL_TOKEN"
+	// EnvCertificateAuthority is the file of the certificate authority of iam-apiserver.
+	EnvCertificateAuthority = "IAMCTL_CERTIFICATE_AUTHORITY"
+	// EnvInsecureSkipTLSVerify is true when the certificate of iam-apiserver is not verified.
+	EnvInsecureSkipTLSVerify = "IAMCTL_INSECURE_SKIP_TLS_VERIFY"
+	// EnvCaller is the path of the iamctl executable which runs the plugin.
+	EnvCaller = "IAMCTL_CALLER"
+)
+
+// tokenTTL is the lifetime of the tokens signed for the plugins of the contexts
+// authenticated with a secret.
+const tokenTTL = time.Hour
+
+// Environ returns the environment of the plugins: the environment of iamctl,
+// with the server and the credentials of the context in use. The plugin still
+// runs, without them, when the iamconfig can not be loaded.
+func Environ(f cmdutil.Factory, errOut io.Writer) []string {
+	env := map[string]string{}
+
+	env[EnvContext] = genericclioptions.CurrentContextName()
+	if env[EnvContext] == "" {
+		env[EnvContext] = genericclioptions.DefaultContextName
+	}
+
+	if caller, err := os.Executable(); err == nil {
+		env[EnvCaller] = caller
+	}
+
+	if config, err := f.ToRESTConfig(); err != nil {
+		fmt.Fprintf(errOut, "warning: the plugin runs without credentials: %v\n", err)
+	} else {
+		setServer(env, config)
+	}
+
+	return mergeEnviron(os.Environ(), env)
+}
+
+// setServer sets the address and the credentials of the config.
+func setServer(env map[string]string, config *restclient.Config) {
+	if config.Host == "" {
+		return
+	}
+
+	env[EnvServer] = config.Host
+
+	switch {
+	case config.BearerToken != "":
+		env[EnvToken] = config.BearerToken
+	case config.SecretID != "" && config.SecretKey != "":
+		env[EnvToken] = cmdutil.SignToken(config.SecretID, config.SecretKey, tokenTTL)
+	}
+
+	if config.CAFile != "" {
+		env[EnvCertificateAuthority] = config.CAFile
+	}
+
+	if config.Insecure {
+		env[EnvInsecureSkipTLSVerify] = "true"
+	}
+}
+
+// pluginVars are the variables set by iamctl, in the order they are set.
+var pluginVars = []string{
+	EnvContext, EnvServer, EnvToken, EnvCertificateAuthority, EnvInsecureSkipTLSVerify, EnvCaller,
+}
+
+// mergeEnviron returns environ with the variables of env. All the variables
+// set by iamctl are dropped from environ first, so that a plugin run by
+// another plugin does not read stale credentials.
+func mergeEnviron(environ []string, env map[string]string) []string {
+	dropped := make(map[string]bool, len(pluginVars))
+	for _, name := range pluginVars {
+		dropped[name] = true
+	}
+
+	merged := make([]string, 0, len(environ)+len(env))
+
+	for _, kv := range environ {
+		if !dropped[strings.SplitN(kv, "=", 2)[0]] {
+			merged = append(merged, kv)
+		}
+	}
+
+	for _, name := range pluginVars {
+		if value, ok := env[name]; ok {
+			merged = append(merged, name+"="+value)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// ValidPrefixes are the prefixes of the names of the plugin executables.
+var ValidPrefixes = []string{"iamctl"}
+
+// Handler finds and runs the plugins.
+type Handler interface {
+	// Lookup returns the path of the plugin executable named filename, without
+	// its prefix.
+	Lookup(filename string) (string, bool)
+	// Execute runs the plugin executable with the arguments and the
+	// environment, it only returns when the plugin can not be run.
+	Execute(executablePath string, cmdArgs, environment []string) error
+}
+
+// DefaultHandler finds the plugins on PATH.
+type DefaultHandler struct {
+	ValidPrefixes []string
+}
+
+var _ Handler = (*DefaultHandler)(nil)
+
+// NewDefaultHandler returns a handler of the plugins named with one of the prefixes.
+func NewDefaultHandler(validPrefixes []string) *DefaultHandler {
+	return &DefaultHandler{ValidPrefixes: validPrefixes}
+}
+
+// Lookup implements Handler.
+func (h *DefaultHandler) Lookup(filename string) (string, bool) {
+	for _, prefix := range h.ValidPrefixes {
+		path, err := exec.LookPath(prefix + "-" + filename)
+		if err == nil && path != "" {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+// Execute implements Handler. The plugin replaces iamctl, except on windows
+// which can not replace a process: the plugin runs as a child and iamctl exits
+// with its status.
+func (h *DefaultHandler) Execute(executablePath string, cmdArgs, environment []string) error {
+	if runtime.GOOS != "windows" {
+		return syscall.Exec(executablePath, append([]string{executablePath}, cmdArgs...), environment)
+	}
+
+	cmd := exec.Command(executablePath, cmdArgs...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = environment
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		return err
+	}
+
+	os.Exit(0)
+
+	return nil
+}
+
+// HandleCommand runs the plugin of the longest command found in cmdArgs, e.g.
+// iamctl-catalog-qa for 'iamctl catalog qa --strict', with the remaining
+// arguments. The dashes of the command names are replaced by underscores in
+// the executable names. It returns nil when there is no such plugin, and does
+// not return when the plugin runs.
+func HandleCommand(handler Handler, cmdArgs []string, environ func() []string) error {
+	var names []string
+
+	for _, arg := range cmdArgs {
+		// the flags and the arguments after them are passed to the plugin.
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		names = append(names, strings.ReplaceAll(arg, "-", "_"))
+	}
+
+	for len(names) > 0 {
+		path, found := handler.Lookup(strings.Join(names, "-"))
+		if found {
+			return handler.Execute(path, cmdArgs[len(names):], environ())
+		}
+
+		names = names[:len(names)-1]
+	}
+
+	return nil
+}
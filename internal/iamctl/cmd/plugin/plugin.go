@@ -0,0 +1,43 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package plugin implements the iamctl plugins: the executables named
+// iamctl-<name> on PATH, which are run as 'iamctl <name>'.
+package plugin
+
+import (
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+var pluginLong = templates.LongDesc(`
+	Provides utilities for interacting with plugins.
+
+	Plugins provide extended functionality that is not part of the major command-line distribution.
+	A plugin is an executable on PATH whose name starts with iamctl-: iamctl-catalog-qa runs as
+	'iamctl catalog qa', with the remaining arguments. Dashes in the command names are underscores
+	in the executable names, iamctl-catalog_qa runs as 'iamctl catalog-qa'. The built-in commands
+	can not be overridden by plugins.
+
+	Plugins receive the server and the credentials of the context in use in the IAMCTL_SERVER,
+	IAMCTL_TOKEN, IAMCTL_CONTEXT, IAMCTL_CERTIFICATE_AUTHORITY and IAMCTL_INSECURE_SKIP_TLS_VERIFY
+	environment variables. Run 'iamctl new --plugin NAME' to generate the skeleton of a plugin.`)
+
+// NewCmdPlugin returns new initialized instance of 'plugin' sub command.
+func NewCmdPlugin(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "plugin SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Provides utilities for interacting with plugins",
+		Long:                  pluginLong,
+		Run:                   cmdutil.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdPluginList(f, ioStreams))
+
+	return cmd
+}
@@ -0,0 +1,245 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// ListOptions is an options struct to support list subcommands.
+type ListOptions struct {
+	NameOnly bool
+
+	verifier    *verifier
+	pluginPaths []string
+	genericclioptions.IOStreams
+}
+
+var (
+	listLong = templates.LongDesc(`
+		List all available plugin files on a user's PATH.
+
+		Available plugin files are those that are:
+		- executable
+		- anywhere on the user's PATH
+		- begin with "iamctl-"
+
+		The plugins which can not be run are reported with a warning: those which are not
+		executable, those hidden by a plugin of the same name earlier on PATH, and those hidden
+		by a built-in command.`)
+
+	listExample = templates.Examples(`
+		# List all available plugins
+		iamctl plugin list
+
+		# List the paths of the plugins only
+		iamctl plugin list --name-only`)
+)
+
+// NewListOptions returns an initialized ListOptions instance.
+func NewListOptions(ioStreams genericclioptions.IOStreams) *ListOptions {
+	return &ListOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdPluginList returns new initialized instance of list sub command.
+func NewCmdPluginList(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewListOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "list",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"ls"},
+		Short:                 "List all visible plugin executables on a user's PATH",
+		TraverseChildren:      true,
+		Long:                  listLong,
+		Example:               listExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().BoolVar(&o.NameOnly, "name-only", o.NameOnly,
+		"If true, display only the binary name of each plugin, rather than its full path.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *ListOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	o.verifier = &verifier{root: cmd.Root(), seen: map[string]string{}}
+	o.pluginPaths = filepath.SplitList(os.Getenv("PATH"))
+
+	return nil
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmdutil.UsageErrorf(cmd, "unexpected arguments: %v", args)
+	}
+
+	return nil
+}
+
+// Run executes a list subcommand using the specified options.
+func (o *ListOptions) Run(args []string) error {
+	var plugins []string
+
+	warnings := 0
+
+	for _, dir := range uniquePaths(o.pluginPaths) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			// the missing and unreadable directories of PATH are ignored, as by the shells.
+			continue
+		}
+
+		for _, file := range files {
+			if file.IsDir() || !hasValidPrefix(file.Name()) {
+				continue
+			}
+
+			if len(plugins) == 0 {
+				fmt.Fprintf(o.Out, "The following compatible plugins are available:\n\n")
+			}
+
+			path := filepath.Join(dir, file.Name())
+			plugins = append(plugins, path)
+
+			if o.NameOnly {
+				fmt.Fprintln(o.Out, file.Name())
+			} else {
+				fmt.Fprintln(o.Out, path)
+			}
+
+			// the plugins are often links, their targets are verified.
+			info, err := os.Stat(path)
+			if err != nil {
+				info = file
+			}
+
+			for _, warning := range o.verifier.verify(path, info) {
+				fmt.Fprintf(o.ErrOut, "  - warning: %s\n", warning)
+				warnings++
+			}
+		}
+	}
+
+	if len(plugins) == 0 {
+		return fmt.Errorf("unable to find any iamctl plugins in your PATH")
+	}
+
+	switch warnings {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("one plugin warning was found")
+	default:
+		return fmt.Errorf("%d plugin warnings were found", warnings)
+	}
+}
+
+// verifier reports the plugins which can not be run.
+type verifier struct {
+	root *cobra.Command
+	// seen are the paths of the plugins already listed, by command.
+	seen map[string]string
+}
+
+// verify returns the warnings of the plugin at path, file describes the
+// executable the plugin resolves to.
+func (v *verifier) verify(path string, file os.FileInfo) []string {
+	var warnings []string
+
+	if !isExecutable(file) {
+		warnings = append(warnings, fmt.Sprintf("%s identified as an iamctl plugin, but it is not executable", path))
+	}
+
+	command := commandOf(filepath.Base(path))
+	if first, ok := v.seen[command]; ok {
+		warnings = append(warnings, fmt.Sprintf("%s is overshadowed by a similarly named plugin: %s", path, first))
+	} else {
+		v.seen[command] = path
+	}
+
+	// the plugins only run for the commands cobra does not find.
+	if cmd, _, err := v.root.Find(strings.Split(command, " ")); err == nil && cmd != v.root {
+		warnings = append(warnings, fmt.Sprintf("%s is overshadowed by the built-in command: iamctl %s",
+			path, strings.TrimPrefix(cmd.CommandPath(), v.root.Name()+" ")))
+	}
+
+	return warnings
+}
+
+// commandOf returns the command run by a plugin executable, e.g. 'catalog qa'
+// for iamctl-catalog-qa.
+func commandOf(filename string) string {
+	if runtime.GOOS == "windows" {
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+
+	for _, prefix := range ValidPrefixes {
+		filename = strings.TrimPrefix(filename, prefix+"-")
+	}
+
+	return strings.ReplaceAll(strings.ReplaceAll(filename, "-", " "), "_", "-")
+}
+
+func hasValidPrefix(filename string) bool {
+	for _, prefix := range ValidPrefixes {
+		if strings.HasPrefix(filename, prefix+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isExecutable(file os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".bat", ".cmd", ".com", ".exe", ".ps1":
+			return true
+		}
+
+		return false
+	}
+
+	return file.Mode()&0o111 != 0
+}
+
+// uniquePaths returns the directories of PATH without the duplicates.
+func uniquePaths(paths []string) []string {
+	seen := map[string]bool{}
+
+	var unique []string
+
+	for _, path := range paths {
+		if path == "" || seen[path] {
+			continue
+		}
+
+		seen[path] = true
+		unique = append(unique, path)
+	}
+
+	return unique
+}
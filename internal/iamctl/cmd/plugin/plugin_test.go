@@ -0,0 +1,117 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package plugin
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// fakeHandler records the plugin it executes.
+type fakeHandler struct {
+	plugins  map[string]string
+	executed string
+	args     []string
+	env      []string
+}
+
+func (h *fakeHandler) Lookup(filename string) (string, bool) {
+	path, ok := h.plugins[filename]
+
+	return path, ok
+}
+
+func (h *fakeHandler) Execute(executablePath string, cmdArgs, environment []string) error {
+	h.executed, h.args, h.env = executablePath, cmdArgs, environment
+
+	return nil
+}
+
+func TestHandleCommand(t *testing.T) {
+	plugins := map[string]string{
+		"catalog":       "/bin/iamctl-catalog",
+		"catalog-qa":    "/bin/iamctl-catalog-qa",
+		"catalog_check": "/bin/iamctl-catalog_check",
+	}
+
+	tests := []struct {
+		args     []string
+		executed string
+		rest     []string
+	}{
+		{[]string{"catalog", "qa", "--strict", "x"}, "/bin/iamctl-catalog-qa", []string{"--strict", "x"}},
+		{[]string{"catalog", "fix", "x"}, "/bin/iamctl-catalog", []string{"fix", "x"}},
+		{[]string{"catalog", "--", "qa"}, "/bin/iamctl-catalog", []string{"--", "qa"}},
+		{[]string{"catalog-check"}, "/bin/iamctl-catalog_check", []string{}},
+		{[]string{"unknown", "qa"}, "", nil},
+	}
+	for _, tt := range tests {
+		handler := &fakeHandler{plugins: plugins}
+		err := HandleCommand(handler, tt.args, func() []string { return []string{"A=1"} })
+		assert.Nil(t, err)
+		assert.Equal(t, tt.executed, handler.executed, "%v", tt.args)
+		assert.Equal(t, tt.rest, handler.args, "%v", tt.args)
+	}
+}
+
+func TestMergeEnviron(t *testing.T) {
+	environ := []string{"HOME=/root", "IAMCTL_TOKEN=stale", "IAMCTL_SERVER=http://old", "IAMCTL_DEBUG=1"}
+	env := map[string]string{EnvContext: "dev", EnvServer: "https://iam.example.com"}
+
+	assert.Equal(t, []string{
+		"HOME=/root",
+		"IAMCTL_DEBUG=1",
+		"IAMCTL_CONTEXT=dev",
+		"IAMCTL_SERVER=https://iam.example.com",
+	}, mergeEnviron(environ, env))
+}
+
+func TestCommandOf(t *testing.T) {
+	assert.Equal(t, "catalog qa", commandOf("iamctl-catalog-qa"))
+	assert.Equal(t, "catalog-qa", commandOf("iamctl-catalog_qa"))
+}
+
+func TestPluginList(t *testing.T) {
+	dirs := []string{t.TempDir(), t.TempDir()}
+	writePlugin(t, dirs[0], "iamctl-catalog-qa", 0o755)
+	writePlugin(t, dirs[0], "iamctl-user", 0o755)
+	writePlugin(t, dirs[1], "iamctl-catalog-qa", 0o755)
+	writePlugin(t, dirs[1], "iamctl-noexec", 0o644)
+	writePlugin(t, dirs[1], "kubectl-foo", 0o755)
+
+	root := &cobra.Command{Use: "iamctl", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(&cobra.Command{Use: "user", Run: func(*cobra.Command, []string) {}})
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	o := NewListOptions(genericclioptions.IOStreams{Out: out, ErrOut: errOut})
+	o.verifier = &verifier{root: root, seen: map[string]string{}}
+	o.pluginPaths = append(dirs, dirs[0], filepath.Join(dirs[0], "missing"))
+
+	err := o.Run(nil)
+	assert.EqualError(t, err, "3 plugin warnings were found")
+	assert.Equal(t, 4, strings.Count(out.String(), "iamctl-"))
+	assert.NotContains(t, out.String(), "kubectl-foo")
+	assert.Contains(t, errOut.String(), "iamctl-user is overshadowed by the built-in command: iamctl user")
+	assert.Contains(t, errOut.String(), "overshadowed by a similarly named plugin: "+filepath.Join(dirs[0], "iamctl-catalog-qa"))
+	assert.Contains(t, errOut.String(), "iamctl-noexec identified as an iamctl plugin, but it is not executable")
+
+	o.pluginPaths = []string{filepath.Join(dirs[0], "missing")}
+	assert.EqualError(t, o.Run(nil), "unable to find any iamctl plugins in your PATH")
+}
+
+func writePlugin(t *testing.T, dir, name string, mode os.FileMode) {
+	t.Helper()
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode))
+}
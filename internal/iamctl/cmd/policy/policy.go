@@ -6,9 +6,15 @@
 package policy
 
 import (
+	"strings"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	"github.com/marmotedu/component-base/pkg/json"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
@@ -33,6 +39,49 @@ func NewCmdPolicy(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cob
 	cmd.AddCommand(NewCmdList(f, ioStreams))
 	cmd.AddCommand(NewCmdDelete(f, ioStreams))
 	cmd.AddCommand(NewCmdUpdate(f, ioStreams))
+	cmd.AddCommand(NewCmdTest(f, ioStreams))
+	cmd.AddCommand(NewCmdLint(f, ioStreams))
+	cmd.AddCommand(NewCmdNew(f, ioStreams))
 
 	return cmd
 }
+
+// policyColumns are the columns of the policy tables.
+var policyColumns = []printers.Column{
+	{Header: "Name", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Policy).Name
+	}},
+	{Header: "Effect", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Policy).Policy.Effect
+	}},
+	{Header: "Subjects", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return strings.Join(obj.(*v1.Policy).Policy.Subjects, ",")
+	}},
+	{Header: "Actions", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		return strings.Join(obj.(*v1.Policy).Policy.Actions, ",")
+	}},
+	{Header: "Resources", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return strings.Join(obj.(*v1.Policy).Policy.Resources, ",")
+	}},
+	{Header: "Conditions", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		conditions := obj.(*v1.Policy).Policy.Conditions
+		if len(conditions) == 0 {
+			return ""
+		}
+
+		data, _ := json.Marshal(conditions)
+
+		return string(data)
+	}},
+	{Header: "Description", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Policy).Policy.Description
+	}},
+	{Header: "Created", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Policy).CreatedAt.Format("2006-01-02 15:04:05")
+	}},
+}
+
+// newPrintFlags returns the print flags of the policy commands.
+func newPrintFlags() *printers.PrintFlags {
+	return printers.NewPrintFlags("policy", policyColumns...)
+}
@@ -17,6 +17,7 @@ import (
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -111,7 +112,7 @@ func (o *CreateOptions) Validate(cmd *cobra.Command, args []string) error {
 		return errs.ToAggregate()
 	}
 
-	return nil
+	return policylint.Validate(&o.Policy.Policy.DefaultPolicy)
 }
 
 // Run executes a create subcommand using the specified options.
@@ -5,18 +5,17 @@
 package policy
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 
-	"github.com/fatih/color"
-	"github.com/marmotedu/component-base/pkg/json"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/marmotedu-sdk-go/marmotedu/service/iam"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -28,6 +27,9 @@ const (
 type GetOptions struct {
 	Name string
 
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
 	iamclient iam.IamInterface
 	genericclioptions.IOStreams
 }
@@ -43,7 +45,9 @@ var (
 // NewGetOptions returns an initialized GetOptions instance.
 func NewGetOptions(ioStreams genericclioptions.IOStreams) *GetOptions {
 	return &GetOptions{
-		IOStreams: ioStreams,
+		PrintFlags: newPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
 	}
 }
 
@@ -67,6 +71,9 @@ func NewCmdGet(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.
 		SuggestFor: []string{},
 	}
 
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
 	return cmd
 }
 
@@ -85,29 +92,43 @@ func (o *GetOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []stri
 		return err
 	}
 
-	return nil
+	return o.WatchFlags.Complete(f)
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *GetOptions) Validate(cmd *cobra.Command, args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
 	return nil
 }
 
 // Run executes a get subcommand using the specified options.
 func (o *GetOptions) Run(args []string) error {
-	policy, err := o.iamclient.APIV1().Policies().Get(context.TODO(), o.Name, metav1.GetOptions{})
+	watcher, err := o.WatchFlags.Start("policy", o.Name)
 	if err != nil {
 		return err
 	}
+	defer watcher.Close()
 
-	bf := bytes.NewBuffer([]byte{})
-	jsonEncoder := json.NewEncoder(bf)
-	jsonEncoder.SetEscapeHTML(false)
-	if err := jsonEncoder.Encode(policy.Policy); err != nil {
+	if err := o.printCurrent(); err != nil {
 		return err
 	}
 
-	fmt.Fprintf(o.Out, "%12s %s\n", color.RedString(policy.Name+":"), bf.String())
+	return watcher.Print(o.PrintFlags, o.Out)
+}
 
-	return nil
+// printCurrent prints the policy, unless only the changes are watched.
+func (o *GetOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
+	}
+
+	policy, err := o.iamclient.APIV1().Policies().Get(context.TODO(), o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return o.PrintFlags.Print(policy, o.Out)
 }
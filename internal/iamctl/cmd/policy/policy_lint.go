@@ -0,0 +1,165 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	lintUsageStr = "lint POLICY_FILE..."
+)
+
+// LintOptions is an options struct to support lint subcommands.
+type LintOptions struct {
+	Files  []string
+	Strict bool
+
+	genericclioptions.IOStreams
+}
+
+var (
+	lintLong = templates.LongDesc(`
+		Check authorization policies without creating them.
+
+		Each POLICY_FILE holds a YAML or JSON policy or list of policies, either ladon policies
+		as accepted by 'iamctl policy create' or policy resources as printed by
+		'iamctl policy get -o yaml' and 'iamctl policy list -o yaml'. Use - to read the
+		standard input.
+
+		Errors are the problems iam-apiserver rejects a policy for: a missing or misspelled
+		effect, empty subjects, actions or resources, regular expressions which do not compile,
+		unknown condition types and invalid condition options. Warnings are valid policies which
+		are likely mistakes: patterns matched literally although they look like regular
+		expressions, duplicate patterns, and allow policies which may be overridden by a deny
+		policy of the same file. The command fails on errors, and on warnings with --strict.`)
+
+	lintExample = templates.Examples(`
+		# Lint the policies in policies.yaml
+		iamctl policy lint policies.yaml
+
+		# Lint a stored policy, failing on warnings as well
+		iamctl policy get foo -o yaml | iamctl policy lint - --strict`)
+
+	lintUsageErrStr = fmt.Sprintf("expected '%s'.\nPOLICY_FILE is required arguments for the lint command", lintUsageStr)
+)
+
+// NewLintOptions returns an initialized LintOptions instance.
+func NewLintOptions(ioStreams genericclioptions.IOStreams) *LintOptions {
+	return &LintOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdLint returns new initialized instance of lint sub command.
+func NewCmdLint(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewLintOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   lintUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Check authorization policies for errors and likely mistakes",
+		TraverseChildren:      true,
+		Long:                  lintLong,
+		Example:               lintExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().BoolVar(&o.Strict, "strict", o.Strict, "If true, fail on warnings as well as on errors.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *LintOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, lintUsageErrStr)
+	}
+
+	o.Files = args
+
+	return nil
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *LintOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Run executes a lint subcommand using the specified options.
+func (o *LintOptions) Run(args []string) error {
+	var policies, errs, warnings int
+
+	for _, file := range o.Files {
+		data, err := o.read(file)
+		if err != nil {
+			return err
+		}
+
+		decoded, findings, err := policylint.Decode(data)
+		if err != nil {
+			return fmt.Errorf("decode %s: %w", file, err)
+		}
+
+		policies += len(decoded)
+		findings = append(findings, policylint.Lint(decoded...)...)
+
+		for _, finding := range findings {
+			if finding.Severity == policylint.SeverityError {
+				errs++
+			} else {
+				warnings++
+			}
+
+			fmt.Fprintf(o.Out, "%s: %s: %s\n", file, finding.Severity, finding)
+		}
+	}
+
+	fmt.Fprintf(o.Out, "%d policies checked, %d errors, %d warnings\n", policies, errs, warnings)
+
+	if errs > 0 || (o.Strict && warnings > 0) {
+		return fmt.Errorf("the policies have %d errors and %d warnings", errs, warnings)
+	}
+
+	return nil
+}
+
+// read reads a YAML or JSON file, or the standard input for -, as JSON.
+func (o *LintOptions) read(file string) ([]byte, error) {
+	var data []byte
+	var err error
+
+	if file == "-" {
+		data, err = ioutil.ReadAll(o.In)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", file, err)
+	}
+
+	return data, nil
+}
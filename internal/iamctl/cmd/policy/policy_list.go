@@ -5,18 +5,16 @@
 package policy
 
 import (
-	"bytes"
 	"context"
-	"fmt"
 
-	"github.com/fatih/color"
-	"github.com/marmotedu/component-base/pkg/json"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/marmotedu-sdk-go/marmotedu/service/iam"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -29,6 +27,9 @@ type ListOptions struct {
 	Offset int64
 	Limit  int64
 
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
 	iamclient iam.IamInterface
 	genericclioptions.IOStreams
 }
@@ -38,14 +39,19 @@ var listExample = templates.Examples(`
 		iamctl poicy list
 
 		# Display all policy resources with offset and limit
-		iamctl policy list --offset=0 --limit=10`)
+		iamctl policy list --offset=0 --limit=10
+
+		# Display all policy resources, then watch their changes
+		iamctl policy list -w`)
 
 // NewListOptions returns an initialized ListOptions instance.
 func NewListOptions(ioStreams genericclioptions.IOStreams) *ListOptions {
 	return &ListOptions{
-		Offset:    0,
-		Limit:     defaultLimit,
-		IOStreams: ioStreams,
+		Offset:     0,
+		Limit:      defaultLimit,
+		PrintFlags: newPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
 	}
 }
 
@@ -69,9 +75,12 @@ func NewCmdList(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra
 		SuggestFor: []string{},
 	}
 
-	cmd.Flags().Int64VarP(&o.Offset, "offset", "o", o.Offset, "Specify the offset of the first row to be returned.")
+	cmd.Flags().Int64Var(&o.Offset, "offset", o.Offset, "Specify the offset of the first row to be returned.")
 	cmd.Flags().Int64VarP(&o.Limit, "limit", "l", o.Limit, "Specify the amount records to be returned.")
 
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
 	return cmd
 }
 
@@ -84,16 +93,39 @@ func (o *ListOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []str
 		return err
 	}
 
-	return nil
+	return o.WatchFlags.Complete(f)
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
 	return nil
 }
 
 // Run executes a list subcommand using the specified options.
 func (o *ListOptions) Run(args []string) error {
+	watcher, err := o.WatchFlags.Start("policy", "")
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
+
+	return watcher.Print(o.PrintFlags, o.Out)
+}
+
+// printCurrent prints the policies, unless only the changes are watched.
+func (o *ListOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
+	}
+
 	policies, err := o.iamclient.APIV1().Policies().List(context.TODO(), metav1.ListOptions{
 		Offset: &o.Offset,
 		Limit:  &o.Limit,
@@ -102,16 +134,5 @@ func (o *ListOptions) Run(args []string) error {
 		return err
 	}
 
-	for _, pol := range policies.Items {
-		bf := bytes.NewBuffer([]byte{})
-		jsonEncoder := json.NewEncoder(bf)
-		jsonEncoder.SetEscapeHTML(false)
-		if err := jsonEncoder.Encode(pol.Policy); err != nil {
-			return err
-		}
-
-		fmt.Fprintf(o.Out, "%12s %s\n", color.RedString(pol.Name+":"), bf.String())
-	}
-
-	return nil
+	return o.PrintFlags.Print(policies, o.Out)
 }
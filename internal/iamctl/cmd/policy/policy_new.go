@@ -0,0 +1,292 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ory/ladon"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	newUsageStr = "new POLICY_NAME"
+)
+
+// NewOptions is an options struct to support new subcommands.
+type NewOptions struct {
+	Name        string
+	Description string
+	Effect      string
+	Subjects    []string
+	Actions     []string
+	Resources   []string
+	Conditions  []string
+	Interactive bool
+
+	reader *bufio.Reader
+	genericclioptions.IOStreams
+}
+
+var (
+	newLong = templates.LongDesc(`
+		Scaffold an authorization policy and print it as JSON.
+
+		The policy is built from the flags, or prompted for with --interactive, where the flags
+		are the defaults of the prompts. Patterns are ladon patterns, regular expressions are
+		enclosed in <>. A condition is given as KEY=TYPE or KEY=TYPE:OPTIONS, OPTIONS being the
+		JSON options of the condition.
+
+		The policy is linted like 'iamctl policy lint' does: the command fails on errors and
+		prints the warnings to the standard error. The printed policy can be passed to
+		'iamctl policy create'.`)
+
+	newExample = templates.Examples(`
+		# Scaffold a policy allowing the editors to update the articles from the office network
+		iamctl policy new editors --subject 'users:<peter|ken>' --action '<create|update>' \
+		  --resource 'resources:articles:<.*>' \
+		  --condition 'remoteIP=CIDRListCondition:{"cidrs":["192.168.0.0/16"]}'
+
+		# Prompt for the policy and create it
+		iamctl policy create editors "$(iamctl policy new editors -i)"`)
+
+	newUsageErrStr = fmt.Sprintf("expected '%s'.\nPOLICY_NAME is required arguments for the new command", newUsageStr)
+)
+
+// NewNewOptions returns an initialized NewOptions instance.
+func NewNewOptions(ioStreams genericclioptions.IOStreams) *NewOptions {
+	return &NewOptions{
+		Effect:    ladon.AllowAccess,
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdNew returns new initialized instance of new sub command.
+func NewCmdNew(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewNewOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   newUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Scaffold an authorization policy from flags or prompts",
+		TraverseChildren:      true,
+		Long:                  newLong,
+		Example:               newExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Description, "description", o.Description, "The description of the policy.")
+	cmd.Flags().StringVar(&o.Effect, "effect", o.Effect, "The effect of the policy, allow or deny.")
+	cmd.Flags().StringSliceVar(&o.Subjects, "subject", o.Subjects, "The subject patterns of the policy.")
+	cmd.Flags().StringSliceVar(&o.Actions, "action", o.Actions, "The action patterns of the policy.")
+	cmd.Flags().StringSliceVar(&o.Resources, "resource", o.Resources, "The resource patterns of the policy.")
+	cmd.Flags().StringArrayVar(&o.Conditions, "condition", o.Conditions,
+		"A condition of the policy, in the form KEY=TYPE or KEY=TYPE:OPTIONS.")
+	cmd.Flags().BoolVarP(&o.Interactive, "interactive", "i", o.Interactive,
+		"If true, prompt for the policy, using the flags as defaults.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *NewOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return cmdutil.UsageErrorf(cmd, newUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	if !o.Interactive {
+		return nil
+	}
+
+	o.reader = bufio.NewReader(o.In)
+
+	return o.prompt()
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *NewOptions) Validate(cmd *cobra.Command, args []string) error {
+	for _, c := range o.Conditions {
+		if !strings.Contains(c, "=") {
+			return cmdutil.UsageErrorf(cmd, "invalid condition %q, expected KEY=TYPE or KEY=TYPE:OPTIONS", c)
+		}
+	}
+
+	return nil
+}
+
+// Run executes a new subcommand using the specified options.
+func (o *NewOptions) Run(args []string) error {
+	data, err := o.policyJSON()
+	if err != nil {
+		return err
+	}
+
+	policies, findings, err := policylint.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	findings = append(findings, policylint.Lint(policies...)...)
+	for _, finding := range findings {
+		fmt.Fprintf(o.ErrOut, "%s: %s\n", finding.Severity, finding)
+	}
+
+	if policylint.HasErrors(findings) || len(policies) == 0 {
+		return fmt.Errorf("policy %s is invalid", o.Name)
+	}
+
+	encoder := json.NewEncoder(o.Out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(policies[0])
+}
+
+// policyJSON returns the policy as JSON, with the conditions undecoded so
+// unknown condition types are reported by the linter.
+func (o *NewOptions) policyJSON() ([]byte, error) {
+	conditions := map[string]interface{}{}
+
+	for _, c := range o.Conditions {
+		key, spec := split(c, "=")
+		typ, options := split(spec, ":")
+
+		condition := map[string]interface{}{"type": typ}
+		if options != "" {
+			if !json.Valid([]byte(options)) {
+				return nil, fmt.Errorf("condition %s: the options are not valid JSON", key)
+			}
+
+			condition["options"] = json.RawMessage(options)
+		}
+
+		conditions[key] = condition
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":          o.Name,
+		"description": o.Description,
+		"effect":      o.Effect,
+		"subjects":    o.Subjects,
+		"actions":     o.Actions,
+		"resources":   o.Resources,
+		"conditions":  conditions,
+	})
+}
+
+// prompt asks for every field of the policy, the current values are kept when
+// the answer is empty.
+func (o *NewOptions) prompt() error {
+	var err error
+
+	if o.Description, err = o.ask("Description", o.Description); err != nil {
+		return err
+	}
+
+	if o.Effect, err = o.ask("Effect (allow or deny)", o.Effect); err != nil {
+		return err
+	}
+
+	for _, field := range []struct {
+		label  string
+		values *[]string
+	}{
+		{"Subjects", &o.Subjects},
+		{"Actions", &o.Actions},
+		{"Resources", &o.Resources},
+	} {
+		answer, err := o.ask(field.label+" (comma separated)", strings.Join(*field.values, ","))
+		if err != nil {
+			return err
+		}
+
+		*field.values = splitList(answer)
+	}
+
+	fmt.Fprintf(o.ErrOut, "Condition types: %s\n", strings.Join(policylint.ConditionTypes(), ", "))
+
+	for {
+		key, err := o.ask("Condition key (empty to finish)", "")
+		if err != nil || key == "" {
+			return err
+		}
+
+		typ, err := o.ask("Condition type", "")
+		if err != nil {
+			return err
+		}
+
+		options, err := o.ask("Condition options (JSON, empty for none)", "")
+		if err != nil {
+			return err
+		}
+
+		condition := key + "=" + typ
+		if options != "" {
+			condition += ":" + options
+		}
+
+		o.Conditions = append(o.Conditions, condition)
+	}
+}
+
+// ask reads a line of the standard input, the default is returned for an
+// empty line.
+func (o *NewOptions) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(o.ErrOut, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(o.ErrOut, "%s: ", label)
+	}
+
+	line, err := o.reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read %s: %w", strings.ToLower(label), err)
+	}
+
+	if line = strings.TrimSpace(line); line == "" {
+		return def, nil
+	}
+
+	return line, nil
+}
+
+// split splits s around the first sep.
+func split(s, sep string) (string, string) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):])
+	}
+
+	return strings.TrimSpace(s), ""
+}
+
+// splitList splits a comma separated list, dropping the empty items.
+func splitList(s string) []string {
+	var items []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
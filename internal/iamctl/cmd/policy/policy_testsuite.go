@@ -0,0 +1,161 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/simulator"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+const (
+	testUsageStr = "test POLICY_FILE CASE_FILE"
+)
+
+// TestOptions is an options struct to support test subcommands.
+type TestOptions struct {
+	Local bool
+
+	Suite *simulator.Suite
+
+	client *restclient.RESTClient
+	genericclioptions.IOStreams
+}
+
+var (
+	testLong = templates.LongDesc(`
+		Evaluate a suite of access requests against a candidate policy set.
+
+		POLICY_FILE holds a YAML or JSON list of policies, CASE_FILE holds a YAML or JSON list
+		of cases, each case has a name, a ladon request and the expected effect: allow or deny.
+		The cases are evaluated by iam-apiserver with the same ladon warden as iam-authz-server,
+		the stored policies and the authorization cache are left untouched. The command fails
+		when any case gets an unexpected effect.`)
+
+	testExample = templates.Examples(`
+		# Evaluate the cases in cases.yaml against the policies in policies.yaml
+		iamctl policy test policies.yaml cases.yaml
+
+		# Evaluate the cases locally, without iam-apiserver
+		iamctl policy test policies.yaml cases.yaml --local`)
+
+	testUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nPOLICY_FILE and CASE_FILE are required arguments for the test command",
+		testUsageStr,
+	)
+)
+
+// NewTestOptions returns an initialized TestOptions instance.
+func NewTestOptions(ioStreams genericclioptions.IOStreams) *TestOptions {
+	return &TestOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdTest returns new initialized instance of test sub command.
+func NewCmdTest(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewTestOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   testUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"simulate"},
+		Short:                 "Test a candidate policy set against expected allow/deny cases",
+		TraverseChildren:      true,
+		Long:                  testLong,
+		Example:               testExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(f, cmd, args))
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().BoolVar(&o.Local, "local", o.Local, "If true, evaluate the cases locally instead of on iam-apiserver.")
+
+	return cmd
+}
+
+// Complete completes all the required options.
+func (o *TestOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) < 2 {
+		return cmdutil.UsageErrorf(cmd, testUsageErrStr)
+	}
+
+	o.Suite = &simulator.Suite{}
+	if err := readYAMLOrJSON(args[0], &o.Suite.Policies); err != nil {
+		return err
+	}
+
+	if err := readYAMLOrJSON(args[1], &o.Suite.Cases); err != nil {
+		return err
+	}
+
+	if o.Local {
+		return nil
+	}
+
+	var err error
+	o.client, err = f.RESTClient()
+
+	return err
+}
+
+// Validate makes sure there is no discrepency in command options.
+func (o *TestOptions) Validate(cmd *cobra.Command, args []string) error {
+	return o.Suite.Validate()
+}
+
+// Run executes a test subcommand using the specified options.
+func (o *TestOptions) Run(args []string) error {
+	var report *simulator.Report
+
+	if o.Local {
+		report = o.Suite.Run()
+	} else if err := o.client.Post().AbsPath("/v1/policies/test").Body(*o.Suite).Do(context.TODO()).Into(&report); err != nil {
+		return err
+	}
+
+	for _, m := range report.Mismatches {
+		fmt.Fprintf(o.Out, "FAIL %s: expected %s, got %s", m.Name, m.Expect, m.Actual)
+		if m.Reason != "" {
+			fmt.Fprintf(o.Out, " (%s)", m.Reason)
+		}
+		fmt.Fprintln(o.Out)
+	}
+
+	fmt.Fprintf(o.Out, "%d/%d cases passed\n", report.Passed, report.Total)
+
+	if len(report.Mismatches) > 0 {
+		return fmt.Errorf("%d cases got an unexpected effect", len(report.Mismatches))
+	}
+
+	return nil
+}
+
+// readYAMLOrJSON decodes a YAML or JSON file into obj. Policies are decoded as
+// JSON after conversion, so their conditions are unmarshalled by ladon.
+func readYAMLOrJSON(file string, obj interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("decode %s: %w", file, err)
+	}
+
+	return nil
+}
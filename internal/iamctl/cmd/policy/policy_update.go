@@ -17,6 +17,7 @@ import (
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/policylint"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -105,7 +106,7 @@ func (o *UpdateOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []s
 
 // Validate makes sure there is no discrepency in command options.
 func (o *UpdateOptions) Validate(cmd *cobra.Command, args []string) error {
-	return nil
+	return policylint.Validate(&o.Policy.Policy.DefaultPolicy)
 }
 
 // Run executes a update subcommand using the specified options.
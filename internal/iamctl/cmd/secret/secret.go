@@ -6,10 +6,14 @@
 package secret
 
 import (
+	"time"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
@@ -38,14 +42,29 @@ func NewCmdSecret(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cob
 	return cmd
 }
 
-// setHeader set headers for secret commands.
-func setHeader(table *tablewriter.Table) *tablewriter.Table {
-	table.SetHeader([]string{"Name", "SecretID", "SecretKey", "Expires", "Created"})
-	table.SetHeaderColor(tablewriter.Colors{tablewriter.FgGreenColor},
-		tablewriter.Colors{tablewriter.FgRedColor},
-		tablewriter.Colors{tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.FgMagentaColor},
-		tablewriter.Colors{tablewriter.FgGreenColor})
+// secretColumns are the columns of the secret tables.
+var secretColumns = []printers.Column{
+	{Header: "Name", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Secret).Name
+	}},
+	{Header: "SecretID", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Secret).SecretID
+	}},
+	{Header: "SecretKey", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Secret).SecretKey
+	}},
+	{Header: "Expires", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		return time.Unix(obj.(*v1.Secret).Expires, 0).Format("2006-01-02 15:04:05")
+	}},
+	{Header: "Description", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Secret).Description
+	}},
+	{Header: "Created", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*v1.Secret).CreatedAt.Format("2006-01-02 15:04:05")
+	}},
+}
 
-	return table
+// newPrintFlags returns the print flags of the secret commands.
+func newPrintFlags() *printers.PrintFlags {
+	return printers.NewPrintFlags("secret", secretColumns...)
 }
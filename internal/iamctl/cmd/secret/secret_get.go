@@ -7,15 +7,15 @@ package secret
 import (
 	"context"
 	"fmt"
-	"time"
 
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/marmotedu-sdk-go/marmotedu/service/iam"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -27,6 +27,9 @@ const (
 type GetOptions struct {
 	Name string
 
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
 	iamclient iam.IamInterface
 
 	genericclioptions.IOStreams
@@ -43,7 +46,9 @@ var (
 // NewGetOptions returns an initialized GetOptions instance.
 func NewGetOptions(ioStreams genericclioptions.IOStreams) *GetOptions {
 	return &GetOptions{
-		IOStreams: ioStreams,
+		PrintFlags: newPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
 	}
 }
 
@@ -67,6 +72,9 @@ func NewCmdGet(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.
 		SuggestFor: []string{},
 	}
 
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
 	return cmd
 }
 
@@ -84,37 +92,43 @@ func (o *GetOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []stri
 		return err
 	}
 
-	return nil
+	return o.WatchFlags.Complete(f)
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *GetOptions) Validate(cmd *cobra.Command, args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
 	return nil
 }
 
 // Run executes a get subcommand using the specified options.
 func (o *GetOptions) Run(args []string) error {
-	secret, err := o.iamclient.APIV1().Secrets().Get(context.TODO(), o.Name, metav1.GetOptions{})
+	watcher, err := o.WatchFlags.Start("secret", o.Name)
 	if err != nil {
 		return err
 	}
+	defer watcher.Close()
+
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
 
-	table := tablewriter.NewWriter(o.Out)
+	return watcher.Print(o.PrintFlags, o.Out)
+}
 
-	data := [][]string{
-		{
-			secret.Name,
-			secret.SecretID,
-			secret.SecretKey,
-			time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05"),
-			secret.CreatedAt.Format("2006-01-02 15:04:05"),
-		},
+// printCurrent prints the secret, unless only the changes are watched.
+func (o *GetOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
 	}
 
-	table = setHeader(table)
-	table = cmdutil.TableWriterDefaultConfig(table)
-	table.AppendBulk(data)
-	table.Render()
+	secret, err := o.iamclient.APIV1().Secrets().Get(context.TODO(), o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return o.PrintFlags.Print(secret, o.Out)
 }
@@ -6,15 +6,15 @@ package secret
 
 import (
 	"context"
-	"time"
 
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/marmotedu-sdk-go/marmotedu/service/iam"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -27,6 +27,9 @@ type ListOptions struct {
 	Offset int64
 	Limit  int64
 
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
 	iamclient iam.IamInterface
 	genericclioptions.IOStreams
 }
@@ -36,14 +39,19 @@ var listExample = templates.Examples(`
 		iamctl secret list
 
 		# List secrets with limit and offset 
-		iamctl secret list --offset=0 --limit=5`)
+		iamctl secret list --offset=0 --limit=5
+
+		# Watch the changes of the secrets, without listing them first
+		iamctl secret list --watch-only`)
 
 // NewListOptions returns an initialized ListOptions instance.
 func NewListOptions(ioStreams genericclioptions.IOStreams) *ListOptions {
 	return &ListOptions{
-		IOStreams: ioStreams,
-		Offset:    0,
-		Limit:     defaltLimit,
+		PrintFlags: newPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
+		Offset:     0,
+		Limit:      defaltLimit,
 	}
 }
 
@@ -67,9 +75,12 @@ func NewCmdList(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra
 		SuggestFor: []string{},
 	}
 
-	cmd.Flags().Int64VarP(&o.Offset, "offset", "o", o.Offset, "Specify the offset of the first row to be returned.")
+	cmd.Flags().Int64Var(&o.Offset, "offset", o.Offset, "Specify the offset of the first row to be returned.")
 	cmd.Flags().Int64VarP(&o.Limit, "limit", "l", o.Limit, "Specify the amount records to be returned.")
 
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
 	return cmd
 }
 
@@ -81,16 +92,39 @@ func (o *ListOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []str
 		return err
 	}
 
-	return nil
+	return o.WatchFlags.Complete(f)
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
 	return nil
 }
 
 // Run executes a list subcommand using the specified options.
 func (o *ListOptions) Run(args []string) error {
+	watcher, err := o.WatchFlags.Start("secret", "")
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
+
+	return watcher.Print(o.PrintFlags, o.Out)
+}
+
+// printCurrent prints the secrets, unless only the changes are watched.
+func (o *ListOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
+	}
+
 	secrets, err := o.iamclient.APIV1().Secrets().List(context.TODO(), metav1.ListOptions{
 		Offset: &o.Offset,
 		Limit:  &o.Limit,
@@ -99,23 +133,5 @@ func (o *ListOptions) Run(args []string) error {
 		return err
 	}
 
-	data := make([][]string, 0, len(secrets.Items))
-	table := tablewriter.NewWriter(o.Out)
-
-	for _, secret := range secrets.Items {
-		data = append(data, []string{
-			secret.Name,
-			secret.SecretID,
-			secret.SecretKey,
-			time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05"),
-			secret.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
-	}
-
-	table = setHeader(table)
-	table = cmdutil.TableWriterDefaultConfig(table)
-	table.AppendBulk(data)
-	table.Render()
-
-	return nil
+	return o.PrintFlags.Print(secrets, o.Out)
 }
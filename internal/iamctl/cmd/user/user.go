@@ -6,10 +6,14 @@
 package user
 
 import (
+	"strconv"
+
+	v1 "github.com/marmotedu/api/apiserver/v1"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
@@ -38,15 +42,35 @@ func NewCmdUser(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra
 	return cmd
 }
 
-// setHeader set headers for user commands.
-func setHeader(table *tablewriter.Table) *tablewriter.Table {
-	table.SetHeader([]string{"Name", "Nickname", "Email", "Phone", "Created", "Updated"})
-	table.SetHeaderColor(tablewriter.Colors{tablewriter.FgGreenColor},
-		tablewriter.Colors{tablewriter.FgRedColor},
-		tablewriter.Colors{tablewriter.FgCyanColor},
-		tablewriter.Colors{tablewriter.FgMagentaColor},
-		tablewriter.Colors{tablewriter.FgGreenColor},
-		tablewriter.Colors{tablewriter.FgWhiteColor})
-
-	return table
+// userColumns are the columns of the user tables.
+var userColumns = []printers.Column{
+	{Header: "Name", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*v1.User).Name
+	}},
+	{Header: "Nickname", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return obj.(*v1.User).Nickname
+	}},
+	{Header: "Email", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*v1.User).Email
+	}},
+	{Header: "Phone", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		return obj.(*v1.User).Phone
+	}},
+	{Header: "Admin", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return strconv.FormatBool(obj.(*v1.User).IsAdmin == 1)
+	}},
+	{Header: "Policies", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return strconv.FormatInt(obj.(*v1.User).TotalPolicy, 10)
+	}},
+	{Header: "Created", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*v1.User).CreatedAt.Format("2006-01-02 15:04:05")
+	}},
+	{Header: "Updated", Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*v1.User).UpdatedAt.Format("2006-01-02 15:04:05")
+	}},
+}
+
+// newPrintFlags returns the print flags of the user commands.
+func newPrintFlags() *printers.PrintFlags {
+	return printers.NewPrintFlags("user", userColumns...)
 }
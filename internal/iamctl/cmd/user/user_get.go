@@ -10,11 +10,12 @@ import (
 
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/marmotedu-sdk-go/marmotedu/service/iam"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -26,6 +27,9 @@ const (
 type GetOptions struct {
 	Name string
 
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
 	iamclient iam.IamInterface
 	genericclioptions.IOStreams
 }
@@ -33,7 +37,10 @@ type GetOptions struct {
 var (
 	getExample = templates.Examples(`
 		# Get user foo detail information
-		iamctl user get foo`)
+		iamctl user get foo
+
+		# Get the email of user foo
+		iamctl user get foo -o jsonpath='{.email}'`)
 
 	getUsageErrStr = fmt.Sprintf("expected '%s'.\nUSERNAME is required arguments for the get command", getUsageStr)
 )
@@ -41,7 +48,9 @@ var (
 // NewGetOptions returns an initialized GetOptions instance.
 func NewGetOptions(ioStreams genericclioptions.IOStreams) *GetOptions {
 	return &GetOptions{
-		IOStreams: ioStreams,
+		PrintFlags: newPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
 	}
 }
 
@@ -65,6 +74,9 @@ func NewCmdGet(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra.
 		SuggestFor: []string{},
 	}
 
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
 	return cmd
 }
 
@@ -82,38 +94,43 @@ func (o *GetOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []stri
 		return err
 	}
 
-	return nil
+	return o.WatchFlags.Complete(f)
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *GetOptions) Validate(cmd *cobra.Command, args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
 	return nil
 }
 
 // Run executes a get subcommand using the specified options.
 func (o *GetOptions) Run(args []string) error {
-	user, err := o.iamclient.APIV1().Users().Get(context.TODO(), o.Name, metav1.GetOptions{})
+	watcher, err := o.WatchFlags.Start("user", o.Name)
 	if err != nil {
 		return err
 	}
+	defer watcher.Close()
 
-	table := tablewriter.NewWriter(o.Out)
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
 
-	data := [][]string{
-		{
-			user.Name,
-			user.Nickname,
-			user.Email,
-			user.Phone,
-			user.CreatedAt.Format("2006-01-02 15:04:05"),
-			user.UpdatedAt.Format("2006-01-02 15:04:05"),
-		},
+	return watcher.Print(o.PrintFlags, o.Out)
+}
+
+// printCurrent prints the user, unless only the changes are watched.
+func (o *GetOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
 	}
 
-	table = setHeader(table)
-	table = cmdutil.TableWriterDefaultConfig(table)
-	table.AppendBulk(data)
-	table.Render()
+	user, err := o.iamclient.APIV1().Users().Get(context.TODO(), o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return o.PrintFlags.Print(user, o.Out)
 }
@@ -9,11 +9,12 @@ import (
 
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/marmotedu-sdk-go/marmotedu/service/iam"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/templates"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/watch"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
 )
 
@@ -26,6 +27,9 @@ type ListOptions struct {
 	Offset int64
 	Limit  int64
 
+	PrintFlags *printers.PrintFlags
+	WatchFlags *watch.Flags
+
 	iamclient iam.IamInterface
 	genericclioptions.IOStreams
 }
@@ -35,14 +39,22 @@ var listExample = templates.Examples(`
 		iamctl user list
 
 		# List users with limit and offset
-		iamctl user list --offset=0 --limit=10`)
+		iamctl user list --offset=0 --limit=10
+
+		# List the names and emails of the users, sorted by name
+		iamctl user list -o custom-columns=NAME:.metadata.name,EMAIL:.email --sort-by=.metadata.name
+
+		# List all users, then watch the changes of the users
+		iamctl user list --watch`)
 
 // NewListOptions returns an initialized ListOptions instance.
 func NewListOptions(ioStreams genericclioptions.IOStreams) *ListOptions {
 	return &ListOptions{
-		IOStreams: ioStreams,
-		Offset:    0,
-		Limit:     defaultLimit,
+		PrintFlags: newPrintFlags(),
+		WatchFlags: watch.NewFlags(),
+		IOStreams:  ioStreams,
+		Offset:     0,
+		Limit:      defaultLimit,
 	}
 }
 
@@ -66,9 +78,12 @@ func NewCmdList(f cmdutil.Factory, ioStreams genericclioptions.IOStreams) *cobra
 		SuggestFor: []string{},
 	}
 
-	cmd.Flags().Int64VarP(&o.Offset, "offset", "o", o.Offset, "Specify the offset of the first row to be returned.")
+	cmd.Flags().Int64Var(&o.Offset, "offset", o.Offset, "Specify the offset of the first row to be returned.")
 	cmd.Flags().Int64VarP(&o.Limit, "limit", "l", o.Limit, "Specify the amount records to be returned.")
 
+	o.PrintFlags.AddFlags(cmd)
+	o.WatchFlags.AddFlags(cmd)
+
 	return cmd
 }
 
@@ -81,38 +96,46 @@ func (o *ListOptions) Complete(f cmdutil.Factory, cmd *cobra.Command, args []str
 		return err
 	}
 
-	return nil
+	return o.WatchFlags.Complete(f)
 }
 
 // Validate makes sure there is no discrepency in command options.
 func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
+	if err := o.PrintFlags.Validate(); err != nil {
+		return cmdutil.UsageErrorf(cmd, "%v", err)
+	}
+
 	return nil
 }
 
 // Run executes a list subcommand using the specified options.
 func (o *ListOptions) Run(args []string) error {
-	users, err := o.iamclient.APIV1().Users().List(context.TODO(), metav1.ListOptions{
-		Offset: &o.Offset,
-		Limit:  &o.Limit,
-	})
+	watcher, err := o.WatchFlags.Start("user", "")
 	if err != nil {
 		return err
 	}
+	defer watcher.Close()
 
-	data := make([][]string, 0, 1)
-	table := tablewriter.NewWriter(o.Out)
+	if err := o.printCurrent(); err != nil {
+		return err
+	}
+
+	return watcher.Print(o.PrintFlags, o.Out)
+}
 
-	for _, user := range users.Items {
-		data = append(data, []string{
-			user.Name, user.Nickname, user.Email,
-			user.Phone, user.CreatedAt.Format("2006-01-02 15:04:05"), user.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
+// printCurrent prints the users, unless only the changes are watched.
+func (o *ListOptions) printCurrent() error {
+	if o.WatchFlags.WatchOnly {
+		return nil
 	}
 
-	table = setHeader(table)
-	table = cmdutil.TableWriterDefaultConfig(table)
-	table.AppendBulk(data)
-	table.Render()
+	users, err := o.iamclient.APIV1().Users().List(context.TODO(), metav1.ListOptions{
+		Offset: &o.Offset,
+		Limit:  &o.Limit,
+	})
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return o.PrintFlags.Print(users, o.Out)
 }
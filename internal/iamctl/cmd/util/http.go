@@ -0,0 +1,82 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+)
+
+// DoRaw sends req with the credentials and the TLS settings of config. It is
+// used for the bodies the rest client can not stream, such as files and event
+// streams.
+func DoRaw(config *restclient.Config, req *http.Request) (*http.Response, error) {
+	setAuthorization(req, config)
+
+	tlsConfig, err := restclient.TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		Timeout:   config.Timeout,
+	}
+
+	return httpClient.Do(req)
+}
+
+// ResponseError returns the error of a failed response, with the message of
+// the error body of iam-apiserver when there is one.
+func ResponseError(resp *http.Response) error {
+	var failure struct {
+		Message string `json:"message"`
+	}
+
+	data, _ := ioutil.ReadAll(resp.Body)
+	if json.Unmarshal(data, &failure) != nil || failure.Message == "" {
+		failure.Message = http.StatusText(resp.StatusCode)
+	}
+
+	return errors.New(failure.Message)
+}
+
+// setAuthorization authenticates req the same way as the rest client.
+func setAuthorization(req *http.Request, config *restclient.Config) {
+	switch {
+	case config.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	case config.SecretID != "" && config.SecretKey != "":
+		req.Header.Set("Authorization", "Bearer "+SignToken(config.SecretID, config.SecretKey, time.Minute))
+	case config.Username != "" && config.Password != "":
+		req.Header.Set("Authorization",
+			"Basic "+base64.StdEncoding.EncodeToString([]byte(config.Username+":"+config.Password)))
+	}
+}
+
+// SignToken returns a token for iam-apiserver signed with the secret, valid for ttl.
+func SignToken(secretID, secretKey string, ttl time.Duration) string {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": now.Add(ttl).Unix(),
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"aud": "iam.api.marmotedu.com",
+		"iss": "iamctl",
+	})
+	token.Header["kid"] = secretID
+
+	// signing with HMAC only fails on keys of another type.
+	tokenString, _ := token.SignedString([]byte(secretKey))
+
+	return tokenString
+}
@@ -0,0 +1,417 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed template of the jsonpath output, e.g.
+// {range .items[*]}{.metadata.name}{"\t"}{.email}{"\n"}{end}.
+//
+// Only a small subset of kubectl's jsonpath is supported: field paths
+// (.metadata.name), all the elements of a list ([*]), an element of a list
+// ([0]), the current object (@), string literals ({"\n"}) and range/end
+// blocks. Anything else, such as filters or slices, is rejected.
+type JSONPath struct {
+	nodes []node
+}
+
+type node interface{}
+
+type textNode string
+
+// pathNode is an expression, the empty one is the current object.
+type pathNode []segment
+
+type rangeNode struct {
+	path pathNode
+	body []node
+}
+
+// segment is a field name, or an index when field is empty; index -1 stands
+// for [*].
+type segment struct {
+	field string
+	index int
+}
+
+var fieldNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ParseJSONPath parses a jsonpath template. A template without braces is taken
+// as a single expression, so that .metadata.name is the same as {.metadata.name}.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	nodes, _, err := parseNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseNodes parses the template up to its end, or up to {end} when inRange
+// is set; it returns what follows {end}.
+func parseNodes(template string, inRange bool) ([]node, string, error) {
+	var nodes []node
+
+	for template != "" {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			nodes = append(nodes, textNode(template))
+
+			break
+		}
+
+		if open > 0 {
+			nodes = append(nodes, textNode(template[:open]))
+		}
+
+		closing, err := closingBrace(template, open)
+		if err != nil {
+			return nil, "", err
+		}
+
+		action := strings.TrimSpace(template[open+1 : closing])
+		template = template[closing+1:]
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("{end} without {range}")
+			}
+
+			return nodes, template, nil
+		case strings.HasPrefix(action, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+
+			body, rest, err := parseNodes(template, true)
+			if err != nil {
+				return nil, "", err
+			}
+
+			nodes = append(nodes, rangeNode{path: path, body: body})
+			template = rest
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid string literal %s", action)
+			}
+
+			nodes = append(nodes, textNode(text))
+		default:
+			path, err := parsePath(action)
+			if err != nil {
+				return nil, "", err
+			}
+
+			nodes = append(nodes, path)
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("{range} without {end}")
+	}
+
+	return nodes, "", nil
+}
+
+// closingBrace returns the index of the brace closing the action opened at
+// open, the braces of the string literals are skipped.
+func closingBrace(template string, open int) (int, error) {
+	quoted := false
+
+	for i := open + 1; i < len(template); i++ {
+		switch c := template[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == '}':
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unclosed action in %q", template[open:])
+}
+
+// parsePath parses an expression such as .items[*].metadata.name.
+func parsePath(expr string) (pathNode, error) {
+	if expr == "@" || expr == "." {
+		return pathNode{}, nil
+	}
+
+	if !strings.HasPrefix(expr, ".") && !strings.HasPrefix(expr, "[") {
+		return nil, unsupportedPath(expr)
+	}
+
+	var path pathNode
+
+	for rest := expr; rest != ""; {
+		if rest[0] == '[' {
+			closing := strings.IndexByte(rest, ']')
+			if closing < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", expr)
+			}
+
+			s, ok := parseIndex(rest[1:closing])
+			if !ok {
+				return nil, unsupportedPath(expr)
+			}
+
+			path = append(path, s)
+			rest = rest[closing+1:]
+
+			continue
+		}
+
+		if rest[0] != '.' {
+			return nil, unsupportedPath(expr)
+		}
+
+		end := strings.IndexAny(rest[1:], ".[") + 1
+		if end == 0 {
+			end = len(rest)
+		}
+
+		if !fieldNameRegexp.MatchString(rest[1:end]) {
+			return nil, unsupportedPath(expr)
+		}
+
+		path = append(path, segment{field: rest[1:end]})
+		rest = rest[end:]
+	}
+
+	return path, nil
+}
+
+// parseIndex parses the subscript of [*] or [n].
+func parseIndex(sub string) (segment, bool) {
+	if sub == "*" {
+		return segment{index: -1}, true
+	}
+
+	n, err := strconv.Atoi(sub)
+	if err != nil || n < 0 || strings.HasPrefix(sub, "+") {
+		return segment{}, false
+	}
+
+	return segment{index: n}, true
+}
+
+func unsupportedPath(expr string) error {
+	return fmt.Errorf("unsupported expression %q, only field paths such as .metadata.name, [*], [n] and @ are supported",
+		expr)
+}
+
+// Execute writes the template applied to obj, which is converted to its JSON
+// form first. Missing fields produce nothing.
+func (j *JSONPath) Execute(w io.Writer, obj interface{}) error {
+	data, err := toJSONValue(obj)
+	if err != nil {
+		return err
+	}
+
+	return j.execute(w, j.nodes, data)
+}
+
+// Values returns the results of the template applied to obj, the template
+// must be a single expression.
+func (j *JSONPath) Values(obj interface{}) ([]interface{}, error) {
+	data, err := toJSONValue(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(j.nodes) != 1 {
+		return nil, fmt.Errorf("a single expression is expected")
+	}
+
+	path, ok := j.nodes[0].(pathNode)
+	if !ok {
+		return nil, fmt.Errorf("a single expression is expected")
+	}
+
+	return path.eval(data), nil
+}
+
+func (j *JSONPath) execute(w io.Writer, nodes []node, current interface{}) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			if _, err := io.WriteString(w, string(n)); err != nil {
+				return err
+			}
+		case pathNode:
+			values := n.eval(current)
+			texts := make([]string, 0, len(values))
+
+			for _, v := range values {
+				text, err := formatValue(v)
+				if err != nil {
+					return err
+				}
+
+				texts = append(texts, text)
+			}
+
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return err
+			}
+		case rangeNode:
+			for _, v := range n.path.eval(current) {
+				// ranging over a list visits its elements.
+				elements, ok := v.([]interface{})
+				if !ok {
+					elements = []interface{}{v}
+				}
+
+				for _, element := range elements {
+					if err := j.execute(w, n.body, element); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p pathNode) eval(current interface{}) []interface{} {
+	values := []interface{}{current}
+
+	for _, s := range p {
+		var next []interface{}
+
+		for _, v := range values {
+			next = append(next, s.apply(v)...)
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+func (s segment) apply(v interface{}) []interface{} {
+	if s.field != "" {
+		if m, ok := v.(map[string]interface{}); ok {
+			if field, ok := m[s.field]; ok {
+				return []interface{}{field}
+			}
+		}
+
+		return nil
+	}
+
+	list, ok := v.([]interface{})
+
+	switch {
+	case !ok:
+		return nil
+	case s.index < 0:
+		return list
+	case s.index < len(list):
+		return []interface{}{list[s.index]}
+	default:
+		return nil
+	}
+}
+
+// compareValues compares two JSON values, numbers numerically and the other
+// scalars as text. ok is false when they can not be compared.
+func compareValues(a, b interface{}) (int, bool) {
+	an, aIsNumber := a.(json.Number)
+	bn, bIsNumber := b.(json.Number)
+
+	// the numbers are compared exactly, large integers such as snowflake IDs
+	// are not exact as floats.
+	if aIsNumber && bIsNumber {
+		ar, aOK := new(big.Rat).SetString(string(an))
+		br, bOK := new(big.Rat).SetString(string(bn))
+
+		if aOK && bOK {
+			return ar.Cmp(br), true
+		}
+	}
+
+	as, aOK := scalarText(a)
+	bs, bOK := scalarText(b)
+
+	if !aOK || !bOK {
+		return 0, false
+	}
+
+	return strings.Compare(as, bs), true
+}
+
+func scalarText(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return string(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// formatValue returns the text of a value: scalars as is, lists and objects
+// as JSON.
+func formatValue(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	if text, ok := scalarText(v); ok {
+		return text, nil
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// toJSONValue converts obj to the generic form of its JSON, the numbers are
+// kept as json.Number so that large IDs are exact.
+func toJSONValue(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	Metadata testMeta `json:"metadata"`
+	Email    string   `json:"email"`
+	Policies int      `json:"totalPolicy"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+type testMeta struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+type testUserList struct {
+	TotalCount int64       `json:"totalCount"`
+	Items      []*testUser `json:"items"`
+}
+
+func testUsers() *testUserList {
+	return &testUserList{
+		TotalCount: 3,
+		Items: []*testUser{
+			{Metadata: testMeta{ID: 3, Name: "peter"}, Email: "peter@example.com", Policies: 10},
+			{Metadata: testMeta{ID: 1, Name: "colin"}, Email: "colin@example.com", Policies: 2, Roles: []string{"admin", "dev"}},
+			{Metadata: testMeta{ID: 2, Name: "tom"}, Email: "tom@example.com", Policies: 9},
+		},
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{.totalCount}", "3"},
+		{".items[0].metadata.name", "peter"},
+		{"{.items[2].email}", "tom@example.com"},
+		{"{.items[*].metadata.name}", "peter colin tom"},
+		{"{.items[1].roles}", `["admin","dev"]`},
+		{"{.items[*].missing}", ""},
+		{`{range .items[*]}{.metadata.name}{"\t"}{.totalPolicy}{"\n"}{end}`, "peter\t10\ncolin\t2\ntom\t9\n"},
+		{`name: {.items[1].metadata.name}`, "name: colin"},
+		{`{range .items[1].roles}[{@}]{end}`, "[admin][dev]"},
+	}
+
+	for _, tt := range tests {
+		path, err := ParseJSONPath(tt.template)
+		if !assert.Nil(t, err, tt.template) {
+			continue
+		}
+
+		var buf bytes.Buffer
+		assert.Nil(t, path.Execute(&buf, testUsers()), tt.template)
+		assert.Equal(t, tt.want, buf.String(), tt.template)
+	}
+}
+
+// jsonPathFixture covers nested lists and maps, missing fields and IDs too
+// large to be exact as floats.
+const jsonPathFixture = `{
+  "kind": "UserList",
+  "totalCount": 3,
+  "items": [
+    {
+      "metadata": {"id": 1735371396125511680, "name": "colin", "labels": {"tier": "gold"}},
+      "email": "colin@example.com", "roles": ["admin", "dev"],
+      "secrets": [{"name": "ci", "expires": 0}, {"name": "deploy", "expires": 1700000000}]
+    },
+    {"metadata": {"id": 1735371396125511681, "name": "peter"}, "email": "peter@example.com", "roles": ["dev"], "secrets": []},
+    {"metadata": {"id": 3, "name": "tom"}, "email": "tom@example.com"}
+  ]
+}`
+
+func TestJSONPathExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"root field", "{.totalCount}", "3"},
+		{"current object", "{range .items[0].roles[*]}{.}{end}", "admindev"},
+		{"map as json", "{.items[0].metadata.labels}", `{"tier":"gold"}`},
+		{"empty list", "{.items[1].secrets}", "[]"},
+		{"missing field", "{.items[2].roles}", ""},
+		{"large id", "{.items[1].metadata.id}", "1735371396125511681"},
+		{"literals", `{"{"}{.kind}{"}"}`, "{UserList}"},
+		{"index out of range", "{.items[3].metadata.name}", ""},
+		{"index of a map", "{.items[0].metadata[0]}", ""},
+		{"nested index", "{.items[0].roles[1]}", "dev"},
+		{"nested wildcards", "{.items[*].secrets[*].name}", "ci deploy"},
+		{"wildcard of a map", "{.items[0].metadata[*]}", ""},
+		{
+			"nested ranges",
+			`{range .items[*]}{.metadata.name}:{range .roles[*]} {@}{end}{"\n"}{end}`,
+			"colin: admin dev\npeter: dev\ntom:\n",
+		},
+		{"range of a list", "{range .items[*]}{range .secrets}{.name}{end}{end}", "cideploy"},
+		{"range of nothing", "{range .missing[*]}{.name}{end}done", "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ParseJSONPath(tt.template)
+			if !assert.Nil(t, err, tt.template) {
+				return
+			}
+
+			var buf bytes.Buffer
+			assert.Nil(t, path.Execute(&buf, json.RawMessage(jsonPathFixture)), tt.template)
+			assert.Equal(t, tt.want, buf.String(), tt.template)
+		})
+	}
+}
+
+func TestJSONPathInvalid(t *testing.T) {
+	for _, template := range []string{
+		"{.items[0}",
+		"{range .items[*]}{.email}",
+		"{end}",
+		`{"unterminated}`,
+		"{range .items[*]}{range .roles[*]}{@}{end}",
+		"{range .items[*]}{end}{end}",
+		"{items}",
+		// the syntax out of the supported subset.
+		"{$.kind}",
+		"{..name}",
+		"{.items.*}",
+		"{.items[-1]}",
+		"{.items[0:2]}",
+		"{.items[?(@.totalPolicy>5)]}",
+		"{.items[0]['email']}",
+		"{.items[*]..name}",
+	} {
+		_, err := ParseJSONPath(template)
+		assert.NotNil(t, err, template)
+	}
+}
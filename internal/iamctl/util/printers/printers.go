@@ -0,0 +1,514 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package printers prints the resources of the iamctl get and list commands.
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/cli/genericclioptions"
+)
+
+// The output formats, jsonpath and custom-columns are followed by '=' and
+// their template, e.g. -o jsonpath='{.metadata.name}'.
+const (
+	FormatTable         = ""
+	FormatWide          = "wide"
+	FormatJSON          = "json"
+	FormatYAML          = "yaml"
+	FormatName          = "name"
+	FormatJSONPath      = "jsonpath"
+	FormatCustomColumns = "custom-columns"
+)
+
+var allowedFormats = []string{
+	FormatJSON, FormatYAML, FormatWide, FormatName, FormatJSONPath + "=...", FormatCustomColumns + "=...",
+}
+
+// ResourcePrinter prints a resource, or a list of resources.
+type ResourcePrinter interface {
+	PrintObj(obj interface{}, w io.Writer) error
+}
+
+// ResourcePrinterFunc is a function which implements ResourcePrinter.
+type ResourcePrinterFunc func(obj interface{}, w io.Writer) error
+
+// PrintObj implements ResourcePrinter.
+func (fn ResourcePrinterFunc) PrintObj(obj interface{}, w io.Writer) error {
+	return fn(obj, w)
+}
+
+// Column is a column of the table printed by default.
+type Column struct {
+	Header string
+	// Wide columns are only printed with -o wide.
+	Wide bool
+	// Value returns the text of the column for a resource, the resource is
+	// an element of the list, or the object printed.
+	Value func(obj interface{}) string
+	// Color is the color of the header, none by default.
+	Color int
+}
+
+// PrintFlags holds the flags which choose how the resources are printed.
+type PrintFlags struct {
+	OutputFormat string
+	NoHeaders    bool
+	SortBy       string
+
+	// Kind names the resources printed by -o name, e.g. user/colin.
+	Kind string
+	// Name returns the name printed by -o name, the metadata.name field of the
+	// resource by default.
+	Name func(obj interface{}) string
+	// Columns are printed by default and by -o wide.
+	Columns []Column
+}
+
+// NewPrintFlags returns the print flags of the resources of kind, printed by
+// default as a table of columns.
+func NewPrintFlags(kind string, columns ...Column) *PrintFlags {
+	return &PrintFlags{Kind: kind, Columns: columns}
+}
+
+// AddFlags adds -o/--output, --no-headers and --sort-by to cmd.
+func (f *PrintFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.OutputFormat, "output", "o", f.OutputFormat,
+		"Output format. One of: "+strings.Join(allowedFormats, "|"))
+	cmd.Flags().BoolVar(&f.NoHeaders, "no-headers", f.NoHeaders,
+		"When using the default, wide or custom-columns output format, don't print headers.")
+	cmd.Flags().StringVar(&f.SortBy, "sort-by", f.SortBy,
+		"If non-empty, sort the listed resources using this field specification, "+
+			"a jsonpath expression such as '{.metadata.name}'.")
+}
+
+// ToPrinter returns the printer of the output format, the default one is
+// the output format of the iamconfig context in use.
+func (f *PrintFlags) ToPrinter() (ResourcePrinter, error) {
+	output := f.OutputFormat
+	if output == "" {
+		output = genericclioptions.CurrentOutputFormat()
+	}
+
+	format, template := output, ""
+	if i := strings.Index(format, "="); i >= 0 {
+		format, template = format[:i], format[i+1:]
+	}
+
+	var (
+		printer ResourcePrinter
+		err     error
+	)
+
+	switch format {
+	case FormatTable, FormatWide:
+		printer = &TablePrinter{Columns: f.Columns, Wide: format == FormatWide, NoHeaders: f.NoHeaders}
+	case FormatJSON:
+		printer = ResourcePrinterFunc(printJSON)
+	case FormatYAML:
+		printer = ResourcePrinterFunc(printYAML)
+	case FormatName:
+		printer = &NamePrinter{Kind: f.Kind, Name: f.Name}
+	case FormatJSONPath:
+		printer, err = NewJSONPathPrinter(template)
+	case FormatCustomColumns:
+		printer, err = NewCustomColumnsPrinter(template, f.NoHeaders)
+	default:
+		return nil, fmt.Errorf("unable to match a printer suitable for the output format %q, allowed formats are: %s",
+			output, strings.Join(allowedFormats, ","))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if f.SortBy == "" {
+		return printer, nil
+	}
+
+	return NewSortingPrinter(f.SortBy, printer)
+}
+
+// Validate makes sure the output format, its template and --sort-by are valid.
+func (f *PrintFlags) Validate() error {
+	_, err := f.ToPrinter()
+
+	return err
+}
+
+// Print prints obj to w in the output format.
+func (f *PrintFlags) Print(obj interface{}, w io.Writer) error {
+	printer, err := f.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	return printer.PrintObj(obj, w)
+}
+
+func printJSON(obj interface{}, w io.Writer) error {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(obj); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+func printYAML(obj interface{}, w io.Writer) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+// TablePrinter prints the resources as a table, a row per resource.
+type TablePrinter struct {
+	Columns   []Column
+	Wide      bool
+	NoHeaders bool
+}
+
+// PrintObj implements ResourcePrinter.
+func (p *TablePrinter) PrintObj(obj interface{}, w io.Writer) error {
+	var columns []Column
+
+	for _, column := range p.Columns {
+		if p.Wide || !column.Wide {
+			columns = append(columns, column)
+		}
+	}
+
+	rows := make([][]string, 0)
+
+	for _, item := range Items(obj) {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, column.Value(item))
+		}
+
+		rows = append(rows, row)
+	}
+
+	table := cmdutil.TableWriterDefaultConfig(tablewriter.NewWriter(w))
+	if !p.NoHeaders {
+		headers := make([]string, 0, len(columns))
+		colors, colored := make([]tablewriter.Colors, 0, len(columns)), false
+
+		for _, column := range columns {
+			headers = append(headers, column.Header)
+			colors = append(colors, tablewriter.Colors{column.Color})
+			colored = colored || column.Color != 0
+		}
+
+		table.SetHeader(headers)
+
+		if colored {
+			table.SetHeaderColor(colors...)
+		}
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+
+	return nil
+}
+
+func printTable(w io.Writer, headers []string, rows [][]string, noHeaders bool) error {
+	table := cmdutil.TableWriterDefaultConfig(tablewriter.NewWriter(w))
+
+	if !noHeaders {
+		table.SetHeader(headers)
+	}
+
+	table.AppendBulk(rows)
+	table.Render()
+
+	return nil
+}
+
+// NamePrinter prints kind/name for every resource.
+type NamePrinter struct {
+	Kind string
+	Name func(obj interface{}) string
+}
+
+// PrintObj implements ResourcePrinter.
+func (p *NamePrinter) PrintObj(obj interface{}, w io.Writer) error {
+	for _, item := range Items(obj) {
+		name, err := p.name(item)
+		if err != nil {
+			return err
+		}
+
+		if p.Kind != "" {
+			name = p.Kind + "/" + name
+		}
+
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// namePaths locate the name of a resource, the first one found is printed.
+var namePaths = []*JSONPath{
+	mustParseJSONPath("{.metadata.name}"),
+	mustParseJSONPath("{.metadata.id}"),
+	mustParseJSONPath("{.id}"),
+}
+
+func (p *NamePrinter) name(item interface{}) (string, error) {
+	if p.Name != nil {
+		return p.Name(item), nil
+	}
+
+	for _, path := range namePaths {
+		values, err := path.Values(item)
+		if err != nil {
+			return "", err
+		}
+
+		if len(values) == 0 || values[0] == nil {
+			continue
+		}
+
+		if name, err := formatValue(values[0]); err == nil && name != "" {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("the resources have no name, use another output format")
+}
+
+// JSONPathPrinter prints the template applied to the object, the list objects
+// are printed as a whole, e.g. {.items[*].metadata.name}.
+type JSONPathPrinter struct {
+	path *JSONPath
+}
+
+// NewJSONPathPrinter returns the printer of a jsonpath template.
+func NewJSONPathPrinter(template string) (*JSONPathPrinter, error) {
+	if template == "" {
+		return nil, fmt.Errorf("template format specified but no template given")
+	}
+
+	path, err := ParseJSONPath(template)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing jsonpath %s: %w", template, err)
+	}
+
+	return &JSONPathPrinter{path: path}, nil
+}
+
+// PrintObj implements ResourcePrinter.
+func (p *JSONPathPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	return p.path.Execute(w, obj)
+}
+
+// CustomColumnsPrinter prints the resources as a table of jsonpath columns,
+// e.g. NAME:.metadata.name,EMAIL:.email.
+type CustomColumnsPrinter struct {
+	headers   []string
+	columns   []*JSONPath
+	NoHeaders bool
+}
+
+// NewCustomColumnsPrinter returns the printer of a custom columns spec.
+func NewCustomColumnsPrinter(spec string, noHeaders bool) (*CustomColumnsPrinter, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+
+	p := &CustomColumnsPrinter{NoHeaders: noHeaders}
+
+	for _, part := range strings.Split(spec, ",") {
+		colon := strings.Index(part, ":")
+		if colon <= 0 {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", part)
+		}
+
+		path, err := ParseJSONPath(part[colon+1:])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing column %s: %w", part[:colon], err)
+		}
+
+		p.headers = append(p.headers, part[:colon])
+		p.columns = append(p.columns, path)
+	}
+
+	return p, nil
+}
+
+// PrintObj implements ResourcePrinter.
+func (p *CustomColumnsPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	var rows [][]string
+
+	for _, item := range Items(obj) {
+		row := make([]string, 0, len(p.columns))
+
+		for _, column := range p.columns {
+			var buf bytes.Buffer
+			if err := column.Execute(&buf, item); err != nil {
+				return err
+			}
+
+			text := buf.String()
+			if text == "" {
+				text = "<none>"
+			}
+
+			row = append(row, text)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return printTable(w, p.headers, rows, p.NoHeaders)
+}
+
+// SortingPrinter sorts the items of a list before printing it.
+type SortingPrinter struct {
+	sortBy   *JSONPath
+	delegate ResourcePrinter
+}
+
+// NewSortingPrinter returns a printer which sorts the lists by the value of
+// the jsonpath expression sortBy, numbers numerically and the rest as text.
+func NewSortingPrinter(sortBy string, delegate ResourcePrinter) (*SortingPrinter, error) {
+	path, err := ParseJSONPath(sortBy)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing --sort-by %s: %w", sortBy, err)
+	}
+
+	if len(path.nodes) != 1 {
+		return nil, fmt.Errorf("--sort-by %s must be a single expression", sortBy)
+	}
+
+	return &SortingPrinter{sortBy: path, delegate: delegate}, nil
+}
+
+// PrintObj implements ResourcePrinter.
+func (p *SortingPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	if list, ok := itemsOf(obj); ok && list.Len() > 1 {
+		keys := make([]interface{}, list.Len())
+
+		for i := range keys {
+			values, err := p.sortBy.Values(list.Index(i).Interface())
+			if err != nil {
+				return err
+			}
+
+			if len(values) > 0 {
+				keys[i] = values[0]
+			}
+		}
+
+		sort.Stable(&sortedItems{swap: reflect.Swapper(list.Interface()), keys: keys})
+	}
+
+	return p.delegate.PrintObj(obj, w)
+}
+
+type sortedItems struct {
+	swap func(i, j int)
+	keys []interface{}
+}
+
+func (s *sortedItems) Len() int { return len(s.keys) }
+
+func (s *sortedItems) Swap(i, j int) {
+	s.swap(i, j)
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+// Less sorts the missing values first.
+func (s *sortedItems) Less(i, j int) bool {
+	a, b := s.keys[i], s.keys[j]
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+
+	if c, ok := compareValues(a, b); ok {
+		return c < 0
+	}
+
+	as, _ := formatValue(a)
+	bs, _ := formatValue(b)
+
+	return as < bs
+}
+
+// Items returns the resources of obj: the elements of its Items field for the
+// list objects, of obj for the slices, or else obj itself.
+func Items(obj interface{}) []interface{} {
+	list, ok := itemsOf(obj)
+	if !ok {
+		return []interface{}{obj}
+	}
+
+	items := make([]interface{}, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		items = append(items, list.Index(i).Interface())
+	}
+
+	return items
+}
+
+func itemsOf(obj interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		return v, true
+	case reflect.Struct:
+		items := v.FieldByName("Items")
+		if items.IsValid() && items.Kind() == reflect.Slice {
+			return items, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func mustParseJSONPath(template string) *JSONPath {
+	path, err := ParseJSONPath(template)
+	if err != nil {
+		panic(err)
+	}
+
+	return path
+}
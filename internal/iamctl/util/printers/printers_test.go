@@ -0,0 +1,86 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package printers
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = []Column{
+	{Header: "Name", Value: func(obj interface{}) string { return obj.(*testUser).Metadata.Name }},
+	{Header: "Email", Value: func(obj interface{}) string { return obj.(*testUser).Email }},
+	{Header: "Policies", Wide: true, Value: func(obj interface{}) string {
+		return strconv.Itoa(obj.(*testUser).Policies)
+	}},
+}
+
+func printString(t *testing.T, flags *PrintFlags, obj interface{}) string {
+	var buf bytes.Buffer
+	assert.Nil(t, flags.Print(obj, &buf))
+
+	return buf.String()
+}
+
+func fields(text string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+
+	return rows
+}
+
+func TestPrintFlags(t *testing.T) {
+	flags := NewPrintFlags("user", testColumns...)
+
+	assert.Equal(t, [][]string{
+		{"NAME", "EMAIL"},
+		{"peter", "peter@example.com"},
+		{"colin", "colin@example.com"},
+		{"tom", "tom@example.com"},
+	}, fields(printString(t, flags, testUsers())))
+
+	flags.OutputFormat, flags.NoHeaders = FormatWide, true
+	assert.Equal(t, [][]string{{"colin", "colin@example.com", "2"}}, fields(printString(t, flags, testUsers().Items[1])))
+
+	flags.OutputFormat, flags.SortBy = FormatName, "{.metadata.name}"
+	assert.Equal(t, "user/colin\nuser/peter\nuser/tom\n", printString(t, flags, testUsers()))
+
+	flags.OutputFormat, flags.SortBy = "custom-columns=ID:.metadata.id,ROLES:.roles[*]", ".totalPolicy"
+	assert.Equal(t, [][]string{{"1", "admin", "dev"}, {"2", "<none>"}, {"3", "<none>"}}, fields(printString(t, flags, testUsers())))
+
+	flags.NoHeaders = false
+	flags.OutputFormat, flags.SortBy = `jsonpath={range .items[*]}{.metadata.id}{","}{end}`, "{.metadata.id}"
+	assert.Equal(t, "1,2,3,", printString(t, flags, testUsers()))
+
+	flags.OutputFormat, flags.SortBy = FormatJSON, ""
+	assert.Equal(t, "{\n  \"metadata\": {\n    \"id\": 2,\n    \"name\": \"tom\"\n  },\n"+
+		"  \"email\": \"tom@example.com\",\n  \"totalPolicy\": 9\n}\n", printString(t, flags, testUsers().Items[2]))
+
+	flags.OutputFormat = FormatYAML
+	assert.Equal(t, "email: tom@example.com\nmetadata:\n  id: 2\n  name: tom\ntotalPolicy: 9\n",
+		printString(t, flags, testUsers().Items[2]))
+
+	for _, invalid := range []string{"xml", "jsonpath=", "custom-columns=NAME", "jsonpath={.items[}"} {
+		flags.OutputFormat = invalid
+		assert.NotNil(t, flags.Validate(), invalid)
+	}
+
+	flags.OutputFormat, flags.SortBy = FormatName, "{.email}{.metadata.name}"
+	assert.NotNil(t, flags.Validate())
+}
+
+func TestItems(t *testing.T) {
+	users := testUsers()
+
+	assert.Len(t, Items(users), 3)
+	assert.Len(t, Items(users.Items), 3)
+	assert.Equal(t, []interface{}{users.Items[0]}, Items(users.Items[0]))
+}
@@ -0,0 +1,244 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package watch implements the --watch flag of the get and list commands, which
+// print the changes streamed by iam-apiserver as server-sent events.
+package watch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	restclient "github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	cmdutil "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/cmd/util"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+)
+
+// Event is a change of a resource, as streamed by iam-apiserver.
+type Event struct {
+	Type   string          `json:"type"`
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	Tenant string          `json:"tenant"`
+	Owner  string          `json:"owner,omitempty"`
+	Object json.RawMessage `json:"object,omitempty"`
+	Time   time.Time       `json:"time"`
+}
+
+// eventColumns are the columns of the event tables.
+var eventColumns = []printers.Column{
+	{Header: "Event", Color: tablewriter.FgGreenColor, Value: func(obj interface{}) string {
+		return obj.(*Event).Type
+	}},
+	{Header: "Kind", Color: tablewriter.FgCyanColor, Value: func(obj interface{}) string {
+		return obj.(*Event).Kind
+	}},
+	{Header: "Name", Color: tablewriter.FgRedColor, Value: func(obj interface{}) string {
+		return obj.(*Event).Name
+	}},
+	{Header: "Owner", Wide: true, Color: tablewriter.FgWhiteColor, Value: func(obj interface{}) string {
+		return obj.(*Event).Owner
+	}},
+	{Header: "Time", Color: tablewriter.FgMagentaColor, Value: func(obj interface{}) string {
+		return obj.(*Event).Time.Local().Format("2006-01-02 15:04:05")
+	}},
+}
+
+// Flags are the watch flags of the get and list commands.
+type Flags struct {
+	Watch     bool
+	WatchOnly bool
+
+	config *restclient.Config
+	client *restclient.RESTClient
+}
+
+// NewFlags returns the watch flags.
+func NewFlags() *Flags {
+	return &Flags{}
+}
+
+// AddFlags adds --watch and --watch-only to cmd.
+func (f *Flags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&f.Watch, "watch", "w", f.Watch,
+		"After printing the requested resources, watch for changes.")
+	cmd.Flags().BoolVar(&f.WatchOnly, "watch-only", f.WatchOnly,
+		"Watch for changes to the requested resources, without printing them first.")
+}
+
+// Complete gets the clients of the watch from the factory.
+func (f *Flags) Complete(factory cmdutil.Factory) error {
+	if !f.Watch && !f.WatchOnly {
+		return nil
+	}
+
+	var err error
+
+	f.config, err = factory.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	f.client, err = factory.RESTClient()
+
+	return err
+}
+
+// Start starts watching the resources of the kind, and of the name when it is
+// not empty. It returns a nil watcher when the watch flags are not set. The
+// watch starts before the resources are printed, so that no change is missed.
+func (f *Flags) Start(kind, name string) (*Watcher, error) {
+	if !f.Watch && !f.WatchOnly {
+		return nil, nil
+	}
+
+	req := f.client.Get().AbsPath("/v1/events").Param("kind", kind)
+	if name != "" {
+		req = req.Param("name", name)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL().String(), nil)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	// the events are streamed, a timeout would end the watch.
+	config := *f.config
+	config.Timeout = 0
+
+	resp, err := cmdutil.DoRaw(&config, httpReq)
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		defer cancel()
+
+		return nil, fmt.Errorf("watch %s: %w", kind, cmdutil.ResponseError(resp))
+	}
+
+	return &Watcher{ctx: ctx, cancel: cancel, body: resp.Body}, nil
+}
+
+// Watcher reads the event stream of a watch.
+type Watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	body   io.ReadCloser
+}
+
+// Close stops the watch, it does nothing on a nil watcher.
+func (w *Watcher) Close() {
+	if w == nil {
+		return
+	}
+
+	w.cancel()
+	w.body.Close()
+}
+
+// Print prints the events in the output format of printFlags until the watch
+// is interrupted. It does nothing on a nil watcher.
+func (w *Watcher) Print(printFlags *printers.PrintFlags, out io.Writer) error {
+	if w == nil {
+		return nil
+	}
+
+	// the names are prefixed with the kind of each event.
+	flags := printers.NewPrintFlags("", eventColumns...)
+	flags.OutputFormat, flags.NoHeaders = printFlags.OutputFormat, printFlags.NoHeaders
+	flags.Name = func(obj interface{}) string {
+		return obj.(*Event).Kind + "/" + obj.(*Event).Name
+	}
+
+	return w.Events(func(e *Event) error {
+		if err := flags.Print(e, out); err != nil {
+			return err
+		}
+
+		// the header is only printed above the first event.
+		flags.NoHeaders = true
+
+		return nil
+	})
+}
+
+// Events calls handle with the events until the watch is interrupted.
+func (w *Watcher) Events(handle func(e *Event) error) error {
+	err := readEvents(w.body, handle)
+	if w.ctx.Err() != nil {
+		return nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("the watch was closed by the server")
+	}
+
+	return err
+}
+
+// readEvents parses the server-sent events of r, the comments and the empty
+// events are skipped.
+func readEvents(r io.Reader, handle func(e *Event) error) error {
+	reader := bufio.NewReader(r)
+
+	var data strings.Builder
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+
+			e := &Event{}
+			if err := json.Unmarshal([]byte(data.String()), e); err != nil {
+				return fmt.Errorf("invalid event: %w", err)
+			}
+
+			data.Reset()
+
+			if err := handle(e); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
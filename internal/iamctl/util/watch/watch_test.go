@@ -0,0 +1,75 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/iamctl/util/printers"
+)
+
+const stream = `event:ADDED
+data:{"type":"ADDED","kind":"user","name":"colin","tenant":"default","object":{"metadata":{"name":"colin"}},"time":"2020-06-01T10:00:00Z"}
+
+: heartbeat
+
+event:DELETED
+data:{"type":"DELETED","kind":"policy","name":"articles","tenant":"default","owner":"colin",
+data:"time":"2020-06-01T10:01:00Z"}
+
+`
+
+func TestReadEvents(t *testing.T) {
+	var events []*Event
+
+	err := readEvents(strings.NewReader(stream), func(e *Event) error {
+		events = append(events, e)
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "ADDED", events[0].Type)
+	assert.JSONEq(t, `{"metadata":{"name":"colin"}}`, string(events[0].Object))
+	assert.Equal(t, "articles", events[1].Name)
+	assert.Equal(t, "colin", events[1].Owner)
+
+	err = readEvents(strings.NewReader("data:{\n\n"), func(e *Event) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestWatcherPrint(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "name", want: "user/colin\npolicy/articles\n"},
+		{output: "jsonpath={.type} {.kind}/{.name}{\"\\n\"}", want: "ADDED user/colin\nDELETED policy/articles\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			w := &Watcher{ctx: ctx, cancel: cancel, body: ioutil.NopCloser(strings.NewReader(stream))}
+
+			out := &bytes.Buffer{}
+			err := w.Print(&printers.PrintFlags{OutputFormat: tt.output}, out)
+			assert.EqualError(t, err, "the watch was closed by the server")
+			assert.Equal(t, tt.want, out.String())
+
+			w.Close()
+		})
+	}
+
+	var w *Watcher
+	assert.NoError(t, w.Print(&printers.PrintFlags{}, &bytes.Buffer{}))
+	w.Close()
+}
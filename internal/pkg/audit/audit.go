@@ -0,0 +1,110 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package audit records who changed which resource, and how, during a request.
+// The audit middleware attaches a Recorder to the request, the services record
+// the changes of the resources on it and the middleware stores them once the
+// request is done. The changes are made already by then, so the failures to
+// record or store them are logged rather than returned.
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Key defines the key in gin context which holds the recorder of the request.
+// It is a plain string so that *gin.Context.Value can resolve it.
+const Key = "audit"
+
+// Define the actions recorded on the resources.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	// ActionLockout is recorded when a user or a client IP is locked out by
+	// too many failed logins.
+	ActionLockout = "lockout"
+)
+
+// Change is a change of a resource made by the request.
+type Change struct {
+	Action string
+	Kind   string
+	Name   string
+	// Owner is the user the policies and the secrets belong to.
+	Owner  string
+	Fields []FieldChange
+}
+
+// Recorder collects the changes made by a request.
+type Recorder struct {
+	mu      sync.Mutex
+	changes []*Change
+}
+
+// NewRecorder returns an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Changes returns the changes recorded so far, in the order they were made.
+func (r *Recorder) Changes() []*Change {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Change(nil), r.changes...)
+}
+
+func (r *Recorder) add(change *Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.changes = append(r.changes, change)
+}
+
+// FromContext returns the recorder of the request, nil when the request is not
+// audited.
+func FromContext(ctx context.Context) *Recorder {
+	if ctx == nil {
+		return nil
+	}
+
+	r, _ := ctx.Value(Key).(*Recorder)
+
+	return r
+}
+
+// NewContext returns a copy of ctx which carries the recorder.
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	// nolint: staticcheck // must be a plain string to be shared with gin.Context.
+	return context.WithValue(ctx, Key, r)
+}
+
+// Enabled tells whether the request of ctx is audited, so that the callers
+// only read the state of a resource before changing it when it is recorded.
+func Enabled(ctx context.Context) bool {
+	return FromContext(ctx) != nil
+}
+
+// Record records the change of a resource made by the request of ctx. Before
+// is nil for the creations and after is nil for the deletions, the fields
+// which hold credentials are redacted.
+func Record(ctx context.Context, action, kind, name, owner string, before, after interface{}) {
+	r := FromContext(ctx)
+	if r == nil {
+		return
+	}
+
+	fields, err := Diff(before, after)
+	if err != nil {
+		log.L(ctx).Errorw("record audit change failed", "kind", kind, "name", name, "error", err.Error())
+
+		return
+	}
+
+	r.add(&Change{Action: action, Kind: kind, Name: name, Owner: owner, Fields: fields})
+}
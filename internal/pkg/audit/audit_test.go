@@ -0,0 +1,94 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type metadata struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type user struct {
+	Metadata metadata `json:"metadata"`
+	Nickname string   `json:"nickname"`
+	Password string   `json:"password,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func fieldsOf(changes []FieldChange) map[string][2]string {
+	ret := map[string][2]string{}
+	for _, c := range changes {
+		ret[c.Field] = [2]string{string(c.Before), string(c.After)}
+	}
+
+	return ret
+}
+
+func TestDiff(t *testing.T) {
+	before := &user{
+		Metadata: metadata{Name: "maria", UpdatedAt: time.Unix(1, 0)},
+		Nickname: "maria",
+		Password: "$2a$10$old",
+		Tags:     []string{"a"},
+	}
+	after := &user{
+		Metadata: metadata{Name: "maria", UpdatedAt: time.Unix(2, 0)},
+		Nickname: "mary",
+		Password: "$2a$10$new",
+	}
+
+	changes, err := Diff(before, after)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][2]string{
+		"nickname": {`"maria"`, `"mary"`},
+		"password": {Redacted, Redacted},
+		"tags":     {`["a"]`, ""},
+	}, fieldsOf(changes))
+
+	changes, err = Diff(nil, after)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][2]string{
+		"metadata.name": {"", `"maria"`},
+		"nickname":      {"", `"mary"`},
+		"password":      {"", Redacted},
+	}, fieldsOf(changes))
+
+	var deleted *user
+	changes, err = Diff(before, deleted)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 4)
+
+	changes, err = Diff(before, before)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestRecord(t *testing.T) {
+	// not audited, nothing to record.
+	Record(context.Background(), ActionCreate, "user", "maria", "", nil, &user{Nickname: "maria"})
+
+	r := NewRecorder()
+	c := &gin.Context{}
+	c.Set(Key, r)
+	assert.True(t, Enabled(c))
+
+	Record(c, ActionUpdate, "user", "maria", "", &user{Nickname: "maria"}, &user{Nickname: "mary"})
+	Record(NewContext(context.Background(), r), ActionDelete, "secret", "ci", "maria", &user{}, nil)
+
+	changes := r.Changes()
+	assert.Len(t, changes, 2)
+	assert.Equal(t, ActionUpdate, changes[0].Action)
+	assert.Equal(t, []FieldChange{{Field: "nickname", Before: []byte(`"maria"`), After: []byte(`"mary"`)}},
+		changes[0].Fields)
+	assert.Equal(t, "maria", changes[1].Owner)
+}
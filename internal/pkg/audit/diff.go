@@ -0,0 +1,166 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces the values of the fields which hold credentials.
+const Redacted = `"[REDACTED]"`
+
+// redactedFields are the json names of the fields which hold credentials, or
+// hashes of them. Their changes are recorded, their values are not.
+var redactedFields = map[string]bool{
+	"password":    true,
+	"secretkey":   true,
+	"previouskey": true,
+	"privatekey":  true,
+	"token":       true,
+}
+
+// ignoredFields change on every update and would only add noise to the diffs.
+var ignoredFields = map[string]bool{
+	"updatedat": true,
+}
+
+// FieldChange is the change of one field of a resource. The nested fields are
+// named by their dotted json path, e.g. metadata.name, the values are json.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Diff returns the fields which differ between the json forms of before and
+// after, sorted by name. Either may be nil, e.g. for a creation all the fields
+// of after are returned.
+func Diff(before, after interface{}) ([]FieldChange, error) {
+	old, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+
+	for field, value := range cur {
+		if prev, ok := old[field]; !ok || !jsonEqual(prev, value) {
+			changes = append(changes, newFieldChange(field, prev, value))
+		}
+	}
+
+	for field, prev := range old {
+		if _, ok := cur[field]; !ok {
+			changes = append(changes, newFieldChange(field, prev, nil))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+func newFieldChange(field string, before, after json.RawMessage) FieldChange {
+	if redactedFields[strings.ToLower(lastSegment(field))] {
+		before, after = redact(before), redact(after)
+	}
+
+	return FieldChange{Field: field, Before: before, After: after}
+}
+
+// redact hides the value, a value which is not set stays empty so that the
+// diff still tells whether the credential was set, changed or removed.
+func redact(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 || string(value) == `""` || string(value) == "null" {
+		return value
+	}
+
+	return json.RawMessage(Redacted)
+}
+
+// flatten returns the leaves of the json form of obj by their dotted path. The
+// arrays are leaves, they are compared as a whole.
+func flatten(obj interface{}) (map[string]json.RawMessage, error) {
+	leaves := map[string]json.RawMessage{}
+	if isNil(obj) {
+		return leaves, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	walk("", root, leaves)
+
+	return leaves, nil
+}
+
+func walk(prefix string, node map[string]json.RawMessage, leaves map[string]json.RawMessage) {
+	for key, value := range node {
+		if ignoredFields[strings.ToLower(key)] {
+			continue
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		var child map[string]json.RawMessage
+		if bytes.HasPrefix(bytes.TrimSpace(value), []byte("{")) && json.Unmarshal(value, &child) == nil {
+			walk(path, child, leaves)
+
+			continue
+		}
+
+		leaves[path] = value
+	}
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
+
+func isNil(obj interface{}) bool {
+	if obj == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(obj)
+
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func lastSegment(field string) string {
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		return field[i+1:]
+	}
+
+	return field
+}
@@ -13,6 +13,18 @@ const (
 
 	// ErrUserAlreadyExist - 400: User already exist.
 	ErrUserAlreadyExist
+
+	// ErrUserPendingVerification - 403: User email has not been verified.
+	ErrUserPendingVerification
+
+	// ErrUserSuspended - 403: User has been suspended.
+	ErrUserSuspended
+
+	// ErrUserDisabled - 403: User has been disabled for inactivity.
+	ErrUserDisabled
+
+	// ErrUserStatusInvalid - 400: User status does not allow the operation.
+	ErrUserStatusInvalid
 )
 
 // iam-apiserver: secret errors.
@@ -29,3 +41,126 @@ const (
 	// ErrPolicyNotFound - 404: Policy not found.
 	ErrPolicyNotFound int = iota + 110201
 )
+
+// iam-apiserver: group errors.
+const (
+	// ErrGroupNotFound - 404: Group not found.
+	ErrGroupNotFound int = iota + 110301
+
+	// ErrGroupAlreadyExist - 400: Group already exist.
+	ErrGroupAlreadyExist
+)
+
+// iam-apiserver: role errors.
+const (
+	// ErrRoleNotFound - 404: Role not found.
+	ErrRoleNotFound int = iota + 110401
+
+	// ErrRoleAlreadyExist - 400: Role already exist.
+	ErrRoleAlreadyExist
+)
+
+// iam-apiserver: tenant errors.
+const (
+	// ErrTenantNotFound - 404: Tenant not found.
+	ErrTenantNotFound int = iota + 110501
+
+	// ErrTenantAlreadyExist - 400: Tenant already exist.
+	ErrTenantAlreadyExist
+
+	// ErrTenantNotEmpty - 400: Tenant still has members.
+	ErrTenantNotEmpty
+)
+
+// iam-apiserver: session errors.
+const (
+	// ErrSessionNotFound - 404: Session not found.
+	ErrSessionNotFound int = iota + 110601
+
+	// ErrRefreshTokenInvalid - 401: Refresh token is invalid or expired.
+	ErrRefreshTokenInvalid
+
+	// ErrRefreshTokenReused - 401: Refresh token has already been used, the session is revoked.
+	ErrRefreshTokenReused
+)
+
+// iam-apiserver: two-factor authentication errors.
+const (
+	// ErrTOTPNotEnabled - 400: Two-factor authentication is not enabled.
+	ErrTOTPNotEnabled int = iota + 110701
+
+	// ErrTOTPAlreadyEnabled - 400: Two-factor authentication is already enabled.
+	ErrTOTPAlreadyEnabled
+
+	// ErrTOTPCodeInvalid - 401: Two-factor authentication code is invalid.
+	ErrTOTPCodeInvalid
+
+	// ErrTOTPChallengeInvalid - 401: Two-factor authentication challenge is invalid or expired.
+	ErrTOTPChallengeInvalid
+)
+
+// iam-apiserver: password reset errors.
+const (
+	// ErrPasswordResetTokenInvalid - 400: Password reset token is invalid or expired.
+	ErrPasswordResetTokenInvalid int = iota + 110801
+)
+
+// iam-apiserver: email verification errors.
+const (
+	// ErrEmailVerificationTokenInvalid - 400: Email verification token is invalid or expired.
+	ErrEmailVerificationTokenInvalid int = iota + 110901
+)
+
+// iam-apiserver: external identity errors.
+const (
+	// ErrIdentityNotLinked - 403: External identity is not linked to any user.
+	ErrIdentityNotLinked int = iota + 111001
+
+	// ErrIdentityConflict - 403: User already exists and is not linked to the external identity.
+	ErrIdentityConflict
+
+	// ErrOIDCStateInvalid - 400: OpenID Connect login state is invalid or expired.
+	ErrOIDCStateInvalid
+
+	// ErrOIDCLoginFailed - 401: OpenID Connect login failed.
+	ErrOIDCLoginFailed
+)
+
+// iam-apiserver: personal access token errors.
+const (
+	// ErrAccessTokenNotFound - 404: Access token not found.
+	ErrAccessTokenNotFound int = iota + 111101
+
+	// ErrAccessTokenAlreadyExist - 400: Access token already exist.
+	ErrAccessTokenAlreadyExist
+
+	// ErrAccessTokenInvalid - 401: Access token is invalid or expired.
+	ErrAccessTokenInvalid
+)
+
+// iam-apiserver: catalog import and export errors.
+const (
+	// ErrImportJobNotFound - 404: Import job not found.
+	ErrImportJobNotFound int = iota + 111201
+
+	// ErrCatalogFormat - 400: Unsupported catalog format, expected csv or jsonl.
+	ErrCatalogFormat
+)
+
+// iam-apiserver: backup and restore errors.
+const (
+	// ErrBackupArchive - 400: Backup archive is invalid or of an unsupported version.
+	ErrBackupArchive int = iota + 111301
+
+	// ErrBackupPassphrase - 400: Backup passphrase is missing or wrong.
+	ErrBackupPassphrase
+
+	// ErrRestoreConflict - 400: Restored resource already exists.
+	ErrRestoreConflict
+)
+
+// iam-apiserver: audit log errors.
+const (
+	// ErrAuditLogNotFound - 404: Audit log not found.
+	ErrAuditLogNotFound int = iota + 111401
+)
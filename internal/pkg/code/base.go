@@ -57,6 +57,12 @@ const (
 
 	// PermissionDenied - 403: Permission denied.
 	ErrPermissionDenied
+
+	// ErrTokenRevoked - 401: Token has been revoked.
+	ErrTokenRevoked
+
+	// ErrAccountLocked - 403: Too many failed logins, try again later.
+	ErrAccountLocked
 )
 
 // common: encode/decode errors.
@@ -10,9 +10,42 @@ package code
 func init() {
 	register(ErrUserNotFound, 404, "User not found")
 	register(ErrUserAlreadyExist, 400, "User already exist")
+	register(ErrUserPendingVerification, 403, "User email has not been verified")
+	register(ErrUserSuspended, 403, "User has been suspended")
+	register(ErrUserDisabled, 403, "User has been disabled for inactivity")
+	register(ErrUserStatusInvalid, 400, "User status does not allow the operation")
 	register(ErrReachMaxCount, 400, "Secret reach the max count")
 	register(ErrSecretNotFound, 404, "Secret not found")
 	register(ErrPolicyNotFound, 404, "Policy not found")
+	register(ErrGroupNotFound, 404, "Group not found")
+	register(ErrGroupAlreadyExist, 400, "Group already exist")
+	register(ErrRoleNotFound, 404, "Role not found")
+	register(ErrRoleAlreadyExist, 400, "Role already exist")
+	register(ErrTenantNotFound, 404, "Tenant not found")
+	register(ErrTenantAlreadyExist, 400, "Tenant already exist")
+	register(ErrTenantNotEmpty, 400, "Tenant still has members")
+	register(ErrSessionNotFound, 404, "Session not found")
+	register(ErrRefreshTokenInvalid, 401, "Refresh token is invalid or expired")
+	register(ErrRefreshTokenReused, 401, "Refresh token has already been used, the session is revoked")
+	register(ErrTOTPNotEnabled, 400, "Two-factor authentication is not enabled")
+	register(ErrTOTPAlreadyEnabled, 400, "Two-factor authentication is already enabled")
+	register(ErrTOTPCodeInvalid, 401, "Two-factor authentication code is invalid")
+	register(ErrTOTPChallengeInvalid, 401, "Two-factor authentication challenge is invalid or expired")
+	register(ErrPasswordResetTokenInvalid, 400, "Password reset token is invalid or expired")
+	register(ErrEmailVerificationTokenInvalid, 400, "Email verification token is invalid or expired")
+	register(ErrIdentityNotLinked, 403, "External identity is not linked to any user")
+	register(ErrIdentityConflict, 403, "User already exists and is not linked to the external identity")
+	register(ErrOIDCStateInvalid, 400, "OpenID Connect login state is invalid or expired")
+	register(ErrOIDCLoginFailed, 401, "OpenID Connect login failed")
+	register(ErrAccessTokenNotFound, 404, "Access token not found")
+	register(ErrAccessTokenAlreadyExist, 400, "Access token already exist")
+	register(ErrAccessTokenInvalid, 401, "Access token is invalid or expired")
+	register(ErrImportJobNotFound, 404, "Import job not found")
+	register(ErrCatalogFormat, 400, "Unsupported catalog format, expected csv or jsonl")
+	register(ErrBackupArchive, 400, "Backup archive is invalid or of an unsupported version")
+	register(ErrBackupPassphrase, 400, "Backup passphrase is missing or wrong")
+	register(ErrRestoreConflict, 400, "Restored resource already exists")
+	register(ErrAuditLogNotFound, 404, "Audit log not found")
 	register(ErrSuccess, 200, "OK")
 	register(ErrUnknown, 500, "Internal server error")
 	register(ErrBind, 400, "Error occurred while binding the request body to the struct")
@@ -27,6 +60,8 @@ func init() {
 	register(ErrMissingHeader, 401, "The `Authorization` header was empty")
 	register(ErrPasswordIncorrect, 401, "Password was incorrect")
 	register(ErrPermissionDenied, 403, "Permission denied")
+	register(ErrTokenRevoked, 401, "Token has been revoked")
+	register(ErrAccountLocked, 403, "Too many failed logins, try again later")
 	register(ErrEncodingFailed, 500, "Encoding failed due to an error with the data")
 	register(ErrDecodingFailed, 500, "Decoding failed due to an error with the data")
 	register(ErrInvalidJSON, 500, "Data is not valid JSON")
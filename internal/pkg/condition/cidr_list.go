@@ -0,0 +1,64 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ory/ladon"
+)
+
+// CIDRListCondition makes sure that the requests' IP address is in one of the
+// given CIDRs.
+type CIDRListCondition struct {
+	CIDRs []string `json:"cidrs"`
+}
+
+// Fulfills returns true if the request is fulfilled by the condition.
+func (c *CIDRListCondition) Fulfills(value interface{}, _ *ladon.Request) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range c.CIDRs {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetName returns the condition's name.
+func (c *CIDRListCondition) GetName() string {
+	return "CIDRListCondition"
+}
+
+// Validate checks that all the CIDRs can be parsed.
+func (c *CIDRListCondition) Validate() error {
+	if len(c.CIDRs) == 0 {
+		return fmt.Errorf("cidrs must not be empty")
+	}
+
+	for _, cidr := range c.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid cidr %q", cidr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ory/ladon"
+)
+
+// now is used to read the current time, replaced in tests.
+var now = time.Now
+
+func init() {
+	for _, factory := range []func() ladon.Condition{
+		func() ladon.Condition { return new(ResourceOwnerCondition) },
+		func() ladon.Condition { return new(TimeWindowCondition) },
+		func() ladon.Condition { return new(CIDRListCondition) },
+		func() ladon.Condition { return new(NumericCompareCondition) },
+	} {
+		ladon.ConditionFactories[factory().GetName()] = factory
+	}
+}
+
+// validator is implemented by conditions which can check their own options.
+type validator interface {
+	Validate() error
+}
+
+// Validate checks the options of all the given conditions and returns the
+// first invalid one.
+func Validate(conditions ladon.Conditions) error {
+	for key, c := range conditions {
+		v, ok := c.(validator)
+		if !ok {
+			continue
+		}
+
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("condition %s(%s): %w", key, c.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// toFloat converts a context value into a float64. JSON numbers are decoded
+// as float64 and numeric strings are accepted as well.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
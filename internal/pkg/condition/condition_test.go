@@ -0,0 +1,71 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ory/ladon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalPolicy(t *testing.T) {
+	data := `{
+		"id": "seller",
+		"subjects": ["users:<.*>"],
+		"resources": ["resources:items:<.*>"],
+		"actions": ["update"],
+		"effect": "allow",
+		"conditions": {
+			"brandOwner": {"type": "ResourceOwnerCondition"},
+			"businessHours": {"type": "TimeWindowCondition", "options": {"start": "09:00", "end": "18:00"}},
+			"remoteIP": {"type": "CIDRListCondition", "options": {"cidrs": ["10.0.0.0/8"]}},
+			"price": {"type": "NumericCompareCondition", "options": {"operator": "lte", "value": 100}}
+		}
+	}`
+
+	var policy ladon.DefaultPolicy
+	assert.Nil(t, json.Unmarshal([]byte(data), &policy))
+	assert.Len(t, policy.Conditions, 4)
+	assert.Nil(t, Validate(policy.Conditions))
+
+	policy.Conditions["price"] = &NumericCompareCondition{Operator: "between"}
+	assert.NotNil(t, Validate(policy.Conditions))
+}
+
+func TestFulfills(t *testing.T) {
+	now = func() time.Time { return time.Date(2020, 6, 1, 10, 30, 0, 0, time.UTC) } // Monday
+	defer func() { now = time.Now }()
+
+	r := &ladon.Request{Context: ladon.Context{"username": "maria"}}
+
+	tests := []struct {
+		name      string
+		condition ladon.Condition
+		value     interface{}
+		want      bool
+	}{
+		{"owner matches", &ResourceOwnerCondition{}, "maria", true},
+		{"owner in list", &ResourceOwnerCondition{}, []interface{}{"colin", "maria"}, true},
+		{"owner mismatch", &ResourceOwnerCondition{}, "colin", false},
+		{"inside window", &TimeWindowCondition{Start: "09:00", End: "18:00", Timezone: "UTC"}, nil, true},
+		{"outside window", &TimeWindowCondition{Start: "11:00", End: "18:00", Timezone: "UTC"}, nil, false},
+		{"window over midnight", &TimeWindowCondition{Start: "22:00", End: "11:00", Timezone: "UTC"}, nil, true},
+		{"wrong weekday", &TimeWindowCondition{Start: "09:00", End: "18:00", Weekdays: []string{"Sat"}, Timezone: "UTC"}, nil, false},
+		{"ip in list", &CIDRListCondition{CIDRs: []string{"192.168.0.0/16", "10.0.0.0/8"}}, "10.1.2.3", true},
+		{"ip not in list", &CIDRListCondition{CIDRs: []string{"192.168.0.0/16"}}, "10.1.2.3", false},
+		{"number lte", &NumericCompareCondition{Operator: OperatorLessThanOrEqual, Value: 100}, float64(100), true},
+		{"numeric string gt", &NumericCompareCondition{Operator: OperatorGreaterThan, Value: 100}, "99.5", false},
+		{"not a number", &NumericCompareCondition{Operator: OperatorEqual, Value: 1}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.condition.Fulfills(tt.value, r))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package condition defines the custom ladon conditions used by iam policies.
+// Importing the package registers the conditions with ladon, so policies using
+// them can be unmarshalled by both iam-apiserver and iam-authz-server.
+package condition
@@ -0,0 +1,69 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import (
+	"fmt"
+
+	"github.com/ory/ladon"
+)
+
+// Supported operators of NumericCompareCondition.
+const (
+	OperatorEqual              = "eq"
+	OperatorNotEqual           = "ne"
+	OperatorLessThan           = "lt"
+	OperatorLessThanOrEqual    = "lte"
+	OperatorGreaterThan        = "gt"
+	OperatorGreaterThanOrEqual = "gte"
+)
+
+// NumericCompareCondition makes sure that the numeric context value compares
+// to the given value with the given operator, e.g. `price lte 100`.
+type NumericCompareCondition struct {
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+}
+
+// Fulfills returns true if the request is fulfilled by the condition.
+func (c *NumericCompareCondition) Fulfills(value interface{}, _ *ladon.Request) bool {
+	v, ok := toFloat(value)
+	if !ok {
+		return false
+	}
+
+	switch c.Operator {
+	case OperatorEqual:
+		return v == c.Value
+	case OperatorNotEqual:
+		return v != c.Value
+	case OperatorLessThan:
+		return v < c.Value
+	case OperatorLessThanOrEqual:
+		return v <= c.Value
+	case OperatorGreaterThan:
+		return v > c.Value
+	case OperatorGreaterThanOrEqual:
+		return v >= c.Value
+	default:
+		return false
+	}
+}
+
+// GetName returns the condition's name.
+func (c *NumericCompareCondition) GetName() string {
+	return "NumericCompareCondition"
+}
+
+// Validate checks that the operator is supported.
+func (c *NumericCompareCondition) Validate() error {
+	switch c.Operator {
+	case OperatorEqual, OperatorNotEqual, OperatorLessThan, OperatorLessThanOrEqual,
+		OperatorGreaterThan, OperatorGreaterThanOrEqual:
+		return nil
+	default:
+		return fmt.Errorf("unsupported operator %q", c.Operator)
+	}
+}
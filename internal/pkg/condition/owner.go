@@ -0,0 +1,59 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import "github.com/ory/ladon"
+
+// ResourceOwnerCondition makes sure that the requester owns the resource. The
+// checked context value holds the owner, or a list of owners, of the resource
+// and is compared with the requester identity read from the `field` context
+// key, `username` by default.
+//
+//	"conditions": {
+//	    "brandOwner": {
+//	        "type": "ResourceOwnerCondition",
+//	        "options": {"field": "username"}
+//	    }
+//	}
+type ResourceOwnerCondition struct {
+	Field string `json:"field,omitempty"`
+}
+
+// Fulfills returns true if the request is fulfilled by the condition.
+func (c *ResourceOwnerCondition) Fulfills(value interface{}, r *ladon.Request) bool {
+	field := c.Field
+	if field == "" {
+		field = "username"
+	}
+
+	requester, ok := r.Context[field].(string)
+	if !ok || requester == "" {
+		return false
+	}
+
+	switch owner := value.(type) {
+	case string:
+		return owner == requester
+	case []string:
+		for _, o := range owner {
+			if o == requester {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, o := range owner {
+			if s, ok := o.(string); ok && s == requester {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GetName returns the condition's name.
+func (c *ResourceOwnerCondition) GetName() string {
+	return "ResourceOwnerCondition"
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package condition
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ory/ladon"
+)
+
+const clockLayout = "15:04"
+
+// TimeWindowCondition makes sure that the request happens inside a daily time
+// window, e.g. business hours. The window is evaluated against the server clock,
+// the context value is ignored so the client can not fake the time. A window
+// whose end is before its start spans midnight.
+//
+//	"conditions": {
+//	    "businessHours": {
+//	        "type": "TimeWindowCondition",
+//	        "options": {
+//	            "start": "09:00",
+//	            "end": "18:00",
+//	            "weekdays": ["Mon", "Tue", "Wed", "Thu", "Fri"],
+//	            "timezone": "Asia/Shanghai"
+//	        }
+//	    }
+//	}
+type TimeWindowCondition struct {
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Weekdays []string `json:"weekdays,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+// Fulfills returns true if the request is fulfilled by the condition.
+func (c *TimeWindowCondition) Fulfills(_ interface{}, _ *ladon.Request) bool {
+	start, err := time.Parse(clockLayout, c.Start)
+	if err != nil {
+		return false
+	}
+
+	end, err := time.Parse(clockLayout, c.End)
+	if err != nil {
+		return false
+	}
+
+	loc := time.Local
+	if c.Timezone != "" {
+		if loc, err = time.LoadLocation(c.Timezone); err != nil {
+			return false
+		}
+	}
+
+	t := now().In(loc)
+	if len(c.Weekdays) > 0 && !c.matchWeekday(t.Weekday()) {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return minute >= from && minute < to
+	}
+
+	return minute >= from || minute < to
+}
+
+// GetName returns the condition's name.
+func (c *TimeWindowCondition) GetName() string {
+	return "TimeWindowCondition"
+}
+
+// Validate checks the clock times, weekdays and timezone of the window.
+func (c *TimeWindowCondition) Validate() error {
+	if _, err := time.Parse(clockLayout, c.Start); err != nil {
+		return fmt.Errorf("invalid start %q, must be in HH:MM format", c.Start)
+	}
+
+	if _, err := time.Parse(clockLayout, c.End); err != nil {
+		return fmt.Errorf("invalid end %q, must be in HH:MM format", c.End)
+	}
+
+	for _, day := range c.Weekdays {
+		if _, ok := parseWeekday(day); !ok {
+			return fmt.Errorf("invalid weekday %q", day)
+		}
+	}
+
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q", c.Timezone)
+		}
+	}
+
+	return nil
+}
+
+func (c *TimeWindowCondition) matchWeekday(weekday time.Weekday) bool {
+	for _, day := range c.Weekdays {
+		if d, ok := parseWeekday(day); ok && d == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseWeekday accepts both the full and the abbreviated english weekday name.
+func parseWeekday(day string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(day, d.String()) || strings.EqualFold(day, d.String()[:3]) {
+			return d, true
+		}
+	}
+
+	return time.Sunday, false
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package event streams the changes of the users, policies, secrets and items
+// to the clients which watch them. The events are published on a redis pub/sub
+// channel, so that the clients of every iam-apiserver instance see the changes
+// made through any of them.
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// RedisPubSubChannel is the redis channel the events are published on. It is
+// not the channel of the notifications of iam-authz-server, which only carry
+// a bare reload command.
+const RedisPubSubChannel = "iam.cluster.events"
+
+// Type tells how the resource changed.
+type Type string
+
+// Define the types of the events.
+const (
+	Added    Type = "ADDED"
+	Modified Type = "MODIFIED"
+	Deleted  Type = "DELETED"
+)
+
+// Define the kinds of the resources which can be watched.
+const (
+	KindUser   = "user"
+	KindPolicy = "policy"
+	KindSecret = "secret"
+	KindItem   = "item"
+)
+
+// Kinds are the kinds of the resources which can be watched.
+var Kinds = []string{KindUser, KindPolicy, KindSecret, KindItem}
+
+// Event is a change of a resource.
+type Event struct {
+	Type Type   `json:"type"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Tenant is the tenant of the request which made the change, only the
+	// members of the tenant see the event.
+	Tenant string `json:"tenant"`
+	// Owner is the user the policies and the secrets belong to.
+	Owner string `json:"owner,omitempty"`
+	// Object is the resource after the change, without its credentials. It is
+	// empty for the deletions.
+	Object json.RawMessage `json:"object,omitempty"`
+	Time   time.Time       `json:"time"`
+}
+
+// New returns the event of a change made by the request of ctx. The object must
+// not hold any credential, it is sent as-is to the watchers.
+func New(ctx context.Context, typ Type, kind, name, owner string, object interface{}) (*Event, error) {
+	e := &Event{Type: typ, Kind: kind, Name: name, Owner: owner, Time: time.Now()}
+
+	e.Tenant, _ = tenant.FromContext(ctx)
+	if e.Tenant == "" {
+		e.Tenant = tenant.Default
+	}
+
+	if object != nil {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+
+		e.Object = data
+	}
+
+	return e, nil
+}
+
+// Broker publishes the events and delivers them to the subscriptions.
+type Broker interface {
+	// Publish delivers the event to the subscriptions of all the instances.
+	Publish(e *Event) error
+	// Subscribe returns a subscription to all the events, which buffers size
+	// events at most.
+	Subscribe(size int) *Subscription
+}
+
+var (
+	client Broker = NewHub(&redisPubSub{})
+	mu     sync.RWMutex
+)
+
+// Client returns the event broker instance.
+func Client() Broker {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return client
+}
+
+// SetClient set the event broker, the hub of the redis channel is used by default.
+func SetClient(b Broker) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	client = b
+}
+
+// Publish publishes the event of a change made by the request of ctx.
+func Publish(ctx context.Context, typ Type, kind, name, owner string, object interface{}) {
+	e, err := New(ctx, typ, kind, name, owner, object)
+	if err == nil {
+		err = Client().Publish(e)
+	}
+
+	if err != nil {
+		log.L(ctx).Errorw("publish event failed", "kind", kind, "name", name, "error", err.Error())
+	}
+}
+
+// Filter selects the events a user may see.
+type Filter struct {
+	Tenant   string
+	Username string
+	// Admin is set for the administrators, who see the changes of all the
+	// users, policies and secrets of the tenant.
+	Admin bool
+	// Kinds are the kinds of the resources watched, all when empty.
+	Kinds []string
+	// Name is the name of the resource watched, all when empty.
+	Name string
+}
+
+// Match tells whether the event passes the filter.
+func (f *Filter) Match(e *Event) bool {
+	if e.Tenant != f.Tenant || (f.Name != "" && e.Name != f.Name) {
+		return false
+	}
+
+	if len(f.Kinds) > 0 && !contains(f.Kinds, e.Kind) {
+		return false
+	}
+
+	switch e.Kind {
+	case KindUser:
+		return f.Admin || e.Name == f.Username
+	case KindPolicy, KindSecret:
+		return f.Admin || e.Owner == f.Username
+	default:
+		return true
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
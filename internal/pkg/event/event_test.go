@@ -0,0 +1,182 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+// fakePubSub loops the published messages back to the subscriber, like a
+// redis channel with a single instance.
+type fakePubSub struct {
+	mu       sync.Mutex
+	down     bool
+	callback func(interface{})
+	stop     chan struct{}
+}
+
+func (f *fakePubSub) Publish(channel, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down || f.callback == nil {
+		return errors.New("redis is down")
+	}
+
+	f.callback(&redis.Message{Channel: channel, Payload: message})
+
+	return nil
+}
+
+func (f *fakePubSub) StartPubSubHandler(channel string, callback func(interface{})) error {
+	f.mu.Lock()
+	f.callback = callback
+	f.mu.Unlock()
+
+	<-f.stop
+
+	return errors.New("closed")
+}
+
+func TestNew(t *testing.T) {
+	ctx := tenant.NewContext(context.Background(), "shop")
+
+	e, err := New(ctx, Added, KindPolicy, "articles", "colin", map[string]string{"name": "articles"})
+	assert.NoError(t, err)
+	assert.Equal(t, "shop", e.Tenant)
+	assert.Equal(t, "colin", e.Owner)
+	assert.JSONEq(t, `{"name":"articles"}`, string(e.Object))
+
+	e, err = New(context.Background(), Deleted, KindItem, "1", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tenant.Default, e.Tenant)
+	assert.Nil(t, e.Object)
+}
+
+func TestFilterMatch(t *testing.T) {
+	events := map[string]*Event{
+		"own user":        {Kind: KindUser, Name: "colin", Tenant: "shop"},
+		"other user":      {Kind: KindUser, Name: "maria", Tenant: "shop"},
+		"own policy":      {Kind: KindPolicy, Name: "articles", Owner: "colin", Tenant: "shop"},
+		"other secret":    {Kind: KindSecret, Name: "ci", Owner: "maria", Tenant: "shop"},
+		"item":            {Kind: KindItem, Name: "1", Tenant: "shop"},
+		"other tenant":    {Kind: KindItem, Name: "1", Tenant: "default"},
+		"named policy":    {Kind: KindPolicy, Name: "comments", Owner: "colin", Tenant: "shop"},
+		"other kind item": {Kind: KindItem, Name: "articles", Tenant: "shop"},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "user",
+			filter: Filter{Tenant: "shop", Username: "colin"},
+			want:   []string{"own user", "own policy", "item", "named policy", "other kind item"},
+		},
+		{
+			name:   "admin",
+			filter: Filter{Tenant: "shop", Username: "colin", Admin: true},
+			want: []string{
+				"own user", "other user", "own policy", "other secret", "item", "named policy", "other kind item",
+			},
+		},
+		{
+			name:   "kind and name",
+			filter: Filter{Tenant: "shop", Username: "colin", Kinds: []string{KindPolicy}, Name: "articles"},
+			want:   []string{"own policy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+
+			for name, e := range events {
+				if tt.filter.Match(e) {
+					got = append(got, name)
+				}
+			}
+
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestHub(t *testing.T) {
+	pubsub := &fakePubSub{stop: make(chan struct{})}
+	hub := NewHub(pubsub)
+
+	// not subscribed yet, the events are delivered locally.
+	s := hub.Subscribe(10)
+	assert.NoError(t, hub.Publish(&Event{Kind: KindItem, Name: "1"}))
+	assert.Equal(t, "1", (<-s.C).Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		hub.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		pubsub.mu.Lock()
+		defer pubsub.mu.Unlock()
+
+		return pubsub.callback != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// through the channel, the object is kept as-is.
+	assert.NoError(t, hub.Publish(&Event{Kind: KindItem, Name: "2", Object: json.RawMessage(`{"id":2}`)}))
+	e := <-s.C
+	assert.Equal(t, "2", e.Name)
+	assert.JSONEq(t, `{"id":2}`, string(e.Object))
+
+	// redis is down, the events are still delivered locally.
+	pubsub.mu.Lock()
+	pubsub.down = true
+	pubsub.mu.Unlock()
+	assert.NoError(t, hub.Publish(&Event{Kind: KindItem, Name: "3"}))
+	assert.Equal(t, "3", (<-s.C).Name)
+
+	cancel()
+	<-done
+
+	_, ok := <-s.C
+	assert.False(t, ok)
+	close(pubsub.stop)
+}
+
+func TestHubSlowSubscription(t *testing.T) {
+	hub := NewHub(&fakePubSub{stop: make(chan struct{})})
+
+	slow, fast := hub.Subscribe(1), hub.Subscribe(2)
+	assert.NoError(t, hub.Publish(&Event{Name: "1"}))
+	assert.NoError(t, hub.Publish(&Event{Name: "2"}))
+
+	assert.Equal(t, "1", (<-slow.C).Name)
+	_, ok := <-slow.C
+	assert.False(t, ok)
+
+	assert.Equal(t, "1", (<-fast.C).Name)
+	assert.Equal(t, "2", (<-fast.C).Name)
+
+	// closing twice, or after the hub, is fine.
+	slow.Close()
+	fast.Close()
+	fast.Close()
+}
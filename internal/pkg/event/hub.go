@@ -0,0 +1,176 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
+)
+
+// resubscribeInterval is how long the hub waits before it subscribes to the
+// redis channel again, e.g. when redis is down.
+const resubscribeInterval = time.Second
+
+// PubSub is a pub/sub channel shared by the iam-apiserver instances.
+type PubSub interface {
+	Publish(channel, message string) error
+	// StartPubSubHandler calls callback with the messages of the channel, it
+	// only returns when the subscription fails.
+	StartPubSubHandler(channel string, callback func(interface{})) error
+}
+
+// redisPubSub implements PubSub with the shared redis cluster.
+type redisPubSub struct {
+	storage.RedisCluster
+}
+
+// Hub implements Broker: the events are published on the pub/sub channel, and
+// the hub delivers the events of the channel to the subscriptions of this
+// instance. The events are delivered locally when the hub is not subscribed,
+// so that a single instance works without redis.
+type Hub struct {
+	pubsub     PubSub
+	subscribed int32
+
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+}
+
+var _ Broker = (*Hub)(nil)
+
+// NewHub returns a hub of the pub/sub channel.
+func NewHub(pubsub PubSub) *Hub {
+	return &Hub{pubsub: pubsub, subscriptions: map[*Subscription]struct{}{}}
+}
+
+// Run subscribes to the pub/sub channel until ctx is done, then closes the
+// subscriptions so that the streams of the watchers end.
+func (h *Hub) Run(ctx context.Context) {
+	go func() {
+		for {
+			atomic.StoreInt32(&h.subscribed, 1)
+			err := h.pubsub.StartPubSubHandler(RedisPubSubChannel, h.handleMessage)
+			atomic.StoreInt32(&h.subscribed, 0)
+
+			if err != nil {
+				log.Debugf("subscribe to the events failed: %s", err.Error())
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(resubscribeInterval):
+			}
+		}
+	}()
+
+	<-ctx.Done()
+	h.Close()
+}
+
+// Publish implements Broker.
+func (h *Hub) Publish(e *Event) error {
+	if atomic.LoadInt32(&h.subscribed) == 1 {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		if err := h.pubsub.Publish(RedisPubSubChannel, string(data)); err == nil {
+			return nil
+		}
+	}
+
+	h.dispatch(e)
+
+	return nil
+}
+
+// Subscribe implements Broker.
+func (h *Hub) Subscribe(size int) *Subscription {
+	c := make(chan *Event, size)
+	s := &Subscription{C: c, c: c, hub: h}
+
+	h.mu.Lock()
+	h.subscriptions[s] = struct{}{}
+	h.mu.Unlock()
+
+	return s
+}
+
+// Close closes all the subscriptions.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscriptions {
+		h.remove(s)
+	}
+}
+
+func (h *Hub) handleMessage(v interface{}) {
+	message, ok := v.(*redis.Message)
+	if !ok {
+		return
+	}
+
+	e := &Event{}
+	if err := json.Unmarshal([]byte(message.Payload), e); err != nil {
+		log.Errorf("Unmarshalling event failed, malformed: %s", err.Error())
+
+		return
+	}
+
+	h.dispatch(e)
+}
+
+// dispatch delivers the event to the subscriptions. A subscription whose
+// buffer is full is closed, its watcher is too slow and must watch again.
+func (h *Hub) dispatch(e *Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscriptions {
+		select {
+		case s.c <- e:
+		default:
+			log.Warnf("close the event subscription of a slow watcher")
+			h.remove(s)
+		}
+	}
+}
+
+// remove closes the subscription, h.mu must be held.
+func (h *Hub) remove(s *Subscription) {
+	if _, ok := h.subscriptions[s]; ok {
+		delete(h.subscriptions, s)
+		close(s.c)
+	}
+}
+
+// Subscription receives the events published after it was made. C is closed
+// when the subscription is closed, either by its watcher or by the hub.
+type Subscription struct {
+	C <-chan *Event
+
+	c   chan *Event
+	hub *Hub
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
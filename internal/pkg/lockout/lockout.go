@@ -0,0 +1,264 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package lockout protects the password authentication against brute force
+// and credential stuffing. Failed logins are counted per username and per
+// client IP, and once a counter goes over its limit the username or the IP is
+// locked out for a duration which doubles with every further failure.
+package lockout
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
+)
+
+const (
+	failuresKeyPrefix = "iam-login-failures-"
+	lockKeyPrefix     = "iam-login-locked-"
+
+	userKind = "user-"
+	ipKind   = "ip-"
+)
+
+// Store defines the storage of the failure counters and the lockouts.
+type Store interface {
+	// Fail counts a failure of the key and returns the number of failures,
+	// they are forgotten after window without any new failure.
+	Fail(key string, window time.Duration) (int64, error)
+	// Reset forgets the failures and the lockout of the key.
+	Reset(key string) error
+	// Lock locks the key out for ttl.
+	Lock(key string, ttl time.Duration) error
+	// LockedFor returns how long the key is still locked out, zero if it is not.
+	LockedFor(key string) (time.Duration, error)
+}
+
+// Policy defines the limits of the failed logins.
+type Policy struct {
+	// MaxUserFailures is the number of failures of a username before it is locked out.
+	MaxUserFailures int64
+	// MaxIPFailures is the number of failures of a client IP before it is locked out.
+	MaxIPFailures int64
+	// Window is how long the failures are remembered after the last one.
+	Window time.Duration
+	// BaseLockout is the duration of the first lockout, it doubles with every further failure.
+	BaseLockout time.Duration
+	// MaxLockout caps the duration of the lockouts.
+	MaxLockout time.Duration
+}
+
+var (
+	client Store = &redisStore{}
+	mu     sync.RWMutex
+)
+
+// Client returns the lockout store client instance.
+func Client() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return client
+}
+
+// SetClient set the lockout store client, the redis store is used by default.
+func SetClient(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	client = s
+}
+
+// Define what a lockout locks out.
+const (
+	LockedUser = "user"
+	LockedIP   = "ip"
+)
+
+// Event is a lockout, recorded for the audit.
+type Event struct {
+	Username string `json:"username"`
+	ClientIP string `json:"clientIP"`
+	// LockedOut is either LockedUser or LockedIP.
+	LockedOut string    `json:"lockedOut"`
+	Failures  int64     `json:"failures"`
+	Until     time.Time `json:"until"`
+}
+
+// Limiter applies a policy to the failed logins. Counting is best effort: the
+// logins are allowed when the store is unavailable.
+type Limiter struct {
+	store  Store
+	policy Policy
+	record func(e *Event)
+}
+
+// NewLimiter creates a limiter applying the policy with the given store. The
+// lockouts are recorded with record, a nil record only logs them.
+func NewLimiter(store Store, policy Policy, record func(e *Event)) *Limiter {
+	return &Limiter{store: store, policy: policy, record: record}
+}
+
+// Allow returns a code.ErrAccountLocked error if the username or the client IP
+// is locked out.
+func (l *Limiter) Allow(username, clientIP string) error {
+	for _, key := range l.keys(username, clientIP) {
+		ttl, err := l.store.LockedFor(key)
+		if err != nil {
+			log.Warnf("get login lockout of %s failed: %s", key, err.Error())
+
+			continue
+		}
+
+		if ttl > 0 {
+			return errors.WithCode(code.ErrAccountLocked, "too many failed logins, retry in %s", ttl.Round(time.Second))
+		}
+	}
+
+	return nil
+}
+
+// Failed counts a failed login of the username from the client IP, and locks
+// them out once they go over their limit.
+func (l *Limiter) Failed(username, clientIP string) {
+	limits := []int64{l.policy.MaxUserFailures, l.policy.MaxIPFailures}
+	lockedOut := []string{LockedUser, LockedIP}
+
+	for i, key := range l.keys(username, clientIP) {
+		failures, err := l.store.Fail(key, l.policy.Window)
+		if err != nil {
+			log.Warnf("count login failure of %s failed: %s", key, err.Error())
+
+			continue
+		}
+
+		if limits[i] <= 0 || failures < limits[i] {
+			continue
+		}
+
+		ttl := l.lockout(failures - limits[i])
+		if err := l.store.Lock(key, ttl); err != nil {
+			log.Warnf("lock out %s failed: %s", key, err.Error())
+
+			continue
+		}
+
+		e := &Event{
+			Username:  username,
+			ClientIP:  clientIP,
+			LockedOut: lockedOut[i],
+			Failures:  failures,
+			Until:     time.Now().Add(ttl),
+		}
+
+		log.Warnw("login locked out",
+			"event", "login.lockout",
+			"username", e.Username,
+			"clientIP", e.ClientIP,
+			"lockedOut", e.LockedOut,
+			"failures", e.Failures,
+			"until", e.Until.Format(time.RFC3339),
+		)
+
+		if l.record != nil {
+			l.record(e)
+		}
+	}
+}
+
+// Succeeded forgets the failures of the username once it logs in.
+func (l *Limiter) Succeeded(username, clientIP string) {
+	if err := l.store.Reset(userKind + username); err != nil {
+		log.Warnf("reset login failures of %s failed: %s", username, err.Error())
+	}
+}
+
+// lockout returns the lockout duration after the given number of failures
+// over the limit.
+func (l *Limiter) lockout(over int64) time.Duration {
+	ttl := l.policy.BaseLockout
+	for i := int64(0); i < over && ttl < l.policy.MaxLockout; i++ {
+		ttl *= 2
+	}
+
+	if ttl > l.policy.MaxLockout {
+		ttl = l.policy.MaxLockout
+	}
+
+	return ttl
+}
+
+func (l *Limiter) keys(username, clientIP string) []string {
+	return []string{userKind + username, ipKind + clientIP}
+}
+
+// Unlock forgets the failures and the lockout of the username, and of the
+// given client IPs of the user which are locked out as well.
+func Unlock(s Store, username string, clientIPs ...string) error {
+	keys := []string{userKind + username}
+	for _, ip := range clientIPs {
+		keys = append(keys, ipKind+ip)
+	}
+
+	for _, key := range keys {
+		if err := s.Reset(key); err != nil {
+			return err
+		}
+	}
+
+	log.Infow("login unlocked", "event", "login.unlock", "username", username, "clientIPs", clientIPs)
+
+	return nil
+}
+
+// redisStore implements Store with the shared redis cluster.
+type redisStore struct {
+	storage.RedisCluster
+}
+
+func (r *redisStore) Fail(key string, window time.Duration) (int64, error) {
+	// IncrememntWithExpire swallows the errors.
+	if !storage.Connected() {
+		return 0, storage.ErrRedisIsDown
+	}
+
+	failures := r.IncrememntWithExpire(failuresKeyPrefix+key, int64(window/time.Second))
+
+	// the window slides with every failure.
+	return failures, r.SetExp(failuresKeyPrefix+key, window)
+}
+
+func (r *redisStore) Reset(key string) error {
+	if !storage.Connected() {
+		return storage.ErrRedisIsDown
+	}
+
+	r.DeleteKey(failuresKeyPrefix + key)
+	r.DeleteKey(lockKeyPrefix + key)
+
+	return nil
+}
+
+func (r *redisStore) Lock(key string, ttl time.Duration) error {
+	return r.SetKey(lockKeyPrefix+key, "1", ttl)
+}
+
+func (r *redisStore) LockedFor(key string) (time.Duration, error) {
+	ttl, err := r.GetExp(lockKeyPrefix + key)
+	if err != nil {
+		return 0, err
+	}
+
+	// missing keys have a negative ttl.
+	if ttl <= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(ttl) * time.Second, nil
+}
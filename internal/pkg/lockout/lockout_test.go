@@ -0,0 +1,125 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+type fakeStore struct {
+	failures map[string]int64
+	locks    map[string]time.Duration
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{failures: map[string]int64{}, locks: map[string]time.Duration{}}
+}
+
+func (f *fakeStore) Fail(key string, window time.Duration) (int64, error) {
+	f.failures[key]++
+
+	return f.failures[key], nil
+}
+
+func (f *fakeStore) Reset(key string) error {
+	delete(f.failures, key)
+	delete(f.locks, key)
+
+	return nil
+}
+
+func (f *fakeStore) Lock(key string, ttl time.Duration) error {
+	f.locks[key] = ttl
+
+	return nil
+}
+
+func (f *fakeStore) LockedFor(key string) (time.Duration, error) {
+	return f.locks[key], nil
+}
+
+// expire ends all the lockouts, as if their ttl had passed.
+func (f *fakeStore) expire() {
+	f.locks = map[string]time.Duration{}
+}
+
+var policy = Policy{
+	MaxUserFailures: 3,
+	MaxIPFailures:   5,
+	Window:          15 * time.Minute,
+	BaseLockout:     time.Minute,
+	MaxLockout:      5 * time.Minute,
+}
+
+func TestUserLockout(t *testing.T) {
+	s := newFakeStore()
+	l := NewLimiter(s, policy, nil)
+
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, l.Allow("colin", "10.0.0.1"))
+		l.Failed("colin", "10.0.0.1")
+	}
+
+	l.Failed("colin", "10.0.0.2")
+	err := l.Allow("colin", "10.0.0.3")
+	assert.True(t, errors.IsCode(err, code.ErrAccountLocked))
+	assert.Equal(t, time.Minute, s.locks[userKind+"colin"])
+
+	// other users are not affected.
+	assert.Nil(t, l.Allow("maria", "10.0.0.3"))
+
+	// each further failure doubles the lockout, up to the maximum.
+	expected := []time.Duration{2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for _, ttl := range expected {
+		s.expire()
+		l.Failed("colin", "10.0.0.4")
+		assert.Equal(t, ttl, s.locks[userKind+"colin"])
+	}
+
+	assert.Nil(t, Unlock(s, "colin"))
+	assert.Nil(t, l.Allow("colin", "10.0.0.5"))
+}
+
+func TestIPLockout(t *testing.T) {
+	s := newFakeStore()
+	var events []*Event
+	l := NewLimiter(s, policy, func(e *Event) { events = append(events, e) })
+
+	for _, user := range []string{"a", "b", "c", "d", "e"} {
+		l.Failed(user, "10.0.0.1")
+	}
+
+	assert.True(t, errors.IsCode(l.Allow("f", "10.0.0.1"), code.ErrAccountLocked))
+	assert.Nil(t, l.Allow("f", "10.0.0.2"))
+
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "e", events[0].Username)
+		assert.Equal(t, LockedIP, events[0].LockedOut)
+		assert.Equal(t, int64(5), events[0].Failures)
+	}
+
+	// unlocking a user unlocks the given client IPs as well.
+	assert.Nil(t, Unlock(s, "e", "10.0.0.1"))
+	assert.Nil(t, l.Allow("f", "10.0.0.1"))
+}
+
+func TestSucceeded(t *testing.T) {
+	s := newFakeStore()
+	l := NewLimiter(s, policy, nil)
+
+	l.Failed("colin", "10.0.0.1")
+	l.Failed("colin", "10.0.0.1")
+	l.Succeeded("colin", "10.0.0.1")
+	l.Failed("colin", "10.0.0.1")
+
+	assert.Nil(t, l.Allow("colin", "10.0.0.1"))
+	assert.Equal(t, int64(1), s.failures[userKind+"colin"])
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+// Audit is a middleware that records the mutating requests in the audit log,
+// one log for every resource the request changed, or one for the request when
+// it changed none of the audited resources. The failed anonymous requests are
+// not recorded, they would only let anyone flood the log.
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mutating(c.Request.Method) {
+			c.Next()
+
+			return
+		}
+
+		recorder := audit.NewRecorder()
+		c.Set(audit.Key, recorder)
+		c.Next()
+
+		logs := auditLogs(c, recorder.Changes())
+		if len(logs) == 0 {
+			return
+		}
+
+		if err := store.Client().AuditLogs().Create(c, logs...); err != nil {
+			log.L(c).Errorw("store audit log failed", "error", err.Error())
+		}
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditLogs returns the audit logs of the request made through c.
+func auditLogs(c *gin.Context, changes []*audit.Change) []*model.AuditLog {
+	actor := c.GetString(UsernameKey)
+	status := c.Writer.Status()
+
+	if actor == "" && len(changes) == 0 && status >= http.StatusBadRequest {
+		return nil
+	}
+
+	name, ok := tenant.FromContext(c)
+	if !ok {
+		name = tenant.Default
+	}
+
+	request := model.AuditLog{
+		Tenant:     name,
+		Actor:      actor,
+		Method:     c.Request.Method,
+		Path:       c.Request.URL.Path,
+		StatusCode: status,
+		RequestID:  c.GetHeader(XRequestIDKey),
+		ClientIP:   c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		CreatedAt:  time.Now(),
+	}
+
+	if len(changes) == 0 {
+		return []*model.AuditLog{&request}
+	}
+
+	logs := make([]*model.AuditLog, 0, len(changes))
+	for _, change := range changes {
+		l := request
+		l.Action, l.Kind, l.Name, l.Owner = change.Action, change.Kind, change.Name, change.Owner
+		l.Changes = change.Fields
+		logs = append(logs, &l)
+	}
+
+	return logs
+}
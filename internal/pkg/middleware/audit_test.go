@@ -0,0 +1,106 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/audit"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+type fakeAuditLogs struct {
+	store.AuditLogStore
+
+	logs []*model.AuditLog
+}
+
+func (f *fakeAuditLogs) Create(ctx context.Context, logs ...*model.AuditLog) error {
+	f.logs = append(f.logs, logs...)
+
+	return nil
+}
+
+type secret struct {
+	Description string `json:"description"`
+	SecretKey   string `json:"secretKey"`
+}
+
+func TestAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logs := &fakeAuditLogs{}
+	factory := &store.MockFactory{}
+	factory.On("AuditLogs").Return(logs)
+
+	defer store.SetClient(store.Client())
+	store.SetClient(factory)
+
+	authenticated := func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+
+			return
+		}
+
+		c.Set(UsernameKey, "admin")
+		c.Set(tenant.Key, "shop")
+	}
+
+	g := gin.New()
+	v1 := g.Group("/v1", Audit(), authenticated)
+	v1.PUT("/secrets/:name", func(c *gin.Context) {
+		audit.Record(c, audit.ActionUpdate, "secret", c.Param("name"), "maria",
+			&secret{Description: "ci", SecretKey: "old"}, &secret{Description: "ci", SecretKey: "new"})
+		c.Status(http.StatusOK)
+	})
+	v1.POST("/groups", func(c *gin.Context) { c.Status(http.StatusOK) })
+	v1.GET("/secrets", func(c *gin.Context) {
+		assert.False(t, audit.Enabled(c))
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(method, path string, authorized bool) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(XRequestIDKey, "req-1")
+		if authorized {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+
+		g.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	serve(http.MethodPut, "/v1/secrets/ci", true)
+	serve(http.MethodPost, "/v1/groups", true)
+	serve(http.MethodGet, "/v1/secrets", true)
+	serve(http.MethodPost, "/v1/groups", false)
+
+	if assert.Len(t, logs.logs, 2) {
+		changed := logs.logs[0]
+		assert.Equal(t, "admin", changed.Actor)
+		assert.Equal(t, "shop", changed.Tenant)
+		assert.Equal(t, audit.ActionUpdate, changed.Action)
+		assert.Equal(t, "ci", changed.Name)
+		assert.Equal(t, "maria", changed.Owner)
+		assert.Equal(t, "req-1", changed.RequestID)
+		assert.Equal(t, http.StatusOK, changed.StatusCode)
+		assert.Equal(t, []audit.FieldChange{
+			{Field: "secretKey", Before: []byte(audit.Redacted), After: []byte(audit.Redacted)},
+		}, changed.Changes)
+
+		request := logs.logs[1]
+		assert.Equal(t, "/v1/groups", request.Path)
+		assert.Empty(t, request.Action)
+		assert.Empty(t, request.Changes)
+	}
+}
@@ -17,19 +17,23 @@ import (
 const authHeaderCount = 2
 
 // AutoStrategy defines authentication strategy which can automatically choose between Basic and Bearer
-// according `Authorization` header.
+// according `Authorization` header. Bearer tokens with the personal access token
+// prefix are handled by the token strategy.
 type AutoStrategy struct {
 	basic middleware.AuthStrategy
 	jwt   middleware.AuthStrategy
+	token middleware.AuthStrategy
 }
 
 var _ middleware.AuthStrategy = &AutoStrategy{}
 
-// NewAutoStrategy create auto strategy with basic strategy and jwt strategy.
-func NewAutoStrategy(basic, jwt middleware.AuthStrategy) AutoStrategy {
+// NewAutoStrategy create auto strategy with basic strategy, jwt strategy and
+// token strategy. A nil token strategy rejects the personal access tokens.
+func NewAutoStrategy(basic, jwt, token middleware.AuthStrategy) AutoStrategy {
 	return AutoStrategy{
 		basic: basic,
 		jwt:   jwt,
+		token: token,
 	}
 }
 
@@ -55,6 +59,9 @@ func (a AutoStrategy) AuthFunc() gin.HandlerFunc {
 			operator.SetStrategy(a.basic)
 		case "Bearer":
 			operator.SetStrategy(a.jwt)
+			if a.token != nil && IsAccessToken(authHeader[1]) {
+				operator.SetStrategy(a.token)
+			}
 			// a.JWT.MiddlewareFunc()(c)
 		default:
 			core.WriteResponse(c, errors.WithCode(code.ErrSignatureInvalid, "unrecognized Authorization header."), nil)
@@ -15,17 +15,32 @@ import (
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
 )
 
+// LoginLimiter limits the failed password authentications of the usernames
+// and the client IPs.
+type LoginLimiter interface {
+	// Allow returns an error if the username or the client IP is locked out.
+	Allow(username, clientIP string) error
+	Failed(username, clientIP string)
+	Succeeded(username, clientIP string)
+}
+
 // BasicStrategy defines Basic authentication strategy.
 type BasicStrategy struct {
-	compare func(username string, password string) bool
+	compare func(username string, password string) error
+	limiter LoginLimiter
 }
 
 var _ middleware.AuthStrategy = &BasicStrategy{}
 
-// NewBasicStrategy create basic strategy with compare function.
-func NewBasicStrategy(compare func(username string, password string) bool) BasicStrategy {
+// NewBasicStrategy create basic strategy with compare function. The password
+// mismatches, told by a code.ErrPasswordIncorrect error of compare, are counted
+// by the given limiter, a nil limiter disables it. The other errors refuse the
+// right credentials of a user who can not use basic authentication, e.g. an
+// inactive one, and are not counted.
+func NewBasicStrategy(compare func(username string, password string) error, limiter LoginLimiter) BasicStrategy {
 	return BasicStrategy{
 		compare: compare,
+		limiter: limiter,
 	}
 }
 
@@ -48,7 +63,25 @@ func (b BasicStrategy) AuthFunc() gin.HandlerFunc {
 		payload, _ := base64.StdEncoding.DecodeString(auth[1])
 		pair := strings.SplitN(string(payload), ":", 2)
 
-		if len(pair) != 2 || !b.compare(pair[0], pair[1]) {
+		if len(pair) == 2 && b.limiter != nil {
+			if err := b.limiter.Allow(pair[0], c.ClientIP()); err != nil {
+				core.WriteResponse(c, err, nil)
+				c.Abort()
+
+				return
+			}
+		}
+
+		var err error
+		if len(pair) == 2 {
+			err = b.compare(pair[0], pair[1])
+		}
+
+		if len(pair) != 2 || err != nil {
+			if errors.IsCode(err, code.ErrPasswordIncorrect) && b.limiter != nil {
+				b.limiter.Failed(pair[0], c.ClientIP())
+			}
+
 			core.WriteResponse(
 				c,
 				errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong."),
@@ -59,6 +92,10 @@ func (b BasicStrategy) AuthFunc() gin.HandlerFunc {
 			return
 		}
 
+		if b.limiter != nil {
+			b.limiter.Succeeded(pair[0], c.ClientIP())
+		}
+
 		c.Set(middleware.UsernameKey, pair[0])
 
 		c.Next()
@@ -10,10 +10,16 @@ import (
 
 	"github.com/dgrijalva/jwt-go/v4"
 	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
 	"github.com/marmotedu/component-base/pkg/core"
 	"github.com/marmotedu/errors"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
 // Defined errors.
@@ -25,22 +31,41 @@ var (
 // Secret contains the basic information of the secret key.
 type Secret struct {
 	Username string
+	Tenant   string
 	ID       string
 	Key      string
 	Expires  int64
+	// Scopes restrict the requests the secret can authorize.
+	Scopes []scope.Scope
+	// PreviousKey is the key replaced by the last rotation, it is still
+	// accepted until PreviousKeyExpires.
+	PreviousKey        string
+	PreviousKeyExpires int64
 }
 
 // CacheStrategy defines jwt bearer authentication strategy which called `cache strategy`.
 // Secrets are obtained through grpc api interface and cached in memory.
 type CacheStrategy struct {
-	get func(kid string) (Secret, error)
+	get        func(kid string) (Secret, error)
+	keys       jwks.PublicKeyGetter
+	revocation revocation.Store
+	used       func(secretID string)
 }
 
 var _ middleware.AuthStrategy = &CacheStrategy{}
 
 // NewCacheStrategy create cache strategy with function which can list and cache secrets.
-func NewCacheStrategy(get func(kid string) (Secret, error)) CacheStrategy {
-	return CacheStrategy{get}
+// Tokens signed by iam-apiserver with an asymmetric key are verified offline
+// with the given keys, a nil getter rejects them, and they are rejected as well
+// once revoked in the given store. used is called with the id of the secret of
+// every authenticated request, it can be nil.
+func NewCacheStrategy(
+	get func(kid string) (Secret, error),
+	keys jwks.PublicKeyGetter,
+	store revocation.Store,
+	used func(secretID string),
+) CacheStrategy {
+	return CacheStrategy{get, keys, store, used}
 }
 
 // AuthFunc defines cache strategy as the gin authentication middleware.
@@ -58,12 +83,47 @@ func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
 		// Parse the header to get the token part.
 		fmt.Sscanf(header, "Bearer %s", &rawJWT)
 
-		// Use own validation logic, see below
-		var secret Secret
+		if cache.keys != nil && signedByKeySet(rawJWT) {
+			cache.authWithKeys(c, rawJWT)
+
+			return
+		}
+
+		secret, err := cache.verify(rawJWT)
+		if err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrSignatureInvalid, err.Error()), nil)
+			c.Abort()
 
-		claims := &jwt.MapClaims{}
-		// Verify the token
-		parsedT, err := jwt.ParseWithClaims(rawJWT, claims, func(token *jwt.Token) (interface{}, error) {
+			return
+		}
+
+		if KeyExpired(secret.Expires) {
+			tm := time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05")
+			core.WriteResponse(c, errors.WithCode(code.ErrExpired, "expired at: %s", tm), nil)
+			c.Abort()
+
+			return
+		}
+
+		if cache.used != nil {
+			cache.used(secret.ID)
+		}
+
+		c.Set(middleware.UsernameKey, secret.Username)
+		c.Set(tenant.Key, secret.Tenant)
+		c.Set(scope.Key, secret.Scopes)
+		c.Next()
+	}
+}
+
+// verify returns the secret the token is signed with. Tokens signed with the
+// key replaced by the last rotation are accepted until its grace period is over.
+func (cache CacheStrategy) verify(rawJWT string) (Secret, error) {
+	var secret Secret
+
+	parse := func(key func(Secret) string) error {
+		// Use own validation logic, see below
+		parsedT, err := jwt.ParseWithClaims(rawJWT, &jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
 			// Validate the alg is HMAC signature
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -80,26 +140,65 @@ func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
 				return nil, ErrMissingSecret
 			}
 
-			return []byte(secret.Key), nil
+			return []byte(key(secret)), nil
 		}, jwt.WithAudience(AuthzAudience))
-		if err != nil || !parsedT.Valid {
-			core.WriteResponse(c, errors.WithCode(code.ErrSignatureInvalid, err.Error()), nil)
-			c.Abort()
-
-			return
+		if err == nil && !parsedT.Valid {
+			err = errors.New("token is invalid")
 		}
 
-		if KeyExpired(secret.Expires) {
-			tm := time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05")
-			core.WriteResponse(c, errors.WithCode(code.ErrExpired, "expired at: %s", tm), nil)
+		return err
+	}
+
+	err := parse(func(s Secret) string { return s.Key })
+	if err != nil && secret.PreviousKey != "" && secret.PreviousKeyExpires > 0 && !KeyExpired(secret.PreviousKeyExpires) {
+		err = parse(func(s Secret) string { return s.PreviousKey })
+	}
+
+	return secret, err
+}
+
+// signedByKeySet tells whether the token is signed with an asymmetric key, rather than a secret.
+func signedByKeySet(rawJWT string) bool {
+	token, _, err := new(gojwt.Parser).ParseUnverified(rawJWT, gojwt.MapClaims{})
+	if err != nil {
+		return false
+	}
+
+	return jwks.Supported(token.Method.Alg())
+}
+
+// authWithKeys authenticates the user of a token signed by iam-apiserver.
+func (cache CacheStrategy) authWithKeys(c *gin.Context, rawJWT string) {
+	claims := gojwt.MapClaims{}
+	if _, err := gojwt.ParseWithClaims(rawJWT, claims, jwks.KeyFunc(cache.keys)); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrSignatureInvalid, err.Error()), nil)
+		c.Abort()
+
+		return
+	}
+
+	username, _ := claims["sub"].(string)
+	if !claims.VerifyAudience(AuthzAudience, true) || username == "" {
+		core.WriteResponse(c, errors.WithCode(code.ErrSignatureInvalid, "token is not issued for iam-authz-server."), nil)
+		c.Abort()
+
+		return
+	}
+
+	if cache.revocation != nil {
+		if err := revocation.Check(cache.revocation, claims); err != nil {
+			log.L(c).Warnf("jwt rejected: %s", err.Error())
+			core.WriteResponse(c, err, nil)
 			c.Abort()
 
 			return
 		}
-
-		c.Set(middleware.UsernameKey, secret.Username)
-		c.Next()
 	}
+
+	name, _ := claims[tenant.Key].(string)
+	c.Set(middleware.UsernameKey, username)
+	c.Set(tenant.Key, name)
+	c.Next()
 }
 
 // KeyExpired checks if a key has expired, if the value of user.SessionState.Expires is 0, it will be ignored.
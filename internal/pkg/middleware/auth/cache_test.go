@@ -0,0 +1,135 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go/v4"
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
+)
+
+func signSecretToken(t *testing.T, kid, key string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"aud": AuthzAudience,
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = kid
+
+	raw, err := token.SignedString([]byte(key))
+	assert.Nil(t, err)
+
+	return raw
+}
+
+func TestCacheStrategyRotatedKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secret := Secret{
+		Username:           "colin",
+		ID:                 "id0",
+		Key:                "new",
+		PreviousKey:        "old",
+		PreviousKeyExpires: time.Now().Add(time.Hour).Unix(),
+		Scopes:             []scope.Scope{{Resources: []string{"resources:articles"}, Actions: []string{"get"}}},
+	}
+
+	var used []string
+	strategy := NewCacheStrategy(func(kid string) (Secret, error) {
+		return secret, nil
+	}, nil, nil, func(secretID string) {
+		used = append(used, secretID)
+	})
+
+	engine := gin.New()
+	engine.GET("/", strategy.AuthFunc(), func(c *gin.Context) {
+		scopes, _ := scope.FromContext(c)
+		assert.Equal(t, secret.Scopes, scopes)
+		c.Status(http.StatusOK)
+	})
+
+	authenticate := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+signSecretToken(t, "id0", key))
+
+		rsp := httptest.NewRecorder()
+		engine.ServeHTTP(rsp, req)
+
+		return rsp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, authenticate("new"))
+	assert.Equal(t, http.StatusOK, authenticate("old"))
+	assert.NotEqual(t, http.StatusOK, authenticate("other"))
+	assert.Equal(t, []string{"id0", "id0"}, used)
+
+	// the replaced key is refused once its grace period is over.
+	secret.PreviousKeyExpires = time.Now().Add(-time.Second).Unix()
+	assert.NotEqual(t, http.StatusOK, authenticate("old"))
+	assert.Equal(t, http.StatusOK, authenticate("new"))
+}
+
+type revokedTokens map[string]bool
+
+func (r revokedTokens) RevokeToken(jti string, ttl time.Duration) error { return nil }
+
+func (r revokedTokens) TokenRevoked(jti string) (bool, error) { return r[jti], nil }
+
+func (r revokedTokens) RevokeSession(sid string, ttl time.Duration) error { return nil }
+
+func (r revokedTokens) SessionRevoked(sid string) (bool, error) { return false, nil }
+
+func (r revokedTokens) RevokeUser(username string, before time.Time) error { return nil }
+
+func (r revokedTokens) UserRevokedBefore(username string) (time.Time, error) { return time.Time{}, nil }
+
+func TestCacheStrategyRevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer, err := jwks.GenerateKey(jwks.RS256)
+	assert.Nil(t, err)
+	keys, err := jwks.NewKeySet([]*jwks.Key{{ID: "rsa", Algorithm: jwks.RS256, Signer: signer}})
+	assert.Nil(t, err)
+
+	revoked := revokedTokens{}
+	strategy := NewCacheStrategy(nil, keys, revoked, nil)
+
+	engine := gin.New()
+	engine.GET("/", strategy.AuthFunc(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	raw, err := keys.Sign(gojwt.MapClaims{
+		"aud":             []string{AuthzAudience},
+		"sub":             "colin",
+		revocation.JTIKey: "token-1",
+		"exp":             time.Now().Add(time.Minute).Unix(),
+	})
+	assert.Nil(t, err)
+
+	authenticate := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+raw)
+
+		rsp := httptest.NewRecorder()
+		engine.ServeHTTP(rsp, req)
+
+		return rsp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, authenticate())
+
+	revoked["token-1"] = true
+	assert.NotEqual(t, http.StatusOK, authenticate())
+}
@@ -5,27 +5,416 @@
 package auth
 
 import (
+	"net/http"
+	"time"
+
 	ginjwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
 )
 
 // AuthzAudience defines the value of jwt audience field.
 const AuthzAudience = "iam.authz.marmotedu.com"
 
+// TokenSigner signs jwt tokens with keys gin-jwt does not know about,
+// e.g. rotated asymmetric keys identified by kid.
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// Session defines the login session an access token is issued for.
+type Session struct {
+	// ID identifies the session, it is set as the sid claim of the access tokens.
+	ID string
+	// RefreshToken is the opaque token the client exchanges for a new access token.
+	RefreshToken string
+	// ExpiresAt is when the session is over unless refreshed, access tokens
+	// issued for the session do not outlive it.
+	ExpiresAt time.Time
+	// Data is the authenticated user, it is passed to PayloadFunc.
+	Data interface{}
+}
+
+// SessionManager starts, refreshes and ends the login sessions backing the
+// opaque refresh tokens.
+type SessionManager interface {
+	// Start starts a new session for the user authenticated by the Authenticator.
+	Start(c *gin.Context, data interface{}) (*Session, error)
+	// Refresh rotates the refresh token of a session.
+	Refresh(c *gin.Context, refreshToken string) (*Session, error)
+	// End ends the session of the user.
+	End(c *gin.Context, username, sid string) error
+}
+
+// Challenge defines the second factor challenge of a login, the client
+// answers it with a code to complete the login.
+type Challenge struct {
+	Token string
+	// Enroll tells whether the user has to enroll before answering the challenge.
+	Enroll bool
+}
+
+// SecondFactor challenges the users who must pass a second factor once their
+// password is verified.
+type SecondFactor interface {
+	// Challenge returns the challenge of the user authenticated by the
+	// Authenticator, nil if no second factor is required.
+	Challenge(c *gin.Context, data interface{}) (*Challenge, error)
+	// Verify answers the challenge with the code and returns the authenticated user.
+	Verify(c *gin.Context, challenge, code string) (interface{}, error)
+}
+
 // JWTStrategy defines jwt bearer authentication strategy.
 type JWTStrategy struct {
 	ginjwt.GinJWTMiddleware
+	revocation   revocation.Store
+	signer       TokenSigner
+	sessions     SessionManager
+	secondFactor SecondFactor
 }
 
 var _ middleware.AuthStrategy = &JWTStrategy{}
 
 // NewJWTStrategy create jwt bearer strategy with GinJWTMiddleware.
-func NewJWTStrategy(gjwt ginjwt.GinJWTMiddleware) JWTStrategy {
-	return JWTStrategy{gjwt}
+// Tokens revoked in the given store are rejected, a nil store disables revocation.
+// Tokens are signed by the given signer, and verified by gjwt.KeyFunc, a nil
+// signer signs with gjwt.Key. Login returns a refresh token of a session
+// started by the given session manager, a nil manager lets tokens be
+// refreshed from themselves as gin-jwt does. Users challenged by the given
+// second factor get their tokens once they answer the challenge, a nil second
+// factor disables two-factor authentication.
+func NewJWTStrategy(
+	gjwt ginjwt.GinJWTMiddleware,
+	store revocation.Store,
+	signer TokenSigner,
+	sessions SessionManager,
+	secondFactor SecondFactor,
+) JWTStrategy {
+	return JWTStrategy{gjwt, store, signer, sessions, secondFactor}
 }
 
 // AuthFunc defines jwt bearer strategy as the gin authentication middleware.
 func (j JWTStrategy) AuthFunc() gin.HandlerFunc {
-	return j.MiddlewareFunc()
+	return func(c *gin.Context) {
+		if err := j.checkRevoked(c); err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		j.MiddlewareFunc()(c)
+	}
+}
+
+// LoginHandler authenticates the user, starts a new session and issues a new
+// access token together with the refresh token of the session. Users who must
+// pass a second factor get a challenge instead, to answer with SecondFactorHandler.
+func (j JWTStrategy) LoginHandler(c *gin.Context) {
+	data, err := j.Authenticator(c)
+	if err != nil {
+		if loginRefused(err) {
+			core.WriteResponse(c, err, nil)
+
+			return
+		}
+
+		j.unauthorized(c, http.StatusUnauthorized, err)
+
+		return
+	}
+
+	if j.secondFactor != nil {
+		challenge, err := j.secondFactor.Challenge(c, data)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+
+			return
+		}
+
+		if challenge != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"mfaRequired":    true,
+				"mfaToken":       challenge.Token,
+				"enrollRequired": challenge.Enroll,
+			})
+
+			return
+		}
+	}
+
+	j.login(c, data)
+}
+
+// secondFactorRequest defines the body of a second factor request.
+type secondFactorRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code"     binding:"required"`
+}
+
+// SecondFactorHandler completes a login challenged by the second factor.
+func (j JWTStrategy) SecondFactorHandler(c *gin.Context) {
+	if j.secondFactor == nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrPageNotFound, "Two-factor authentication is disabled."), nil)
+
+		return
+	}
+
+	var r secondFactorRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	data, err := j.secondFactor.Verify(c, r.MFAToken, r.Code)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	j.login(c, data)
+}
+
+// LoginWith starts a new session for a user authenticated by other means than
+// the Authenticator, e.g. an external identity provider, and issues its tokens.
+// The data is passed to PayloadFunc and to the session manager.
+func (j JWTStrategy) LoginWith(c *gin.Context, data interface{}) {
+	j.login(c, data)
+}
+
+// login starts a new session for the authenticated user and issues its tokens.
+func (j JWTStrategy) login(c *gin.Context, data interface{}) {
+	var (
+		session *Session
+		err     error
+	)
+
+	if j.sessions != nil {
+		if session, err = j.sessions.Start(c, data); err != nil {
+			core.WriteResponse(c, err, nil)
+
+			return
+		}
+	}
+
+	j.issue(c, j.payload(data), session)
+}
+
+// LogoutHandler revokes the token of the request until it can no longer be
+// refreshed and ends its session, then removes the jwt cookie.
+func (j JWTStrategy) LogoutHandler(c *gin.Context) {
+	claims, err := j.GetClaimsFromJWT(c)
+	if err == nil && j.revocation != nil {
+		if jti, _ := claims[revocation.JTIKey].(string); jti != "" {
+			if err := j.revocation.RevokeToken(jti, j.remainingLife(claims)); err != nil {
+				core.WriteResponse(c, errors.WithCode(code.ErrUnknown, err.Error()), nil)
+
+				return
+			}
+		}
+	}
+
+	if err == nil && j.sessions != nil {
+		username, _ := claims[revocation.SubjectKey].(string)
+		if sid, _ := claims[revocation.SessionKey].(string); sid != "" {
+			// the session may have been revoked already.
+			if err := j.sessions.End(c, username, sid); err != nil && !errors.IsCode(err, code.ErrSessionNotFound) {
+				core.WriteResponse(c, err, nil)
+
+				return
+			}
+		}
+	}
+
+	j.GinJWTMiddleware.LogoutHandler(c)
+}
+
+// refreshRequest defines the body of a refresh request.
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// RefreshHandler exchanges the refresh token of the request for a new access
+// token and a new refresh token.
+func (j JWTStrategy) RefreshHandler(c *gin.Context) {
+	if j.sessions == nil {
+		j.refreshFromToken(c)
+
+		return
+	}
+
+	var r refreshRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	session, err := j.sessions.Refresh(c, r.RefreshToken)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	j.issue(c, j.payload(session.Data), session)
+}
+
+// refreshFromToken refreshes the access token of the request unless it has been revoked.
+func (j JWTStrategy) refreshFromToken(c *gin.Context) {
+	if err := j.checkRevoked(c); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	claims, err := j.CheckIfTokenExpire(c)
+	if err != nil {
+		j.unauthorized(c, http.StatusUnauthorized, err)
+
+		return
+	}
+
+	j.issue(c, jwt.MapClaims(claims), nil)
+}
+
+func (j JWTStrategy) payload(data interface{}) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	if j.PayloadFunc != nil {
+		for key, value := range j.PayloadFunc(data) {
+			claims[key] = value
+		}
+	}
+
+	return claims
+}
+
+// issue signs the claims the same way gin-jwt does, with the signer if any,
+// and sends the new token together with the refresh token of the session.
+func (j JWTStrategy) issue(c *gin.Context, claims jwt.MapClaims, session *Session) {
+	expire := j.TimeFunc().Add(j.Timeout)
+	if session != nil {
+		if session.ExpiresAt.Before(expire) {
+			expire = session.ExpiresAt
+		}
+
+		claims[revocation.SessionKey] = session.ID
+	}
+
+	claims["exp"] = expire.Unix()
+	claims["orig_iat"] = j.TimeFunc().Unix()
+
+	token, err := j.sign(claims)
+	if err != nil {
+		j.unauthorized(c, http.StatusUnauthorized, ginjwt.ErrFailedTokenCreation)
+
+		return
+	}
+
+	if j.SendCookie {
+		if j.CookieSameSite != 0 {
+			c.SetSameSite(j.CookieSameSite)
+		}
+
+		c.SetCookie(j.CookieName, token, int(j.CookieMaxAge/time.Second), "/", j.CookieDomain, j.SecureCookie, j.CookieHTTPOnly)
+	}
+
+	rsp := gin.H{
+		"token":  token,
+		"expire": expire.Format(time.RFC3339),
+	}
+	if session != nil {
+		rsp["refreshToken"] = session.RefreshToken
+		rsp["refreshExpire"] = session.ExpiresAt.Format(time.RFC3339)
+	}
+
+	c.JSON(http.StatusOK, rsp)
+}
+
+func (j JWTStrategy) sign(claims jwt.MapClaims) (string, error) {
+	if j.signer != nil {
+		return j.signer.Sign(claims)
+	}
+
+	return jwt.NewWithClaims(jwt.GetSigningMethod(j.SigningAlgorithm), claims).SignedString(j.Key)
+}
+
+func (j JWTStrategy) unauthorized(c *gin.Context, code int, err error) {
+	c.Header("WWW-Authenticate", "JWT realm="+j.Realm)
+	c.Abort()
+	j.Unauthorized(c, code, j.HTTPStatusMessageFunc(err, c))
+}
+
+// checkRevoked returns an error if the token of the request has been revoked,
+// or if the revocation store is unavailable. Requests without a valid token
+// are left to gin-jwt.
+func (j JWTStrategy) checkRevoked(c *gin.Context) error {
+	if j.revocation == nil {
+		return nil
+	}
+
+	// expired tokens are checked as well, since they can still be refreshed.
+	token, err := j.ParseToken(c)
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); !ok || verr.Errors != jwt.ValidationErrorExpired {
+			return nil
+		}
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+
+	if err := revocation.Check(j.revocation, claims); err != nil {
+		log.L(c).Warnf("jwt rejected: %s", err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+// remainingLife returns how long the token, or a token refreshed from it, can
+// still be used.
+func (j JWTStrategy) remainingLife(claims ginjwt.MapClaims) time.Duration {
+	var last int64
+
+	if exp, ok := claims["exp"].(float64); ok {
+		last = int64(exp)
+	}
+
+	if origIat, ok := claims["orig_iat"].(float64); ok {
+		if refresh := int64(origIat) + int64(j.MaxRefresh/time.Second); refresh > last {
+			last = refresh
+		}
+	}
+
+	return time.Until(time.Unix(last, 0))
+}
+
+// loginRefused tells whether the authenticator refused a login for a reason the
+// user must know, like a locked or a suspended account.
+func loginRefused(err error) bool {
+	for _, refused := range []int{
+		code.ErrAccountLocked,
+		code.ErrUserPendingVerification,
+		code.ErrUserSuspended,
+		code.ErrUserDisabled,
+	} {
+		if errors.IsCode(err, refused) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,87 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+)
+
+const (
+	// AccessTokenPrefix defines the prefix of the personal access tokens, it
+	// tells them apart from the jwt bearer tokens.
+	AccessTokenPrefix = "iamp_"
+
+	// AccessTokenKey defines the key in gin context which holds the name of
+	// the personal access token a request is authenticated with.
+	AccessTokenKey = "accessToken"
+)
+
+// AccessToken contains what a personal access token grants.
+type AccessToken struct {
+	Name     string
+	Username string
+	// Scopes restrict the requests the token can authenticate, resources are
+	// matched with the request path and actions with the request method.
+	Scopes []scope.Scope
+}
+
+// TokenStrategy defines personal access token authentication strategy.
+type TokenStrategy struct {
+	verify func(ctx context.Context, token string) (*AccessToken, error)
+}
+
+var _ middleware.AuthStrategy = &TokenStrategy{}
+
+// NewTokenStrategy create token strategy with the function which verifies the
+// tokens. It returns a `github.com/marmotedu/errors.withCode` error.
+func NewTokenStrategy(verify func(ctx context.Context, token string) (*AccessToken, error)) TokenStrategy {
+	return TokenStrategy{verify: verify}
+}
+
+// IsAccessToken tells whether the bearer token is a personal access token.
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, AccessTokenPrefix)
+}
+
+// AuthFunc defines token strategy as the gin authentication middleware.
+func (t TokenStrategy) AuthFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := strings.SplitN(c.Request.Header.Get("Authorization"), " ", 2)
+		if len(header) != 2 || header[0] != "Bearer" || !IsAccessToken(header[1]) {
+			core.WriteResponse(c, errors.WithCode(code.ErrInvalidAuthHeader, "Authorization header format is wrong."), nil)
+			c.Abort()
+
+			return
+		}
+
+		token, err := t.verify(c, header[1])
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		if !scope.Allowed(token.Scopes, c.Request.URL.Path, c.Request.Method) {
+			core.WriteResponse(c, errors.WithCode(code.ErrPermissionDenied,
+				"%s %s is out of the scopes of access token %s", c.Request.Method, c.Request.URL.Path, token.Name), nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Set(middleware.UsernameKey, token.Username)
+		c.Set(AccessTokenKey, token.Name)
+		c.Next()
+	}
+}
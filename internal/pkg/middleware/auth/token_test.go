@@ -0,0 +1,73 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/middleware"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+)
+
+// teapotStrategy answers every request it authenticates with a teapot.
+type teapotStrategy struct{}
+
+func (teapotStrategy) AuthFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTeapot)
+	}
+}
+
+func TestTokenStrategy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token := NewTokenStrategy(func(ctx context.Context, token string) (*AccessToken, error) {
+		if token != AccessTokenPrefix+"valid" {
+			return nil, errors.WithCode(code.ErrAccessTokenInvalid, "access token is invalid")
+		}
+
+		return &AccessToken{
+			Name:     "ci",
+			Username: "colin",
+			Scopes:   []scope.Scope{{Resources: []string{"/v1/secrets<.*>"}, Actions: []string{"GET"}}},
+		}, nil
+	})
+
+	engine := gin.New()
+	engine.Use(NewAutoStrategy(teapotStrategy{}, teapotStrategy{}, token).AuthFunc())
+	engine.Any("/*path", func(c *gin.Context) {
+		assert.Equal(t, "colin", c.GetString(middleware.UsernameKey))
+		assert.Equal(t, "ci", c.GetString(AccessTokenKey))
+		c.Status(http.StatusOK)
+	})
+
+	authenticate := func(method, path, bearer string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+bearer)
+
+		rsp := httptest.NewRecorder()
+		engine.ServeHTTP(rsp, req)
+
+		return rsp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, authenticate(http.MethodGet, "/v1/secrets", AccessTokenPrefix+"valid"))
+	assert.Equal(t, http.StatusUnauthorized, authenticate(http.MethodGet, "/v1/secrets", AccessTokenPrefix+"other"))
+
+	// the scopes of the token restrict both the path and the method.
+	assert.Equal(t, http.StatusForbidden, authenticate(http.MethodDelete, "/v1/secrets/s0", AccessTokenPrefix+"valid"))
+	assert.Equal(t, http.StatusForbidden, authenticate(http.MethodGet, "/v1/users", AccessTokenPrefix+"valid"))
+
+	// the other bearer tokens are still left to the jwt strategy.
+	assert.Equal(t, http.StatusTeapot, authenticate(http.MethodGet, "/v1/secrets", "eyJhbGciOi"))
+}
@@ -33,10 +33,13 @@ func Publish() gin.HandlerFunc {
 		method := c.Request.Method
 
 		switch resource {
-		case "policies":
+		case "policies", "groups", "roles", "tenants":
 			notify(c, method, load.NoticePolicyChanged)
 		case "secrets":
 			notify(c, method, load.NoticeSecretChanged)
+		case "restore":
+			notify(c, method, load.NoticePolicyChanged)
+			notify(c, method, load.NoticeSecretChanged)
 		default:
 		}
 	}
@@ -0,0 +1,69 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/marmotedu/component-base/pkg/core"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+)
+
+// Tenant is a middleware that resolves the tenant of the request and injects it
+// to gin.Context, the storage limits every query to the data of that tenant.
+// Authenticated users always act in their own tenant, anonymous requests choose
+// the tenant with the `X-Tenant` header.
+func Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, err := resolveTenant(c)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Set(tenant.Key, name)
+		c.Next()
+	}
+}
+
+// resolveTenant returns the tenant of the request.
+// It returns a `github.com/marmotedu/errors.withCode` error.
+func resolveTenant(c *gin.Context) (string, error) {
+	username := c.GetString(UsernameKey)
+	if username == "" {
+		name := c.GetHeader(tenant.Header)
+		if name == "" || name == tenant.Default {
+			return tenant.Default, nil
+		}
+
+		if _, err := store.Client().Tenants().Get(c, name, metav1.GetOptions{}); err != nil {
+			return "", err
+		}
+
+		return name, nil
+	}
+
+	name := tenant.Default
+	member, err := store.Client().Tenants().GetMember(c, username, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		name = member.Tenant
+	case !errors.IsCode(err, code.ErrTenantNotFound):
+		return "", err
+	}
+
+	// a token issued before the user moved to another tenant is not valid any more.
+	if claimed, ok := jwt.ExtractClaims(c)[tenant.Key].(string); ok && claimed != name {
+		return "", errors.WithCode(code.ErrTokenInvalid, "token was issued for tenant %s", claimed)
+	}
+
+	return name, nil
+}
@@ -8,9 +8,11 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	v1 "github.com/marmotedu/api/apiserver/v1"
 	"github.com/marmotedu/component-base/pkg/core"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
 	"github.com/marmotedu/errors"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
 )
@@ -44,18 +46,94 @@ func Validation() gin.HandlerFunc {
 	}
 }
 
-// isAdmin make sure the user is administrator.
+// AdminRequired make sure only administrators can access the resource.
+func AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := isAdmin(c); err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrPermissionDenied, err.Error()), nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PlatformAdminRequired make sure only platform administrators can access the resource,
+// e.g. the tenants which span all the storefronts.
+func PlatformAdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := isPlatformAdmin(c); err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrPermissionDenied, err.Error()), nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsAdmin tells whether the user of the request is an administrator, for the
+// handlers which show more to the administrators rather than deny the others.
+func IsAdmin(c *gin.Context) bool {
+	return isAdmin(c) == nil
+}
+
+// isAdmin make sure the user is administrator, either a platform administrator or
+// an administrator of the tenant of the user.
 // It returns a `github.com/marmotedu/errors.withCode` error.
 func isAdmin(c *gin.Context) error {
-	username := c.GetString(UsernameKey)
-	user, err := store.Client().Users().Get(c, username, metav1.GetOptions{})
+	user, err := activeUser(c)
 	if err != nil {
-		return errors.WithCode(code.ErrDatabase, err.Error())
+		return err
+	}
+
+	if user.IsAdmin == 1 {
+		return nil
+	}
+
+	member, err := store.Client().Tenants().GetMember(c, user.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsCode(err, code.ErrTenantNotFound) {
+		return err
+	}
+
+	if member == nil || member.IsAdmin != 1 {
+		return errors.WithCode(code.ErrPermissionDenied, "user %s is not a administrator", user.Name)
+	}
+
+	return nil
+}
+
+// isPlatformAdmin make sure the user is platform administrator.
+// It returns a `github.com/marmotedu/errors.withCode` error.
+func isPlatformAdmin(c *gin.Context) error {
+	user, err := activeUser(c)
+	if err != nil {
+		return err
 	}
 
 	if user.IsAdmin != 1 {
-		return errors.WithCode(code.ErrPermissionDenied, "user %s is not a administrator", username)
+		return errors.WithCode(code.ErrPermissionDenied, "user %s is not a platform administrator", user.Name)
 	}
 
 	return nil
 }
+
+// activeUser returns the user of the request, refusing the users which are not
+// active any more, so that a suspended administrator with a token still valid
+// can not use the administrator APIs.
+func activeUser(c *gin.Context) (*v1.User, error) {
+	username := c.GetString(UsernameKey)
+	user, err := store.Client().Users().Get(c, username, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	if user.Status != model.UserStatusActive {
+		return nil, errors.WithCode(code.ErrPermissionDenied, "user %s is %s", username, model.UserStatusName(user.Status))
+	}
+
+	return user, nil
+}
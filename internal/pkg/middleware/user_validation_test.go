@@ -0,0 +1,72 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	v1 "github.com/marmotedu/api/apiserver/v1"
+	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+)
+
+type fakeUsers struct {
+	store.UserStore
+
+	users map[string]*v1.User
+}
+
+func (f *fakeUsers) Get(ctx context.Context, username string, opts metav1.GetOptions) (*v1.User, error) {
+	if user, ok := f.users[username]; ok {
+		return user, nil
+	}
+
+	return nil, errors.WithCode(code.ErrUserNotFound, "user %s not found", username)
+}
+
+func TestPlatformAdminRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	factory := &store.MockFactory{}
+	factory.On("Users").Return(&fakeUsers{users: map[string]*v1.User{
+		"admin":     {ObjectMeta: metav1.ObjectMeta{Name: "admin"}, IsAdmin: 1, Status: model.UserStatusActive},
+		"suspended": {ObjectMeta: metav1.ObjectMeta{Name: "suspended"}, IsAdmin: 1, Status: model.UserStatusSuspended},
+		"maria":     {ObjectMeta: metav1.ObjectMeta{Name: "maria"}, Status: model.UserStatusActive},
+	}})
+
+	defer store.SetClient(store.Client())
+	store.SetClient(factory)
+
+	tests := []struct {
+		username string
+		allowed  bool
+	}{
+		{username: "admin", allowed: true},
+		{username: "suspended", allowed: false},
+		{username: "maria", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			g := gin.New()
+			g.GET("/v1/tenants", func(c *gin.Context) { c.Set(UsernameKey, tt.username) },
+				PlatformAdminRequired(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/tenants", nil))
+
+			assert.Equal(t, tt.allowed, w.Code == http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package notifier sends the messages addressed to the users, like the
+// password reset links. The delivery is pluggable: the log and the file
+// notifiers do not deliver anything and are meant for local development.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+const (
+	// TypeLog writes the messages to the log.
+	TypeLog = "log"
+	// TypeFile appends the messages to a file.
+	TypeFile = "file"
+)
+
+// Message defines a message sent to a user.
+type Message struct {
+	// To is the address of the recipient, usually an email address.
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier defines the interface of the message deliveries.
+type Notifier interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	client Notifier = &logNotifier{}
+	mu     sync.RWMutex
+)
+
+// Client returns the notifier client instance.
+func Client() Notifier {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return client
+}
+
+// SetClient set the notifier client, the log notifier is used by default.
+func SetClient(n Notifier) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	client = n
+}
+
+// New returns the notifier of the given type, file is only used by the file notifier.
+func New(typ, file string) (Notifier, error) {
+	switch typ {
+	case TypeLog:
+		return &logNotifier{}, nil
+	case TypeFile:
+		if file == "" {
+			return nil, fmt.Errorf("file notifier requires a file")
+		}
+
+		return &fileNotifier{path: file}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier type `%s`", typ)
+	}
+}
+
+// logNotifier writes the messages to the log.
+type logNotifier struct{}
+
+func (n *logNotifier) Send(ctx context.Context, msg *Message) error {
+	log.L(ctx).Infow("notification", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+
+	return nil
+}
+
+// fileNotifier appends the messages to a file. The messages carry secrets like
+// reset tokens, so the file is only readable by its owner.
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (n *fileNotifier) Send(ctx context.Context, msg *Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package notifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(TypeLog, "")
+	assert.Nil(t, err)
+
+	_, err = New(TypeFile, "")
+	assert.NotNil(t, err)
+
+	_, err = New("smtp", "")
+	assert.NotNil(t, err)
+}
+
+func TestFileNotifier_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	n, err := New(TypeFile, path)
+	assert.Nil(t, err)
+
+	assert.Nil(t, n.Send(context.Background(), &Message{To: "a@example.com", Subject: "first", Body: "one"}))
+	assert.Nil(t, n.Send(context.Background(), &Message{To: "b@example.com", Subject: "second", Body: "two"}))
+
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "Subject: "))
+	assert.Contains(t, string(data), "To: a@example.com\nSubject: first\n\none\n")
+	assert.Contains(t, string(data), "To: b@example.com\nSubject: second\n\ntwo\n")
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
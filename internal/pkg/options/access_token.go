@@ -0,0 +1,66 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// AccessTokenOptions contains configuration items related to the personal access tokens.
+type AccessTokenOptions struct {
+	MaxPerUser int64         `json:"max-per-user" mapstructure:"max-per-user"`
+	DefaultTTL time.Duration `json:"default-ttl"  mapstructure:"default-ttl"`
+	MaxTTL     time.Duration `json:"max-ttl"      mapstructure:"max-ttl"`
+}
+
+// NewAccessTokenOptions creates a AccessTokenOptions object with default parameters.
+func NewAccessTokenOptions() *AccessTokenOptions {
+	return &AccessTokenOptions{
+		MaxPerUser: 20,
+		DefaultTTL: 30 * 24 * time.Hour,
+		MaxTTL:     365 * 24 * time.Hour,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *AccessTokenOptions) Validate() []error {
+	var errs []error
+
+	if o.MaxPerUser <= 0 {
+		errs = append(errs, fmt.Errorf("--access-token.max-per-user must be greater than 0"))
+	}
+
+	if o.DefaultTTL < 0 || o.MaxTTL < 0 {
+		errs = append(errs, fmt.Errorf("--access-token.default-ttl and --access-token.max-ttl must not be less than 0"))
+	}
+
+	if o.MaxTTL > 0 && (o.DefaultTTL == 0 || o.DefaultTTL > o.MaxTTL) {
+		errs = append(errs, fmt.Errorf("--access-token.default-ttl must be between 0 and --access-token.max-ttl"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to the personal access tokens for a specific api
+// server to the specified FlagSet.
+func (o *AccessTokenOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.Int64Var(&o.MaxPerUser, "access-token.max-per-user", o.MaxPerUser,
+		"Maximum number of personal access tokens a user can create.")
+
+	fs.DurationVar(&o.DefaultTTL, "access-token.default-ttl", o.DefaultTTL, ""+
+		"Lifetime of the personal access tokens when the create request does not choose it, "+
+		"0 means they never expire.")
+
+	fs.DurationVar(&o.MaxTTL, "access-token.max-ttl", o.MaxTTL, ""+
+		"Longest lifetime a create request can choose, 0 allows the tokens which never expire.")
+}
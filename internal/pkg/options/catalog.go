@@ -0,0 +1,46 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// CatalogOptions contains configuration items related to the catalog imports.
+type CatalogOptions struct {
+	MaxImportSize int64 `json:"max-import-size" mapstructure:"max-import-size"`
+}
+
+// NewCatalogOptions creates a CatalogOptions object with default parameters.
+func NewCatalogOptions() *CatalogOptions {
+	return &CatalogOptions{
+		MaxImportSize: 64 << 20,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *CatalogOptions) Validate() []error {
+	var errs []error
+
+	if o.MaxImportSize <= 0 {
+		errs = append(errs, fmt.Errorf("--catalog.max-import-size must be greater than 0"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to the catalog imports for a specific api server
+// to the specified FlagSet.
+func (o *CatalogOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.Int64Var(&o.MaxImportSize, "catalog.max-import-size", o.MaxImportSize,
+		"Largest catalog file accepted by the import, in bytes.")
+}
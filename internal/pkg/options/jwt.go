@@ -10,15 +10,20 @@ import (
 
 	"github.com/asaskevich/govalidator"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/server"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
 	"github.com/spf13/pflag"
 )
 
 // JwtOptions contains configuration items related to API server features.
 type JwtOptions struct {
-	Realm      string        `json:"realm"       mapstructure:"realm"`
-	Key        string        `json:"key"         mapstructure:"key"`
-	Timeout    time.Duration `json:"timeout"     mapstructure:"timeout"`
-	MaxRefresh time.Duration `json:"max-refresh" mapstructure:"max-refresh"`
+	Realm            string        `json:"realm"             mapstructure:"realm"`
+	Key              string        `json:"key"               mapstructure:"key"`
+	Timeout          time.Duration `json:"timeout"           mapstructure:"timeout"`
+	MaxRefresh       time.Duration `json:"max-refresh"       mapstructure:"max-refresh"`
+	SigningAlgorithm string        `json:"signing-algorithm" mapstructure:"signing-algorithm"`
+	// RevocationFailOpen accepts the tokens when the revocation store is
+	// unavailable, rather than rejecting them.
+	RevocationFailOpen bool `json:"revocation-fail-open" mapstructure:"revocation-fail-open"`
 }
 
 // NewJwtOptions creates a JwtOptions object with default parameters.
@@ -26,20 +31,22 @@ func NewJwtOptions() *JwtOptions {
 	defaults := server.NewConfig()
 
 	return &JwtOptions{
-		Realm:      defaults.Jwt.Realm,
-		Key:        defaults.Jwt.Key,
-		Timeout:    defaults.Jwt.Timeout,
-		MaxRefresh: defaults.Jwt.MaxRefresh,
+		Realm:            defaults.Jwt.Realm,
+		Key:              defaults.Jwt.Key,
+		Timeout:          defaults.Jwt.Timeout,
+		MaxRefresh:       defaults.Jwt.MaxRefresh,
+		SigningAlgorithm: defaults.Jwt.SigningAlgorithm,
 	}
 }
 
 // ApplyTo applies the run options to the method receiver and returns self.
 func (s *JwtOptions) ApplyTo(c *server.Config) error {
 	c.Jwt = &server.JwtInfo{
-		Realm:      s.Realm,
-		Key:        s.Key,
-		Timeout:    s.Timeout,
-		MaxRefresh: s.MaxRefresh,
+		Realm:            s.Realm,
+		Key:              s.Key,
+		Timeout:          s.Timeout,
+		MaxRefresh:       s.MaxRefresh,
+		SigningAlgorithm: s.SigningAlgorithm,
 	}
 
 	return nil
@@ -54,6 +61,10 @@ func (s *JwtOptions) Validate() []error {
 		errs = append(errs, fmt.Errorf("--secret-key must larger than 5 and little than 33"))
 	}
 
+	if s.SigningAlgorithm != "HS256" && !jwks.Supported(s.SigningAlgorithm) {
+		errs = append(errs, fmt.Errorf("--jwt.signing-algorithm must be one of HS256, RS256, ES256 or EdDSA"))
+	}
+
 	return errs
 }
 
@@ -69,5 +80,14 @@ func (s *JwtOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&s.Timeout, "jwt.timeout", s.Timeout, "JWT token timeout.")
 
 	fs.DurationVar(&s.MaxRefresh, "jwt.max-refresh", s.MaxRefresh, ""+
-		"This field allows clients to refresh their token until MaxRefresh has passed.")
+		"This field allows clients to refresh their token until MaxRefresh has passed. "+
+		"Login sessions whose refresh token has not been used for MaxRefresh are over.")
+
+	fs.StringVar(&s.SigningAlgorithm, "jwt.signing-algorithm", s.SigningAlgorithm, ""+
+		"Algorithm used to sign jwt token, one of HS256, RS256, ES256 or EdDSA. HS256 signs with jwt.key, "+
+		"the other ones sign with the rotated keys published at /.well-known/jwks.json.")
+
+	fs.BoolVar(&s.RevocationFailOpen, "jwt.revocation-fail-open", s.RevocationFailOpen, ""+
+		"Accept the tokens when redis is unavailable and their revocation cannot be checked. "+
+		"By default they are rejected, so that logged out and disabled users are never let in.")
 }
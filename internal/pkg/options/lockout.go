@@ -0,0 +1,71 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// LockoutOptions contains configuration items related to the login brute-force protection.
+type LockoutOptions struct {
+	MaxUserFailures int64         `json:"max-user-failures" mapstructure:"max-user-failures"`
+	MaxIPFailures   int64         `json:"max-ip-failures"   mapstructure:"max-ip-failures"`
+	Window          time.Duration `json:"window"            mapstructure:"window"`
+	BaseDuration    time.Duration `json:"base-duration"     mapstructure:"base-duration"`
+	MaxDuration     time.Duration `json:"max-duration"      mapstructure:"max-duration"`
+}
+
+// NewLockoutOptions creates a LockoutOptions object with default parameters.
+func NewLockoutOptions() *LockoutOptions {
+	return &LockoutOptions{
+		MaxUserFailures: 5,
+		MaxIPFailures:   20,
+		Window:          15 * time.Minute,
+		BaseDuration:    time.Minute,
+		MaxDuration:     time.Hour,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *LockoutOptions) Validate() []error {
+	var errs []error
+
+	if o.Window <= 0 {
+		errs = append(errs, fmt.Errorf("--lockout.window must be greater than 0"))
+	}
+
+	if o.BaseDuration <= 0 || o.MaxDuration < o.BaseDuration {
+		errs = append(errs, fmt.Errorf("--lockout.max-duration must be greater than --lockout.base-duration, itself greater than 0"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to the login brute-force protection for a
+// specific api server to the specified FlagSet.
+func (o *LockoutOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.Int64Var(&o.MaxUserFailures, "lockout.max-user-failures", o.MaxUserFailures, ""+
+		"Number of failed logins of a username before it is locked out, 0 disables the limit.")
+
+	fs.Int64Var(&o.MaxIPFailures, "lockout.max-ip-failures", o.MaxIPFailures, ""+
+		"Number of failed logins from a client IP before it is locked out, 0 disables the limit.")
+
+	fs.DurationVar(&o.Window, "lockout.window", o.Window,
+		"How long the failed logins are remembered after the last one.")
+
+	fs.DurationVar(&o.BaseDuration, "lockout.base-duration", o.BaseDuration,
+		"Duration of the first lockout, it doubles with every further failed login.")
+
+	fs.DurationVar(&o.MaxDuration, "lockout.max-duration", o.MaxDuration,
+		"Maximum duration of a lockout.")
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// NotifierOptions contains configuration items related to the messages sent to the users.
+type NotifierOptions struct {
+	Type string `json:"type" mapstructure:"type"`
+	File string `json:"file" mapstructure:"file"`
+}
+
+// NewNotifierOptions creates a NotifierOptions object with default parameters.
+func NewNotifierOptions() *NotifierOptions {
+	return &NotifierOptions{
+		Type: "log",
+		File: "",
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *NotifierOptions) Validate() []error {
+	var errs []error
+
+	switch o.Type {
+	case "log":
+	case "file":
+		if o.File == "" {
+			errs = append(errs, fmt.Errorf("--notifier.file must be set when --notifier.type is file"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("--notifier.type must be one of log, file"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to the notifier for a specific api server to the
+// specified FlagSet.
+func (o *NotifierOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringVar(&o.Type, "notifier.type", o.Type, ""+
+		"How the messages like the password reset links are sent to the users. "+
+		"Supported types are log and file, both are meant for local development.")
+
+	fs.StringVar(&o.File, "notifier.file", o.File,
+		"File the messages are appended to when --notifier.type is file.")
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/pflag"
+)
+
+// OIDCOptions contains configuration items related to the OpenID Connect login.
+type OIDCOptions struct {
+	Issuer        string   `json:"issuer"         mapstructure:"issuer"`
+	ClientID      string   `json:"client-id"      mapstructure:"client-id"`
+	ClientSecret  string   `json:"-"              mapstructure:"client-secret"`
+	RedirectURL   string   `json:"redirect-url"   mapstructure:"redirect-url"`
+	Scopes        []string `json:"scopes"         mapstructure:"scopes"`
+	UsernameClaim string   `json:"username-claim" mapstructure:"username-claim"`
+	EmailClaim    string   `json:"email-claim"    mapstructure:"email-claim"`
+	NicknameClaim string   `json:"nickname-claim" mapstructure:"nickname-claim"`
+	Provision     bool     `json:"provision"      mapstructure:"provision"`
+}
+
+// NewOIDCOptions creates a OIDCOptions object with default parameters.
+func NewOIDCOptions() *OIDCOptions {
+	return &OIDCOptions{
+		Scopes:        []string{"openid", "profile", "email"},
+		UsernameClaim: "preferred_username",
+		EmailClaim:    "email",
+		NicknameClaim: "name",
+		Provision:     true,
+	}
+}
+
+// Enabled tells whether the OpenID Connect login is configured.
+func (o *OIDCOptions) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *OIDCOptions) Validate() []error {
+	var errs []error
+
+	if !o.Enabled() {
+		return errs
+	}
+
+	if u, err := url.Parse(o.Issuer); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("--oidc.issuer must be an http(s) url"))
+	}
+
+	if o.ClientID == "" {
+		errs = append(errs, fmt.Errorf("--oidc.client-id is required when --oidc.issuer is set"))
+	}
+
+	if u, err := url.Parse(o.RedirectURL); err != nil || !u.IsAbs() {
+		errs = append(errs, fmt.Errorf("--oidc.redirect-url must be an absolute url"))
+	}
+
+	if !contains(o.Scopes, "openid") {
+		errs = append(errs, fmt.Errorf("--oidc.scopes must contain openid"))
+	}
+
+	if o.UsernameClaim == "" || o.EmailClaim == "" {
+		errs = append(errs, fmt.Errorf("--oidc.username-claim and --oidc.email-claim must not be empty"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to the OpenID Connect login for a specific api
+// server to the specified FlagSet.
+func (o *OIDCOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringVar(&o.Issuer, "oidc.issuer", o.Issuer, ""+
+		"Issuer url of the OpenID Connect provider, its configuration is discovered from it. "+
+		"The OpenID Connect login is disabled when empty.")
+
+	fs.StringVar(&o.ClientID, "oidc.client-id", o.ClientID,
+		"Client id of the apiserver registered at the OpenID Connect provider.")
+
+	fs.StringVar(&o.ClientSecret, "oidc.client-secret", o.ClientSecret,
+		"Client secret of the apiserver, empty for a public client.")
+
+	fs.StringVar(&o.RedirectURL, "oidc.redirect-url", o.RedirectURL, ""+
+		"Url the provider redirects the users to once authenticated, "+
+		"it must point to the /login/oidc/callback route of the apiserver.")
+
+	fs.StringSliceVar(&o.Scopes, "oidc.scopes", o.Scopes, "Scopes requested from the OpenID Connect provider.")
+
+	fs.StringVar(&o.UsernameClaim, "oidc.username-claim", o.UsernameClaim,
+		"ID token claim mapped to the username of the provisioned users.")
+
+	fs.StringVar(&o.EmailClaim, "oidc.email-claim", o.EmailClaim,
+		"ID token claim mapped to the email of the provisioned users.")
+
+	fs.StringVar(&o.NicknameClaim, "oidc.nickname-claim", o.NicknameClaim,
+		"ID token claim mapped to the nickname of the provisioned users, the username is used when missing.")
+
+	fs.BoolVar(&o.Provision, "oidc.provision", o.Provision, ""+
+		"Provision an active user on the first login of an identity, "+
+		"otherwise only the identities already linked to a user can log in.")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
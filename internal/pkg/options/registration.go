@@ -0,0 +1,39 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// RegistrationOptions contains configuration items related to the creation of the users.
+type RegistrationOptions struct {
+	RequireEmailVerification bool `json:"require-email-verification" mapstructure:"require-email-verification"`
+}
+
+// NewRegistrationOptions creates a RegistrationOptions object with default parameters.
+func NewRegistrationOptions() *RegistrationOptions {
+	return &RegistrationOptions{
+		RequireEmailVerification: false,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *RegistrationOptions) Validate() []error {
+	return []error{}
+}
+
+// AddFlags adds flags related to the creation of the users for a specific api
+// server to the specified FlagSet.
+func (o *RegistrationOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.BoolVar(&o.RequireEmailVerification, "registration.require-email-verification", o.RequireEmailVerification, ""+
+		"New users are pending until they verify their email with the token sent by the notifier, "+
+		"otherwise they are active right away.")
+}
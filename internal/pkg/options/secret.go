@@ -0,0 +1,64 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// SecretOptions contains configuration items related to the API secrets of the users.
+type SecretOptions struct {
+	MaxPerUser             int64         `json:"max-per-user"              mapstructure:"max-per-user"`
+	RotationGracePeriod    time.Duration `json:"rotation-grace-period"     mapstructure:"rotation-grace-period"`
+	MaxRotationGracePeriod time.Duration `json:"max-rotation-grace-period" mapstructure:"max-rotation-grace-period"`
+}
+
+// NewSecretOptions creates a SecretOptions object with default parameters.
+func NewSecretOptions() *SecretOptions {
+	return &SecretOptions{
+		MaxPerUser:             10,
+		RotationGracePeriod:    24 * time.Hour,
+		MaxRotationGracePeriod: 7 * 24 * time.Hour,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *SecretOptions) Validate() []error {
+	var errs []error
+
+	if o.MaxPerUser <= 0 {
+		errs = append(errs, fmt.Errorf("--secret.max-per-user must be greater than 0"))
+	}
+
+	if o.RotationGracePeriod < 0 || o.MaxRotationGracePeriod < o.RotationGracePeriod {
+		errs = append(errs, fmt.Errorf(""+
+			"--secret.max-rotation-grace-period must not be less than --secret.rotation-grace-period, "+
+			"itself not less than 0"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to the API secrets for a specific api server to
+// the specified FlagSet.
+func (o *SecretOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.Int64Var(&o.MaxPerUser, "secret.max-per-user", o.MaxPerUser,
+		"Maximum number of secrets a user can create.")
+
+	fs.DurationVar(&o.RotationGracePeriod, "secret.rotation-grace-period", o.RotationGracePeriod, ""+
+		"How long the key replaced by a rotation is still accepted, "+
+		"when the rotation request does not choose it.")
+
+	fs.DurationVar(&o.MaxRotationGracePeriod, "secret.max-rotation-grace-period", o.MaxRotationGracePeriod,
+		"Longest grace period a rotation request can choose.")
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// TwoFactorOptions contains configuration items related to two-factor authentication.
+type TwoFactorOptions struct {
+	Issuer           string `json:"issuer"             mapstructure:"issuer"`
+	RequireForAdmins bool   `json:"require-for-admins" mapstructure:"require-for-admins"`
+}
+
+// NewTwoFactorOptions creates a TwoFactorOptions object with default parameters.
+func NewTwoFactorOptions() *TwoFactorOptions {
+	return &TwoFactorOptions{
+		Issuer:           "iam",
+		RequireForAdmins: false,
+	}
+}
+
+// Validate is used to parse and validate the parameters entered by the user at
+// the command line when the program starts.
+func (o *TwoFactorOptions) Validate() []error {
+	var errs []error
+
+	if o.Issuer == "" || strings.Contains(o.Issuer, ":") {
+		errs = append(errs, fmt.Errorf("--two-factor.issuer must be non empty and must not contain ':'"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to two-factor authentication for a specific api
+// server to the specified FlagSet.
+func (o *TwoFactorOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringVar(&o.Issuer, "two-factor.issuer", o.Issuer,
+		"Issuer shown by the authenticator apps next to the TOTP codes.")
+
+	fs.BoolVar(&o.RequireForAdmins, "two-factor.require-for-admins", o.RequireForAdmins, ""+
+		"Require two-factor authentication for administrators, they have to enroll on their next login "+
+		"and can no longer use basic authentication.")
+}
@@ -0,0 +1,225 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package policylint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ory/ladon"
+)
+
+// Decode decodes a JSON policy, a JSON array of policies or an iam policy list,
+// which holds the policies in its `items` field. A policy is either a ladon
+// policy or an iam policy resource, whose ladon policy is held by the `policy`
+// field and named after `metadata.name`.
+//
+// Conditions of unknown types, which make ladon reject the whole policy, are
+// reported as findings and left out, so the rest of the policy can be linted.
+// Policies which cannot be decoded at all are reported and skipped.
+func Decode(data []byte) ([]*ladon.DefaultPolicy, []Finding, error) {
+	var raws []json.RawMessage
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, nil, err
+		}
+
+		raws = list.Items
+		if raws == nil {
+			raws = []json.RawMessage{data}
+		}
+	}
+
+	var policies []*ladon.DefaultPolicy
+	var findings []Finding
+
+	for i, raw := range raws {
+		policy, found, err := decodePolicy(raw)
+		if err != nil {
+			findings = append(findings, Finding{
+				Policy:   fmt.Sprintf("#%d", i),
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+
+			continue
+		}
+
+		if policy.ID == "" {
+			policy.ID = fmt.Sprintf("#%d", i)
+		}
+
+		for j := range found {
+			found[j].Policy = policy.ID
+		}
+
+		policies = append(policies, policy)
+		findings = append(findings, found...)
+	}
+
+	return policies, findings, nil
+}
+
+// decodePolicy decodes a single policy, leaving out the unknown conditions.
+func decodePolicy(data []byte) (*ladon.DefaultPolicy, []Finding, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+
+	var name string
+
+	if inner, ok := fields["policy"]; ok && fields["subjects"] == nil {
+		var meta struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, nil, err
+		}
+
+		name = meta.Metadata.Name
+		fields = nil
+		if err := json.Unmarshal(inner, &fields); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	findings, err := dropUnknownConditions(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy := &ladon.DefaultPolicy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, nil, err
+	}
+
+	if policy.ID == "" {
+		policy.ID = name
+	}
+
+	return policy, findings, nil
+}
+
+// dropUnknownConditions removes the conditions whose type is not registered
+// with ladon and reports them.
+func dropUnknownConditions(fields map[string]json.RawMessage) ([]Finding, error) {
+	raw, ok := fields["conditions"]
+	if !ok || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var conditions map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		return nil, fmt.Errorf("conditions: %w", err)
+	}
+
+	var findings []Finding
+
+	for key, c := range conditions {
+		var typ struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(c, &typ); err != nil {
+			return nil, fmt.Errorf("conditions.%s: %w", key, err)
+		}
+
+		if _, ok := ladon.ConditionFactories[typ.Type]; ok {
+			continue
+		}
+
+		message := fmt.Sprintf("unknown condition type %q", typ.Type)
+		if suggestion := suggestConditionType(typ.Type); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+
+		findings = append(findings, Finding{
+			Field:    "conditions." + key,
+			Severity: SeverityError,
+			Message:  message,
+		})
+		delete(conditions, key)
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Field < findings[j].Field })
+
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return nil, err
+	}
+	fields["conditions"] = data
+
+	return findings, nil
+}
+
+// ConditionTypes returns the names of the registered condition types.
+func ConditionTypes() []string {
+	types := make([]string, 0, len(ladon.ConditionFactories))
+	for name := range ladon.ConditionFactories {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// suggestConditionType returns the registered condition type closest to typ,
+// or an empty string when none is close enough.
+func suggestConditionType(typ string) string {
+	best, bestDistance := "", 4
+
+	for _, name := range ConditionTypes() {
+		if d := distance(typ, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+
+	return best
+}
+
+// distance returns the case insensitive edit distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
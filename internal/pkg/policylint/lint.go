@@ -0,0 +1,274 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package policylint checks ladon policies before they are stored: their
+// structure, the regular expressions of their patterns, their conditions, and
+// the allow and deny policies which may overlap. It is shared by iam-apiserver
+// and iamctl, so a policy accepted by `iamctl policy lint` is accepted by the
+// server as well.
+package policylint
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ory/ladon"
+	"github.com/ory/ladon/compiler"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/condition"
+)
+
+const (
+	// SeverityError marks a finding which makes the policy invalid.
+	SeverityError = "error"
+
+	// SeverityWarning marks a finding which is valid but likely a mistake.
+	SeverityWarning = "warning"
+)
+
+// Finding defines a problem of a policy.
+type Finding struct {
+	Policy   string `json:"policy,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// String returns the finding in the form `policy foo: subjects: message`.
+func (f Finding) String() string {
+	parts := make([]string, 0, 3)
+	if f.Policy != "" {
+		parts = append(parts, "policy "+f.Policy)
+	}
+
+	if f.Field != "" {
+		parts = append(parts, f.Field)
+	}
+
+	return strings.Join(append(parts, f.Message), ": ")
+}
+
+// HasErrors tells whether any of the findings is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate lints a single policy and returns its errors joined together, the
+// warnings are ignored.
+func Validate(policy *ladon.DefaultPolicy) error {
+	var messages []string
+
+	for _, f := range lintPolicy("", policy) {
+		if f.Severity == SeverityError {
+			messages = append(messages, f.String())
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// Lint checks the policies one by one, then warns about the allow policies
+// which may be overridden by one of the deny policies.
+func Lint(policies ...*ladon.DefaultPolicy) []Finding {
+	findings := []Finding{}
+	names := make([]string, len(policies))
+
+	for i, policy := range policies {
+		names[i] = policy.ID
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("#%d", i)
+		}
+
+		findings = append(findings, lintPolicy(names[i], policy)...)
+	}
+
+	for i, allow := range policies {
+		if allow.Effect != ladon.AllowAccess {
+			continue
+		}
+
+		for j, deny := range policies {
+			if deny.Effect != ladon.DenyAccess || !overlap(allow, deny) {
+				continue
+			}
+
+			findings = append(findings, Finding{
+				Policy:   names[i],
+				Severity: SeverityWarning,
+				Message: fmt.Sprintf("may be overridden by deny policy %s, their subjects, actions and resources overlap",
+					names[j]),
+			})
+		}
+	}
+
+	return findings
+}
+
+// lintPolicy checks the structure, the patterns and the conditions of a policy.
+func lintPolicy(name string, policy *ladon.DefaultPolicy) []Finding {
+	var findings []Finding
+
+	add := func(severity, field, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Policy:   name,
+			Field:    field,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if policy.Effect != ladon.AllowAccess && policy.Effect != ladon.DenyAccess {
+		add(SeverityError, "effect", "must be %q or %q, got %q", ladon.AllowAccess, ladon.DenyAccess, policy.Effect)
+	}
+
+	for _, field := range []struct {
+		name     string
+		patterns []string
+	}{
+		{"subjects", policy.Subjects},
+		{"actions", policy.Actions},
+		{"resources", policy.Resources},
+	} {
+		if len(field.patterns) == 0 {
+			add(SeverityError, field.name, "must not be empty, the policy would never apply")
+
+			continue
+		}
+
+		seen := map[string]bool{}
+		for i, pattern := range field.patterns {
+			path := fmt.Sprintf("%s[%d]", field.name, i)
+
+			switch {
+			case pattern == "":
+				add(SeverityError, path, "must not be empty")
+			case isRegex(pattern):
+				if _, err := compiler.CompileRegex(pattern, policy.GetStartDelimiter(), policy.GetEndDelimiter()); err != nil {
+					add(SeverityError, path, "invalid pattern %q: %v", pattern, err)
+				}
+			case strings.Contains(pattern, "*"):
+				add(SeverityWarning, path, "%q is matched literally, enclose regular expressions in <>, e.g. <.*>", pattern)
+			}
+
+			if seen[pattern] {
+				add(SeverityWarning, path, "duplicate pattern %q", pattern)
+			}
+			seen[pattern] = true
+		}
+	}
+
+	keys := make([]string, 0, len(policy.Conditions))
+	for key := range policy.Conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		c := policy.Conditions[key]
+		if c == nil {
+			add(SeverityError, "conditions."+key, "must not be null")
+
+			continue
+		}
+
+		if err := validateCondition(c); err != nil {
+			add(SeverityError, "conditions."+key, "%v", err)
+		}
+	}
+
+	return findings
+}
+
+// validateCondition checks the options of a condition. The conditions of iam
+// validate themselves, the options of the ladon ones are checked here.
+func validateCondition(c ladon.Condition) error {
+	switch c := c.(type) {
+	case *ladon.CIDRCondition:
+		if _, _, err := net.ParseCIDR(c.CIDR); err != nil {
+			return err
+		}
+	case *ladon.StringMatchCondition:
+		if _, err := regexp.Compile(c.Matches); err != nil {
+			return err
+		}
+	default:
+		return errors.Unwrap(condition.Validate(ladon.Conditions{"": c}))
+	}
+
+	return nil
+}
+
+// isRegex tells whether the pattern holds a regular expression.
+func isRegex(pattern string) bool {
+	return strings.ContainsAny(pattern, "<>")
+}
+
+// overlap tells whether a request may match both policies. Conditions are not
+// taken into account.
+func overlap(a, b *ladon.DefaultPolicy) bool {
+	return patternsOverlap(a.Subjects, b.Subjects) &&
+		patternsOverlap(a.Actions, b.Actions) &&
+		patternsOverlap(a.Resources, b.Resources)
+}
+
+// patternsOverlap tells whether a value may match a pattern of both lists.
+// Two regular expressions overlap when the literal text in front of one is a
+// prefix of the other's, which may report overlaps which do not exist.
+func patternsOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if patternOverlap(x, y) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func patternOverlap(x, y string) bool {
+	switch {
+	case x == y:
+		return true
+	case !isRegex(x):
+		return matches(y, x)
+	case !isRegex(y):
+		return matches(x, y)
+	}
+
+	px, py := literalPrefix(x), literalPrefix(y)
+
+	return strings.HasPrefix(px, py) || strings.HasPrefix(py, px)
+}
+
+// matches tells whether the value matches the pattern, invalid patterns match
+// nothing.
+func matches(pattern, value string) bool {
+	ok, err := ladon.DefaultMatcher.Matches(&ladon.DefaultPolicy{}, []string{pattern}, value)
+
+	return err == nil && ok
+}
+
+// literalPrefix returns the text in front of the first regular expression.
+func literalPrefix(pattern string) string {
+	if i := strings.IndexByte(pattern, '<'); i >= 0 {
+		return pattern[:i]
+	}
+
+	return pattern
+}
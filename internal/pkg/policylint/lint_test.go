@@ -0,0 +1,153 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package policylint
+
+import (
+	"testing"
+
+	"github.com/ory/ladon"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/condition"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *ladon.DefaultPolicy
+		want   []Finding
+	}{
+		{
+			name: "valid",
+			policy: &ladon.DefaultPolicy{
+				ID: "p", Effect: "allow", Subjects: []string{"users:<.*>"},
+				Actions: []string{"<get|list>"}, Resources: []string{"resources:articles"},
+				Conditions: ladon.Conditions{"ip": &ladon.CIDRCondition{CIDR: "10.0.0.0/8"}},
+			},
+			want: []Finding{},
+		},
+		{
+			name: "structure",
+			policy: &ladon.DefaultPolicy{
+				ID: "p", Effect: "Allow", Actions: []string{"get", ""}, Resources: []string{"articles:*", "articles:*"},
+			},
+			want: []Finding{
+				{Policy: "p", Field: "effect", Severity: SeverityError, Message: `must be "allow" or "deny", got "Allow"`},
+				{Policy: "p", Field: "subjects", Severity: SeverityError, Message: "must not be empty, the policy would never apply"},
+				{Policy: "p", Field: "actions[1]", Severity: SeverityError, Message: "must not be empty"},
+				{Policy: "p", Field: "resources[0]", Severity: SeverityWarning, Message: `"articles:*" is matched literally, enclose regular expressions in <>, e.g. <.*>`},
+				{Policy: "p", Field: "resources[1]", Severity: SeverityWarning, Message: `"articles:*" is matched literally, enclose regular expressions in <>, e.g. <.*>`},
+				{Policy: "p", Field: "resources[1]", Severity: SeverityWarning, Message: `duplicate pattern "articles:*"`},
+			},
+		},
+		{
+			name: "patterns and conditions",
+			policy: &ladon.DefaultPolicy{
+				ID: "p", Effect: "deny", Subjects: []string{"users:<[a-z>"}, Actions: []string{"<get"},
+				Resources: []string{"articles"},
+				Conditions: ladon.Conditions{
+					"ip":    &ladon.CIDRCondition{CIDR: "10.0.0.0"},
+					"price": &condition.NumericCompareCondition{Operator: "between"},
+				},
+			},
+			want: []Finding{
+				{Policy: "p", Field: "subjects[0]", Severity: SeverityError},
+				{Policy: "p", Field: "actions[0]", Severity: SeverityError},
+				{Policy: "p", Field: "conditions.ip", Severity: SeverityError},
+				{Policy: "p", Field: "conditions.price", Severity: SeverityError},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lint(tt.policy)
+			if !assert.Len(t, got, len(tt.want)) {
+				return
+			}
+
+			for i := range got {
+				if tt.want[i].Message == "" {
+					got[i].Message = ""
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLintOverlap(t *testing.T) {
+	allow := &ladon.DefaultPolicy{
+		ID: "readers", Effect: "allow", Subjects: []string{"users:<.*>"},
+		Actions: []string{"get"}, Resources: []string{"resources:articles:<.*>"},
+	}
+	deny := &ladon.DefaultPolicy{
+		ID: "no-drafts", Effect: "deny", Subjects: []string{"users:maria"},
+		Actions: []string{"<get|list>"}, Resources: []string{"resources:articles:drafts:<.*>"},
+	}
+	unrelated := &ladon.DefaultPolicy{
+		ID: "printers", Effect: "deny", Subjects: []string{"users:<.*>"},
+		Actions: []string{"get"}, Resources: []string{"resources:printers:<.*>"},
+	}
+
+	assert.Equal(t, []Finding{{
+		Policy:   "readers",
+		Severity: SeverityWarning,
+		Message:  "may be overridden by deny policy no-drafts, their subjects, actions and resources overlap",
+	}}, Lint(allow, deny, unrelated))
+}
+
+func TestValidate(t *testing.T) {
+	policy := &ladon.DefaultPolicy{
+		Effect: "allow", Subjects: []string{"users:<.*>"}, Actions: []string{"get", "get"},
+		Resources: []string{"articles"},
+	}
+	assert.Nil(t, Validate(policy))
+
+	policy.Effect = "permit"
+	policy.Resources = nil
+	assert.EqualError(t, Validate(policy),
+		`effect: must be "allow" or "deny", got "permit"; resources: must not be empty, the policy would never apply`)
+}
+
+func TestDecode(t *testing.T) {
+	data := `[
+		{"id": "a", "effect": "allow", "subjects": ["users:maria"], "actions": ["get"], "resources": ["articles"],
+		 "conditions": {"ip": {"type": "CidrCondition", "options": {"cidr": "10.0.0.0/8"}},
+		                "owner": {"type": "ResourceOwnerCondition"},
+		                "weird": {"type": "NoSuchThing"}}},
+		{"metadata": {"name": "b"}, "policy": {"effect": "deny", "subjects": ["users:maria"], "actions": ["get"],
+		 "resources": ["articles"]}},
+		{"effect": "allow", "subjects": "users:maria"}
+	]`
+
+	policies, findings, err := Decode([]byte(data))
+	assert.Nil(t, err)
+	assert.Len(t, policies, 2)
+	assert.Equal(t, "a", policies[0].ID)
+	assert.Len(t, policies[0].Conditions, 1)
+	assert.Equal(t, "b", policies[1].ID)
+	assert.Equal(t, "deny", policies[1].Effect)
+
+	assert.Len(t, findings, 3)
+	assert.Equal(t, Finding{
+		Policy: "a", Field: "conditions.ip", Severity: SeverityError,
+		Message: `unknown condition type "CidrCondition", did you mean "CIDRCondition"?`,
+	}, findings[0])
+	assert.Equal(t, `policy a: conditions.weird: unknown condition type "NoSuchThing"`, findings[1].String())
+	assert.Equal(t, "#2", findings[2].Policy)
+
+	policies, findings, err = Decode([]byte(`{"id": "c", "effect": "allow"}`))
+	assert.Nil(t, err)
+	assert.Len(t, policies, 1)
+	assert.Empty(t, findings)
+
+	policies, _, err = Decode([]byte(`{"totalCount": 1, "items": [{"metadata": {"name": "d"}, "policy": {}}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "d", policies[0].ID)
+
+	_, _, err = Decode([]byte(`[{`))
+	assert.NotNil(t, err)
+}
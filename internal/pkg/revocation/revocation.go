@@ -0,0 +1,247 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package revocation keeps track of the jwt tokens which must not be accepted
+// anymore although they are not expired yet: tokens revoked one by one on
+// logout, all the tokens of a revoked login session, and all the tokens of a
+// user issued before a watermark, bumped when the user changes the password or
+// the account is disabled. The tokens are rejected when the revocation store is
+// unavailable, unless the store is wrapped by FailOpen.
+package revocation
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/marmotedu/errors"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
+)
+
+const (
+	// JTIKey defines the jwt claim which identifies a token.
+	JTIKey = "jti"
+
+	// IssuedAtKey defines the jwt claim which records when the token was issued.
+	// It is kept by token refreshing, contrary to gin-jwt's orig_iat claim.
+	IssuedAtKey = "iat"
+
+	// SubjectKey defines the jwt claim which holds the username.
+	SubjectKey = "sub"
+
+	// SessionKey defines the jwt claim which holds the login session identifier.
+	SessionKey = "sid"
+
+	tokenKeyPrefix   = "iam-revoked-token-"
+	sessionKeyPrefix = "iam-revoked-session-"
+	userKeyPrefix    = "iam-revoked-user-"
+)
+
+// Store defines the storage of the revoked tokens and the per-user watermarks.
+type Store interface {
+	// RevokeToken revokes the token with the given jti, ttl should cover the
+	// remaining life of the token.
+	RevokeToken(jti string, ttl time.Duration) error
+	// TokenRevoked tells whether the token with the given jti has been revoked.
+	TokenRevoked(jti string) (bool, error)
+	// RevokeSession revokes all the tokens issued for the login session, ttl
+	// should cover the remaining life of the session.
+	RevokeSession(sid string, ttl time.Duration) error
+	// SessionRevoked tells whether the login session has been revoked.
+	SessionRevoked(sid string) (bool, error)
+	// RevokeUser revokes all the tokens of the user issued before the given time.
+	RevokeUser(username string, before time.Time) error
+	// UserRevokedBefore returns the watermark of the user, zero if there is none.
+	UserRevokedBefore(username string) (time.Time, error)
+}
+
+var (
+	client Store = &redisStore{}
+	mu     sync.RWMutex
+)
+
+// Client returns the revocation store client instance.
+func Client() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return client
+}
+
+// SetClient set the revocation store client, the redis store is used by default.
+func SetClient(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	client = s
+}
+
+// Revoked tells whether the token with the given claims has been revoked,
+// either by its jti, by its login session or by the watermark of its subject.
+func Revoked(s Store, claims map[string]interface{}) (bool, error) {
+	if jti, ok := claims[JTIKey].(string); ok && jti != "" {
+		revoked, err := s.TokenRevoked(jti)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	if sid, ok := claims[SessionKey].(string); ok && sid != "" {
+		revoked, err := s.SessionRevoked(sid)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	username, ok := claims[SubjectKey].(string)
+	if !ok || username == "" {
+		return false, nil
+	}
+
+	before, err := s.UserRevokedBefore(username)
+	if err != nil || before.IsZero() {
+		return false, err
+	}
+
+	return issuedAt(claims) < before.Unix(), nil
+}
+
+// Check returns a code.ErrTokenRevoked error if the token with the given claims
+// has been revoked, and a code.ErrUnknown error if the store is unavailable.
+func Check(s Store, claims map[string]interface{}) error {
+	revoked, err := Revoked(s, claims)
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, "check token revocation failed: %s", err.Error())
+	}
+
+	if revoked {
+		return errors.WithCode(code.ErrTokenRevoked, "Token has been revoked.")
+	}
+
+	return nil
+}
+
+// FailOpen returns a store which tells that nothing has been revoked when s is
+// unavailable, for the deployments where availability must win over the
+// revocations.
+func FailOpen(s Store) Store {
+	return &failOpenStore{s}
+}
+
+// failOpenStore logs the failures of the store it wraps, and ignores them.
+type failOpenStore struct {
+	Store
+}
+
+func (f *failOpenStore) TokenRevoked(jti string) (bool, error) {
+	revoked, err := f.Store.TokenRevoked(jti)
+	if err != nil {
+		log.Warnf("check token revocation failed, accept the token: %s", err.Error())
+	}
+
+	return revoked, nil
+}
+
+func (f *failOpenStore) SessionRevoked(sid string) (bool, error) {
+	revoked, err := f.Store.SessionRevoked(sid)
+	if err != nil {
+		log.Warnf("check session revocation failed, accept the session: %s", err.Error())
+	}
+
+	return revoked, nil
+}
+
+func (f *failOpenStore) UserRevokedBefore(username string) (time.Time, error) {
+	before, err := f.Store.UserRevokedBefore(username)
+	if err != nil {
+		log.Warnf("get revocation watermark of user %s failed, ignore it: %s", username, err.Error())
+
+		return time.Time{}, nil
+	}
+
+	return before, nil
+}
+
+// issuedAt returns the iat claim, tokens issued before it was introduced fall
+// back to orig_iat.
+func issuedAt(claims map[string]interface{}) int64 {
+	for _, key := range []string{IssuedAtKey, "orig_iat"} {
+		if v, ok := claims[key].(float64); ok {
+			return int64(v)
+		}
+	}
+
+	return 0
+}
+
+// redisStore implements Store with the shared redis cluster.
+type redisStore struct {
+	storage.RedisCluster
+}
+
+func (r *redisStore) RevokeToken(jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return r.SetKey(tokenKeyPrefix+jti, "1", ttl)
+}
+
+func (r *redisStore) TokenRevoked(jti string) (bool, error) {
+	// RedisCluster.Exists does not check the connection itself.
+	if !storage.Connected() {
+		return false, storage.ErrRedisIsDown
+	}
+
+	return r.Exists(tokenKeyPrefix + jti)
+}
+
+func (r *redisStore) RevokeSession(sid string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return r.SetKey(sessionKeyPrefix+sid, "1", ttl)
+}
+
+func (r *redisStore) SessionRevoked(sid string) (bool, error) {
+	if !storage.Connected() {
+		return false, storage.ErrRedisIsDown
+	}
+
+	return r.Exists(sessionKeyPrefix + sid)
+}
+
+func (r *redisStore) RevokeUser(username string, before time.Time) error {
+	// tokens can be refreshed for ever, so the watermark never expires.
+	return r.SetKey(userKeyPrefix+username, strconv.FormatInt(before.Unix(), 10), 0)
+}
+
+func (r *redisStore) UserRevokedBefore(username string) (time.Time, error) {
+	if !storage.Connected() {
+		return time.Time{}, storage.ErrRedisIsDown
+	}
+
+	// GetKey reports every failure as a missing key, so the existence is
+	// checked first to tell them apart.
+	exists, err := r.Exists(userKeyPrefix + username)
+	if err != nil || !exists {
+		return time.Time{}, err
+	}
+
+	value, err := r.GetKey(userKeyPrefix + username)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(sec, 0), nil
+}
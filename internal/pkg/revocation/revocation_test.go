@@ -0,0 +1,116 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package revocation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marmotedu/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/code"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
+)
+
+type fakeStore struct {
+	tokens   map[string]bool
+	sessions map[string]bool
+	users    map[string]time.Time
+}
+
+func (f *fakeStore) RevokeToken(jti string, ttl time.Duration) error {
+	f.tokens[jti] = true
+
+	return nil
+}
+
+func (f *fakeStore) TokenRevoked(jti string) (bool, error) {
+	return f.tokens[jti], nil
+}
+
+func (f *fakeStore) RevokeSession(sid string, ttl time.Duration) error {
+	f.sessions[sid] = true
+
+	return nil
+}
+
+func (f *fakeStore) SessionRevoked(sid string) (bool, error) {
+	return f.sessions[sid], nil
+}
+
+func (f *fakeStore) RevokeUser(username string, before time.Time) error {
+	f.users[username] = before
+
+	return nil
+}
+
+func (f *fakeStore) UserRevokedBefore(username string) (time.Time, error) {
+	return f.users[username], nil
+}
+
+func TestRevoked(t *testing.T) {
+	s := &fakeStore{tokens: map[string]bool{}, sessions: map[string]bool{}, users: map[string]time.Time{}}
+	issued := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	claims := map[string]interface{}{
+		JTIKey:      "token-1",
+		SubjectKey:  "maria",
+		IssuedAtKey: float64(issued.Unix()),
+	}
+
+	revoked, err := Revoked(s, claims)
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+
+	// a watermark older than the token keeps it valid.
+	_ = s.RevokeUser("maria", issued.Add(-time.Hour))
+	revoked, _ = Revoked(s, claims)
+	assert.False(t, revoked)
+
+	_ = s.RevokeUser("maria", issued.Add(time.Second))
+	revoked, _ = Revoked(s, claims)
+	assert.True(t, revoked)
+
+	// legacy tokens without iat fall back to orig_iat.
+	legacy := map[string]interface{}{SubjectKey: "maria", "orig_iat": float64(issued.Add(time.Hour).Unix())}
+	revoked, _ = Revoked(s, legacy)
+	assert.False(t, revoked)
+
+	_ = s.RevokeToken("token-2", time.Hour)
+	revoked, _ = Revoked(s, map[string]interface{}{JTIKey: "token-2", SubjectKey: "colin"})
+	assert.True(t, revoked)
+
+	_ = s.RevokeSession("session-1", time.Hour)
+	revoked, _ = Revoked(s, map[string]interface{}{JTIKey: "token-3", SessionKey: "session-1", SubjectKey: "colin"})
+	assert.True(t, revoked)
+}
+
+// downStore is a store whose redis is down.
+type downStore struct {
+	fakeStore
+}
+
+func (d *downStore) TokenRevoked(jti string) (bool, error) {
+	return false, storage.ErrRedisIsDown
+}
+
+func (d *downStore) SessionRevoked(sid string) (bool, error) {
+	return false, storage.ErrRedisIsDown
+}
+
+func (d *downStore) UserRevokedBefore(username string) (time.Time, error) {
+	return time.Time{}, storage.ErrRedisIsDown
+}
+
+func TestCheck(t *testing.T) {
+	s := &fakeStore{tokens: map[string]bool{"token-1": true}, sessions: map[string]bool{}, users: map[string]time.Time{}}
+	assert.True(t, errors.IsCode(Check(s, map[string]interface{}{JTIKey: "token-1"}), code.ErrTokenRevoked))
+	assert.Nil(t, Check(s, map[string]interface{}{JTIKey: "token-2", SubjectKey: "colin"}))
+
+	// the tokens are rejected when the store is down, unless it fails open.
+	claims := map[string]interface{}{JTIKey: "token-2", SessionKey: "session-1", SubjectKey: "colin"}
+	assert.True(t, errors.IsCode(Check(&downStore{}, claims), code.ErrUnknown))
+	assert.Nil(t, Check(FailOpen(&downStore{}), claims))
+}
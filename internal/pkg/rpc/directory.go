@@ -0,0 +1,250 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/scope"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/tenant"
+	"google.golang.org/grpc"
+)
+
+// ListMembershipsRequest defines the request of Directory.ListMemberships.
+type ListMembershipsRequest struct{}
+
+// PolicyRef references a policy by its owner and name.
+type PolicyRef struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// ListMembershipsResponse defines the response of Directory.ListMemberships.
+type ListMembershipsResponse struct {
+	// Principals maps a username to the groups and roles the user belongs to,
+	// qualified by their tenant, e.g. `shop/group:warehouse` and `shop/role:picker`.
+	// Roles bound to a group are resolved to the members of the group.
+	Principals map[string][]string `json:"principals"`
+
+	// Policies maps a group or role principal to the policies bound to it.
+	Policies map[string][]PolicyRef `json:"policies"`
+
+	// Tenants maps a username to the tenant of the user. Users which are missing
+	// belong to the default tenant.
+	Tenants map[string]string `json:"tenants"`
+}
+
+// TenantOf returns the tenant of the given user.
+func (r *ListMembershipsResponse) TenantOf(username string) string {
+	if name, ok := r.Tenants[username]; ok && name != "" {
+		return name
+	}
+
+	return tenant.Default
+}
+
+// ListSecretKeysRequest defines the request of Directory.ListSecretKeys.
+type ListSecretKeysRequest struct{}
+
+// SecretInfo defines a secret with what iam-authz-server needs to verify the
+// tokens signed with it.
+type SecretInfo struct {
+	SecretID  string `json:"secretID"`
+	Username  string `json:"username"`
+	SecretKey string `json:"secretKey"`
+	Expires   int64  `json:"expires"`
+
+	// Scopes restrict the requests the secret can authorize.
+	Scopes []scope.Scope `json:"scopes,omitempty"`
+
+	// PreviousKey is the key replaced by the last rotation, it is accepted
+	// until PreviousKeyExpires.
+	PreviousKey        string `json:"previousKey,omitempty"`
+	PreviousKeyExpires int64  `json:"previousKeyExpires,omitempty"`
+}
+
+// ListSecretKeysResponse defines the response of Directory.ListSecretKeys.
+type ListSecretKeysResponse struct {
+	Items []*SecretInfo `json:"items"`
+}
+
+// ReportSecretUsageRequest defines the request of Directory.ReportSecretUsage.
+type ReportSecretUsageRequest struct {
+	// LastUsed maps a secret id to the unix time the secret was last used.
+	LastUsed map[string]int64 `json:"lastUsed"`
+}
+
+// ReportSecretUsageResponse defines the response of Directory.ReportSecretUsage.
+type ReportSecretUsageResponse struct{}
+
+// DirectoryServer is the server API for Directory service.
+type DirectoryServer interface {
+	ListMemberships(context.Context, *ListMembershipsRequest) (*ListMembershipsResponse, error)
+	ListSecretKeys(context.Context, *ListSecretKeysRequest) (*ListSecretKeysResponse, error)
+	ReportSecretUsage(context.Context, *ReportSecretUsageRequest) (*ReportSecretUsageResponse, error)
+}
+
+// DirectoryClient is the client API for Directory service.
+type DirectoryClient interface {
+	ListMemberships(ctx context.Context, in *ListMembershipsRequest, opts ...grpc.CallOption) (*ListMembershipsResponse, error)
+	ListSecretKeys(ctx context.Context, in *ListSecretKeysRequest, opts ...grpc.CallOption) (*ListSecretKeysResponse, error)
+	ReportSecretUsage(
+		ctx context.Context,
+		in *ReportSecretUsageRequest,
+		opts ...grpc.CallOption,
+	) (*ReportSecretUsageResponse, error)
+}
+
+type directoryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewDirectoryClient creates a Directory client on the given connection.
+func NewDirectoryClient(cc grpc.ClientConnInterface) DirectoryClient {
+	return &directoryClient{cc}
+}
+
+func (c *directoryClient) ListMemberships(
+	ctx context.Context,
+	in *ListMembershipsRequest,
+	opts ...grpc.CallOption,
+) (*ListMembershipsResponse, error) {
+	out := new(ListMembershipsResponse)
+	if err := c.cc.Invoke(ctx, "/rpc.Directory/ListMemberships", in, out, callOptions(opts)...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *directoryClient) ListSecretKeys(
+	ctx context.Context,
+	in *ListSecretKeysRequest,
+	opts ...grpc.CallOption,
+) (*ListSecretKeysResponse, error) {
+	out := new(ListSecretKeysResponse)
+	if err := c.cc.Invoke(ctx, "/rpc.Directory/ListSecretKeys", in, out, callOptions(opts)...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *directoryClient) ReportSecretUsage(
+	ctx context.Context,
+	in *ReportSecretUsageRequest,
+	opts ...grpc.CallOption,
+) (*ReportSecretUsageResponse, error) {
+	out := new(ReportSecretUsageResponse)
+	if err := c.cc.Invoke(ctx, "/rpc.Directory/ReportSecretUsage", in, out, callOptions(opts)...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// RegisterDirectoryServer registers the Directory service on the given gRPC server.
+func RegisterDirectoryServer(s *grpc.Server, srv DirectoryServer) {
+	s.RegisterService(&directoryServiceDesc, srv)
+}
+
+func directoryListMembershipsHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	in := new(ListMembershipsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(DirectoryServer).ListMemberships(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Directory/ListMemberships",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DirectoryServer).ListMemberships(ctx, req.(*ListMembershipsRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func directoryListSecretKeysHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	in := new(ListSecretKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(DirectoryServer).ListSecretKeys(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Directory/ListSecretKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DirectoryServer).ListSecretKeys(ctx, req.(*ListSecretKeysRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+func directoryReportSecretUsageHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	in := new(ReportSecretUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(DirectoryServer).ReportSecretUsage(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Directory/ReportSecretUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DirectoryServer).ReportSecretUsage(ctx, req.(*ReportSecretUsageRequest))
+	}
+
+	return interceptor(ctx, in, info, handler)
+}
+
+var directoryServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rpc.Directory",
+	HandlerType: (*DirectoryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListMemberships",
+			Handler:    directoryListMembershipsHandler,
+		},
+		{
+			MethodName: "ListSecretKeys",
+			Handler:    directoryListSecretKeysHandler,
+		},
+		{
+			MethodName: "ReportSecretUsage",
+			Handler:    directoryReportSecretUsageHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "internal/pkg/rpc/directory.go",
+}
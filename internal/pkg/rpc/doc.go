@@ -0,0 +1,8 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package rpc defines the gRPC services between iam-apiserver and iam-authz-server
+// which are not part of github.com/marmotedu/api. The messages are plain go structs
+// encoded as JSON, so no generated protobuf code is needed.
+package rpc
@@ -0,0 +1,88 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package scope restricts the requests a secret can authorize to a set of
+// resources and actions, matched like the resources and actions of a policy.
+package scope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ory/ladon"
+)
+
+// Key defines the key in gin context which holds the scopes of the secret a
+// request is authenticated with.
+const Key = "scopes"
+
+// Scope allows the actions on the resources. Both are ladon patterns, e.g.
+// `resources:articles:<.*>` and `<create|update>`.
+type Scope struct {
+	Resources []string `json:"resources"`
+	Actions   []string `json:"actions"`
+}
+
+// Allows tells whether the scope covers the action on the resource.
+func (s Scope) Allows(resource, action string) bool {
+	policy := &ladon.DefaultPolicy{Resources: s.Resources, Actions: s.Actions}
+
+	if ok, err := ladon.DefaultMatcher.Matches(policy, s.Resources, resource); err != nil || !ok {
+		return false
+	}
+
+	ok, err := ladon.DefaultMatcher.Matches(policy, s.Actions, action)
+
+	return err == nil && ok
+}
+
+// Allowed tells whether any of the scopes covers the action on the resource.
+// A secret without scopes is not restricted.
+func Allowed(scopes []Scope, resource, action string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+
+	for _, s := range scopes {
+		if s.Allows(resource, action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks that every scope has resources and actions, and that all
+// their patterns compile.
+func Validate(scopes []Scope) error {
+	for i, s := range scopes {
+		if len(s.Resources) == 0 || len(s.Actions) == 0 {
+			return fmt.Errorf("scopes[%d] must have at least one resource and one action", i)
+		}
+
+		for _, pattern := range append(append([]string{}, s.Resources...), s.Actions...) {
+			policy := &ladon.DefaultPolicy{}
+			if _, err := ladon.DefaultMatcher.Matches(policy, []string{pattern}, ""); err != nil {
+				return fmt.Errorf("scopes[%d]: invalid pattern `%s`: %w", i, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FromContext returns the scopes carried by ctx. The second return value is
+// false when the request is not restricted to any scope.
+func FromContext(ctx context.Context) ([]Scope, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	scopes, ok := ctx.Value(Key).([]Scope)
+	if !ok || len(scopes) == 0 {
+		return nil, false
+	}
+
+	return scopes, true
+}
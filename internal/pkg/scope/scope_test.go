@@ -0,0 +1,33 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowed(t *testing.T) {
+	scopes := []Scope{
+		{Resources: []string{"resources:articles:<.*>"}, Actions: []string{"<get|list>"}},
+		{Resources: []string{"resources:orders:ladon"}, Actions: []string{"update"}},
+	}
+
+	assert.True(t, Allowed(scopes, "resources:articles:ladon", "get"))
+	assert.True(t, Allowed(scopes, "resources:orders:ladon", "update"))
+	assert.False(t, Allowed(scopes, "resources:articles:ladon", "delete"))
+	assert.False(t, Allowed(scopes, "resources:orders:other", "update"))
+
+	// secrets without scopes are not restricted.
+	assert.True(t, Allowed(nil, "resources:orders:other", "delete"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(nil))
+	assert.Nil(t, Validate([]Scope{{Resources: []string{"resources:<.*>"}, Actions: []string{"get"}}}))
+	assert.NotNil(t, Validate([]Scope{{Resources: []string{"resources:<[>"}, Actions: []string{"get"}}}))
+	assert.NotNil(t, Validate([]Scope{{Resources: []string{"resources:articles"}}}))
+}
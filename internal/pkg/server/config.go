@@ -74,6 +74,8 @@ type JwtInfo struct {
 	Timeout time.Duration
 	// defaults to zero
 	MaxRefresh time.Duration
+	// defaults to "HS256"
+	SigningAlgorithm string
 }
 
 // NewConfig returns a Config struct with the default values.
@@ -85,9 +87,10 @@ func NewConfig() *Config {
 		EnableProfiling: true,
 		EnableMetrics:   true,
 		Jwt: &JwtInfo{
-			Realm:      "iam jwt",
-			Timeout:    1 * time.Hour,
-			MaxRefresh: 1 * time.Hour,
+			Realm:            "iam jwt",
+			Timeout:          1 * time.Hour,
+			MaxRefresh:       1 * time.Hour,
+			SigningAlgorithm: "HS256",
 		},
 	}
 }
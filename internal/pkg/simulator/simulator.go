@@ -0,0 +1,140 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package simulator evaluates a suite of access requests against a candidate
+// policy set, using the same ladon warden as iam-authz-server but without
+// touching its policy cache.
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/ory/ladon"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/authzserver/authorization"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/condition"
+)
+
+const (
+	// EffectAllow is the expected effect of a request which must be granted.
+	EffectAllow = "allow"
+
+	// EffectDeny is the expected effect of a request which must be rejected.
+	EffectDeny = "deny"
+)
+
+// Case defines an access request together with the effect it is expected to get.
+type Case struct {
+	Name    string        `json:"name"`
+	Request ladon.Request `json:"request"`
+	Expect  string        `json:"expect"`
+}
+
+// Suite defines a candidate policy set and the cases to evaluate against it.
+type Suite struct {
+	Policies []*ladon.DefaultPolicy `json:"policies"`
+	Cases    []Case                 `json:"cases"`
+}
+
+// Result defines the outcome of a single case.
+type Result struct {
+	Name   string `json:"name"`
+	Expect string `json:"expect"`
+	Actual string `json:"actual"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Report defines the outcome of a suite, only the mismatched cases are listed.
+type Report struct {
+	Total      int      `json:"total"`
+	Passed     int      `json:"passed"`
+	Mismatches []Result `json:"mismatches"`
+}
+
+// Validate checks the policies and the cases of the suite.
+func (s *Suite) Validate() error {
+	if len(s.Cases) == 0 {
+		return fmt.Errorf("no cases to evaluate")
+	}
+
+	for _, policy := range s.Policies {
+		if err := condition.Validate(policy.Conditions); err != nil {
+			return fmt.Errorf("policy %s: %w", policy.ID, err)
+		}
+	}
+
+	for i, c := range s.Cases {
+		if c.Expect != EffectAllow && c.Expect != EffectDeny {
+			return fmt.Errorf("case %d: expect must be '%s' or '%s'", i, EffectAllow, EffectDeny)
+		}
+	}
+
+	return nil
+}
+
+// Run evaluates all the cases of the suite and reports the mismatches.
+func (s *Suite) Run() *Report {
+	authorizer := authorization.NewAuthorizer(&staticPolicies{policies: s.Policies})
+	report := &Report{Total: len(s.Cases), Mismatches: []Result{}}
+
+	for i := range s.Cases {
+		c := s.Cases[i]
+		request := c.Request
+		if request.Context == nil {
+			request.Context = ladon.Context{}
+		}
+
+		rsp := authorizer.Authorize(&request)
+		actual := EffectDeny
+		if rsp.Allowed {
+			actual = EffectAllow
+		}
+
+		if actual == c.Expect {
+			report.Passed++
+
+			continue
+		}
+
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("case-%d", i)
+		}
+
+		report.Mismatches = append(report.Mismatches, Result{
+			Name:   name,
+			Expect: c.Expect,
+			Actual: actual,
+			Reason: rsp.Reason,
+		})
+	}
+
+	return report
+}
+
+// staticPolicies implements authorization.AuthorizationInterface on top of a
+// fixed policy set. Every request sees the whole set and nothing is audited.
+type staticPolicies struct {
+	policies []*ladon.DefaultPolicy
+}
+
+func (s *staticPolicies) Create(*ladon.DefaultPolicy) error { return nil }
+
+func (s *staticPolicies) Update(*ladon.DefaultPolicy) error { return nil }
+
+func (s *staticPolicies) Delete(string) error { return nil }
+
+func (s *staticPolicies) DeleteCollection([]string) error { return nil }
+
+func (s *staticPolicies) Get(string) (*ladon.DefaultPolicy, error) {
+	return &ladon.DefaultPolicy{}, nil
+}
+
+func (s *staticPolicies) List(string) ([]*ladon.DefaultPolicy, error) {
+	return s.policies, nil
+}
+
+func (s *staticPolicies) LogRejectedAccessRequest(*ladon.Request, ladon.Policies, ladon.Policies) {}
+
+func (s *staticPolicies) LogGrantedAccessRequest(*ladon.Request, ladon.Policies, ladon.Policies) {}
@@ -0,0 +1,76 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+const suite = `
+policies:
+- id: editors
+  subjects: ["users:<.*>"]
+  resources: ["resources:articles:<.*>"]
+  actions: ["<read|update>"]
+  effect: allow
+  conditions:
+    remoteIP:
+      type: CIDRListCondition
+      options:
+        cidrs: ["10.0.0.0/8"]
+- id: no-drafts
+  subjects: ["users:<.*>"]
+  resources: ["resources:articles:drafts"]
+  actions: ["<.*>"]
+  effect: deny
+cases:
+- name: update from office
+  expect: allow
+  request:
+    subject: users:maria
+    resource: resources:articles:ladon
+    action: update
+    context:
+      remoteIP: 10.1.2.3
+- name: update from home
+  expect: deny
+  request:
+    subject: users:maria
+    resource: resources:articles:ladon
+    action: update
+    context:
+      remoteIP: 192.168.1.1
+- name: read drafts
+  expect: allow
+  request:
+    subject: users:maria
+    resource: resources:articles:drafts
+    action: read
+    context:
+      remoteIP: 10.1.2.3
+`
+
+func TestRun(t *testing.T) {
+	var s Suite
+	assert.Nil(t, yaml.Unmarshal([]byte(suite), &s))
+	assert.Nil(t, s.Validate())
+
+	report := s.Run()
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.Passed)
+	assert.Len(t, report.Mismatches, 1)
+	assert.Equal(t, "read drafts", report.Mismatches[0].Name)
+	assert.Equal(t, EffectDeny, report.Mismatches[0].Actual)
+	assert.NotEmpty(t, report.Mismatches[0].Reason)
+}
+
+func TestValidate(t *testing.T) {
+	s := Suite{Cases: []Case{{Expect: "maybe"}}}
+	assert.NotNil(t, s.Validate())
+	assert.NotNil(t, (&Suite{}).Validate())
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package tenant defines how the tenant (organization) of a request is carried
+// between the authentication middlewares and the storage layer.
+package tenant
+
+import "context"
+
+const (
+	// Key defines the key in gin context which represents the tenant of the request.
+	// It is a plain string so that *gin.Context.Value can resolve it.
+	Key = "tenant"
+
+	// Header defines the http header used to choose the tenant of an anonymous request,
+	// e.g. when a customer signs up on a storefront.
+	Header = "X-Tenant"
+
+	// Default is the tenant of the users which are not member of any tenant.
+	Default = "default"
+)
+
+// FromContext returns the tenant carried by ctx. The second return value is false
+// when ctx carries no tenant, which is the case for the internal callers which
+// must see the data of all the tenants, e.g. the grpc cache service.
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	name, ok := ctx.Value(Key).(string)
+	if !ok || name == "" {
+		return "", false
+	}
+
+	return name, true
+}
+
+// Unscoped returns a copy of ctx which carries no tenant. It is used by the
+// platform administrators to manage the users of all the tenants.
+func Unscoped(ctx context.Context) context.Context {
+	// nolint: staticcheck // must be a plain string to be shared with gin.Context.
+	return context.WithValue(ctx, Key, "")
+}
+
+// Qualify returns the tenant qualified form of name, used to partition the data
+// of different tenants which share one key space.
+func Qualify(tenant, name string) string {
+	if tenant == "" {
+		tenant = Default
+	}
+
+	return tenant + "/" + name
+}
+
+// NewContext returns a copy of ctx which carries the tenant, it is used by the
+// background jobs which outlive the request of the tenant.
+func NewContext(ctx context.Context, name string) context.Context {
+	// nolint: staticcheck // must be a plain string to be shared with gin.Context.
+	return context.WithValue(ctx, Key, name)
+}
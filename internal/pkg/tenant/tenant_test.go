@@ -0,0 +1,33 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	c := &gin.Context{}
+	c.Set(Key, "shop")
+
+	name, ok := FromContext(c)
+	assert.True(t, ok)
+	assert.Equal(t, "shop", name)
+
+	_, ok = FromContext(Unscoped(c))
+	assert.False(t, ok)
+}
+
+func TestQualify(t *testing.T) {
+	assert.Equal(t, "shop/maria", Qualify("shop", "maria"))
+	assert.Equal(t, "default/maria", Qualify("", "maria"))
+}
@@ -1,6 +1,8 @@
 package options
 
 import (
+	"time"
+
 	cliflag "github.com/marmotedu/component-base/pkg/cli/flag"
 	"github.com/marmotedu/component-base/pkg/json"
 	"github.com/marmotedu/log"
@@ -17,10 +19,23 @@ type TaskOptions struct {
 	MaxInactiveDays int `json:"max-inactive-days" mapstructure:"max-inactive-days"`
 }
 
+// JwksOptions defines options for jwks watcher.
+type JwksOptions struct {
+	RotationPeriod time.Duration `json:"rotation-period" mapstructure:"rotation-period"`
+	Retention      time.Duration `json:"retention"       mapstructure:"retention"`
+}
+
+// AuditOptions defines options for audit watcher.
+type AuditOptions struct {
+	MaxReserveDays int `json:"max-reserve-days" mapstructure:"max-reserve-days"`
+}
+
 // WatcherOptions defines options for watchers.
 type WatcherOptions struct {
 	Clean CleanOptions `json:"clean" mapstructure:"clean"`
 	Task  TaskOptions  `json:"task"  mapstructure:"task"`
+	Jwks  JwksOptions  `json:"jwks"  mapstructure:"jwks"`
+	Audit AuditOptions `json:"audit" mapstructure:"audit"`
 }
 
 // Options runs a pumpserver.
@@ -47,6 +62,13 @@ func NewOptions() *Options {
 			Task: TaskOptions{
 				MaxInactiveDays: 0, // not expire by default
 			},
+			Jwks: JwksOptions{
+				RotationPeriod: 30 * 24 * time.Hour,
+				Retention:      48 * time.Hour,
+			},
+			Audit: AuditOptions{
+				MaxReserveDays: 365, // default one year
+			},
 		},
 		Log: log.NewOptions(),
 	}
@@ -80,6 +102,25 @@ func (o *Options) Flags() (fss cliflag.NamedFlagSets) {
 		o.WatcherOptions.Task.MaxInactiveDays,
 		"Maximum user inactivity time. Otherwise the account will be disabled.",
 	)
+	fs.DurationVar(
+		&o.WatcherOptions.Jwks.RotationPeriod,
+		"watcher.jwks.rotation-period",
+		o.WatcherOptions.Jwks.RotationPeriod,
+		"How often the jwt signing key of iam-apiserver is rotated, 0 means never.",
+	)
+	fs.DurationVar(
+		&o.WatcherOptions.Jwks.Retention,
+		"watcher.jwks.retention",
+		o.WatcherOptions.Jwks.Retention,
+		"How long a rotated jwt signing key keeps verifying tokens, "+
+			"must be longer than both jwt.timeout and jwt.max-refresh of iam-apiserver.",
+	)
+	fs.IntVar(
+		&o.WatcherOptions.Audit.MaxReserveDays,
+		"watcher.audit.max-reserve-days",
+		o.WatcherOptions.Audit.MaxReserveDays,
+		"Audit log of iam-apiserver maximum retention days, 0 means forever.",
+	)
 
 	return fss
 }
@@ -1,6 +1,7 @@
 package watcher
 
 import (
+	"context"
 	"time"
 
 	"github.com/marmotedu/log"
@@ -11,6 +12,7 @@ import (
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/options"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/shutdown"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/shutdown/shutdownmanagers/posixsignal"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/storage"
 )
 
 type watcherServer struct {
@@ -51,11 +53,42 @@ func (s *watcherServer) PrepareRun() preparedWatcherServer {
 		return mysqlStore.Close()
 	}))
 
+	s.initRedisStore()
+
 	s.cron = newWatchJob(s.redisOptions, s.watcherOptions).addWatchers()
 
 	return preparedWatcherServer{s}
 }
 
+// initRedisStore connects the shared redis store, used to revoke the tokens of disabled users.
+func (s *watcherServer) initRedisStore() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
+		cancel()
+
+		return nil
+	}))
+
+	config := &storage.Config{
+		Host:                  s.redisOptions.Host,
+		Port:                  s.redisOptions.Port,
+		Addrs:                 s.redisOptions.Addrs,
+		MasterName:            s.redisOptions.MasterName,
+		Username:              s.redisOptions.Username,
+		Password:              s.redisOptions.Password,
+		Database:              s.redisOptions.Database,
+		MaxIdle:               s.redisOptions.MaxIdle,
+		MaxActive:             s.redisOptions.MaxActive,
+		Timeout:               s.redisOptions.Timeout,
+		EnableCluster:         s.redisOptions.EnableCluster,
+		UseSSL:                s.redisOptions.UseSSL,
+		SSLInsecureSkipVerify: s.redisOptions.SSLInsecureSkipVerify,
+	}
+
+	// try to connect to redis
+	go storage.ConnectToRedis(ctx, config)
+}
+
 func (s preparedWatcherServer) Run() error {
 	stopCh := make(chan struct{})
 	s.gs.AddShutdownCallback(shutdown.ShutdownFunc(func(string) error {
@@ -6,6 +6,8 @@ package all
 
 // nolint: golint
 import (
+	_ "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher/audit"
 	_ "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher/clean"
+	_ "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher/jwks"
 	_ "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher/task"
 )
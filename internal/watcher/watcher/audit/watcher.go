@@ -0,0 +1,80 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+
+	"github.com/go-redsync/redsync/v4"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store/mysql"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/options"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+type auditWatcher struct {
+	ctx            context.Context
+	mutex          *redsync.Mutex
+	maxReserveDays int
+}
+
+// Run runs the watcher job.
+func (aw *auditWatcher) Run() {
+	// the audit log is kept forever.
+	if aw.maxReserveDays <= 0 {
+		return
+	}
+
+	if err := aw.mutex.Lock(); err != nil {
+		log.L(aw.ctx).Info("auditWatcher already run.")
+
+		return
+	}
+
+	defer func() {
+		if _, err := aw.mutex.Unlock(); err != nil {
+			log.L(aw.ctx).Errorf("could not release auditWatcher lock. err: %v", err)
+
+			return
+		}
+	}()
+
+	db, _ := mysql.GetMySQLFactoryOr(nil)
+
+	rowsAffected, err := db.AuditLogs().ClearOutdated(aw.ctx, aw.maxReserveDays)
+	if err != nil {
+		log.L(aw.ctx).Errorw("clean data from audit_log failed", "error", err)
+
+		return
+	}
+
+	log.L(aw.ctx).Debugf("clean data from audit_log succ, %d rows affected", rowsAffected)
+}
+
+// Spec is parsed using the time zone of audit Cron instance as the default.
+func (aw *auditWatcher) Spec() string {
+	return "@every 1d"
+}
+
+// Init initializes the watcher for later execution.
+func (aw *auditWatcher) Init(ctx context.Context, rs *redsync.Mutex, config interface{}) error {
+	cfg, ok := config.(*options.WatcherOptions)
+	if !ok {
+		return watcher.ErrConfigUnavailable
+	}
+
+	*aw = auditWatcher{
+		ctx:            ctx,
+		mutex:          rs,
+		maxReserveDays: cfg.Audit.MaxReserveDays,
+	}
+
+	return nil
+}
+
+func init() {
+	watcher.Register("audit", &auditWatcher{})
+}
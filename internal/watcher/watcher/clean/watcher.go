@@ -6,6 +6,7 @@ package clean
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-redsync/redsync/v4"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store/mysql"
@@ -47,6 +48,33 @@ func (cw *cleanWatcher) Run() {
 	}
 
 	log.L(cw.ctx).Debugf("clean data from policy_audit succ, %d rows affected", rowsAffected)
+
+	rowsAffected, err = db.Sessions().DeleteExpired(cw.ctx, time.Now())
+	if err != nil {
+		log.L(cw.ctx).Errorw("clean expired sessions failed", "error", err)
+
+		return
+	}
+
+	log.L(cw.ctx).Debugf("clean expired sessions succ, %d rows affected", rowsAffected)
+
+	rowsAffected, err = db.PasswordResets().DeleteExpired(cw.ctx, time.Now())
+	if err != nil {
+		log.L(cw.ctx).Errorw("clean expired password resets failed", "error", err)
+
+		return
+	}
+
+	log.L(cw.ctx).Debugf("clean expired password resets succ, %d rows affected", rowsAffected)
+
+	rowsAffected, err = db.EmailVerifications().DeleteExpired(cw.ctx, time.Now())
+	if err != nil {
+		log.L(cw.ctx).Errorw("clean expired email verifications failed", "error", err)
+
+		return
+	}
+
+	log.L(cw.ctx).Debugf("clean expired email verifications succ, %d rows affected", rowsAffected)
 }
 
 // Spec is parsed using the time zone of clean Cron instance as the default.
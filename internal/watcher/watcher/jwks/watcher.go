@@ -0,0 +1,100 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jwks
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redsync/redsync/v4"
+	srvv1 "github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/service/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store/mysql"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/options"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/log"
+)
+
+type jwksWatcher struct {
+	ctx            context.Context
+	mutex          *redsync.Mutex
+	rotationPeriod time.Duration
+	retention      time.Duration
+}
+
+// Run runs the watcher job.
+func (jw *jwksWatcher) Run() {
+	if err := jw.mutex.Lock(); err != nil {
+		log.L(jw.ctx).Info("jwksWatcher already run.")
+
+		return
+	}
+	defer func() {
+		if _, err := jw.mutex.Unlock(); err != nil {
+			log.L(jw.ctx).Errorf("could not release jwksWatcher lock. err: %v", err)
+
+			return
+		}
+	}()
+
+	db, _ := mysql.GetMySQLFactoryOr(nil)
+	srv := srvv1.NewService(db)
+
+	rowsAffected, err := srv.SigningKeys().DeleteExpired(jw.ctx)
+	if err != nil {
+		log.L(jw.ctx).Errorw("delete expired jwt signing keys failed", "error", err)
+
+		return
+	}
+
+	log.L(jw.ctx).Debugf("delete expired jwt signing keys succ, %d rows affected", rowsAffected)
+
+	// if rotationPeriod equal to 0, means never rotate
+	if jw.rotationPeriod == 0 {
+		return
+	}
+
+	active, err := srv.SigningKeys().Active(jw.ctx)
+	if err != nil {
+		log.L(jw.ctx).Errorw("get active jwt signing key failed", "error", err)
+
+		return
+	}
+
+	// no active key means iam-apiserver signs tokens with HS256.
+	if active == nil || time.Since(active.CreatedAt) < jw.rotationPeriod {
+		return
+	}
+
+	if err := srv.SigningKeys().Rotate(jw.ctx, active.Algorithm, jw.retention); err != nil {
+		log.L(jw.ctx).Errorw("rotate jwt signing key failed", "error", err)
+	}
+}
+
+// Spec is parsed using the time zone of jwks Cron instance as the default.
+func (jw *jwksWatcher) Spec() string {
+	return "@every 1h"
+}
+
+// Init initializes the watcher for later execution.
+func (jw *jwksWatcher) Init(ctx context.Context, rs *redsync.Mutex, config interface{}) error {
+	cfg, ok := config.(*options.WatcherOptions)
+	if !ok {
+		return watcher.ErrConfigUnavailable
+	}
+
+	*jw = jwksWatcher{
+		ctx:            ctx,
+		mutex:          rs,
+		rotationPeriod: cfg.Jwks.RotationPeriod,
+		retention:      cfg.Jwks.Retention,
+	}
+
+	return nil
+}
+
+func init() {
+	watcher.Register("jwks", &jwksWatcher{})
+}
@@ -6,11 +6,14 @@ package task
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-redsync/redsync/v4"
 	metav1 "github.com/marmotedu/component-base/pkg/meta/v1"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/iam/v1/model"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/apiserver/store/mysql"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/pkg/revocation"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/options"
 	"github.com/skeleton1231/go-iam-ecommerce-microservice/internal/watcher/watcher"
 
@@ -53,11 +56,30 @@ func (tw *taskWatcher) Run() {
 			continue
 		}
 
+		// only the active users are disabled, the others can not log in anyway.
+		if user.Status != model.UserStatusActive {
+			continue
+		}
+
 		if time.Since(user.LoginedAt) > time.Duration(tw.maxInactiveDays)*(24*time.Hour) {
 			log.L(tw.ctx).Infof("user %s not active for %d days, disable his account", user.Name, tw.maxInactiveDays)
 
-			user.Status = 0
-			_ = db.Users().Update(tw.ctx, user, metav1.UpdateOptions{})
+			change := &model.UserStatusChange{
+				Username:  user.Name,
+				Status:    model.UserStatusDisabled,
+				Reason:    fmt.Sprintf("inactive for %d days", tw.maxInactiveDays),
+				CreatedAt: time.Now(),
+			}
+			if err := db.UserStatuses().Change(tw.ctx, change, model.UserStatusActive); err != nil {
+				log.L(tw.ctx).Errorf("disable user %s failed: %s", user.Name, err.Error())
+
+				continue
+			}
+
+			// the tokens issued before the account was disabled must not be accepted anymore.
+			if err := revocation.Client().RevokeUser(user.Name, time.Now()); err != nil {
+				log.L(tw.ctx).Errorf("revoke tokens of user %s failed: %s", user.Name, err.Error())
+			}
 		}
 	}
 }
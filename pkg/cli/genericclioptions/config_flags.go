@@ -20,6 +20,7 @@ import (
 // Defines flag for iamctl.
 const (
 	FlagIAMConfig     = "iamconfig"
+	FlagContext       = "context"
 	FlagBearerToken   = "user.token"
 	FlagUsername      = "user.username"
 	FlagPassword      = "user.password"
@@ -52,6 +53,7 @@ var _ RESTClientGetter = &ConfigFlags{}
 // for obtaining a REST client config.
 type ConfigFlags struct {
 	IAMConfig *string
+	Context   *string
 
 	BearerToken *string
 	Username    *string
@@ -70,6 +72,9 @@ type ConfigFlags struct {
 	MaxRetries    *int
 	RetryInterval *time.Duration
 
+	// flags are the flags added by AddFlags, the ones set on the command line
+	// take precedence over the context.
+	flags        *pflag.FlagSet
 	clientConfig clientcmd.ClientConfig
 	lock         sync.Mutex
 	// If set to true, will use persistent client config and
@@ -103,9 +108,143 @@ func (f *ConfigFlags) toRawIAMConfigLoader() clientcmd.ClientConfig {
 		panic(err)
 	}
 
+	context, err := CurrentContext()
+	if err != nil {
+		return errClientConfig{err}
+	}
+
+	if context != nil {
+		context.apply(config)
+
+		if err := f.applyChangedFlags(config); err != nil {
+			return errClientConfig{err}
+		}
+	}
+
+	name := DefaultContextName
+	if context != nil {
+		name = context.Name
+	}
+
+	if err := useCachedToken(name, config); err != nil {
+		return errClientConfig{err}
+	}
+
 	return clientcmd.NewClientConfigFromConfig(config)
 }
 
+// applyChangedFlags sets the values of the flags given on the command line,
+// which take precedence over the context.
+func (f *ConfigFlags) applyChangedFlags(config *clientcmd.Config) error {
+	if f.flags == nil {
+		return nil
+	}
+
+	changed := viper.New()
+	// the parsed flags are marked in the flag set of the command run, not in
+	// this one, only the flags themselves are shared.
+	f.flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			changed.Set(flag.Name, flag.Value.String())
+		}
+	})
+
+	return changed.Unmarshal(config)
+}
+
+// useCachedToken authenticates with the token cached by iamctl login for the
+// context, the token is refreshed first when it is about to expire. The other
+// credentials are used when there is no valid token.
+func useCachedToken(context string, config *clientcmd.Config) error {
+	if config.AuthInfo.Token != "" {
+		return nil
+	}
+
+	token, err := LoadToken(context)
+	if err != nil || token == nil || token.Server != config.Server.Address {
+		return err
+	}
+
+	if token.Expired() {
+		if token, err = refreshToken(context, config); err != nil {
+			if hasCredentials(config.AuthInfo) {
+				return nil
+			}
+
+			return fmt.Errorf("%w, run 'iamctl login' again", err)
+		}
+	}
+
+	config.AuthInfo.Token = token.Token
+
+	return nil
+}
+
+// refreshToken refreshes the cached token of the context. The cache is locked
+// and read again first, since another iamctl may have refreshed it already,
+// and a refresh token can only be used once.
+func refreshToken(context string, config *clientcmd.Config) (*Token, error) {
+	unlock, err := lockToken(context)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	token, err := LoadToken(context)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil || token.Server != config.Server.Address {
+		return nil, fmt.Errorf("the login of context %s has been removed", context)
+	}
+
+	if !token.Expired() {
+		return token, nil
+	}
+
+	if !token.Refreshable() {
+		return nil, fmt.Errorf("the login of context %s has expired", context)
+	}
+
+	restConfig, err := clientcmd.NewClientConfigFromConfig(config).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewTokenClient(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed, err := client.Refresh(token)
+	if err != nil {
+		return nil, fmt.Errorf("refresh the token of context %s: %w", context, err)
+	}
+
+	refreshed.Server = token.Server
+	if refreshed.RefreshToken == "" && token.RefreshToken != "" {
+		refreshed.RefreshToken, refreshed.RefreshExpire = token.RefreshToken, token.RefreshExpire
+	}
+
+	return refreshed, SaveToken(context, refreshed)
+}
+
+func hasCredentials(user *clientcmd.AuthInfo) bool {
+	return user.Username != "" || user.SecretID != "" || user.ClientCertificate != "" ||
+		user.ClientCertificateData != ""
+}
+
+// errClientConfig is the client config of an invalid iamconfig.
+type errClientConfig struct {
+	err error
+}
+
+// ClientConfig implements clientcmd.ClientConfig.
+func (c errClientConfig) ClientConfig() (*rest.Config, error) {
+	return nil, c.err
+}
+
 // toRawIAMPersistentConfigLoader binds config flag values to config overrides
 // Returns a persistent clientConfig for propagation.
 func (f *ConfigFlags) toRawIAMPersistentConfigLoader() clientcmd.ClientConfig {
@@ -121,11 +260,18 @@ func (f *ConfigFlags) toRawIAMPersistentConfigLoader() clientcmd.ClientConfig {
 
 // AddFlags binds client configuration flags to a given flagset.
 func (f *ConfigFlags) AddFlags(flags *pflag.FlagSet) {
+	f.flags = flags
+
 	if f.IAMConfig != nil {
 		flags.StringVar(f.IAMConfig, FlagIAMConfig, *f.IAMConfig,
 			fmt.Sprintf("Path to the %s file to use for CLI requests", FlagIAMConfig))
 	}
 
+	if f.Context != nil {
+		flags.StringVar(f.Context, FlagContext, *f.Context,
+			fmt.Sprintf("The name of the %s context to use", FlagIAMConfig))
+	}
+
 	if f.BearerToken != nil {
 		flags.StringVar(
 			f.BearerToken,
@@ -216,6 +362,7 @@ func (f *ConfigFlags) WithDeprecatedSecretFlag() *ConfigFlags {
 func NewConfigFlags(usePersistentConfig bool) *ConfigFlags {
 	return &ConfigFlags{
 		IAMConfig: pointer.ToString(""),
+		Context:   pointer.ToString(""),
 
 		BearerToken:   pointer.ToString(""),
 		Insecure:      pointer.ToBool(false),
@@ -0,0 +1,266 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package genericclioptions
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/marmotedu/marmotedu-sdk-go/tools/clientcmd"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// The keys of the contexts in the iamconfig file.
+const (
+	keyCurrentContext = "current-context"
+	keyContexts       = "contexts"
+)
+
+// DefaultContextName names the server and the credentials of the iamconfig
+// file when no context is used, e.g. to cache the token of iamctl login.
+const DefaultContextName = "default"
+
+// contextNamePattern restricts the names of the contexts to the ones which can
+// be used as file names, e.g. of the token cache.
+var contextNamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateContextName checks the name of a new context.
+func ValidateContextName(name string) error {
+	if !contextNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid context name %q: up to 63 letters, digits, '.', '_' or '-', "+
+			"starting and ending with a letter or a digit", name)
+	}
+
+	return nil
+}
+
+// defaultIAMConfigName is the name of the iamconfig file created by iamctl
+// when none is found.
+const defaultIAMConfigName = "iamctl.yaml"
+
+// Context is a named server with its credentials, such as dev, staging or prod.
+// Its fields replace the ones of the top level server and user sections.
+type Context struct {
+	Name   string        `yaml:"name"             mapstructure:"name"`
+	Server ContextServer `yaml:"server,omitempty" mapstructure:"server"`
+	User   ContextUser   `yaml:"user,omitempty"   mapstructure:"user"`
+	// Output is the default output format of the get and list commands.
+	Output string `yaml:"output,omitempty" mapstructure:"output"`
+}
+
+// ContextServer tells how to reach the iam-apiserver of a context.
+type ContextServer struct {
+	Address                  string        `yaml:"address,omitempty"                    mapstructure:"address"`
+	CertificateAuthority     string        `yaml:"certificate-authority,omitempty"      mapstructure:"certificate-authority"`
+	CertificateAuthorityData string        `yaml:"certificate-authority-data,omitempty" mapstructure:"certificate-authority-data"`
+	InsecureSkipTLSVerify    bool          `yaml:"insecure-skip-tls-verify,omitempty"   mapstructure:"insecure-skip-tls-verify"`
+	TLSServerName            string        `yaml:"tls-server-name,omitempty"            mapstructure:"tls-server-name"`
+	Timeout                  time.Duration `yaml:"timeout,omitempty"                    mapstructure:"timeout"`
+}
+
+// ContextUser holds the credentials of a context.
+type ContextUser struct {
+	Token             string `yaml:"token,omitempty"              mapstructure:"token"`
+	Username          string `yaml:"username,omitempty"           mapstructure:"username"`
+	Password          string `yaml:"password,omitempty"           mapstructure:"password"`
+	SecretID          string `yaml:"secret-id,omitempty"          mapstructure:"secret-id"`
+	SecretKey         string `yaml:"secret-key,omitempty"         mapstructure:"secret-key"`
+	ClientCertificate string `yaml:"client-certificate,omitempty" mapstructure:"client-certificate"`
+	ClientKey         string `yaml:"client-key,omitempty"         mapstructure:"client-key"`
+}
+
+// apply replaces the server and the credentials of config by the ones of the
+// context. The settings of another server, or other credentials, are dropped
+// rather than mixed with the ones of the context.
+func (c *Context) apply(config *clientcmd.Config) {
+	if c.Server.Address != "" {
+		config.Server = &clientcmd.Server{
+			Address:                  c.Server.Address,
+			CertificateAuthority:     c.Server.CertificateAuthority,
+			CertificateAuthorityData: c.Server.CertificateAuthorityData,
+			InsecureSkipTLSVerify:    c.Server.InsecureSkipTLSVerify,
+			TLSServerName:            c.Server.TLSServerName,
+			Timeout:                  config.Server.Timeout,
+			MaxRetries:               config.Server.MaxRetries,
+			RetryInterval:            config.Server.RetryInterval,
+		}
+	}
+
+	if c.Server.Timeout != 0 {
+		config.Server.Timeout = c.Server.Timeout
+	}
+
+	if c.User != (ContextUser{}) {
+		config.AuthInfo = &clientcmd.AuthInfo{
+			Token:             c.User.Token,
+			Username:          c.User.Username,
+			Password:          c.User.Password,
+			SecretID:          c.User.SecretID,
+			SecretKey:         c.User.SecretKey,
+			ClientCertificate: c.User.ClientCertificate,
+			ClientKey:         c.User.ClientKey,
+		}
+	}
+}
+
+// CurrentContextName returns the name of the context chosen by --context, or
+// else by the current-context of the iamconfig file; it is empty when the
+// iamconfig file has no contexts.
+func CurrentContextName() string {
+	if name := viper.GetString(FlagContext); name != "" {
+		return name
+	}
+
+	return viper.GetString(keyCurrentContext)
+}
+
+// CurrentContext returns the context in use, nil when there is none.
+func CurrentContext() (*Context, error) {
+	name := CurrentContextName()
+	if name == "" {
+		return nil, nil
+	}
+
+	var contexts []*Context
+	if err := viper.UnmarshalKey(keyContexts, &contexts); err != nil {
+		return nil, fmt.Errorf("invalid contexts in the iamconfig file: %w", err)
+	}
+
+	for _, c := range contexts {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("context %q does not exist", name)
+}
+
+// CurrentOutputFormat returns the default output format of the context in use.
+func CurrentOutputFormat() string {
+	c, err := CurrentContext()
+	if err != nil || c == nil {
+		return ""
+	}
+
+	return c.Output
+}
+
+// IAMConfigFilePath returns the path of the iamconfig file: the one loaded,
+// else the one given by --iamconfig, else the default one in $HOME/.iam.
+func IAMConfigFilePath() string {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+
+	if path := viper.GetString(FlagIAMConfig); path != "" {
+		return path
+	}
+
+	return filepath.Join(clientcmd.RecommendedConfigDir, defaultIAMConfigName)
+}
+
+// IAMConfigFile is an iamconfig file edited by the iamctl config commands.
+// Only the contexts are changed, the other settings are written back as-is.
+type IAMConfigFile struct {
+	Path           string
+	CurrentContext string
+	Contexts       []*Context
+
+	raw map[string]interface{}
+}
+
+// LoadIAMConfigFile reads the iamconfig file at path, a missing file is an
+// empty one.
+func LoadIAMConfigFile(path string) (*IAMConfigFile, error) {
+	file := &IAMConfigFile{Path: path, raw: map[string]interface{}{}}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+
+		return nil, err
+	}
+
+	var content struct {
+		CurrentContext string     `yaml:"current-context"`
+		Contexts       []*Context `yaml:"contexts"`
+	}
+
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &file.raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if file.raw == nil {
+		file.raw = map[string]interface{}{}
+	}
+
+	file.CurrentContext, file.Contexts = content.CurrentContext, content.Contexts
+
+	return file, nil
+}
+
+// Context returns the context with the name, nil when it does not exist.
+func (f *IAMConfigFile) Context(name string) *Context {
+	for _, c := range f.Contexts {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// SetContext adds the context, or replaces the one with the same name.
+func (f *IAMConfigFile) SetContext(context *Context) {
+	for i, c := range f.Contexts {
+		if c.Name == context.Name {
+			f.Contexts[i] = context
+
+			return
+		}
+	}
+
+	f.Contexts = append(f.Contexts, context)
+}
+
+// Save writes the file, it holds credentials so it is only readable by its owner.
+func (f *IAMConfigFile) Save() error {
+	f.raw[keyCurrentContext] = f.CurrentContext
+	if f.CurrentContext == "" {
+		delete(f.raw, keyCurrentContext)
+	}
+
+	f.raw[keyContexts] = f.Contexts
+	if len(f.Contexts) == 0 {
+		delete(f.raw, keyContexts)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(f.raw); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(f.Path, buf.Bytes(), 0o600)
+}
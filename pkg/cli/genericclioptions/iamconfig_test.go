@@ -0,0 +1,83 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package genericclioptions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marmotedu/marmotedu-sdk-go/tools/clientcmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextApply(t *testing.T) {
+	config := clientcmd.NewConfig()
+	config.Server.Address = "http://127.0.0.1:8080"
+	config.Server.InsecureSkipTLSVerify = true
+	config.Server.Timeout = 10 * time.Second
+	config.AuthInfo.Username = "admin"
+
+	context := &Context{
+		Name:   "prod",
+		Server: ContextServer{Address: "https://iam.example.com:8443"},
+		User:   ContextUser{SecretID: "id", SecretKey: "key"},
+	}
+	context.apply(config)
+
+	assert.Equal(t, "https://iam.example.com:8443", config.Server.Address)
+	assert.False(t, config.Server.InsecureSkipTLSVerify)
+	assert.Equal(t, 10*time.Second, config.Server.Timeout)
+	assert.Equal(t, &clientcmd.AuthInfo{SecretID: "id", SecretKey: "key"}, config.AuthInfo)
+
+	(&Context{Name: "empty"}).apply(config)
+	assert.Equal(t, "https://iam.example.com:8443", config.Server.Address)
+	assert.Equal(t, "id", config.AuthInfo.SecretID)
+}
+
+func TestIAMConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "iamctl.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("server:\n  address: http://127.0.0.1:8080\n"), 0o600))
+
+	file, err := LoadIAMConfigFile(path)
+	assert.NoError(t, err)
+	assert.Empty(t, file.Contexts)
+
+	file.SetContext(&Context{Name: "dev", Server: ContextServer{Address: "http://dev:8080"}})
+	file.SetContext(&Context{Name: "prod", Output: "yaml"})
+	file.SetContext(&Context{Name: "dev", Server: ContextServer{Address: "http://dev:9090"}})
+	file.CurrentContext = "prod"
+	assert.NoError(t, file.Save())
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	file, err = LoadIAMConfigFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", file.CurrentContext)
+	assert.Len(t, file.Contexts, 2)
+	assert.Equal(t, "http://dev:9090", file.Context("dev").Server.Address)
+	assert.Equal(t, "yaml", file.Context("prod").Output)
+	assert.Nil(t, file.Context("staging"))
+	assert.Equal(t, "http://127.0.0.1:8080", file.raw["server"].(map[string]interface{})["address"])
+
+	file, err = LoadIAMConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Empty(t, file.Contexts)
+}
+
+func TestValidateContextName(t *testing.T) {
+	for _, name := range []string{"dev", "prod-eu.1", "a", "staging_2"} {
+		assert.NoError(t, ValidateContextName(name), name)
+	}
+
+	for _, name := range []string{"", "../prod", "a/b", ".dev", "dev-", "with space", strings.Repeat("a", 64)} {
+		assert.Error(t, ValidateContextName(name), name)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package genericclioptions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marmotedu/marmotedu-sdk-go/rest"
+	"github.com/marmotedu/marmotedu-sdk-go/tools/clientcmd"
+)
+
+// tokenRefreshWindow is how long before it expires a cached token is refreshed.
+const tokenRefreshWindow = time.Minute
+
+// tokenLockTimeout is how long iamctl waits for another iamctl refreshing the
+// token of the same context, older locks are left by a killed iamctl.
+const tokenLockTimeout = 30 * time.Second
+
+// tokenCacheDir holds the tokens cached by iamctl login, a file per context.
+var tokenCacheDir = filepath.Join(clientcmd.RecommendedConfigDir, "cache", "tokens")
+
+// Token is the JWT returned by the login of iam-apiserver. The refresh token
+// is only returned when iam-apiserver keeps the login sessions.
+type Token struct {
+	Token         string    `json:"token"`
+	Expire        time.Time `json:"expire"`
+	RefreshToken  string    `json:"refreshToken,omitempty"`
+	RefreshExpire time.Time `json:"refreshExpire,omitempty"`
+	// Server is the address of the iam-apiserver which issued the token, the
+	// token is not sent to other servers.
+	Server string `json:"server"`
+}
+
+// Expired tells whether the token must be refreshed before it is used.
+func (t *Token) Expired() bool {
+	return time.Until(t.Expire) < tokenRefreshWindow
+}
+
+// Refreshable tells whether the token can still be refreshed. Without a
+// refresh token, iam-apiserver decides from the token itself.
+func (t *Token) Refreshable() bool {
+	return t.RefreshToken == "" || time.Now().Before(t.RefreshExpire)
+}
+
+// tokenCachePath returns the cache file of the context. The bytes of the name
+// which are not safe in a file name are escaped as %XX, so that the name can
+// not point outside of the cache directory.
+func tokenCachePath(context string) string {
+	var name strings.Builder
+	for _, b := range []byte(context) {
+		if b == '-' || b == '_' || b == '.' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') {
+			name.WriteByte(b)
+		} else {
+			fmt.Fprintf(&name, "%%%02X", b)
+		}
+	}
+
+	return filepath.Join(tokenCacheDir, name.String()+".json")
+}
+
+// LoadToken returns the token cached for the context, nil when there is none.
+func LoadToken(context string) (*Token, error) {
+	data, err := ioutil.ReadFile(tokenCachePath(context))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	token := &Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("invalid token cache of context %s: %w", context, err)
+	}
+
+	return token, nil
+}
+
+// SaveToken caches the token of the context, the file is only readable by its owner.
+func SaveToken(context string, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(tokenCacheDir, 0o700); err != nil {
+		return err
+	}
+
+	// the file is replaced at once, so that it is never read half written.
+	file, err := ioutil.TempFile(tokenCacheDir, ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), tokenCachePath(context))
+}
+
+// lockToken locks the token cache of the context against the other iamctl
+// processes, until the returned function is called.
+func lockToken(context string) (func(), error) {
+	if err := os.MkdirAll(tokenCacheDir, 0o700); err != nil {
+		return nil, err
+	}
+
+	path := tokenCachePath(context) + ".lock"
+	deadline := time.Now().Add(tokenLockTimeout)
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+
+			return func() { os.Remove(path) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > tokenLockTimeout {
+			os.Remove(path)
+
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the token cache of context %s is locked, remove %s if no iamctl is running", context, path)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// DeleteToken removes the token cached for the context.
+func DeleteToken(context string) error {
+	if err := os.Remove(tokenCachePath(context)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// LoginResult is the answer of iam-apiserver to a login. Users who must pass a
+// second factor get a challenge instead of a token.
+type LoginResult struct {
+	Token
+
+	MFARequired    bool   `json:"mfaRequired"`
+	MFAToken       string `json:"mfaToken"`
+	EnrollRequired bool   `json:"enrollRequired"`
+}
+
+// TokenClient requests the tokens of iam-apiserver. The requests carry no
+// credentials but the ones of their body, so that a stale token can not get
+// in the way of a login.
+type TokenClient struct {
+	server string
+	client *http.Client
+}
+
+// NewTokenClient returns a client of the iam-apiserver of config.
+func NewTokenClient(config *rest.Config) (*TokenClient, error) {
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	server := strings.TrimSuffix(config.Host, "/")
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+
+	return &TokenClient{
+		server: server,
+		client: &http.Client{
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+			Timeout:   config.Timeout,
+		},
+	}, nil
+}
+
+// Login authenticates the user with their password.
+func (c *TokenClient) Login(username, password string) (*LoginResult, error) {
+	result := &LoginResult{}
+
+	err := c.post("/login", "", map[string]string{"username": username, "password": password}, result)
+
+	return result, err
+}
+
+// SecondFactor answers the challenge of a login with the code of the second factor.
+func (c *TokenClient) SecondFactor(mfaToken, code string) (*Token, error) {
+	token := &Token{}
+
+	err := c.post("/login/2fa", "", map[string]string{"mfaToken": mfaToken, "code": code}, token)
+
+	return token, err
+}
+
+// Refresh exchanges the token for a new one, with its refresh token when it
+// has one, or else with the token itself.
+func (c *TokenClient) Refresh(token *Token) (*Token, error) {
+	refreshed := &Token{}
+
+	if token.RefreshToken != "" {
+		err := c.post("/refresh", "", map[string]string{"refreshToken": token.RefreshToken}, refreshed)
+
+		return refreshed, err
+	}
+
+	err := c.post("/refresh", token.Token, nil, refreshed)
+
+	return refreshed, err
+}
+
+func (c *TokenClient) post(path, bearer string, body interface{}, into interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.server+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+
+		if json.Unmarshal(data, &failure) != nil || failure.Message == "" {
+			failure.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return fmt.Errorf("%s: %s", strings.TrimPrefix(path, "/"), failure.Message)
+	}
+
+	return json.Unmarshal(data, into)
+}
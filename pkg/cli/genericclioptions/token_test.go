@@ -0,0 +1,156 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package genericclioptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marmotedu/marmotedu-sdk-go/tools/clientcmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func useTempTokenCache(t *testing.T) {
+	dir := tokenCacheDir
+	tokenCacheDir = t.TempDir()
+
+	t.Cleanup(func() { tokenCacheDir = dir })
+}
+
+func TestTokenCache(t *testing.T) {
+	useTempTokenCache(t)
+
+	token, err := LoadToken("dev")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	saved := &Token{Token: "jwt", Expire: time.Now().Add(time.Hour).Round(0), Server: "http://dev:8080"}
+	assert.NoError(t, SaveToken("dev", saved))
+
+	token, err = LoadToken("dev")
+	assert.NoError(t, err)
+	assert.True(t, saved.Expire.Equal(token.Expire))
+	assert.Equal(t, saved.Token, token.Token)
+	assert.False(t, token.Expired())
+
+	assert.NoError(t, DeleteToken("dev"))
+	assert.NoError(t, DeleteToken("dev"))
+
+	token, err = LoadToken("dev")
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestUseCachedToken(t *testing.T) {
+	useTempTokenCache(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if r.URL.Path != "/refresh" || body["refreshToken"] != "refresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"invalid refresh token"}`))
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":  "refreshed",
+			"expire": time.Now().Add(time.Hour).Format(time.RFC3339),
+		})
+	}))
+	defer server.Close()
+
+	newConfig := func() *clientcmd.Config {
+		config := clientcmd.NewConfig()
+		config.Server.Address = server.URL
+
+		return config
+	}
+
+	// no cached token
+	config := newConfig()
+	assert.NoError(t, useCachedToken("dev", config))
+	assert.Empty(t, config.AuthInfo.Token)
+
+	// the token of another server is not used
+	assert.NoError(t, SaveToken("dev", &Token{Token: "jwt", Expire: time.Now().Add(time.Hour), Server: "http://other"}))
+	assert.NoError(t, useCachedToken("dev", config))
+	assert.Empty(t, config.AuthInfo.Token)
+
+	// an expired token is refreshed and cached again
+	assert.NoError(t, SaveToken("dev", &Token{
+		Token:         "jwt",
+		Expire:        time.Now(),
+		RefreshToken:  "refresh",
+		RefreshExpire: time.Now().Add(time.Hour),
+		Server:        server.URL,
+	}))
+	assert.NoError(t, useCachedToken("dev", config))
+	assert.Equal(t, "refreshed", config.AuthInfo.Token)
+
+	token, err := LoadToken("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, "refreshed", token.Token)
+	assert.Equal(t, "refresh", token.RefreshToken)
+	assert.Equal(t, server.URL, token.Server)
+
+	// a token which can not be refreshed requires a new login, unless there
+	// are other credentials
+	assert.NoError(t, SaveToken("dev", &Token{Token: "jwt", Expire: time.Now(), RefreshToken: "revoked",
+		RefreshExpire: time.Now().Add(time.Hour), Server: server.URL}))
+	err = useCachedToken("dev", newConfig())
+	assert.EqualError(t, err,
+		"refresh the token of context dev: refresh: invalid refresh token, run 'iamctl login' again")
+
+	config = newConfig()
+	config.AuthInfo.SecretID, config.AuthInfo.SecretKey = "id", "key"
+	assert.NoError(t, useCachedToken("dev", config))
+	assert.Empty(t, config.AuthInfo.Token)
+}
+
+func TestTokenCachePath(t *testing.T) {
+	useTempTokenCache(t)
+
+	assert.Equal(t, filepath.Join(tokenCacheDir, "dev.json"), tokenCachePath("dev"))
+	assert.Equal(t, filepath.Join(tokenCacheDir, "..%2F..%2Fetc%2Fpasswd.json"), tokenCachePath("../../etc/passwd"))
+	assert.NotEqual(t, tokenCachePath("a/b"), tokenCachePath("a%2Fb"))
+}
+
+func TestRefreshTokenWaitsForLock(t *testing.T) {
+	useTempTokenCache(t)
+
+	// the refresh token has been used by the other iamctl, it is refused.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, SaveToken("dev", &Token{Token: "jwt", Expire: time.Now(), RefreshToken: "refresh",
+		RefreshExpire: time.Now().Add(time.Hour), Server: server.URL}))
+
+	unlock, err := lockToken("dev")
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	config := clientcmd.NewConfig()
+	config.Server.Address = server.URL
+
+	go func() { done <- useCachedToken("dev", config) }()
+
+	// another iamctl refreshes the token while holding the lock.
+	time.Sleep(200 * time.Millisecond)
+	assert.NoError(t, SaveToken("dev", &Token{Token: "refreshed", Expire: time.Now().Add(time.Hour),
+		RefreshToken: "refresh-2", RefreshExpire: time.Now().Add(time.Hour), Server: server.URL}))
+	unlock()
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, "refreshed", config.AuthInfo.Token)
+}
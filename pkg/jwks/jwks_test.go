@@ -0,0 +1,136 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newKey(t *testing.T, id, alg string) *Key {
+	signer, err := GenerateKey(alg)
+	assert.Nil(t, err)
+
+	// keys are stored as PEM, make sure they survive the round trip.
+	data, err := MarshalPrivateKey(signer)
+	assert.Nil(t, err)
+	signer, err = ParsePrivateKey(data)
+	assert.Nil(t, err)
+
+	return &Key{ID: id, Algorithm: alg, Signer: signer}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	for _, alg := range []string{RS256, ES256, EdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			old := newKey(t, "old", alg)
+			ks, err := NewKeySet([]*Key{old})
+			assert.Nil(t, err)
+
+			oldToken, err := ks.Sign(jwt.MapClaims{"sub": "maria"})
+			assert.Nil(t, err)
+
+			// rotate: the old key only verifies, the new one signs.
+			old.Retired = true
+			assert.Nil(t, ks.Replace([]*Key{old, newKey(t, "new", alg)}))
+
+			newToken, err := ks.Sign(jwt.MapClaims{"sub": "maria"})
+			assert.Nil(t, err)
+
+			token, err := jwt.Parse(newToken, KeyFunc(ks))
+			assert.Nil(t, err)
+			assert.Equal(t, "new", token.Header["kid"])
+
+			// verify offline through the published key set.
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(ks.JWKS())
+			}))
+			defer srv.Close()
+
+			remote := NewRemoteKeySet(srv.URL, time.Minute)
+			for _, raw := range []string{oldToken, newToken} {
+				_, err = jwt.Parse(raw, KeyFunc(remote))
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejected(t *testing.T) {
+	ks, _ := NewKeySet([]*Key{newKey(t, "rsa", RS256)})
+	other, _ := NewKeySet([]*Key{newKey(t, "other", RS256)})
+
+	raw, _ := other.Sign(jwt.MapClaims{"sub": "maria"})
+	_, err := jwt.Parse(raw, KeyFunc(ks))
+	assert.NotNil(t, err)
+
+	// a key must not verify tokens of another algorithm.
+	_, err = ks.PublicKey("rsa", ES256)
+	assert.NotNil(t, err)
+
+	empty, _ := NewKeySet(nil)
+	_, err = empty.Sign(jwt.MapClaims{})
+	assert.Equal(t, ErrNoSigningKey, err)
+}
+
+func TestRemoteKeyWithoutAlg(t *testing.T) {
+	ks, _ := NewKeySet([]*Key{newKey(t, "rsa", RS256)})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set := ks.JWKS()
+		for i := range set.Keys {
+			set.Keys[i].Alg = ""
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	remote := NewRemoteKeySet(srv.URL, time.Minute)
+
+	raw, _ := ks.Sign(jwt.MapClaims{"sub": "maria"})
+	_, err := jwt.Parse(raw, KeyFunc(remote))
+	assert.Nil(t, err)
+
+	// the type of the key still has to match the algorithm.
+	_, err = remote.PublicKey("rsa", ES256)
+	assert.NotNil(t, err)
+	_, err = remote.PublicKey("rsa", "HS256")
+	assert.NotNil(t, err)
+}
+
+func TestRemoteKeySetSharesFetch(t *testing.T) {
+	ks, _ := NewKeySet([]*Key{newKey(t, "rsa", RS256)})
+
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		time.Sleep(50 * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(ks.JWKS())
+	}))
+	defer srv.Close()
+
+	remote := NewRemoteKeySet(srv.URL, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := remote.PublicKey("rsa", RS256)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package jwks signs jwt tokens with a set of asymmetric keys identified by
+// kid, publishes their public part as a JSON Web Key Set (RFC 7517), and
+// verifies tokens offline against a local or a remote key set.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Supported signing algorithms.
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+const rsaKeyBits = 2048
+
+// ErrUnsupportedAlgorithm is returned for algorithms other than RS256, ES256 and EdDSA.
+var ErrUnsupportedAlgorithm = errors.New("jwks: unsupported signing algorithm")
+
+// Supported tells whether the algorithm is one of the asymmetric algorithms supported.
+func Supported(alg string) bool {
+	switch alg {
+	case RS256, ES256, EdDSA:
+		return true
+	default:
+		return false
+	}
+}
+
+// SigningMethod returns the jwt signing method of the algorithm.
+func SigningMethod(alg string) (jwt.SigningMethod, error) {
+	if !Supported(alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	return jwt.GetSigningMethod(alg), nil
+}
+
+// GenerateKey generates a new private key for the algorithm.
+func GenerateKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case RS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case EdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+
+		return key, err
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// MarshalPrivateKey encodes the private key as a PKCS #8 PEM block.
+func MarshalPrivateKey(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// ParsePrivateKey decodes a private key encoded by MarshalPrivateKey.
+func ParsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("jwks: no PEM block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwks: unsupported private key type %T", key)
+	}
+
+	return signer, nil
+}
+
+// checkKey makes sure the public key can be used with the algorithm.
+func checkKey(alg string, pub crypto.PublicKey) error {
+	var ok bool
+
+	switch alg {
+	case RS256:
+		_, ok = pub.(*rsa.PublicKey)
+	case ES256:
+		var key *ecdsa.PublicKey
+		if key, ok = pub.(*ecdsa.PublicKey); ok {
+			ok = key.Curve == elliptic.P256()
+		}
+	case EdDSA:
+		_, ok = pub.(ed25519.PublicKey)
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	if !ok {
+		return fmt.Errorf("jwks: %T can not be used with %s", pub, alg)
+	}
+
+	return nil
+}
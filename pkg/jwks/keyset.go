@@ -0,0 +1,143 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jwks
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Defined errors.
+var (
+	ErrNoSigningKey = errors.New("jwks: no active signing key")
+	ErrMissingKID   = errors.New("jwks: missing kid in token header")
+	ErrUnknownKID   = errors.New("jwks: unknown kid")
+)
+
+// PublicKeyGetter returns the public key identified by kid, which must be
+// usable with the given algorithm.
+type PublicKeyGetter interface {
+	PublicKey(kid, alg string) (crypto.PublicKey, error)
+}
+
+// KeyFunc returns a jwt.Keyfunc which verifies tokens with the keys of the getter.
+func KeyFunc(getter PublicKeyGetter) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, ErrMissingKID
+		}
+
+		return getter.PublicKey(kid, token.Method.Alg())
+	}
+}
+
+// Key is a private signing key.
+type Key struct {
+	ID        string
+	Algorithm string
+	Signer    crypto.Signer
+	// Retired keys do not sign tokens anymore, they are only kept to verify
+	// the tokens they have signed until these expire.
+	Retired bool
+}
+
+// KeySet holds the signing keys of a token issuer. The last active key signs
+// the new tokens, all the keys verify them.
+type KeySet struct {
+	mu      sync.RWMutex
+	current *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet creates a key set, keys are ordered from the oldest to the newest.
+func NewKeySet(keys []*Key) (*KeySet, error) {
+	ks := &KeySet{}
+	if err := ks.Replace(keys); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Replace replaces all the keys of the set, e.g. after a rotation.
+func (ks *KeySet) Replace(keys []*Key) error {
+	var current *Key
+
+	index := make(map[string]*Key, len(keys))
+	for _, key := range keys {
+		if err := checkKey(key.Algorithm, key.Signer.Public()); err != nil {
+			return fmt.Errorf("key %s: %w", key.ID, err)
+		}
+
+		index[key.ID] = key
+		if !key.Retired {
+			current = key
+		}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.current = current
+	ks.keys = index
+
+	return nil
+}
+
+// Sign signs the claims with the current key and records its kid in the token header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	key := ks.current
+	ks.mu.RUnlock()
+
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Signer)
+}
+
+// PublicKey implements PublicKeyGetter.
+func (ks *KeySet) PublicKey(kid, alg string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKID
+	}
+
+	if key.Algorithm != alg {
+		return nil, fmt.Errorf("jwks: key %s can not verify %s tokens", kid, alg)
+	}
+
+	return key.Signer.Public(), nil
+}
+
+// JWKS returns the public keys of the set.
+func (ks *KeySet) JWKS() Set {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	set := Set{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		jwk, err := NewJWK(key.ID, key.Algorithm, key.Signer.Public())
+		if err != nil {
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jwks
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// minRefreshInterval limits how often an unknown kid triggers a fetch.
+const minRefreshInterval = 10 * time.Second
+
+// RemoteKeySet verifies tokens offline with the keys published at a JWKS url.
+// Keys are cached for the refresh interval, and fetched again as soon as a
+// token is signed by an unknown key, e.g. right after a rotation.
+type RemoteKeySet struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+	group   singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]JWK
+	fetchedAt time.Time
+}
+
+var _ PublicKeyGetter = &RemoteKeySet{}
+
+// NewRemoteKeySet creates a key set backed by the JWKS url.
+func NewRemoteKeySet(url string, refresh time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// PublicKey implements PublicKeyGetter.
+func (r *RemoteKeySet) PublicKey(kid, alg string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	jwk, ok := r.keys[kid]
+	stale := !ok || time.Since(r.fetchedAt) > r.refresh
+	fetchable := r.keys == nil || time.Since(r.fetchedAt) > minRefreshInterval
+	r.mu.Unlock()
+
+	if stale && fetchable {
+		keys, err := r.refreshKeys()
+		if err != nil && !ok {
+			return nil, err
+		}
+
+		if err == nil {
+			jwk, ok = keys[kid]
+		}
+	}
+
+	if !ok {
+		return nil, ErrUnknownKID
+	}
+
+	// alg is optional in a JWKS, identity providers often leave it out, the
+	// type of the key is still checked against the algorithm of the token.
+	if jwk.Alg == "" {
+		jwk.Alg = alg
+	}
+
+	if jwk.Alg != alg {
+		return nil, fmt.Errorf("jwks: key %s can not verify %s tokens", kid, alg)
+	}
+
+	return jwk.PublicKey()
+}
+
+// refreshKeys fetches the key set without holding the lock, concurrent
+// requests share a single fetch.
+func (r *RemoteKeySet) refreshKeys() (map[string]JWK, error) {
+	v, err, _ := r.group.Do(r.url, func() (interface{}, error) {
+		keys, err := r.fetch()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.fetchedAt = time.Now()
+		if err != nil {
+			return nil, err
+		}
+		r.keys = keys
+
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(map[string]JWK), nil
+}
+
+// fetch downloads the key set.
+func (r *RemoteKeySet) fetch() (map[string]JWK, error) {
+	rsp, err := r.client.Get(r.url)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: fetch %s: unexpected status %s", r.url, rsp.Status)
+	}
+
+	var set Set
+	if err := json.NewDecoder(rsp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]JWK, len(set.Keys))
+	for _, key := range set.Keys {
+		keys[key.Kid] = key
+	}
+
+	return keys, nil
+}
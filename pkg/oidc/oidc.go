@@ -0,0 +1,280 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package oidc implements the relying party side of the OpenID Connect
+// authorization code flow with PKCE (RFC 7636): provider discovery,
+// authorization requests, code exchange, and ID token validation against the
+// JWKS published by the provider.
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
+)
+
+// DiscoveryPath is where a provider publishes its configuration, relative to the issuer.
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// keyRefreshInterval defines how long the keys of the provider are cached,
+// unknown keys are fetched right away.
+const keyRefreshInterval = time.Hour
+
+// ErrInvalidIDToken is returned for the ID tokens which fail validation.
+var ErrInvalidIDToken = errors.New("oidc: invalid id token")
+
+// Config defines the client registered at the provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Provider defines the endpoints published in the discovery document of a provider.
+type Provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Token defines the response of the token endpoint.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// IDToken defines the claims of a validated ID token.
+type IDToken struct {
+	Issuer  string
+	Subject string
+	Claims  jwt.MapClaims
+}
+
+// String returns the claim with the given name, empty if it is missing or not a string.
+func (t *IDToken) String(name string) string {
+	value, _ := t.Claims[name].(string)
+
+	return value
+}
+
+// Client is an OpenID Connect client. The provider is discovered on first use,
+// so that the provider does not have to be up when the client is created.
+type Client struct {
+	config Config
+	http   *http.Client
+
+	mu       sync.Mutex
+	provider *Provider
+	keys     *jwks.RemoteKeySet
+}
+
+// NewClient creates a client of the provider identified by config.Issuer.
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Provider returns the configuration of the provider, discovered on first call.
+func (c *Client) Provider(ctx context.Context) (*Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.provider != nil {
+		return c.provider, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(c.config.Issuer, "/")+DiscoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: discover %s: unexpected status %s", c.config.Issuer, rsp.Status)
+	}
+
+	var provider Provider
+	if err := json.NewDecoder(rsp.Body).Decode(&provider); err != nil {
+		return nil, err
+	}
+
+	// the issuer of the ID tokens is checked against the discovered one.
+	if provider.Issuer != c.config.Issuer {
+		return nil, fmt.Errorf("oidc: issuer %s does not match the configured issuer %s",
+			provider.Issuer, c.config.Issuer)
+	}
+
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: incomplete configuration of issuer %s", provider.Issuer)
+	}
+
+	c.provider, c.keys = &provider, jwks.NewRemoteKeySet(provider.JWKSURI, keyRefreshInterval)
+
+	return c.provider, nil
+}
+
+// AuthCodeURL returns the url of the authorization request the user agent is
+// redirected to. The state and the nonce bind the response and the ID token
+// to the request, the code challenge is derived from the verifier which is
+// sent with the code exchange.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	provider, err := c.Provider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.config.ClientID)
+	query.Set("redirect_uri", c.config.RedirectURL)
+	query.Set("scope", strings.Join(c.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", Challenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange exchanges the authorization code for the tokens of the user, the
+// verifier must be the one the challenge of the authorization request was
+// derived from.
+func (c *Client) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	provider, err := c.Provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"client_id":     {c.config.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+	}
+
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &e)
+
+		return nil, fmt.Errorf("oidc: exchange code: %s %s %s", rsp.Status, e.Error, e.Description)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+
+	if token.IDToken == "" {
+		return nil, errors.New("oidc: no id_token in token response")
+	}
+
+	return &token, nil
+}
+
+// Verify validates the signature of the ID token against the keys of the
+// provider, its issuer, audience and expiry, and makes sure it was issued
+// for the authorization request with the given nonce.
+func (c *Client) Verify(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	provider, err := c.Provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	keys := c.keys
+	c.mu.Unlock()
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwks.RS256, jwks.ES256, jwks.EdDSA}))
+	if _, err := parser.ParseWithClaims(raw, claims, jwks.KeyFunc(keys)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIDToken, err.Error())
+	}
+
+	if !claims.VerifyIssuer(provider.Issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidIDToken)
+	}
+
+	if !claims.VerifyAudience(c.config.ClientID, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidIDToken)
+	}
+
+	// the authorized party, if any, must be this client.
+	if azp, ok := claims["azp"].(string); ok && azp != c.config.ClientID {
+		return nil, fmt.Errorf("%w: unexpected authorized party", ErrInvalidIDToken)
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("%w: missing expiry", ErrInvalidIDToken)
+	}
+
+	got, _ := claims["nonce"].(string)
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: unexpected nonce", ErrInvalidIDToken)
+	}
+
+	token := &IDToken{Issuer: provider.Issuer, Claims: claims}
+	if token.Subject = token.String("sub"); token.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return token, nil
+}
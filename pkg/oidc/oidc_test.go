@@ -0,0 +1,135 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/oidc"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/oidc/oidctest"
+)
+
+func newProvider(t *testing.T) (*oidctest.Provider, *oidc.Client) {
+	provider, err := oidctest.NewProvider("iam", "secret")
+	assert.Nil(t, err)
+	t.Cleanup(provider.Close)
+
+	client := oidc.NewClient(oidc.Config{
+		Issuer:       provider.Issuer(),
+		ClientID:     "iam",
+		ClientSecret: "secret",
+		RedirectURL:  "https://iam.example.com/login/oidc/callback",
+		Scopes:       []string{"openid", "profile", "email"},
+	})
+
+	return provider, client
+}
+
+func TestLogin(t *testing.T) {
+	provider, client := newProvider(t)
+	provider.SetClaims(jwt.MapClaims{"sub": "248289761001", "preferred_username": "colin"})
+	ctx := context.Background()
+
+	state, _ := oidc.RandomToken()
+	nonce, _ := oidc.RandomToken()
+	verifier, _ := oidc.RandomToken()
+
+	authURL, err := client.AuthCodeURL(ctx, state, nonce, verifier)
+	assert.Nil(t, err)
+
+	callback, err := provider.Authorize(authURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "/login/oidc/callback", callback.Path)
+	assert.Equal(t, state, callback.Query().Get("state"))
+
+	token, err := client.Exchange(ctx, callback.Query().Get("code"), verifier)
+	assert.Nil(t, err)
+
+	idToken, err := client.Verify(ctx, token.IDToken, nonce)
+	assert.Nil(t, err)
+	assert.Equal(t, provider.Issuer(), idToken.Issuer)
+	assert.Equal(t, "248289761001", idToken.Subject)
+	assert.Equal(t, "colin", idToken.String("preferred_username"))
+
+	// the ID token is bound to the nonce of the authorization request.
+	_, err = client.Verify(ctx, token.IDToken, "other")
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+
+	// codes can only be exchanged once.
+	_, err = client.Exchange(ctx, callback.Query().Get("code"), verifier)
+	assert.NotNil(t, err)
+}
+
+func TestExchangeRequiresVerifier(t *testing.T) {
+	provider, client := newProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := oidc.RandomToken()
+	authURL, _ := client.AuthCodeURL(ctx, "state", "nonce", verifier)
+
+	callback, err := provider.Authorize(authURL)
+	assert.Nil(t, err)
+
+	other, _ := oidc.RandomToken()
+	_, err = client.Exchange(ctx, callback.Query().Get("code"), other)
+	assert.NotNil(t, err)
+}
+
+func TestVerifyRejected(t *testing.T) {
+	provider, client := newProvider(t)
+	ctx := context.Background()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   provider.Issuer(),
+			"aud":   "iam",
+			"sub":   "248289761001",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	raw, _ := provider.Sign(valid())
+	_, err := client.Verify(ctx, raw, "nonce")
+	assert.Nil(t, err)
+
+	for name, change := range map[string]func(jwt.MapClaims){
+		"issuer":          func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"audience":        func(c jwt.MapClaims) { c["aud"] = "other" },
+		"authorizedParty": func(c jwt.MapClaims) { c["aud"], c["azp"] = []string{"iam", "other"}, "other" },
+		"expired":         func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"noExpiry":        func(c jwt.MapClaims) { delete(c, "exp") },
+		"noNonce":         func(c jwt.MapClaims) { delete(c, "nonce") },
+		"noSubject":       func(c jwt.MapClaims) { delete(c, "sub") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			claims := valid()
+			change(claims)
+
+			raw, _ := provider.Sign(claims)
+			_, err := client.Verify(ctx, raw, "nonce")
+			assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken), err)
+		})
+	}
+
+	// symmetric tokens signed with a guessable key are refused.
+	raw, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("iam"))
+	_, err = client.Verify(ctx, raw, "nonce")
+	assert.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	provider, _ := newProvider(t)
+
+	client := oidc.NewClient(oidc.Config{Issuer: provider.Issuer() + "/", ClientID: "iam"})
+	_, err := client.Provider(context.Background())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package oidctest provides an in-process OpenID Connect provider to test
+// the login of the relying parties.
+package oidctest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/jwks"
+	"github.com/skeleton1231/go-iam-ecommerce-microservice/pkg/oidc"
+)
+
+// authorization is an authorization code waiting to be exchanged.
+type authorization struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	claims      jwt.MapClaims
+}
+
+// Provider is an OpenID Connect provider which authenticates every
+// authorization request as the user set by SetClaims.
+type Provider struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+
+	keys *jwks.KeySet
+
+	mu     sync.Mutex
+	claims jwt.MapClaims
+	codes  map[string]*authorization
+}
+
+// NewProvider starts a provider with a single registered client, close it when done.
+func NewProvider(clientID, clientSecret string) (*Provider, error) {
+	signer, err := jwks.GenerateKey(jwks.RS256)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := jwks.NewKeySet([]*jwks.Key{{ID: "oidctest", Algorithm: jwks.RS256, Signer: signer}})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		keys:         keys,
+		claims:       jwt.MapClaims{"sub": "oidctest"},
+		codes:        map[string]*authorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidc.DiscoveryPath, p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+
+	return p, nil
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (p *Provider) Issuer() string {
+	return p.URL
+}
+
+// SetClaims sets the claims of the user authenticated by the next
+// authorization requests, e.g. sub, preferred_username and email.
+func (p *Provider) SetClaims(claims jwt.MapClaims) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.claims = claims
+}
+
+// Sign signs the claims with the key of the provider, to forge ID tokens.
+func (p *Provider) Sign(claims jwt.MapClaims) (string, error) {
+	return p.keys.Sign(claims)
+}
+
+// Authorize follows the authorization request as the user agent would, and
+// returns the url the provider redirects the user agent to.
+func (p *Provider) Authorize(authCodeURL string) (*url.URL, error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	rsp, err := client.Get(authCodeURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusFound {
+		return nil, errors.New("oidctest: authorization request refused: " + rsp.Status)
+	}
+
+	return rsp.Location()
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.Provider{
+		Issuer:                p.URL,
+		AuthorizationEndpoint: p.URL + "/authorize",
+		TokenEndpoint:         p.URL + "/token",
+		JWKSURI:               p.URL + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, p.keys.JWKS())
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != p.ClientID ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+
+		return
+	}
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+
+		return
+	}
+
+	code, err := oidc.RandomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	p.mu.Lock()
+	claims := jwt.MapClaims{}
+	for key, value := range p.claims {
+		claims[key] = value
+	}
+	p.codes[code] = &authorization{
+		redirectURI: redirect.String(),
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		claims:      claims,
+	}
+	p.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+
+		return
+	}
+
+	id, secret, _ := r.BasicAuth()
+	if id, _ = url.QueryUnescape(id); id != p.ClientID {
+		writeError(w, http.StatusUnauthorized, "invalid_client")
+
+		return
+	}
+
+	if secret, _ = url.QueryUnescape(secret); secret != p.ClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid_client")
+
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+
+		return
+	}
+
+	// codes can only be used once.
+	p.mu.Lock()
+	auth, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	if !ok || auth.redirectURI != r.PostForm.Get("redirect_uri") ||
+		oidc.Challenge(r.PostForm.Get("code_verifier")) != auth.challenge {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   p.URL,
+		"aud":   p.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": auth.nonce,
+	}
+	for key, value := range auth.claims {
+		claims[key] = value
+	}
+
+	idToken, err := p.Sign(claims)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error")
+
+		return
+	}
+
+	accessToken, err := oidc.RandomToken()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, oidc.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		IDToken:     idToken,
+		ExpiresIn:   300,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package totp implements time-based one-time passwords as defined by RFC 6238,
+// on top of the HMAC-based one-time passwords of RFC 4226, with the parameters
+// understood by all authenticator apps: HMAC-SHA1 and base32 encoded secrets.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // nolint: gosec // RFC 6238 default, supported by all authenticator apps.
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPeriod is the time step of the codes.
+	DefaultPeriod = 30 * time.Second
+
+	// DefaultDigits is the length of the codes.
+	DefaultDigits = 6
+
+	// DefaultSkew is the number of time steps accepted before and after the
+	// current one, to allow for clock drift and typing delay.
+	DefaultSkew = 1
+
+	secretSize = 20
+)
+
+// encoding is the base32 encoding of the secrets, without padding since
+// authenticator apps do not expect it.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// TOTP generates and validates time-based one-time passwords.
+type TOTP struct {
+	Period time.Duration
+	Digits int
+	Skew   int
+	Clock  Clock
+}
+
+// New returns a TOTP with the default parameters, on the system clock.
+func New() *TOTP {
+	return &TOTP{
+		Period: DefaultPeriod,
+		Digits: DefaultDigits,
+		Skew:   DefaultSkew,
+		Clock:  systemClock{},
+	}
+}
+
+// GenerateSecret returns a new random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// Step returns the time step of the given time.
+func (t *TOTP) Step(at time.Time) int64 {
+	return at.Unix() / int64(t.Period/time.Second)
+}
+
+// Code returns the code of the secret at the given time.
+func (t *TOTP) Code(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return HOTP(key, uint64(t.Step(at)), t.Digits), nil
+}
+
+// Validate checks the code against the current time step of the clock and the
+// Skew steps around it, and returns the matched time step. Steps up to
+// lastStep are rejected, so that a code can only be used once.
+func (t *TOTP) Validate(secret, code string, lastStep int64) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != t.Digits {
+		return 0, false
+	}
+
+	current := t.Step(t.Clock.Now())
+	for step := current - int64(t.Skew); step <= current+int64(t.Skew); step++ {
+		if step <= lastStep || step < 0 {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(HOTP(key, uint64(step), t.Digits)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI returns the otpauth provisioning URI of the secret, usually rendered as a
+// QR code scanned by the authenticator app.
+func (t *TOTP) URI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(t.Digits))
+	v.Set("period", fmt.Sprint(int64(t.Period/time.Second)))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String()
+}
+
+// HOTP returns the HMAC-based one-time password of the key and the counter, as
+// defined by RFC 4226.
+func HOTP(key []byte, counter uint64, digits int) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+
+	key, err := encoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid totp secret: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Talhuang<talhuang1231@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors, base32 encoded.
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	g := New()
+	g.Digits = 8
+
+	for sec, want := range vectors {
+		code, err := g.Code(rfcSecret, time.Unix(sec, 0))
+		assert.Nil(t, err)
+		assert.Equal(t, want, code, "time %d", sec)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1111111111, 0)}
+	g := New()
+	g.Clock = clock
+
+	code, _ := g.Code(rfcSecret, clock.now)
+	step, ok := g.Validate(rfcSecret, code, 0)
+	assert.True(t, ok)
+	assert.Equal(t, g.Step(clock.now), step)
+
+	// a code can only be used once.
+	_, ok = g.Validate(rfcSecret, code, step)
+	assert.False(t, ok)
+
+	// the previous step is still accepted, but not the one before.
+	clock.now = clock.now.Add(g.Period)
+	_, ok = g.Validate(rfcSecret, code, 0)
+	assert.True(t, ok)
+
+	clock.now = clock.now.Add(g.Period)
+	_, ok = g.Validate(rfcSecret, code, 0)
+	assert.False(t, ok)
+
+	_, ok = g.Validate(rfcSecret, "12345", 0)
+	assert.False(t, ok)
+	_, ok = g.Validate("not base32!", code, 0)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	u, err := url.Parse(New().URI("iam", "colin", secret))
+	assert.Nil(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/iam:colin", u.Path)
+	assert.Equal(t, secret, u.Query().Get("secret"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}